@@ -16,6 +16,9 @@ func main() {
 		fmt.Println(common.Version())
 		return
 	}
+	if len(os.Args) > 1 && os.Args[1] == "migrate-policy-rules-v2" {
+		os.Exit(zpa.MigratePolicyRulesV2(os.Args[2:], os.Stdout, os.Stderr))
+	}
 	var debug bool
 	if len(os.Args) > 1 && os.Args[1] == "debug" {
 		debug = true
@@ -6,30 +6,18 @@ import (
 	"log"
 	"sort"
 	"strconv"
-	"strings"
 	"sync"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/zscaler/zscaler-sdk-go/v3/zscaler"
 	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/appconnectorgroup"
-	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/applicationsegment"
-	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/cloud_connector_group"
 	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/common"
 	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/customerversionprofile"
-	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/idpcontroller"
-	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/machinegroup"
-	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/platforms"
 	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontroller"
 	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontrollerv2"
-	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/postureprofile"
-	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/samlattribute"
-	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/scimattributeheader"
-	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/scimgroup"
-	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/segmentgroup"
 	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/servergroup"
 	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/serviceedgegroup"
-	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/trustednetwork"
 )
 
 var (
@@ -86,207 +74,53 @@ func validateOperands(ctx context.Context, operands []policysetcontroller.Operan
 	return nil
 }
 
+// validateOperand validates a v1 operand against the operand registry,
+// including the lookup of the tenant objects it references.
 func validateOperand(ctx context.Context, operand policysetcontroller.Operands, zClient *Client, microtenantID string) error {
-	switch operand.ObjectType {
-	case "APP":
-		return customValidate(operand, []string{"id"}, "application segment ID", Getter(func(id string) error {
-			_, _, err := applicationsegment.Get(ctx, zClient.Service.WithMicroTenant(microtenantID), id)
-			return err
-		}))
-	case "APP_GROUP":
-		return customValidate(operand, []string{"id"}, "Segment Group ID", Getter(func(id string) error {
-			_, _, err := segmentgroup.Get(ctx, zClient.Service.WithMicroTenant(microtenantID), id)
-			return err
-		}))
-
-	case "IDP":
-		return customValidate(operand, []string{"id"}, "IDP ID", Getter(func(id string) error {
-			_, _, err := idpcontroller.Get(ctx, zClient.Service, id)
-			return err
-		}))
-	case "EDGE_CONNECTOR_GROUP":
-		return customValidate(operand, []string{"id"}, "cloud connector group ID", Getter(func(id string) error {
-			_, _, err := cloud_connector_group.Get(ctx, zClient.Service, id)
-			return err
-		}))
-	case "CLIENT_TYPE":
-		return customValidate(operand, []string{"id"}, "'zpn_client_type_zapp' or 'zpn_client_type_exporter' or 'zpn_client_type_exporter_noauth' or 'zpn_client_type_ip_anchoring' or 'zpn_client_type_browser_isolation' or 'zpn_client_type_machine_tunnel' or 'zpn_client_type_edge_connector' or 'zpn_client_type_slogger' or 'zpn_client_type_branch_connector' or 'zpn_client_type_zapp_partner' or 'zpn_client_type_vdi'", Getter(func(id string) error {
-			if id != "zpn_client_type_zapp" &&
-				id != "zpn_client_type_exporter" &&
-				id != "zpn_client_type_exporter_noauth" &&
-				id != "zpn_client_type_ip_anchoring" &&
-				id != "zpn_client_type_browser_isolation" &&
-				id != "zpn_client_type_machine_tunnel" &&
-				id != "zpn_client_type_edge_connector" &&
-				id != "zpn_client_type_slogger" &&
-				id != "zpn_client_type_branch_connector" &&
-				id != "zpn_client_type_zapp_partner" &&
-				id != "zpn_client_type_vdi" {
-				return fmt.Errorf("RHS values must be one of 'zpn_client_type_zapp', 'zpn_client_type_exporter', 'zpn_client_type_exporter_noauth', 'zpn_client_type_ip_anchoring', 'zpn_client_type_browser_isolation', 'zpn_client_type_machine_tunnel', 'zpn_client_type_edge_connector', 'zpn_client_type_slogger', 'zpn_client_type_branch_connector', 'zpn_client_type_zapp_partner', or 'zpn_client_type_vdi' when object type is CLIENT_TYPE")
-			}
-			return nil
-		}))
+	t, ok := lookupPolicyOperandType(operand.ObjectType)
+	if !ok {
+		return fmt.Errorf("[WARN] invalid operand object type %s", operand.ObjectType)
+	}
+	v := operandValue{lhs: operand.LHS, rhs: operand.RHS, idpID: operand.IdpID}
+	if t.v1RequiresIdP && v.idpID == "" {
+		return fmt.Errorf("[WARN] when operand object type is %v Idp ID must be set", operand.ObjectType)
+	}
+	rhs := t.rhs
+	if t.v1rhs != nil {
+		rhs = *t.v1rhs
+	}
+	var cache *referenceLookupCache
+	if zClient != nil {
+		cache = zClient.referenceCache
+	}
+	scope := ""
+	if t.microtenantScoped {
+		scope = microtenantID
+	}
 
-	case "MACHINE_GRP":
-		return customValidate(operand, []string{"id"}, "machine group ID", Getter(func(id string) error {
-			_, _, err := machinegroup.Get(ctx, zClient.Service.WithMicroTenant(microtenantID), id)
+	if t.valueKind == operandValueEntry {
+		if err := rejectCountryAlias(operand.ObjectType, v.lhs); err != nil {
 			return err
-		}))
-	case "POSTURE":
-		if operand.LHS == "" {
-			return lhsWarn(operand.ObjectType, "valid posture network ID", operand.LHS, nil)
-		}
-		_, _, err := postureprofile.GetByPostureUDID(ctx, zClient.Service, operand.LHS)
-		if err != nil {
-			return lhsWarn(operand.ObjectType, "valid posture network ID", operand.LHS, err)
-		}
-		if !contains([]string{"true", "false"}, operand.RHS) {
-			return rhsWarn(operand.ObjectType, "\"true\"/\"false\"", operand.RHS, nil)
 		}
-		return nil
-	case "TRUSTED_NETWORK":
-		if operand.LHS == "" {
-			return lhsWarn(operand.ObjectType, "valid trusted network ID", operand.LHS, nil)
-		}
-		_, _, err := trustednetwork.GetByNetID(ctx, zClient.Service, operand.LHS)
-		if err != nil {
-			return lhsWarn(operand.ObjectType, "valid trusted network ID", operand.LHS, err)
-		}
-		if operand.RHS != "true" {
-			return rhsWarn(operand.ObjectType, "\"true\"", operand.RHS, nil)
-		}
-		return nil
-	case "PLATFORM":
-		if operand.LHS == "" {
-			return lhsWarn(operand.ObjectType, "valid platform ID", operand.LHS, nil)
+		if err := t.lhs.validate(v.lhs); err != nil {
+			return lhsWarn(operand.ObjectType, t.lhs.description, operand.LHS, err)
 		}
-		_, _, err := platforms.GetAllPlatforms(ctx, zClient.Service)
-		if err != nil {
-			return lhsWarn(operand.ObjectType, "valid platform ID", operand.LHS, err)
-		}
-		if operand.RHS != "true" {
-			return rhsWarn(operand.ObjectType, "\"true\"", operand.RHS, nil)
-		}
-		return nil
-	case "SAML":
-		if operand.LHS == "" {
-			return lhsWarn(operand.ObjectType, "valid SAML Attribute ID", operand.LHS, nil)
-		}
-		_, _, err := samlattribute.Get(ctx, zClient.Service, operand.LHS)
-		if err != nil {
-			return lhsWarn(operand.ObjectType, "valid SAML Attribute ID", operand.LHS, err)
-		}
-		if operand.RHS == "" {
-			return rhsWarn(operand.ObjectType, "SAML Attribute Value", operand.RHS, nil)
-		}
-		return nil
-	case "SCIM":
-		// Validate that IDP ID is provided
-		if operand.IdpID == "" {
-			return fmt.Errorf("[WARN] when operand object type is %v Idp ID must be set", operand.ObjectType)
-		}
-		// Validate that LHS (SCIM Attribute ID) is provided
-		if operand.LHS == "" {
-			return lhsWarn(operand.ObjectType, "valid SCIM Attribute ID", operand.LHS, nil)
-		}
-		// Fetch the SCIM attribute header to verify it exists
-		scim, _, err := scimattributeheader.Get(ctx, zClient.Service, operand.IdpID, operand.LHS)
-		if err != nil {
-			return lhsWarn(operand.ObjectType, "valid SCIM Attribute ID", operand.LHS, err)
-		}
-		// Validate that RHS (SCIM Attribute Value) is provided
-		if operand.RHS == "" {
-			return rhsWarn(operand.ObjectType, "SCIM Attribute Value", operand.RHS, nil)
-		}
-		// Get all available values for the SCIM attribute to validate against
-		// This retrieves the complete list of values that users can reference by name
-		values, err := scimattributeheader.GetValues(ctx, zClient.Service, scim.IdpID, scim.ID)
-		if err != nil {
-			return rhsWarn(operand.ObjectType, "SCIM Attribute Value", operand.RHS, err)
-		}
-		// Case-insensitive match: SCIM attribute values (emails, names, etc.)
-		// are case-insensitive per RFC 7643, so "adam@example.com" should
-		// match "Adam@example.com" from the upstream IdP.
-		valueFound := false
-		for _, v := range values {
-			if strings.EqualFold(v, operand.RHS) {
-				valueFound = true
-				break
+		if t.lhs.validatedAgainstTenant() {
+			if err := t.lhs.exists(ctx, zClient, cache, scope, v, v.lhs); err != nil {
+				return lhsWarn(operand.ObjectType, t.lhs.description, operand.LHS, err)
 			}
 		}
-		if !valueFound {
-			return rhsWarn(operand.ObjectType, fmt.Sprintf("valid SCIM Attribute Value from the list: %v", values), operand.RHS, nil)
-		}
-		return nil
-	case "SCIM_GROUP":
-		if operand.LHS == "" {
-			return lhsWarn(operand.ObjectType, "valid IDP Controller ID", operand.LHS, nil)
-		}
-		_, _, err := idpcontroller.Get(ctx, zClient.Service, operand.LHS)
-		if err != nil {
-			return lhsWarn(operand.ObjectType, "valid IDP Controller ID", operand.LHS, err)
-		}
-		if operand.RHS == "" {
-			return rhsWarn(operand.ObjectType, "SCIM Group ID", operand.RHS, nil)
-		}
-
-		// Call the Get function with ScimGroup as the service parameter
-		_, _, err = scimgroup.Get(ctx, zClient.Service, operand.RHS)
-		if err != nil {
-			return rhsWarn(operand.ObjectType, "SCIM Group ID", operand.RHS, err)
-		}
-		return nil
-	case "COUNTRY_CODE":
-		if operand.LHS == "" || !isValidAlpha2(operand.LHS) {
-			return lhsWarn(operand.ObjectType, "valid ISO-3166 Alpha-2 country code. Please visit the following site for reference: https://en.wikipedia.org/wiki/List_of_ISO_3166_country_codes", operand.LHS, nil)
-		}
-		return nil
-	case "RISK_FACTOR_TYPE":
-		// Check if lhs is "ZIA"
-		if operand.LHS != "ZIA" {
-			return lhsWarn(operand.ObjectType, "\"ZIA\"", operand.LHS, nil)
-		}
-		// Validate rhs for RISK_FACTOR_TYPE
-		validRHSValues := []string{"UNKNOWN", "LOW", "MEDIUM", "HIGH", "CRITICAL"}
-		if !contains(validRHSValues, operand.RHS) {
-			return rhsWarn(operand.ObjectType, "\"UNKNOWN\", \"LOW\", \"MEDIUM\", \"HIGH\", \"CRITICAL\"", operand.RHS, nil)
-		}
-		return nil
-	case "CHROME_ENTERPRISE":
-		if operand.LHS == "" {
-			return lhsWarn(operand.ObjectType, "managed", operand.LHS, nil)
-		}
-		if operand.LHS != "managed" {
-			return lhsWarn(operand.ObjectType, "managed", operand.LHS, nil)
-		}
-		if operand.RHS == "" {
-			return rhsWarn(operand.ObjectType, "true/false", operand.RHS, nil)
-		}
-		if operand.RHS != "true" && operand.RHS != "false" {
-			return rhsWarn(operand.ObjectType, "true/false", operand.RHS, nil)
-		}
-		return nil
-	default:
-		return fmt.Errorf("[WARN] invalid operand object type %s", operand.ObjectType)
+	} else if operand.LHS != "id" {
+		return lhsWarn(operand.ObjectType, []string{"id"}, operand.LHS, nil)
 	}
-}
-
-type Getter func(id string) error
-
-func (g Getter) Get(id string) error {
-	return g(id)
-}
 
-func customValidate(operand policysetcontroller.Operands, expectedLHS []string, expectedRHS string, clientRHS Getter) error {
-	if operand.LHS == "" || !contains(expectedLHS, operand.LHS) {
-		return lhsWarn(operand.ObjectType, expectedLHS, operand.LHS, nil)
-	}
-	if operand.RHS == "" {
-		return rhsWarn(operand.ObjectType, expectedRHS, operand.RHS, nil)
+	if err := rhs.validate(v.rhs); err != nil {
+		return rhsWarn(operand.ObjectType, rhs.description, operand.RHS, err)
 	}
-	err := clientRHS.Get(operand.RHS)
-	if err != nil {
-		return rhsWarn(operand.ObjectType, expectedRHS, operand.RHS, err)
+	if rhs.validatedAgainstTenant() {
+		if err := rhs.exists(ctx, zClient, cache, scope, v, v.rhs); err != nil {
+			return rhsWarn(operand.ObjectType, rhs.description, operand.RHS, err)
+		}
 	}
 	return nil
 }
@@ -815,28 +649,33 @@ func GetGlobalPolicySetByPolicyType(ctx context.Context, zClient *Client, policy
 func ExpandPolicyConditionsV2(d *schema.ResourceData) ([]policysetcontrollerv2.PolicyRuleResourceConditions, error) {
 	conditionInterface, ok := d.GetOk("conditions")
 	if ok {
-		// Assert conditionInterface as a *schema.Set
-		conditionsSet := conditionInterface.(*schema.Set)
-		log.Printf("[INFO] conditions data: %+v\n", conditionsSet.List())
+		return expandPolicyConditionSetV2(conditionInterface.(*schema.Set))
+	}
+	return []policysetcontrollerv2.PolicyRuleResourceConditions{}, nil
+}
 
-		var conditionSets []policysetcontrollerv2.PolicyRuleResourceConditions
-		for _, condition := range conditionsSet.List() { // Use Set.List() to get []interface{}
-			conditionSet, _ := condition.(map[string]interface{})
-			if conditionSet != nil {
-				operands, err := expandOperandsListV2(conditionSet["operands"])
-				if err != nil {
-					return nil, err
-				}
-				conditionSets = append(conditionSets, policysetcontrollerv2.PolicyRuleResourceConditions{
-					ID:       conditionSet["id"].(string),
-					Operator: conditionSet["operator"].(string),
-					Operands: operands,
-				})
+// expandPolicyConditionSetV2 expands a v2 conditions set, either of a policy
+// rule resource or of a rule block of zpa_policy_set.
+func expandPolicyConditionSetV2(conditionsSet *schema.Set) ([]policysetcontrollerv2.PolicyRuleResourceConditions, error) {
+	log.Printf("[INFO] conditions data: %+v\n", conditionsSet.List())
+
+	var conditionSets []policysetcontrollerv2.PolicyRuleResourceConditions
+	for _, condition := range conditionsSet.List() { // Use Set.List() to get []interface{}
+		conditionSet, _ := condition.(map[string]interface{})
+		if conditionSet != nil {
+			operands, err := expandOperandsListV2(conditionSet["operands"])
+			if err != nil {
+				return nil, err
 			}
+			id, _ := conditionSet["id"].(string)
+			conditionSets = append(conditionSets, policysetcontrollerv2.PolicyRuleResourceConditions{
+				ID:       id,
+				Operator: conditionSet["operator"].(string),
+				Operands: operands,
+			})
 		}
-		return conditionSets, nil
 	}
-	return []policysetcontrollerv2.PolicyRuleResourceConditions{}, nil
+	return conditionSets, nil
 }
 
 func expandOperandsListV2(ops interface{}) ([]policysetcontrollerv2.PolicyRuleResourceOperands, error) {
@@ -950,10 +789,11 @@ func validateObjectTypeUniqueness(d *schema.ResourceData) error {
 	if !ok {
 		return nil
 	}
+	return validateConditionSetObjectTypeUniqueness(conditions.(*schema.Set))
+}
 
-	conditionsSet := conditions.(*schema.Set).List()
-
-	for _, condition := range conditionsSet {
+func validateConditionSetObjectTypeUniqueness(conditions *schema.Set) error {
+	for _, condition := range conditions.List() {
 		conditionMap := condition.(map[string]interface{})
 		if operands, ok := conditionMap["operands"].(*schema.Set); ok {
 			objectTypeSet := make(map[string]struct{})
@@ -982,27 +822,12 @@ func ValidatePolicyRuleConditions(d *schema.ResourceData) error {
 		return nil
 	}
 
-	validClientTypes := []string{
-		"zpn_client_type_exporter",
-		"zpn_client_type_exporter_noauth",
-		"zpn_client_type_machine_tunnel",
-		"zpn_client_type_edge_connector",
-		"zpn_client_type_zia_inspection",
-		"zpn_client_type_vdi",
-		"zpn_client_type_zapp",
-		"zpn_client_type_slogger",
-		"zpn_client_type_browser_isolation",
-		"zpn_client_type_ip_anchoring",
-		"zpn_client_type_zapp_partner",
-		"zpn_client_type_branch_connector",
-	}
-
-	validPlatformTypes := []string{"mac", "linux", "ios", "windows", "android"}
-
-	validRiskScore := []string{"UNKNOWN", "LOW", "MEDIUM", "HIGH", "CRITICAL"}
+	return validatePolicyConditionSet(conditions.(*schema.Set))
+}
 
-	// Adjust to handle *schema.Set instead of []interface{}
-	conditionsSet := conditions.(*schema.Set)
+// validatePolicyConditionSet checks the operands of a v2 conditions set
+// offline.
+func validatePolicyConditionSet(conditionsSet *schema.Set) error {
 	for _, condition := range conditionsSet.List() {
 		conditionMap := condition.(map[string]interface{})
 		operandsSet, ok := conditionMap["operands"].(*schema.Set)
@@ -1014,221 +839,35 @@ func ValidatePolicyRuleConditions(d *schema.ResourceData) error {
 		for _, operand := range operandsSet.List() {
 			operandMap := operand.(map[string]interface{})
 			objectType := operandMap["object_type"].(string)
-			valuesSet, valuesPresent := operandMap["values"].(*schema.Set)
+			t, ok := lookupPolicyOperandType(objectType)
+			if !ok {
+				// Unknown object types are rejected by the schema
+				continue
+			}
 
-			switch objectType {
-			case "APP":
-				if !valuesPresent || valuesSet.Len() == 0 {
-					return fmt.Errorf("an Application Segment ID must be provided when object_type = APP")
-				}
-			case "APP_GROUP":
-				if !valuesPresent || valuesSet.Len() == 0 {
-					return fmt.Errorf("a Segment Group ID must be provided when object_type = APP_GROUP")
-				}
-			case "MACHINE_GRP":
-				if !valuesPresent || valuesSet.Len() == 0 {
-					return fmt.Errorf("a Machine Group ID must be provided when object_type = MACHINE_GRP")
-				}
-			case "LOCATION":
-				if !valuesPresent || valuesSet.Len() == 0 {
-					return fmt.Errorf("a Location ID must be provided when object_type = LOCATION")
-				}
-			case "EDGE_CONNECTOR_GROUP":
-				if !valuesPresent || valuesSet.Len() == 0 {
-					return fmt.Errorf("a Edge Connector Group ID must be provided when object_type = EDGE_CONNECTOR_GROUP")
-				}
-			case "BRANCH_CONNECTOR_GROUP":
-				if !valuesPresent || valuesSet.Len() == 0 {
-					return fmt.Errorf("a Branch Connector Group ID must be provided when object_type = BRANCH_CONNECTOR_GROUP")
-				}
-			case "USER_PORTAL":
-				if !valuesPresent || valuesSet.Len() == 0 {
-					return fmt.Errorf("a User Portal ID must be provided when object_type = USER_PORTAL")
-				}
-			case "CLIENT_TYPE":
+			if t.valueKind != operandValueEntry {
+				valuesSet, valuesPresent := operandMap["values"].(*schema.Set)
 				if !valuesPresent || valuesSet.Len() == 0 {
-					return fmt.Errorf("please provide one of the valid Client Types: %v", validClientTypes)
+					return fmt.Errorf("%s must be provided in 'values' when object_type = %s", t.rhs.description, objectType)
 				}
 				for _, v := range valuesSet.List() {
-					value := v.(string)
-					if !contains(validClientTypes, value) {
-						return fmt.Errorf("invalid Client Type '%s'. Please provide one of the valid Client Types: %v", value, validClientTypes)
-					}
-				}
-			case "PLATFORM":
-				entryValuesSet, ok := operandMap["entry_values"].(*schema.Set)
-				if !ok || entryValuesSet.Len() == 0 {
-					return fmt.Errorf("please provide one of the valid platform types: %v", validPlatformTypes)
-				}
-				for _, ev := range entryValuesSet.List() {
-					evMap := ev.(map[string]interface{})
-					lhs, lhsOk := evMap["lhs"].(string)
-					// rhs, rhsOk := evMap["rhs"].(string)
-					if !lhsOk || !contains(validPlatformTypes, lhs) {
-						return fmt.Errorf("please provide one of the valid platform types: %v", validPlatformTypes)
-					}
-					// if !rhsOk || rhs != "true" {
-					// 	return fmt.Errorf("rhs value must be 'true' for PLATFORM object_type")
-					// }
-				}
-			case "RISK_FACTOR_TYPE":
-				entryValuesSet, ok := operandMap["entry_values"].(*schema.Set)
-				if !ok || entryValuesSet.Len() == 0 {
-					return fmt.Errorf("please provide valid risk factor values: %v", validRiskScore)
-				}
-				for _, ev := range entryValuesSet.List() {
-					evMap := ev.(map[string]interface{})
-					lhs, lhsOk := evMap["lhs"].(string)
-					rhs, rhsOk := evMap["rhs"].(string)
-
-					// Ensure lhs is "ZIA"
-					if !lhsOk || lhs != "ZIA" {
-						return fmt.Errorf("LHS must be 'ZIA' for RISK_FACTOR_TYPE")
-					}
-
-					// Ensure rhs is one of the valid risk scores
-					validRHSValues := []string{"UNKNOWN", "LOW", "MEDIUM", "HIGH", "CRITICAL"}
-					if !rhsOk || !contains(validRHSValues, rhs) {
-						return fmt.Errorf("RHS must be one of 'UNKNOWN', 'LOW', 'MEDIUM', 'HIGH', 'CRITICAL' for RISK_FACTOR_TYPE")
-					}
-				}
-			case "POSTURE":
-				entryValuesSet, ok := operandMap["entry_values"].(*schema.Set)
-				if !ok || entryValuesSet.Len() == 0 {
-					return fmt.Errorf("please provide a valid Posture UDID")
-				}
-				for _, ev := range entryValuesSet.List() {
-					evMap := ev.(map[string]interface{})
-					lhs, lhsOk := evMap["lhs"].(string)
-					rhs, rhsOk := evMap["rhs"].(string)
-					if !lhsOk || lhs == "" {
-						return fmt.Errorf("LHS must be a valid Posture UDID and cannot be empty for POSTURE object_type")
-					}
-					if !rhsOk || (rhs != "true" && rhs != "false") {
-						return fmt.Errorf("rhs value must be 'true' or 'false' for POSTURE object_type")
-					}
-				}
-			case "TRUSTED_NETWORK":
-				entryValuesSet, ok := operandMap["entry_values"].(*schema.Set)
-				if !ok || entryValuesSet.Len() == 0 {
-					return fmt.Errorf("please provide a valid Network ID")
-				}
-				for _, ev := range entryValuesSet.List() {
-					evMap := ev.(map[string]interface{})
-					lhs, lhsOk := evMap["lhs"].(string)
-					rhs, rhsOk := evMap["rhs"].(string)
-
-					if !lhsOk || lhs == "" {
-						return fmt.Errorf("LHS must be a valid Network ID and cannot be empty for TRUSTED_NETWORK object_type")
-					}
-					if !rhsOk || (rhs != "true" && rhs != "false") {
-						return fmt.Errorf("rhs value must be 'true' or 'false' for TRUSTED_NETWORK object_type")
-					}
-				}
-			case "COUNTRY_CODE":
-				entryValuesSet, ok := operandMap["entry_values"].(*schema.Set)
-				if !ok || entryValuesSet.Len() == 0 {
-					return fmt.Errorf("please provide a valid country code in 'entry_values'")
-				}
-
-				var invalidCodes []string
-				for _, ev := range entryValuesSet.List() {
-					evMap := ev.(map[string]interface{})
-					lhs, lhsOk := evMap["lhs"].(string)
-					rhs, rhsOk := evMap["rhs"].(string)
-
-					// Validate 'lhs' as a country code
-					if lhsOk {
-						_, errors := validateCountryCode(lhs, "lhs")
-						if len(errors) > 0 {
-							// Collect invalid country codes instead of returning immediately
-							invalidCodes = append(invalidCodes, lhs)
-						}
-					} else {
-						return fmt.Errorf("a valid ISO-3166 Alpha-2 country code must be provided in 'lhs'")
-					}
-
-					// Ensure 'rhs' is "true"
-					if !rhsOk || rhs != "true" {
-						return fmt.Errorf("rhs value must be 'true' for COUNTRY_CODE object_type")
-					}
-				}
-
-				// If there are any invalid country codes, return an aggregated error message
-				if len(invalidCodes) > 0 {
-					return fmt.Errorf("'%s' is not a valid ISO-3166 Alpha-2 country code. Please visit the following site for reference: https://en.wikipedia.org/wiki/List_of_ISO_3166_country_codes", strings.Join(invalidCodes, "', '"))
-				}
-			case "SAML":
-				entryValuesSet, ok := operandMap["entry_values"].(*schema.Set)
-				if !ok || entryValuesSet.Len() == 0 {
-					return fmt.Errorf("entry_values must be provided for SAML object_type")
-				}
-				for _, ev := range entryValuesSet.List() {
-					evMap := ev.(map[string]interface{})
-					lhs, lhsOk := evMap["lhs"].(string)
-					rhs := evMap["rhs"].(string) // Directly accessing the value, assuming zero value (empty string) is not valid
-
-					if !lhsOk || lhs == "" {
-						return fmt.Errorf("LHS must be a valid SAML attribute ID and cannot be empty for SAML object_type")
-					}
-					if rhs == "" {
-						return fmt.Errorf("RHS must be a valid string i.e email adddress, department, group etc and cannot be empty for SAML object_type")
-					}
-				}
-			case "SCIM":
-				entryValuesSet, ok := operandMap["entry_values"].(*schema.Set)
-				if !ok || entryValuesSet.Len() == 0 {
-					return fmt.Errorf("entry_values must be provided for SCIM object_type")
-				}
-				for _, ev := range entryValuesSet.List() {
-					evMap := ev.(map[string]interface{})
-					lhs, lhsOk := evMap["lhs"].(string)
-					rhs := evMap["rhs"].(string) // Directly accessing the value, assuming zero value (empty string) is not valid
-
-					if !lhsOk || lhs == "" {
-						return fmt.Errorf("LHS must be a valid IdP ID and cannot be empty for SCIM object_type")
-					}
-					if rhs == "" {
-						return fmt.Errorf("RHS must be a valid string i.e email adddress, department, group etc and cannot be empty for SCIM object_type")
-					}
-				}
-			case "SCIM_GROUP":
-				entryValuesSet, ok := operandMap["entry_values"].(*schema.Set)
-				if !ok || entryValuesSet.Len() == 0 {
-					return fmt.Errorf("entry_values must be provided for SCIM_GROUP object_type")
-				}
-				for _, ev := range entryValuesSet.List() {
-					evMap := ev.(map[string]interface{})
-					lhs, lhsOk := evMap["lhs"].(string)
-					rhs := evMap["rhs"].(string) // Directly accessing the value, assuming zero value (empty string) is not valid
-
-					if !lhsOk || lhs == "" {
-						return fmt.Errorf("LHS must be a valid IdP ID and cannot be empty for SCIM_GROUP object_type")
+					if err := t.validateValue(operandValue{rhs: v.(string)}); err != nil {
+						return fmt.Errorf("invalid operand with object_type = %s: %v", objectType, err)
 					}
-					if rhs == "" {
-						return fmt.Errorf("RHS must be a valid scim group ID and cannot be empty for SCIM_GROUP object_type")
-					}
-				}
-			case "CHROME_POSTURE_PROFILE":
-				if !valuesPresent || valuesSet.Len() == 0 {
-					return fmt.Errorf("a Chrome Posture Profile ID must be provided when object_type = CHROME_POSTURE_PROFILE")
-				}
-			case "CHROME_ENTERPRISE":
-				entryValuesSet, ok := operandMap["entry_values"].(*schema.Set)
-				if !ok || entryValuesSet.Len() == 0 {
-					return fmt.Errorf("entry_values must be provided for CHROME_ENTERPRISE object_type")
 				}
-				for _, ev := range entryValuesSet.List() {
-					evMap := ev.(map[string]interface{})
-					lhs, lhsOk := evMap["lhs"].(string)
-					rhs, rhsOk := evMap["rhs"].(string)
+				continue
+			}
 
-					if !lhsOk || lhs != "managed" {
-						return fmt.Errorf("LHS must be 'managed' for CHROME_ENTERPRISE object_type")
-					}
-					if !rhsOk || (rhs != "true" && rhs != "false") {
-						return fmt.Errorf("rhs value must be 'true' or 'false' for CHROME_ENTERPRISE object_type")
-					}
+			entryValuesSet, ok := operandMap["entry_values"].(*schema.Set)
+			if !ok || entryValuesSet.Len() == 0 {
+				return fmt.Errorf("entry_values must be provided for %s object_type", objectType)
+			}
+			for _, ev := range entryValuesSet.List() {
+				evMap := ev.(map[string]interface{})
+				lhs, _ := evMap["lhs"].(string)
+				rhs, _ := evMap["rhs"].(string)
+				if err := t.validateValue(operandValue{lhs: lhs, rhs: rhs}); err != nil {
+					return fmt.Errorf("invalid entry_values for %s object_type: %v", objectType, err)
 				}
 			}
 		}
@@ -1236,12 +875,17 @@ func ValidatePolicyRuleConditions(d *schema.ResourceData) error {
 	return nil
 }
 
-func fetchPolicySetIDByType(ctx context.Context, zClient *Client, policyType string, microTenantID string) (string, error) {
-	// Create cache key including microtenant ID for multi-tenant scenarios
-	cacheKey := policyType
+// policySetCacheKey identifies a policy set by type, including the
+// microtenant ID for multi-tenant scenarios.
+func policySetCacheKey(policyType, microTenantID string) string {
 	if microTenantID != "" {
-		cacheKey = policyType + ":" + microTenantID
+		return policyType + ":" + microTenantID
 	}
+	return policyType
+}
+
+func fetchPolicySetIDByType(ctx context.Context, zClient *Client, policyType string, microTenantID string) (string, error) {
+	cacheKey := policySetCacheKey(policyType, microTenantID)
 
 	// First check: read lock (fast path for cached values)
 	zClient.mu.RLock()
@@ -1716,6 +1360,25 @@ func flattenServiceEdgeGroupSimple(serviceEdgeGroups []serviceedgegroup.ServiceE
 	return results
 }
 
+// flattenServiceEdgeGroupIDs flattens service edge groups into a single
+// service_edge_groups block, as expected by expandCommonServiceEdgeGroups.
+func flattenServiceEdgeGroupIDs(serviceEdgeGroups []serviceedgegroup.ServiceEdgeGroup) []interface{} {
+	ids := make([]interface{}, 0, len(serviceEdgeGroups))
+	for _, group := range serviceEdgeGroups {
+		if group.ID != "" {
+			ids = append(ids, group.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return []interface{}{
+		map[string]interface{}{
+			"id": schema.NewSet(schema.HashString, ids),
+		},
+	}
+}
+
 func flattenCommonAppServerGroups(serverGroups []servergroup.ServerGroup) []interface{} {
 	result := make([]interface{}, 1)
 	mapIds := make(map[string]interface{})
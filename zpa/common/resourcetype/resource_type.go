@@ -22,6 +22,8 @@ const (
 	ZPAPolicyAccessRuleV2              = "zpa_policy_access_rule_v2"
 	ZPAPolicyTimeOutRule               = "zpa_policy_timeout_rule"
 	ZPAPolicyTimeOutRuleV2             = "zpa_policy_timeout_rule_v2"
+	ZPAPolicySet                       = "zpa_policy_set"
+	ZPAPolicyRuleJSON                  = "zpa_policy_rule_json"
 	ZPAPolicyForwardingRule            = "zpa_policy_forwarding_rule"
 	ZPAPolicyForwardingRuleV2          = "zpa_policy_forwarding_rule_v2"
 	ZPAPolicyIsolationRuleV2           = "zpa_policy_isolation_rule_v2"
@@ -29,6 +31,7 @@ const (
 	ZPAPolicyInspectionRule            = "zpa_policy_inspection_rule"
 	ZPAPolicyIsolationRule             = "zpa_policy_isolation_rule"
 	ZPAPolicyRedirectionRule           = "zpa_policy_redirection_rule"
+	ZPAPolicyRedirectionRuleV2         = "zpa_policy_redirection_rule_v2"
 	ZPAPolicyCredentialRule            = "zpa_policy_credential_rule"
 	ZPAPolicyCapabilitiesRule          = "zpa_policy_capabilities_rule"
 	ZPAPRAConsoleController            = "zpa_pra_console_controller"
@@ -40,6 +43,7 @@ const (
 	ZPACustomerVersionProfile          = "zpa_customer_version_profile"
 	ZPAEnrollmentCertificate           = "zpa_enrollment_cert"
 	ZPALSSController                   = "zpa_lss_config_controller"
+	ZPALSSReceiver                     = "zpa_lss_receiver"
 	ZPAInspectionCustomControl         = "zpa_inspection_custom_controls"
 	ZPAInspectionProfile               = "zpa_inspection_profile"
 	ZPAMicrotenant                     = "zpa_microtenant_controller"
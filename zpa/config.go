@@ -3,10 +3,9 @@ package zpa
 import (
 	"fmt"
 	"log"
-	"net/url"
+	"net/http"
 	"os"
 	"runtime"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -30,6 +29,13 @@ type (
 		cloud              string
 		privateKey         string
 		httpProxy          string
+		httpsProxy         string
+		apiEndpoint        string
+		authEndpoint       string
+		noProxy            string
+		caBundleFile       string
+		clientCertFile     string
+		clientKeyFile      string
 		retryCount         int
 		parallelism        int
 		backoff            bool
@@ -38,11 +44,26 @@ type (
 		logLevel           int
 		requestTimeout     int
 		useLegacyClient    bool
+		readPrefetch       bool
+		getRateLimit       int
+		writeRateLimit     int
+		rateLimitInterval  int
+		tokenCache         bool
+		tokenCacheDir      string
+		skipReferenceCheck bool
+		pruneDangling      bool
+		expiredRuleAction  string
+		countryAliases     map[string][]string
 		zscalerSDKClientV3 *zscaler.Client
 		logger             hclog.Logger
 		TerraformVersion   string // New field for Terraform version
 		ProviderVersion    string // New field for Provider version
 
+		// Shared HTTP client, built once by httpClient()
+		httpClientOnce   sync.Once
+		sharedHTTPClient *http.Client
+		httpClientErr    error
+
 		// Options for Legacy V2 SDK
 		zpaClientID     string
 		zpaClientSecret string
@@ -53,8 +74,26 @@ type (
 
 type Client struct {
 	Service          *zscaler.Service
-	policySetIDCache map[string]string // Cache for policySetIDs by type
-	mu               sync.RWMutex      // Mutex for cache access
+	policySetIDCache map[string]string     // Cache for policySetIDs by type
+	mu               sync.RWMutex          // Mutex for cache access
+	prefetch         *readPrefetchCache    // Read prefetch cache, nil unless read_prefetch is enabled
+	referenceCache   *referenceLookupCache // Plan-time policy reference lookups, nil when disabled
+	// Rule creation queues by policy set, keyed like policySetIDCache
+	ruleCreationQueues map[string]*policyRuleCreationQueue
+	// Locks of the reorders of each policy set, keyed like policySetIDCache
+	ruleOrderLocks    map[string]*sync.Mutex
+	expiredRuleAction string // expired_policy_rule_action, "disable" or "delete"
+	// prune_dangling_references, only effective when referenceCache is set
+	pruneDanglingReferences bool
+	// Built-in and configured country aliases, by name without the @ prefix
+	countryAliases map[string][]string
+	// LSS status codes and client types, fetched on first use
+	lssCatalogOnce sync.Once
+	lssCatalog     *lssCatalog
+	// Relative placements planned through the client
+	rulePlacements *policyRulePlacementGraph
+	// Privileged approval windows planned through the client
+	praApprovalPlans *praApprovalPlans
 }
 
 func (c *Client) GetConfig() *zscaler.Configuration {
@@ -64,13 +103,14 @@ func (c *Client) GetConfig() *zscaler.Configuration {
 func NewConfig(d *schema.ResourceData) *Config {
 	// defaults
 	config := Config{
-		backoff:        true,
-		minWait:        2,
-		maxWait:        10,
-		retryCount:     100,
-		parallelism:    1,
-		logLevel:       int(hclog.Error),
-		requestTimeout: 240,
+		backoff:           true,
+		minWait:           2,
+		maxWait:           10,
+		retryCount:        100,
+		parallelism:       1,
+		logLevel:          int(hclog.Error),
+		requestTimeout:    240,
+		rateLimitInterval: 10,
 	}
 	logLevel := hclog.Level(config.logLevel)
 	if os.Getenv("TF_LOG") != "" {
@@ -157,6 +197,20 @@ func NewConfig(d *schema.ResourceData) *Config {
 		config.BaseURL = os.Getenv("ZPA_CLOUD")
 	}
 
+	if val, ok := d.GetOk("api_endpoint"); ok {
+		config.apiEndpoint = val.(string)
+	}
+	if config.apiEndpoint == "" && os.Getenv("ZSCALER_API_ENDPOINT") != "" {
+		config.apiEndpoint = os.Getenv("ZSCALER_API_ENDPOINT")
+	}
+
+	if val, ok := d.GetOk("auth_endpoint"); ok {
+		config.authEndpoint = val.(string)
+	}
+	if config.authEndpoint == "" && os.Getenv("ZSCALER_AUTH_ENDPOINT") != "" {
+		config.authEndpoint = os.Getenv("ZSCALER_AUTH_ENDPOINT")
+	}
+
 	if val, ok := d.GetOk("microtenant_id"); ok {
 		config.microtenantID = val.(string)
 	}
@@ -192,6 +246,63 @@ func NewConfig(d *schema.ResourceData) *Config {
 		config.requestTimeout = val.(int)
 	}
 
+	if val, ok := d.GetOk("get_rate_limit"); ok {
+		config.getRateLimit = val.(int)
+	}
+
+	if val, ok := d.GetOk("write_rate_limit"); ok {
+		config.writeRateLimit = val.(int)
+	}
+
+	if val, ok := d.GetOk("rate_limit_interval_seconds"); ok {
+		config.rateLimitInterval = val.(int)
+	}
+
+	if val, ok := d.GetOk("read_prefetch"); ok {
+		config.readPrefetch = val.(bool)
+	} else if os.Getenv("ZSCALER_READ_PREFETCH") != "" {
+		config.readPrefetch = strings.ToLower(os.Getenv("ZSCALER_READ_PREFETCH")) == "true"
+	}
+
+	if val, ok := d.GetOk("token_cache"); ok {
+		config.tokenCache = val.(bool)
+	} else if os.Getenv("ZSCALER_TOKEN_CACHE") != "" {
+		config.tokenCache = strings.ToLower(os.Getenv("ZSCALER_TOKEN_CACHE")) == "true"
+	}
+
+	if val, ok := d.GetOk("token_cache_dir"); ok {
+		config.tokenCacheDir = val.(string)
+	}
+	if config.tokenCacheDir == "" && os.Getenv("ZSCALER_TOKEN_CACHE_DIR") != "" {
+		config.tokenCacheDir = os.Getenv("ZSCALER_TOKEN_CACHE_DIR")
+	}
+
+	if val, ok := d.GetOk("skip_policy_reference_validation"); ok {
+		config.skipReferenceCheck = val.(bool)
+	} else if os.Getenv("ZSCALER_SKIP_POLICY_REFERENCE_VALIDATION") != "" {
+		config.skipReferenceCheck = strings.ToLower(os.Getenv("ZSCALER_SKIP_POLICY_REFERENCE_VALIDATION")) == "true"
+	}
+
+	if val, ok := d.GetOk("prune_dangling_references"); ok {
+		config.pruneDangling = val.(bool)
+	} else if os.Getenv("ZSCALER_PRUNE_DANGLING_REFERENCES") != "" {
+		config.pruneDangling = strings.ToLower(os.Getenv("ZSCALER_PRUNE_DANGLING_REFERENCES")) == "true"
+	}
+
+	if val, ok := d.GetOk("expired_policy_rule_action"); ok {
+		config.expiredRuleAction = val.(string)
+	} else if os.Getenv("ZSCALER_EXPIRED_POLICY_RULE_ACTION") != "" {
+		config.expiredRuleAction = strings.ToLower(os.Getenv("ZSCALER_EXPIRED_POLICY_RULE_ACTION"))
+	}
+
+	if aliases, ok := d.Get("country_alias").(*schema.Set); ok && aliases.Len() > 0 {
+		config.countryAliases = make(map[string][]string, aliases.Len())
+		for _, alias := range aliases.List() {
+			aliasMap := alias.(map[string]interface{})
+			config.countryAliases[aliasMap["name"].(string)] = SetToStringSlice(aliasMap["countries"].(*schema.Set))
+		}
+	}
+
 	if httpProxy, ok := d.Get("http_proxy").(string); ok {
 		config.httpProxy = httpProxy
 	}
@@ -199,6 +310,41 @@ func NewConfig(d *schema.ResourceData) *Config {
 		config.httpProxy = os.Getenv("ZSCALER_HTTP_PROXY")
 	}
 
+	if val, ok := d.GetOk("https_proxy"); ok {
+		config.httpsProxy = val.(string)
+	}
+	if config.httpsProxy == "" && os.Getenv("ZSCALER_HTTPS_PROXY") != "" {
+		config.httpsProxy = os.Getenv("ZSCALER_HTTPS_PROXY")
+	}
+
+	if val, ok := d.GetOk("no_proxy"); ok {
+		config.noProxy = val.(string)
+	}
+	if config.noProxy == "" && os.Getenv("ZSCALER_NO_PROXY") != "" {
+		config.noProxy = os.Getenv("ZSCALER_NO_PROXY")
+	}
+
+	if val, ok := d.GetOk("ca_bundle_file"); ok {
+		config.caBundleFile = val.(string)
+	}
+	if config.caBundleFile == "" && os.Getenv("ZSCALER_CA_BUNDLE_FILE") != "" {
+		config.caBundleFile = os.Getenv("ZSCALER_CA_BUNDLE_FILE")
+	}
+
+	if val, ok := d.GetOk("client_cert_file"); ok {
+		config.clientCertFile = val.(string)
+	}
+	if config.clientCertFile == "" && os.Getenv("ZSCALER_CLIENT_CERT_FILE") != "" {
+		config.clientCertFile = os.Getenv("ZSCALER_CLIENT_CERT_FILE")
+	}
+
+	if val, ok := d.GetOk("client_key_file"); ok {
+		config.clientKeyFile = val.(string)
+	}
+	if config.clientKeyFile == "" && os.Getenv("ZSCALER_CLIENT_KEY_FILE") != "" {
+		config.clientKeyFile = os.Getenv("ZSCALER_CLIENT_KEY_FILE")
+	}
+
 	return &config
 }
 
@@ -273,32 +419,14 @@ func zscalerSDKV2Client(c *Config) (*zscaler.Service, error) {
 		setters = append(setters, zpa.WithZPAMicrotenantID(c.microtenantID))
 	}
 
-	// Configure HTTP proxy if provided
-	if c.httpProxy != "" {
-		_url, err := url.Parse(c.httpProxy)
+	// Proxy, TLS, endpoint, rate limit and token cache settings are carried
+	// by the HTTP client. Without them the SDK keeps its own client.
+	if c.usesHTTPClient() {
+		httpClient, err := c.httpClient()
 		if err != nil {
-			return nil, fmt.Errorf("invalid proxy URL: %v", err)
-		}
-		setters = append(setters, zpa.WithProxyHost(_url.Hostname()))
-
-		// Default to port 80 if not provided
-		sPort := _url.Port()
-		if sPort == "" {
-			sPort = "80"
-		}
-		// Parse the port as a 32-bit integer
-		port64, err := strconv.ParseInt(sPort, 10, 32)
-		if err != nil {
-			return nil, fmt.Errorf("invalid proxy port: %v", err)
-		}
-
-		// Optionally, you can also check the port range if needed
-		if port64 < 1 || port64 > 65535 {
-			return nil, fmt.Errorf("invalid port number: must be between 1 and 65535, got: %d", port64)
+			return nil, err
 		}
-		// Safe cast to int32
-		port32 := int32(port64)
-		setters = append(setters, zpa.WithProxyPort(port32))
+		setters = append(setters, zpa.WithHttpClientPtr(httpClient))
 	}
 
 	// Initialize ZPA configuration
@@ -339,31 +467,14 @@ func zscalerSDKV3Client(c *Config) (*zscaler.Client, error) {
 		log.Println("[DEBUG] SDK debug logging enabled")
 	}
 
-	// Configure HTTP proxy if provided
-	if c.httpProxy != "" {
-		_url, err := url.Parse(c.httpProxy)
+	// Proxy, TLS, endpoint, rate limit and token cache settings are carried
+	// by the HTTP client. Without them the SDK keeps its own client.
+	if c.usesHTTPClient() {
+		httpClient, err := c.httpClient()
 		if err != nil {
 			return nil, err
 		}
-		setters = append(setters, zscaler.WithProxyHost(_url.Hostname()))
-
-		sPort := _url.Port()
-		if sPort == "" {
-			sPort = "80"
-		}
-		// Parse the port as a 32-bit integer
-		port64, err := strconv.ParseInt(sPort, 10, 32)
-		if err != nil {
-			return nil, fmt.Errorf("invalid proxy port: %v", err)
-		}
-
-		// Optionally, you can also check the port range if needed
-		if port64 < 1 || port64 > 65535 {
-			return nil, fmt.Errorf("invalid port number: must be between 1 and 65535, got: %d", port64)
-		}
-		// Safe cast to int32
-		port32 := int32(port64)
-		setters = append(setters, zscaler.WithProxyPort(port32))
+		setters = append(setters, zscaler.WithHttpClientPtr(httpClient))
 	}
 
 	// Main switch to handle the different authentication methods
@@ -408,6 +519,7 @@ func zscalerSDKV3Client(c *Config) (*zscaler.Client, error) {
 	default:
 		return nil, fmt.Errorf("invalid authentication configuration: missing required parameters")
 	}
+	c.logEffectiveEndpoints()
 
 	// Create the Zscaler configuration with the assembled setters
 	config, err := zscaler.NewConfiguration(setters...)
@@ -428,25 +540,48 @@ func zscalerSDKV3Client(c *Config) (*zscaler.Client, error) {
 
 // Client instantiates the provider client with necessary configurations.
 func (c *Config) Client() (*Client, error) {
+	switch c.expiredRuleAction {
+	case "", expiredPolicyRuleDisable, expiredPolicyRuleDelete:
+	default:
+		return nil, fmt.Errorf("invalid expired_policy_rule_action %q, expected %q or %q", c.expiredRuleAction, expiredPolicyRuleDisable, expiredPolicyRuleDelete)
+	}
+	countryAliases, err := mergeCountryAliases(c.countryAliases)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("[INFO] Using country alias dataset version %s\n", builtinCountryAliases.Version)
+
+	var service *zscaler.Service
 	if c.useLegacyClient {
 		wrappedV2Client, err := zscalerSDKV2Client(c)
 		if err != nil {
 			return nil, fmt.Errorf("failed to initialize legacy v2 client: %w", err)
 		}
-
-		return &Client{
-			Service:          zscaler.NewService(wrappedV2Client.Client, nil),
-			policySetIDCache: make(map[string]string),
-		}, nil
+		service = zscaler.NewService(wrappedV2Client.Client, nil)
+	} else {
+		// Fallback to v3 client initialization
+		v3Client, err := zscalerSDKV3Client(c)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize v3 client: %w", err)
+		}
+		service = zscaler.NewService(v3Client, nil)
 	}
 
-	// Fallback to v3 client initialization
-	v3Client, err := zscalerSDKV3Client(c)
-	if err != nil {
-		return nil, fmt.Errorf("failed to initialize v3 client: %w", err)
+	client := &Client{
+		Service:           service,
+		policySetIDCache:  make(map[string]string),
+		expiredRuleAction: c.expiredRuleAction,
+		countryAliases:    countryAliases,
+	}
+	if c.readPrefetch {
+		log.Println("[INFO] Read prefetch cache enabled")
+		client.prefetch = newReadPrefetchCache()
+	}
+	if !c.skipReferenceCheck {
+		client.referenceCache = newReferenceLookupCache()
+		client.pruneDanglingReferences = c.pruneDangling
+	} else if c.pruneDangling {
+		log.Println("[WARN] prune_dangling_references has no effect when skip_policy_reference_validation is set")
 	}
-	return &Client{
-		Service:          zscaler.NewService(v3Client, nil),
-		policySetIDCache: make(map[string]string),
-	}, nil
+	return client, nil
 }
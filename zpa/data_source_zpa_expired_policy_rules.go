@@ -0,0 +1,121 @@
+package zpa
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontrollerv2"
+)
+
+func dataSourceExpiredPolicyRules() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceExpiredPolicyRulesRead,
+		Schema: map[string]*schema.Schema{
+			"policy_types": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "The policy types to search. Defaults to all the policy types whose rules support expires_at.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice(expiringPolicyTypes, false),
+				},
+			},
+			"microtenant_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"rules": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The rules whose expires_at has passed, whether or not they have been disabled yet.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"policy_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"expires_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"disabled": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"modified_time": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceExpiredPolicyRulesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zClient := meta.(*Client)
+	service := zClient.Service
+
+	microTenantID := GetString(d.Get("microtenant_id"))
+	if microTenantID != "" {
+		service = service.WithMicroTenant(microTenantID)
+	}
+
+	policyTypes := expiringPolicyTypes
+	if _, ok := d.GetOk("policy_types"); ok {
+		policyTypes = SetToStringList(d, "policy_types")
+		sort.Strings(policyTypes)
+	}
+
+	now := time.Now()
+	var rules []interface{}
+	for _, policyType := range policyTypes {
+		log.Printf("[INFO] Getting expired %s rules\n", policyType)
+		resp, _, err := policysetcontrollerv2.GetAllByType(ctx, service, policyType)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("failed to get %s rules: %v", policyType, err))
+		}
+		for _, rule := range resp {
+			_, expiresAt := splitPolicyRuleExpiry(rule.Description)
+			if !policyRuleExpiryPassed(expiresAt, now) {
+				continue
+			}
+			modifiedTime := rule.ModifiedTime
+			if modifiedTime != "" {
+				if t, err := epochToRFC1123(modifiedTime, false); err == nil {
+					modifiedTime = t
+				}
+			}
+			rules = append(rules, map[string]interface{}{
+				"id":            rule.ID,
+				"name":          rule.Name,
+				"policy_type":   policyType,
+				"expires_at":    expiresAt,
+				"disabled":      rule.Disabled == "1",
+				"modified_time": modifiedTime,
+			})
+		}
+	}
+
+	d.SetId(strings.Join(policyTypes, ","))
+	if err := d.Set("rules", rules); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
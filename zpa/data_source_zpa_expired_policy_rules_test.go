@@ -0,0 +1,32 @@
+package zpa
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceExpiredPolicyRules_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDataSourceExpiredPolicyRules_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.zpa_expired_policy_rules.all", "id", "ACCESS_POLICY,CLIENT_FORWARDING_POLICY"),
+					resource.TestCheckResourceAttrSet("data.zpa_expired_policy_rules.all", "rules.#"),
+					resource.TestCheckResourceAttr("data.zpa_expired_policy_rules.access", "id", "ACCESS_POLICY"),
+					resource.TestCheckResourceAttrSet("data.zpa_expired_policy_rules.access", "rules.#"),
+				),
+			},
+		},
+	})
+}
+
+var testAccCheckDataSourceExpiredPolicyRules_basic = `
+data "zpa_expired_policy_rules" "all" {}
+
+data "zpa_expired_policy_rules" "access" {
+  policy_types = ["ACCESS_POLICY"]
+}`
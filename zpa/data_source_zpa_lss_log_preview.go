@@ -0,0 +1,243 @@
+package zpa
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// lssPreviewDirective matches the placeholders of LSS formats, capturing the
+// conversion, the variable and its modifier, as in %j{LogTimestamp:time}.
+var lssPreviewDirective = regexp.MustCompile(`%([a-z])\{([A-Za-z0-9_.]+)(?::([^}]*))?\}`)
+
+// lssPreviewEpoch is the time of the first sample record. Samples do not use
+// the current time so that the records are the same on every plan.
+var lssPreviewEpoch = time.Date(2024, time.January, 15, 10, 30, 0, 0, time.UTC)
+
+// lssPreviewSamples are the sample values of the variables of the published
+// templates. Other variables get a value derived from their name.
+var lssPreviewSamples = map[string]string{
+	"Customer":             "Acme Corp",
+	"Username":             "jdoe@acme.com",
+	"SessionID":            "8rT4mQ2vLx9bKc7WzN1p",
+	"ConnectionID":         "AAcXJeQJhcDr9cu2UkEN,8rT4mQ2vLx9bKc7WzN1p",
+	"ConnectionStatus":     "close",
+	"IPProtocol":           "6",
+	"DoubleEncryption":     "Off",
+	"Host":                 "intranet.acme.com",
+	"Application":          "Intranet",
+	"AppGroup":             "Corporate Applications",
+	"AppLearnTime":         "0",
+	"Server":               "0",
+	"ServerIP":             "10.10.1.25",
+	"ServerPort":           "443",
+	"Policy":               "Allow Engineering",
+	"ClientPublicIP":       "203.0.113.24",
+	"ClientPrivateIP":      "192.168.1.45",
+	"ClientLatitude":       "40.7128",
+	"ClientLongitude":      "-74.0060",
+	"ClientCountryCode":    "US",
+	"ClientZEN":            "US-NY-8179",
+	"ClientType":           "zpn_client_type_zapp",
+	"Connector":            "connector-nyc-01",
+	"ConnectorGroup":       "NYC Connectors",
+	"ConnectorZEN":         "US-NY-8180",
+	"ConnectorIP":          "10.10.1.5",
+	"ConnectorPort":        "20043",
+	"Platform":             "windows",
+	"Version":              "4.3.0.188",
+	"Hostname":             "LAPTOP-JDOE",
+	"Status":               "ZPN_STATUS_AUTHENTICATED",
+	"Exporter":             "unset",
+	"Idp":                  "Okta",
+	"TotalBytesRx":         "125433",
+	"TotalBytesTx":         "2341",
+	"ZENtoClientBytes":     "125433",
+	"ClientToZENBytes":     "2341",
+	"PolicyProcessingTime": "42",
+	"ServerSetupTime":      "120",
+	"ModifiedBy":           "admin@acme.com",
+	"AuditOperationType":   "Update",
+	"ObjectType":           "Application Segment",
+	"ObjectName":           "Intranet",
+	"ObjectID":             "72058304855015574",
+	"AuditOldValue":        "{}",
+	"AuditNewValue":        "{}",
+	"ClientIPAddress":      "203.0.113.24",
+	"SAMLAttributes":       "{\"department\":\"Engineering\"}",
+}
+
+func dataSourceLSSLogPreview() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceLSSLogPreviewRead,
+		Schema: map[string]*schema.Schema{
+			"source_log_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(lssSourceLogTypes, false),
+			},
+			"format": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The format to render, as set in the config of zpa_lss_config_controller.",
+			},
+			"sample_count": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				ValidateFunc: validation.IntBetween(1, 10),
+				Description:  "Number of sample records to render.",
+			},
+			"strict": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Fail instead of warning when the format uses unknown variables or renders invalid JSON.",
+			},
+			"records": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The sample records, as the receiver would get them.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"unknown_variables": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The variables of the format that are not part of the templates of the log type.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"json_errors": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Why the sample records are not valid JSON, for formats that start with {.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceLSSLogPreviewRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zClient := meta.(*Client)
+	service := zClient.Service
+
+	sourceLogType := d.Get("source_log_type").(string)
+	format := d.Get("format").(string)
+	log.Printf("[INFO] Rendering LSS log preview of %s\n", sourceLogType)
+
+	t, err := fetchLSSFormatTemplate(ctx, service, sourceLogType)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var records []string
+	unknown := map[string]bool{}
+	var jsonErrors []string
+	isJSON := strings.HasPrefix(strings.TrimSpace(format), "{")
+	for i := 0; i < d.Get("sample_count").(int); i++ {
+		record := renderLSSLogRecord(format, t.variables, i, unknown)
+		records = append(records, record)
+		if !isJSON {
+			continue
+		}
+		var v interface{}
+		if err := json.Unmarshal([]byte(strings.TrimSpace(record)), &v); err != nil {
+			jsonErrors = append(jsonErrors, fmt.Sprintf("record %d: %v", i+1, err))
+		}
+	}
+	unknownVariables := make([]string, 0, len(unknown))
+	for name := range unknown {
+		unknownVariables = append(unknownVariables, name)
+	}
+	sort.Strings(unknownVariables)
+
+	d.SetId("lss_log_preview_" + sourceLogType)
+	_ = d.Set("records", records)
+	_ = d.Set("unknown_variables", unknownVariables)
+	_ = d.Set("json_errors", jsonErrors)
+
+	var problems []string
+	if len(unknownVariables) > 0 {
+		problems = append(problems, fmt.Sprintf("The format uses variables that are not part of the templates of %s: %s. They are rendered as empty values.", sourceLogType, strings.Join(unknownVariables, ", ")))
+	}
+	if len(jsonErrors) > 0 {
+		problems = append(problems, "The format does not render valid JSON:\n  - "+strings.Join(jsonErrors, "\n  - "))
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	severity := diag.Warning
+	if d.Get("strict").(bool) {
+		severity = diag.Error
+	}
+	return diag.Diagnostics{{
+		Severity: severity,
+		Summary:  "LSS format preview found problems",
+		Detail:   strings.Join(problems, "\n\n"),
+	}}
+}
+
+// renderLSSLogRecord renders the index-th sample record of format. Variables
+// missing from known are rendered empty and added to unknown.
+func renderLSSLogRecord(format string, known map[string]bool, index int, unknown map[string]bool) string {
+	return lssPreviewDirective.ReplaceAllStringFunc(format, func(directive string) string {
+		m := lssPreviewDirective.FindStringSubmatch(directive)
+		conversion, name, modifier := m[1], m[2], m[3]
+		value := ""
+		if known[name] {
+			value = sampleLSSValue(name, modifier, index)
+		} else {
+			unknown[name] = true
+		}
+		switch conversion {
+		case "d":
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return "0"
+			}
+			return value
+		case "j":
+			b, _ := json.Marshal(value)
+			return string(b)
+		default:
+			return value
+		}
+	})
+}
+
+// sampleLSSValue returns the value of a variable in the index-th sample
+// record.
+func sampleLSSValue(name, modifier string, index int) string {
+	at := lssPreviewEpoch.Add(time.Duration(index) * time.Minute)
+	switch modifier {
+	case "time":
+		return at.Format(time.ANSIC)
+	case "iso8601":
+		return at.Format("2006-01-02T15:04:05.000Z")
+	case "epoch":
+		return strconv.FormatInt(at.Unix(), 10)
+	}
+	if value, ok := lssPreviewSamples[name]; ok {
+		return value
+	}
+	switch {
+	case strings.Contains(name, "Timestamp"):
+		return at.Format(time.RFC3339)
+	case strings.HasSuffix(name, "IP"):
+		return fmt.Sprintf("10.0.%d.%d", index/250, index%250+10)
+	case strings.HasSuffix(name, "Port"):
+		return "443"
+	case strings.Contains(name, "Bytes"), strings.Contains(name, "Count"), strings.Contains(name, "Time"):
+		return strconv.Itoa(100 + index)
+	case strings.HasSuffix(name, "ID"):
+		return strconv.FormatInt(72058304855015574+int64(index), 10)
+	}
+	return fmt.Sprintf("sample-%s-%d", name, index+1)
+}
@@ -0,0 +1,53 @@
+package zpa
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceLSSLogPreview_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDataSourceLSSLogPreview_basic(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.zpa_lss_log_preview.template", "records.#", "2"),
+					resource.TestCheckResourceAttr("data.zpa_lss_log_preview.template", "unknown_variables.#", "0"),
+					resource.TestCheckResourceAttr("data.zpa_lss_log_preview.template", "json_errors.#", "0"),
+				),
+			},
+			{
+				Config:      testAccCheckDataSourceLSSLogPreview_invalid(),
+				ExpectError: regexp.MustCompile("NotAVariable"),
+			},
+		},
+	})
+}
+
+func testAccCheckDataSourceLSSLogPreview_basic() string {
+	return `
+data "zpa_lss_config_log_type_formats" "zpn_trans_log" {
+  log_type = "zpn_trans_log"
+}
+
+data "zpa_lss_log_preview" "template" {
+  source_log_type = "zpn_trans_log"
+  format          = data.zpa_lss_config_log_type_formats.zpn_trans_log.json
+  sample_count    = 2
+}
+`
+}
+
+func testAccCheckDataSourceLSSLogPreview_invalid() string {
+	return `
+data "zpa_lss_log_preview" "invalid" {
+  source_log_type = "zpn_trans_log"
+  format          = "{\"Customer\": %j{Customer},\"Oops\": %j{NotAVariable}\n"
+  strict          = true
+}
+`
+}
@@ -0,0 +1,283 @@
+package zpa
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/applicationsegment"
+)
+
+func dataSourcePolicyEvaluation() *schema.Resource {
+	attributeBlock := func(description string) *schema.Schema {
+		return &schema.Schema{
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Description: description,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"id": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"value": {
+						Type:     schema.TypeString,
+						Required: true,
+					},
+				},
+			},
+		}
+	}
+
+	return &schema.Resource{
+		ReadContext: dataSourcePolicyEvaluationRead,
+		Schema: map[string]*schema.Schema{
+			"policy_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(conditionPolicyTypes, false),
+			},
+			"microtenant_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"application_segment_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The application segment being accessed. When segment_group_id is not set, the segment group of the application segment is used.",
+			},
+			"segment_group_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"idp_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"client_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(mustPolicyOperandType("CLIENT_TYPE").rhs.allowed, false),
+			},
+			"platform": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(mustPolicyOperandType("PLATFORM").lhs.allowed, false),
+			},
+			"country_code": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateCountryCode,
+			},
+			"risk_level": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(mustPolicyOperandType("RISK_FACTOR_TYPE").rhs.allowed, false),
+			},
+			"saml_attribute": attributeBlock("SAML attributes of the user. id is the SAML attribute ID. Repeat the block for multi-valued attributes."),
+			"scim_attribute": attributeBlock("SCIM attributes of the user. id is the SCIM attribute header ID. Repeat the block for multi-valued attributes."),
+			"scim_group_ids": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"posture": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Posture profile results. Posture profiles that are not listed are treated as failed.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"udid": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"result": {
+							Type:     schema.TypeBool,
+							Required: true,
+						},
+					},
+				},
+			},
+			"trusted_network_ids": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Network IDs of the trusted networks the client is on.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"operand": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Operands of any other object type that hold for the client, for example a machine group.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"object_type": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"lhs": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "id",
+						},
+						"rhs": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+					},
+				},
+			},
+			"matched": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"rule_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"rule_name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"action": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"trace": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"rule_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"rule_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"rule_order": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"matched": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"reason": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func mustPolicyOperandType(objectType string) *policyOperandType {
+	t, ok := lookupPolicyOperandType(objectType)
+	if !ok {
+		panic(fmt.Sprintf("operand type %s is not in the operand registry", objectType))
+	}
+	return t
+}
+
+func dataSourcePolicyEvaluationRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zClient := meta.(*Client)
+	service := zClient.Service
+
+	microTenantID := GetString(d.Get("microtenant_id"))
+	if microTenantID != "" {
+		service = service.WithMicroTenant(microTenantID)
+	}
+
+	clientContext := expandPolicyClientContext(d)
+	if clientContext.applicationSegmentID != "" && clientContext.segmentGroupID == "" {
+		segment, _, err := applicationsegment.Get(ctx, service, clientContext.applicationSegmentID)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("failed to get application segment %s: %v", clientContext.applicationSegmentID, err))
+		}
+		clientContext.segmentGroupID = segment.SegmentGroupID
+	}
+
+	policyType := d.Get("policy_type").(string)
+	log.Printf("[INFO] Evaluating %s rules locally", policyType)
+	rules, err := fetchOrderedPolicyRules(ctx, service, policyType)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to get %s rules: %v", policyType, err))
+	}
+
+	var trace []interface{}
+	var match *policyRuleExpr
+	for i := range rules {
+		matched, reason := rules[i].evaluate(clientContext)
+		trace = append(trace, map[string]interface{}{
+			"rule_id":    rules[i].id,
+			"rule_name":  rules[i].name,
+			"rule_order": rules[i].order,
+			"matched":    matched,
+			"reason":     reason,
+		})
+		if matched {
+			match = &rules[i]
+			break
+		}
+	}
+
+	_ = d.Set("trace", trace)
+	if match == nil {
+		d.SetId(policyType + ":none")
+		_ = d.Set("matched", false)
+		_ = d.Set("rule_id", "")
+		_ = d.Set("rule_name", "")
+		_ = d.Set("action", "")
+		return nil
+	}
+	d.SetId(policyType + ":" + match.id)
+	_ = d.Set("matched", true)
+	_ = d.Set("rule_id", match.id)
+	_ = d.Set("rule_name", match.name)
+	_ = d.Set("action", match.action)
+	return nil
+}
+
+func expandPolicyClientContext(d *schema.ResourceData) *policyClientContext {
+	c := &policyClientContext{
+		applicationSegmentID: d.Get("application_segment_id").(string),
+		segmentGroupID:       d.Get("segment_group_id").(string),
+		idpID:                d.Get("idp_id").(string),
+		clientType:           d.Get("client_type").(string),
+		platform:             d.Get("platform").(string),
+		countryCode:          d.Get("country_code").(string),
+		riskLevel:            d.Get("risk_level").(string),
+		samlAttributes:       expandPolicyEvaluationAttributes(d.Get("saml_attribute")),
+		scimAttributes:       expandPolicyEvaluationAttributes(d.Get("scim_attribute")),
+		scimGroupIDs:         SetToStringList(d, "scim_group_ids"),
+		trustedNetworkIDs:    SetToStringList(d, "trusted_network_ids"),
+		postureResults:       map[string]bool{},
+		otherOperands:        map[string]bool{},
+	}
+	for _, p := range d.Get("posture").(*schema.Set).List() {
+		posture := p.(map[string]interface{})
+		c.postureResults[posture["udid"].(string)] = posture["result"].(bool)
+	}
+	for _, o := range d.Get("operand").(*schema.Set).List() {
+		operand := o.(map[string]interface{})
+		atom := policyAtom{objectType: operand["object_type"].(string), lhs: operand["lhs"].(string), rhs: operand["rhs"].(string)}
+		c.otherOperands[atom.String()] = true
+	}
+	return c
+}
+
+func expandPolicyEvaluationAttributes(v interface{}) map[string][]string {
+	attributes := map[string][]string{}
+	for _, a := range v.(*schema.Set).List() {
+		attribute := a.(map[string]interface{})
+		id := attribute["id"].(string)
+		attributes[id] = append(attributes[id], attribute["value"].(string))
+	}
+	return attributes
+}
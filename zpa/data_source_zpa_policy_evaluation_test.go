@@ -0,0 +1,256 @@
+package zpa
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/zscaler/terraform-provider-zpa/v4/zpa/common/resourcetype"
+	"github.com/zscaler/terraform-provider-zpa/v4/zpa/common/testing/method"
+	"github.com/zscaler/terraform-provider-zpa/v4/zpa/common/testing/variable"
+)
+
+func TestAccDataSourcePolicyEvaluation_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDataSourcePolicyEvaluation_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.zpa_policy_evaluation.this", "matched"),
+					resource.TestCheckResourceAttrSet("data.zpa_policy_evaluation.this", "trace.#"),
+					resource.TestCheckResourceAttr("data.zpa_policy_evaluation.redirection", "policy_type", "REDIRECTION_POLICY"),
+				),
+			},
+		},
+	})
+}
+
+// The rule created for the segment group is the one that matches a client
+// accessing it.
+func TestAccDataSourcePolicyEvaluation_MatchesRule(t *testing.T) {
+	segmentGroupTypeAndName, _, segmentGroupGeneratedName := method.GenerateRandomSourcesTypeAndName(resourcetype.ZPASegmentGroup)
+	segmentGroupHCL := testAccCheckSegmentGroupConfigure(segmentGroupTypeAndName, "tf-acc-test-"+segmentGroupGeneratedName, variable.SegmentGroupDescription, variable.SegmentGroupEnabled)
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccCheckPolicyAccessRuleV2Destroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDataSourcePolicyEvaluationRuleConfigure(segmentGroupHCL, segmentGroupTypeAndName, rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.zpa_policy_evaluation.this", "matched", "true"),
+					resource.TestCheckResourceAttrPair("data.zpa_policy_evaluation.this", "rule_id", resourcetype.ZPAPolicyAccessRuleV2+".this", "id"),
+					resource.TestCheckResourceAttr("data.zpa_policy_evaluation.this", "rule_name", rName),
+					resource.TestCheckResourceAttr("data.zpa_policy_evaluation.this", "action", "DENY"),
+				),
+			},
+		},
+	})
+}
+
+func TestPolicyClientContext_Matches(t *testing.T) {
+	c := &policyClientContext{
+		applicationSegmentID: "1",
+		segmentGroupID:       "2",
+		idpID:                "3",
+		clientType:           "zpn_client_type_zapp",
+		platform:             "windows",
+		countryCode:          "US",
+		riskLevel:            "LOW",
+		samlAttributes:       map[string][]string{"10": {"jdoe@example.com", "jdoe@example.org"}},
+		scimAttributes:       map[string][]string{"20": {"Engineering"}},
+		scimGroupIDs:         []string{"30"},
+		postureResults:       map[string]bool{"posture-pass": true, "posture-fail": false},
+		trustedNetworkIDs:    []string{"network-1"},
+		otherOperands:        map[string]bool{"MACHINE_GRP=40": true},
+	}
+	for _, tc := range []struct {
+		atom policyAtom
+		want bool
+	}{
+		{policyAtom{objectType: "APP", lhs: "id", rhs: "1"}, true},
+		{policyAtom{objectType: "APP", lhs: "id", rhs: "2"}, false},
+		{policyAtom{objectType: "APP_GROUP", lhs: "id", rhs: "2"}, true},
+		{policyAtom{objectType: "IDP", lhs: "id", rhs: "4"}, false},
+		{policyAtom{objectType: "CLIENT_TYPE", lhs: "id", rhs: "zpn_client_type_zapp"}, true},
+		{policyAtom{objectType: "CLIENT_TYPE", lhs: "id", rhs: "zpn_client_type_exporter"}, false},
+		{policyAtom{objectType: "PLATFORM", lhs: "Windows", rhs: "true"}, true},
+		{policyAtom{objectType: "PLATFORM", lhs: "mac", rhs: "true"}, false},
+		{policyAtom{objectType: "COUNTRY_CODE", lhs: "us", rhs: "true"}, true},
+		{policyAtom{objectType: "RISK_FACTOR_TYPE", lhs: "ZIA", rhs: "low"}, true},
+		{policyAtom{objectType: "RISK_FACTOR_TYPE", lhs: "ZIA", rhs: "HIGH"}, false},
+		// SAML values are compared exactly, SCIM values ignoring case
+		{policyAtom{objectType: "SAML", lhs: "10", rhs: "jdoe@example.org"}, true},
+		{policyAtom{objectType: "SAML", lhs: "10", rhs: "JDoe@example.org"}, false},
+		{policyAtom{objectType: "SAML", lhs: "11", rhs: "jdoe@example.org"}, false},
+		{policyAtom{objectType: "SCIM", lhs: "20", rhs: "engineering"}, true},
+		{policyAtom{objectType: "SCIM", lhs: "20", rhs: "ENGINEERING"}, true},
+		{policyAtom{objectType: "SCIM", lhs: "21", rhs: "Engineering"}, false},
+		{policyAtom{objectType: "SCIM_GROUP", lhs: "3", rhs: "30"}, true},
+		{policyAtom{objectType: "SCIM_GROUP", lhs: "3", rhs: "31"}, false},
+		// Posture profiles that are not listed are failed
+		{policyAtom{objectType: "POSTURE", lhs: "posture-pass", rhs: "true"}, true},
+		{policyAtom{objectType: "POSTURE", lhs: "posture-pass", rhs: "false"}, false},
+		{policyAtom{objectType: "POSTURE", lhs: "posture-fail", rhs: "false"}, true},
+		{policyAtom{objectType: "POSTURE", lhs: "posture-unknown", rhs: "false"}, true},
+		{policyAtom{objectType: "POSTURE", lhs: "posture-unknown", rhs: "true"}, false},
+		{policyAtom{objectType: "TRUSTED_NETWORK", lhs: "network-1", rhs: "true"}, true},
+		{policyAtom{objectType: "TRUSTED_NETWORK", lhs: "network-1", rhs: "false"}, false},
+		{policyAtom{objectType: "TRUSTED_NETWORK", lhs: "network-2", rhs: "false"}, true},
+		{policyAtom{objectType: "TRUSTED_NETWORK", lhs: "network-2", rhs: "true"}, false},
+		{policyAtom{objectType: "MACHINE_GRP", lhs: "id", rhs: "40"}, true},
+		{policyAtom{objectType: "MACHINE_GRP", lhs: "id", rhs: "41"}, false},
+	} {
+		if got := c.matches(tc.atom); got != tc.want {
+			t.Errorf("matches(%s) = %t, want %t", tc.atom, got, tc.want)
+		}
+	}
+}
+
+func TestPolicyRuleExpr_Evaluate(t *testing.T) {
+	c := &policyClientContext{
+		applicationSegmentID: "1",
+		clientType:           "zpn_client_type_zapp",
+		platform:             "windows",
+	}
+	app := func(id string) policyAtom { return policyAtom{objectType: "APP", lhs: "id", rhs: id} }
+	platform := func(p string) policyAtom { return policyAtom{objectType: "PLATFORM", lhs: p, rhs: "true"} }
+	zapp := policyAtom{objectType: "CLIENT_TYPE", lhs: "id", rhs: "zpn_client_type_zapp"}
+
+	for _, tc := range []struct {
+		name        string
+		rule        policyRuleExpr
+		wantMatched bool
+		wantReason  string
+	}{
+		{
+			name:        "disabled",
+			rule:        policyRuleExpr{disabled: true, conditions: []policyConditionExpr{{operator: "OR", atoms: []policyAtom{app("1")}}}},
+			wantMatched: false,
+			wantReason:  "rule is disabled",
+		},
+		{
+			name:        "no conditions",
+			rule:        policyRuleExpr{},
+			wantMatched: true,
+			wantReason:  "rule has no conditions",
+		},
+		{
+			name: "rule operator defaults to AND",
+			rule: policyRuleExpr{conditions: []policyConditionExpr{
+				{operator: "OR", atoms: []policyAtom{app("1")}},
+				{operator: "OR", atoms: []policyAtom{zapp}},
+			}},
+			wantMatched: true,
+			wantReason:  "all conditions matched",
+		},
+		{
+			name: "AND rule with a failed condition",
+			rule: policyRuleExpr{operator: "AND", conditions: []policyConditionExpr{
+				{operator: "OR", atoms: []policyAtom{app("1")}},
+				{operator: "OR", atoms: []policyAtom{app("2"), app("3")}},
+			}},
+			wantMatched: false,
+			wantReason:  "condition 2: none of APP=2, APP=3 matched",
+		},
+		{
+			name: "OR rule with a matched condition",
+			rule: policyRuleExpr{operator: "OR", conditions: []policyConditionExpr{
+				{operator: "OR", atoms: []policyAtom{app("2")}},
+				{operator: "OR", atoms: []policyAtom{app("1")}},
+			}},
+			wantMatched: true,
+			wantReason:  "at least one condition matched",
+		},
+		{
+			name: "OR rule without a matched condition",
+			rule: policyRuleExpr{operator: "OR", conditions: []policyConditionExpr{
+				{operator: "OR", atoms: []policyAtom{app("2")}},
+				{operator: "AND", atoms: []policyAtom{zapp, platform("mac")}},
+			}},
+			wantMatched: false,
+			wantReason:  "condition 1: none of APP=2 matched; condition 2: AND condition not satisfied by PLATFORM[mac]=true",
+		},
+		{
+			name: "AND condition",
+			rule: policyRuleExpr{conditions: []policyConditionExpr{
+				{operator: "AND", atoms: []policyAtom{zapp, platform("windows")}},
+			}},
+			wantMatched: true,
+			wantReason:  "all conditions matched",
+		},
+		{
+			name: "OR condition with a matched atom",
+			rule: policyRuleExpr{conditions: []policyConditionExpr{
+				{operator: "OR", atoms: []policyAtom{platform("mac"), platform("windows")}},
+			}},
+			wantMatched: true,
+			wantReason:  "all conditions matched",
+		},
+		{
+			name: "empty condition",
+			rule: policyRuleExpr{conditions: []policyConditionExpr{
+				{operator: "OR"},
+			}},
+			wantMatched: true,
+			wantReason:  "all conditions matched",
+		},
+	} {
+		matched, reason := tc.rule.evaluate(c)
+		if matched != tc.wantMatched || reason != tc.wantReason {
+			t.Errorf("%s: evaluate() = %t, %q, want %t, %q", tc.name, matched, reason, tc.wantMatched, tc.wantReason)
+		}
+	}
+}
+
+var testAccCheckDataSourcePolicyEvaluation_basic = `
+data "zpa_policy_evaluation" "this" {
+  policy_type = "ACCESS_POLICY"
+  client_type = "zpn_client_type_zapp"
+  platform    = "windows"
+  risk_level  = "LOW"
+}
+
+data "zpa_policy_evaluation" "redirection" {
+  policy_type = "REDIRECTION_POLICY"
+  client_type = "zpn_client_type_edge_connector"
+}`
+
+func testAccCheckDataSourcePolicyEvaluationRuleConfigure(segmentGroupHCL, segmentGroupTypeAndName, rName string) string {
+	return fmt.Sprintf(`
+%[1]s
+
+resource "%[3]s" "this" {
+  name     = "%[4]s"
+  action   = "DENY"
+  operator = "AND"
+  conditions {
+    operator = "OR"
+    operands {
+      object_type = "APP_GROUP"
+      values      = [%[2]s.id]
+    }
+  }
+  conditions {
+    operator = "OR"
+    operands {
+      object_type = "CLIENT_TYPE"
+      values      = ["zpn_client_type_exporter"]
+    }
+  }
+}
+
+data "zpa_policy_evaluation" "this" {
+  policy_type      = "ACCESS_POLICY"
+  segment_group_id = %[2]s.id
+  client_type      = "zpn_client_type_exporter"
+
+  depends_on = [%[3]s.this]
+}
+`, segmentGroupHCL, segmentGroupTypeAndName, resourcetype.ZPAPolicyAccessRuleV2, rName)
+}
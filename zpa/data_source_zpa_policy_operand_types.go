@@ -0,0 +1,146 @@
+package zpa
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var conditionPolicyTypes = []string{
+	policyTypeAccess,
+	policyTypeTimeout,
+	policyTypeForwarding,
+	policyTypeInspection,
+	policyTypeIsolation,
+	policyTypeRedirection,
+	policyTypeSessionProtection,
+}
+
+func dataSourcePolicyOperandTypes() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourcePolicyOperandTypesRead,
+		Schema: map[string]*schema.Schema{
+			"policy_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "Only return the operand types accepted by conditions of this policy type.",
+				ValidateFunc: validation.StringInSlice(conditionPolicyTypes, false),
+			},
+			"operand_types": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"object_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"value_kind": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "One of `id`, `enum` or `entry`.",
+						},
+						"value_attribute": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Attribute of v2 policy rule operands holding the values: `values` or `entry_values`.",
+						},
+						"lhs_description": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"lhs_allowed_values": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"rhs_description": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"rhs_allowed_values": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"validated_against_tenant": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the referenced objects are looked up in the tenant during validation.",
+						},
+						"supports_name_references": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether values can reference objects by name with the `name:` prefix.",
+						},
+						"microtenant_scoped": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"policy_types": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourcePolicyOperandTypesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	policyType := d.Get("policy_type").(string)
+
+	var operandTypes []interface{}
+	for _, t := range policyOperandRegistry {
+		if policyType != "" && !t.allowedIn(policyType) {
+			continue
+		}
+		operandTypes = append(operandTypes, flattenPolicyOperandType(t))
+	}
+
+	if policyType != "" {
+		d.SetId(policyType)
+	} else {
+		d.SetId("all")
+	}
+	if err := d.Set("operand_types", operandTypes); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func flattenPolicyOperandType(t *policyOperandType) map[string]interface{} {
+	valueAttribute := "values"
+	lhsDescription := "\"id\" (v1 policy rules only)"
+	lhsAllowed := []string{"id"}
+	if t.valueKind == operandValueEntry {
+		valueAttribute = "entry_values"
+		lhsDescription = t.lhs.description
+		lhsAllowed = t.lhs.allowed
+	}
+
+	var policyTypes []string
+	for _, policyType := range conditionPolicyTypes {
+		if t.allowedIn(policyType) {
+			policyTypes = append(policyTypes, policyType)
+		}
+	}
+
+	return map[string]interface{}{
+		"object_type":              t.objectType,
+		"value_kind":               t.valueKind,
+		"value_attribute":          valueAttribute,
+		"lhs_description":          lhsDescription,
+		"lhs_allowed_values":       lhsAllowed,
+		"rhs_description":          t.rhs.description,
+		"rhs_allowed_values":       t.rhs.allowed,
+		"validated_against_tenant": t.lhs.validatedAgainstTenant() || t.rhs.validatedAgainstTenant(),
+		"supports_name_references": t.lhs.resolve != nil || t.rhs.resolve != nil,
+		"microtenant_scoped":       t.microtenantScoped,
+		"policy_types":             policyTypes,
+	}
+}
@@ -0,0 +1,43 @@
+package zpa
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourcePolicyOperandTypes_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDataSourcePolicyOperandTypes_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.zpa_policy_operand_types.all", "operand_types.#"),
+					resource.TestCheckResourceAttr("data.zpa_policy_operand_types.redirection", "operand_types.#", "1"),
+					resource.TestCheckResourceAttr("data.zpa_policy_operand_types.redirection", "operand_types.0.object_type", "CLIENT_TYPE"),
+					resource.TestCheckResourceAttr("data.zpa_policy_operand_types.redirection", "operand_types.0.value_attribute", "values"),
+					resource.TestCheckTypeSetElemNestedAttrs("data.zpa_policy_operand_types.access", "operand_types.*", map[string]string{
+						"object_type":              "SCIM_GROUP",
+						"value_kind":               "entry",
+						"value_attribute":          "entry_values",
+						"validated_against_tenant": "true",
+						"supports_name_references": "true",
+					}),
+				),
+			},
+		},
+	})
+}
+
+var testAccCheckDataSourcePolicyOperandTypes_basic = `
+data "zpa_policy_operand_types" "all" {}
+
+data "zpa_policy_operand_types" "access" {
+  policy_type = "ACCESS_POLICY"
+}
+
+data "zpa_policy_operand_types" "redirection" {
+  policy_type = "REDIRECTION_POLICY"
+}`
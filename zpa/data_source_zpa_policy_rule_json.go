@@ -0,0 +1,100 @@
+package zpa
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontrollerv2"
+)
+
+func dataSourcePolicyRuleJSON() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourcePolicyRuleJSONRead,
+		Schema: map[string]*schema.Schema{
+			"policy_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(conditionPolicyTypes, false),
+			},
+			"id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "name"},
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"microtenant_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"policy_set_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"rule_json": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The rule as JSON, without the attributes set by the API, in the format accepted by the rule_json attribute of zpa_policy_rule_json.",
+			},
+		},
+	}
+}
+
+func dataSourcePolicyRuleJSONRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zClient := meta.(*Client)
+	service := zClient.Service
+
+	microTenantID := GetString(d.Get("microtenant_id"))
+	if microTenantID != "" {
+		service = service.WithMicroTenant(microTenantID)
+	}
+
+	policyType := d.Get("policy_type").(string)
+	policySetID, err := fetchPolicySetIDByType(ctx, zClient, policyType, microTenantID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var rule *policysetcontrollerv2.PolicyRuleResource
+	if id, ok := d.Get("id").(string); ok && id != "" {
+		log.Printf("[INFO] Getting %s rule by id: %s\n", policyType, id)
+		rule, _, err = policysetcontrollerv2.GetPolicyRule(ctx, service, policySetID, id)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("couldn't find any %s rule with id '%s': %v", policyType, id, err))
+		}
+	} else {
+		name := d.Get("name").(string)
+		log.Printf("[INFO] Getting %s rule by name: %s\n", policyType, name)
+		rules, _, err := policysetcontrollerv2.GetAllByType(ctx, service, policyType)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("failed to get %s rules: %v", policyType, err))
+		}
+		for i := range rules {
+			if rules[i].Name == name {
+				rule = &rules[i]
+				break
+			}
+		}
+		if rule == nil {
+			return diag.FromErr(fmt.Errorf("couldn't find any %s rule with name '%s'", policyType, name))
+		}
+	}
+
+	ruleJSON, err := exportPolicyRuleJSON(*rule)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.SetId(rule.ID)
+	_ = d.Set("name", rule.Name)
+	_ = d.Set("policy_set_id", policySetID)
+	_ = d.Set("rule_json", ruleJSON)
+	return nil
+}
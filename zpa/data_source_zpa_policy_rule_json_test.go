@@ -0,0 +1,48 @@
+package zpa
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/zscaler/terraform-provider-zpa/v4/zpa/common/resourcetype"
+)
+
+func TestAccDataSourcePolicyRuleJSON_ByID(t *testing.T) {
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDataSourcePolicyRuleJSONByID(rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.zpa_policy_rule_json.test", "name", rName),
+					resource.TestCheckResourceAttrSet("data.zpa_policy_rule_json.test", "policy_set_id"),
+					resource.TestCheckResourceAttrSet("data.zpa_policy_rule_json.test", "rule_json"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckDataSourcePolicyRuleJSONByID(rName string) string {
+	return fmt.Sprintf(`
+resource "%[1]s" "test" {
+  policy_type = "TIMEOUT_POLICY"
+  rule_json = jsonencode({
+    name              = "%[2]s"
+    action            = "RE_AUTH"
+    reauthTimeout     = "172800"
+    reauthIdleTimeout = "600"
+  })
+}
+
+data "zpa_policy_rule_json" "test" {
+  policy_type = "TIMEOUT_POLICY"
+  id          = %[1]s.test.id
+}
+`, resourcetype.ZPAPolicyRuleJSON, rName)
+}
@@ -0,0 +1,191 @@
+package zpa
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontrollerv2"
+)
+
+func dataSourcePolicyRuleLint() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourcePolicyRuleLintRead,
+		Schema: map[string]*schema.Schema{
+			"policy_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(conditionPolicyTypes, false),
+			},
+			"microtenant_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"check_references": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Look up the objects referenced by the rules in the tenant to find rules that can never match.",
+			},
+			"rule_count": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"findings": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"kind": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "One of `shadowed`, `duplicate` or `unreachable`.",
+						},
+						"rule_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"rule_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"rule_order": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"related_rule_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The earlier rule that shadows or duplicates the rule.",
+						},
+						"related_rule_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"message": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourcePolicyRuleLintRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zClient := meta.(*Client)
+	service := zClient.Service
+
+	microTenantID := GetString(d.Get("microtenant_id"))
+	if microTenantID != "" {
+		service = service.WithMicroTenant(microTenantID)
+	}
+
+	policyType := d.Get("policy_type").(string)
+	log.Printf("[INFO] Linting %s rules", policyType)
+	resp, _, err := policysetcontrollerv2.GetAllByType(ctx, service, policyType)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("failed to get %s rules: %v", policyType, err))
+	}
+	rules := make([]lintPolicyRule, 0, len(resp))
+	for _, rule := range resp {
+		rules = append(rules, newLintPolicyRule(rule))
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].order < rules[j].order
+	})
+
+	var dead func(policyAtom) bool
+	if d.Get("check_references").(bool) {
+		dead = deadPolicyAtoms(ctx, zClient, rules, microTenantID)
+	}
+
+	var findings []interface{}
+	for _, f := range lintPolicyRules(rules, dead) {
+		finding := map[string]interface{}{
+			"kind":       f.kind,
+			"rule_id":    f.rule.id,
+			"rule_name":  f.rule.name,
+			"rule_order": f.rule.order,
+			"message":    f.message,
+		}
+		if f.relatedRule != nil {
+			finding["related_rule_id"] = f.relatedRule.id
+			finding["related_rule_name"] = f.relatedRule.name
+		}
+		findings = append(findings, finding)
+	}
+
+	d.SetId(policyType)
+	_ = d.Set("rule_count", len(rules))
+	if err := d.Set("findings", findings); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+// deadPolicyAtoms looks up every object referenced by the enabled rules and
+// returns a predicate reporting the atoms that reference a missing object.
+// Lookups that fail for other reasons leave the atom alive.
+func deadPolicyAtoms(ctx context.Context, zClient *Client, rules []lintPolicyRule, microTenantID string) func(policyAtom) bool {
+	cache := zClient.referenceCache
+	if cache == nil {
+		cache = newReferenceLookupCache()
+	}
+
+	seen := map[string]bool{}
+	var refs []policyReference
+	atomRefs := map[string][]string{}
+	for _, rule := range rules {
+		if rule.disabled {
+			continue
+		}
+		for _, condition := range rule.conditions {
+			for _, atom := range condition.atoms {
+				t, ok := lookupPolicyOperandType(atom.objectType)
+				if !ok {
+					continue
+				}
+				key := atom.String()
+				if _, done := atomRefs[key]; done {
+					continue
+				}
+				atomRefs[key] = nil
+				for _, ref := range operandValueReferences(t, operandValue{lhs: atom.lhs, rhs: atom.rhs}, microTenantID) {
+					atomRefs[key] = append(atomRefs[key], ref.key)
+					if !seen[ref.key] {
+						seen[ref.key] = true
+						refs = append(refs, ref)
+					}
+				}
+			}
+		}
+	}
+	log.Printf("[DEBUG] Looking up %d objects referenced by policy rules", len(refs))
+
+	missing := map[string]bool{}
+	for i, err := range resolvePolicyReferences(ctx, zClient, cache, refs) {
+		if err == nil {
+			continue
+		}
+		if !isPolicyReferenceNotFound(err) {
+			log.Printf("[WARN] Could not look up %s: %v", refs[i].describe(), err)
+			continue
+		}
+		missing[refs[i].key] = true
+	}
+
+	return func(a policyAtom) bool {
+		for _, key := range atomRefs[a.String()] {
+			if missing[key] {
+				return true
+			}
+		}
+		return false
+	}
+}
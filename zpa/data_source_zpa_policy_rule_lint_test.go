@@ -0,0 +1,261 @@
+package zpa
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourcePolicyRuleLint_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccPreCheck(t) },
+		ProviderFactories: testAccProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckDataSourcePolicyRuleLint_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.zpa_policy_rule_lint.access", "rule_count"),
+					resource.TestCheckResourceAttrSet("data.zpa_policy_rule_lint.access", "findings.#"),
+					resource.TestCheckResourceAttr("data.zpa_policy_rule_lint.timeout", "check_references", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestPolicyRuleExpr_Normalize(t *testing.T) {
+	for _, tc := range []struct {
+		name           string
+		rule           policyRuleExpr
+		want           []string
+		wantNormalized bool
+	}{
+		{
+			name: "AND rule",
+			rule: policyRuleExpr{conditions: []policyConditionExpr{
+				lintCondition("OR", "APP=1", "APP=2"),
+				lintCondition("AND", "CLIENT_TYPE=zpn_client_type_zapp", "PLATFORM[windows]=true"),
+				lintCondition("OR"),
+			}},
+			want:           []string{"APP=1 | APP=2", "CLIENT_TYPE=zpn_client_type_zapp", "PLATFORM[windows]=true"},
+			wantNormalized: true,
+		},
+		{
+			name: "OR rule with one condition",
+			rule: policyRuleExpr{operator: "OR", conditions: []policyConditionExpr{
+				lintCondition("AND", "APP=1", "CLIENT_TYPE=zpn_client_type_zapp"),
+			}},
+			want:           []string{"APP=1", "CLIENT_TYPE=zpn_client_type_zapp"},
+			wantNormalized: true,
+		},
+		{
+			name: "OR rule",
+			rule: policyRuleExpr{operator: "OR", conditions: []policyConditionExpr{
+				lintCondition("OR", "APP=1"),
+				lintCondition("AND", "APP=2"),
+				lintCondition("OR", "APP_GROUP=3", "APP=1"),
+			}},
+			want:           []string{"APP=1 | APP=2 | APP_GROUP=3"},
+			wantNormalized: true,
+		},
+		{
+			name: "OR rule with an empty condition matches everything",
+			rule: policyRuleExpr{operator: "OR", conditions: []policyConditionExpr{
+				lintCondition("OR", "APP=1"),
+				lintCondition("OR"),
+			}},
+			want:           nil,
+			wantNormalized: true,
+		},
+		{
+			name: "OR rule of AND conditions",
+			rule: policyRuleExpr{operator: "OR", conditions: []policyConditionExpr{
+				lintCondition("AND", "APP=1", "CLIENT_TYPE=zpn_client_type_zapp"),
+				lintCondition("OR", "APP=2"),
+			}},
+			want:           nil,
+			wantNormalized: false,
+		},
+	} {
+		clauses, normalized := tc.rule.normalize()
+		if got := policyClauseStrings(clauses); !reflect.DeepEqual(got, tc.want) || normalized != tc.wantNormalized {
+			t.Errorf("%s: normalize() = %q, %t, want %q, %t", tc.name, got, normalized, tc.want, tc.wantNormalized)
+		}
+	}
+}
+
+func TestPolicyClausesImply(t *testing.T) {
+	clauses := func(clauses ...[]string) []policyClause {
+		var result []policyClause
+		for _, atoms := range clauses {
+			result = append(result, lintCondition("OR", atoms...).clause())
+		}
+		return result
+	}
+	for _, tc := range []struct {
+		name string
+		a, b []policyClause
+		want bool
+	}{
+		{"narrower", clauses([]string{"APP=1"}, []string{"CLIENT_TYPE=zpn_client_type_zapp"}), clauses([]string{"APP=1", "APP=2"}), true},
+		{"broader", clauses([]string{"APP=1", "APP=2"}), clauses([]string{"APP=1"}), false},
+		{"same", clauses([]string{"APP=1", "APP=2"}), clauses([]string{"APP=2", "APP=1"}), true},
+		{"disjoint", clauses([]string{"APP=1"}), clauses([]string{"APP=2"}), false},
+		{"b matches everything", clauses([]string{"APP=1"}), nil, true},
+		{"a matches everything", nil, clauses([]string{"APP=1"}), false},
+	} {
+		if got := policyClausesImply(tc.a, tc.b); got != tc.want {
+			t.Errorf("%s: policyClausesImply() = %t, want %t", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestLintPolicyRules(t *testing.T) {
+	dead := func(a policyAtom) bool { return a.String() == "APP=9" }
+	for _, tc := range []struct {
+		name  string
+		rules []lintPolicyRule
+		want  []string
+	}{
+		{
+			name: "shadowed",
+			rules: []lintPolicyRule{
+				lintRule("broad", "ALLOW", "AND", lintCondition("OR", "APP=1", "APP=2")),
+				lintRule("narrow", "DENY", "AND", lintCondition("OR", "APP=1"), lintCondition("OR", "CLIENT_TYPE=zpn_client_type_zapp")),
+				lintRule("other", "DENY", "AND", lintCondition("OR", "APP=3")),
+			},
+			want: []string{`shadowed narrow by broad: rule is never evaluated, every request it matches is matched first by rule "broad" (order 1) with action ALLOW instead of DENY`},
+		},
+		{
+			name: "shadowed by a rule without conditions",
+			rules: []lintPolicyRule{
+				lintRule("everything", "ALLOW", "AND"),
+				lintRule("narrow", "ALLOW", "AND", lintCondition("OR", "APP=1")),
+			},
+			want: []string{`shadowed narrow by everything: rule is never evaluated, every request it matches is matched first by rule "everything" (order 1)`},
+		},
+		{
+			name: "duplicate",
+			rules: []lintPolicyRule{
+				lintRule("first", "ALLOW", "AND", lintCondition("OR", "APP=1", "APP=2"), lintCondition("AND", "CLIENT_TYPE=zpn_client_type_zapp")),
+				lintRule("second", "allow", "", lintCondition("AND", "CLIENT_TYPE=zpn_client_type_zapp"), lintCondition("OR", "APP=2", "APP=1")),
+			},
+			want: []string{`duplicate second by first: rule has the same conditions and action as rule "first" (order 1)`},
+		},
+		{
+			name: "disabled rules are skipped",
+			rules: []lintPolicyRule{
+				lintDisabled(lintRule("disabled broad", "ALLOW", "AND")),
+				lintRule("narrow", "ALLOW", "AND", lintCondition("OR", "APP=1")),
+				lintDisabled(lintRule("disabled narrower", "ALLOW", "AND", lintCondition("OR", "APP=1"))),
+			},
+			want: nil,
+		},
+		{
+			name: "unreachable",
+			rules: []lintPolicyRule{
+				lintRule("dead", "ALLOW", "AND", lintCondition("OR", "APP=9"), lintCondition("OR", "CLIENT_TYPE=zpn_client_type_zapp")),
+				lintRule("alive", "ALLOW", "AND", lintCondition("OR", "APP=9", "APP=1")),
+				lintRule("dead or alive", "DENY", "OR", lintCondition("OR", "APP=9"), lintCondition("OR", "APP=2")),
+			},
+			want: []string{`unreachable dead: rule can never match, it references objects that do not exist: APP=9`},
+		},
+		// Rules that cannot be normalized are left out of the analysis, so
+		// neither shadow nor are shadowed.
+		{
+			name: "rules that cannot be normalized",
+			rules: []lintPolicyRule{
+				lintNotNormalized(lintRule("broad, not normalized", "ALLOW", "AND")),
+				lintRule("OR of AND", "ALLOW", "OR", lintCondition("AND", "APP=1", "CLIENT_TYPE=zpn_client_type_zapp"), lintCondition("OR", "APP=2")),
+				lintRule("same OR of AND", "ALLOW", "OR", lintCondition("AND", "APP=1", "CLIENT_TYPE=zpn_client_type_zapp"), lintCondition("OR", "APP=2")),
+				lintRule("narrow", "ALLOW", "AND", lintCondition("OR", "APP=1")),
+			},
+			want: nil,
+		},
+	} {
+		for i := range tc.rules {
+			tc.rules[i].order = i + 1
+		}
+		var got []string
+		for _, finding := range lintPolicyRules(tc.rules, dead) {
+			line := finding.kind + " " + finding.rule.name
+			if finding.relatedRule != nil {
+				line += " by " + finding.relatedRule.name
+			}
+			got = append(got, line+": "+finding.message)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: lintPolicyRules() =\n%s\nwant\n%s", tc.name, strings.Join(got, "\n"), strings.Join(tc.want, "\n"))
+		}
+	}
+}
+
+// lintCondition returns a condition of atoms written as policyAtom.String()
+// returns them, OBJECT_TYPE=id or OBJECT_TYPE[lhs]=rhs.
+func lintCondition(operator string, atoms ...string) policyConditionExpr {
+	c := policyConditionExpr{operator: operator}
+	for _, s := range atoms {
+		head, rhs, _ := strings.Cut(s, "=")
+		objectType, lhs, ok := strings.Cut(strings.TrimSuffix(head, "]"), "[")
+		if !ok {
+			lhs = "id"
+		}
+		c.atoms = append(c.atoms, policyAtom{objectType: objectType, lhs: lhs, rhs: rhs})
+	}
+	return c
+}
+
+func (c policyConditionExpr) clause() policyClause {
+	clause := policyClause{}
+	for _, atom := range c.atoms {
+		clause[atom.String()] = atom
+	}
+	return clause
+}
+
+func lintRule(name, action, operator string, conditions ...policyConditionExpr) lintPolicyRule {
+	r := lintPolicyRule{policyRuleExpr: policyRuleExpr{id: name, name: name, action: action, operator: operator, conditions: conditions}}
+	r.clauses, r.normalized = r.normalize()
+	return r
+}
+
+func lintDisabled(r lintPolicyRule) lintPolicyRule {
+	r.disabled = true
+	return r
+}
+
+// lintNotNormalized marks a rule as one newLintPolicyRule does not normalize,
+// such as a rule with operands the v2 conversion drops.
+func lintNotNormalized(r lintPolicyRule) lintPolicyRule {
+	r.clauses, r.normalized = nil, false
+	return r
+}
+
+// policyClauseStrings returns clauses as sorted strings of their sorted atoms
+// joined with " | ".
+func policyClauseStrings(clauses []policyClause) []string {
+	var result []string
+	for _, clause := range clauses {
+		var atoms []string
+		for key := range clause {
+			atoms = append(atoms, key)
+		}
+		sort.Strings(atoms)
+		result = append(result, strings.Join(atoms, " | "))
+	}
+	sort.Strings(result)
+	return result
+}
+
+var testAccCheckDataSourcePolicyRuleLint_basic = `
+data "zpa_policy_rule_lint" "access" {
+  policy_type = "ACCESS_POLICY"
+}
+
+data "zpa_policy_rule_lint" "timeout" {
+  policy_type      = "TIMEOUT_POLICY"
+  check_references = false
+}`
@@ -0,0 +1,163 @@
+package zpa
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+)
+
+// lssCatalog holds the status codes accepted by the filter of each source log
+// type and the client types accepted by CLIENT_TYPE operands.
+type lssCatalog struct {
+	statusCodes map[string]map[string]bool
+	clientTypes map[string]bool
+	live        bool // Fetched from the API, rather than the built-in lists
+}
+
+// offlineLSSCatalog is the catalog of the lists built into the provider, used
+// when the API cannot be reached.
+func offlineLSSCatalog() *lssCatalog {
+	toSet := func(codes []string) map[string]bool {
+		set := make(map[string]bool, len(codes))
+		for _, code := range codes {
+			set[code] = true
+		}
+		return set
+	}
+	c := &lssCatalog{
+		statusCodes: map[string]map[string]bool{
+			"zpn_auth_log":     toSet(supportedLSSUserStatus),
+			"zpn_trans_log":    toSet(supportedLSSUserActivity),
+			"zpn_ast_auth_log": toSet(supportedAppConnectorStatus),
+			"zpn_sys_auth_log": toSet(supportedPrivateServiceEdgeStatus),
+		},
+		clientTypes: map[string]bool{},
+	}
+	for clientType := range supportedClientTypes {
+		c.clientTypes[clientType] = true
+	}
+	return c
+}
+
+const (
+	lssStatusCodesEndpoint = "/zpa/mgmtconfig/v2/admin/lssConfig/statusCodes"
+	lssClientTypesEndpoint = "/zpa/mgmtconfig/v2/admin/lssConfig/clientTypes"
+)
+
+// fetchLSSCatalog fetches the catalog from the endpoints of the
+// zpa_lss_config_status_codes and zpa_lss_config_client_types data sources.
+// The typed SDK responses only carry the fields known to the SDK, so the raw
+// responses are decoded instead.
+func fetchLSSCatalog(ctx context.Context, zClient *Client) (*lssCatalog, error) {
+	var codesByLogType map[string]interface{}
+	if _, err := zClient.Service.Client.NewRequestDo(ctx, "GET", lssStatusCodesEndpoint, nil, nil, &codesByLogType); err != nil {
+		return nil, fmt.Errorf("failed to get the LSS status codes: %v", err)
+	}
+	var clientTypeNames map[string]interface{}
+	if _, err := zClient.Service.Client.NewRequestDo(ctx, "GET", lssClientTypesEndpoint, nil, nil, &clientTypeNames); err != nil {
+		return nil, fmt.Errorf("failed to get the LSS client types: %v", err)
+	}
+	return liveLSSCatalog(codesByLogType, clientTypeNames), nil
+}
+
+// liveLSSCatalog returns the catalog of the raw status codes and client types
+// responses. The built-in lists are left out, so that codes ZPA no longer
+// accepts are rejected.
+func liveLSSCatalog(codesByLogType, clientTypeNames map[string]interface{}) *lssCatalog {
+	c := &lssCatalog{
+		statusCodes: make(map[string]map[string]bool, len(codesByLogType)),
+		clientTypes: make(map[string]bool, len(clientTypeNames)),
+		live:        true,
+	}
+	for logType, codes := range codesByLogType {
+		codes, ok := codes.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		c.statusCodes[logType] = make(map[string]bool, len(codes))
+		for code := range codes {
+			c.statusCodes[logType][code] = true
+		}
+	}
+	for clientType := range clientTypeNames {
+		c.clientTypes[clientType] = true
+	}
+	return c
+}
+
+// lssCatalogOf returns the catalog of the client, fetched once per provider
+// process. The built-in lists are used when the catalog cannot be fetched.
+// The fetch does not use the cancellation of ctx, which belongs to whichever
+// caller comes first, so that the catalog of the whole process does not
+// depend on it.
+func lssCatalogOf(ctx context.Context, zClient *Client) *lssCatalog {
+	if zClient == nil {
+		return offlineLSSCatalog()
+	}
+	zClient.lssCatalogOnce.Do(func() {
+		c, err := fetchLSSCatalog(context.WithoutCancel(ctx), zClient)
+		if err != nil {
+			log.Printf("[WARN] Validating LSS filters and client types against the built-in lists: %v", err)
+			c = offlineLSSCatalog()
+		}
+		zClient.lssCatalog = c
+	})
+	return zClient.lssCatalog
+}
+
+// check returns an error naming the closest known code when value is not one
+// of codes.
+func (c *lssCatalog) check(what, value string, codes map[string]bool) error {
+	if codes[value] {
+		return nil
+	}
+	source := "the built-in list"
+	if c.live {
+		source = "the ZPA catalog"
+	}
+	if closest := closestCode(value, codes); closest != "" {
+		return fmt.Errorf("invalid %s: %s is not in %s, did you mean %s?", what, value, source, closest)
+	}
+	return fmt.Errorf("invalid %s: %s is not in %s", what, value, source)
+}
+
+// closestCode returns the code of codes closest to value, as long as it is
+// close enough to be a typo.
+func closestCode(value string, codes map[string]bool) string {
+	candidates := make([]string, 0, len(codes))
+	for code := range codes {
+		candidates = append(candidates, code)
+	}
+	sort.Strings(candidates)
+
+	best, bestDistance := "", len(value)/3+2
+	for _, code := range candidates {
+		if distance := editDistance(strings.ToUpper(value), strings.ToUpper(code)); distance < bestDistance {
+			best, bestDistance = code, distance
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
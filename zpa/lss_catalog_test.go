@@ -0,0 +1,86 @@
+package zpa
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestLSSCatalog_ClosestCode(t *testing.T) {
+	codes := map[string]bool{
+		"ZPN_STATUS_AUTHENTICATED":       true,
+		"ZPN_STATUS_AUTH_FAILED":         true,
+		"ZPN_STATUS_DISCONNECTED":        true,
+		"zpn_client_type_edge_connector": true,
+	}
+	for value, want := range map[string]string{
+		"ZPN_STATUS_AUTHENTICATD":            "ZPN_STATUS_AUTHENTICATED",
+		"zpn_status_auth_failed":             "ZPN_STATUS_AUTH_FAILED",
+		"zpn_client_type_edge_conector":      "zpn_client_type_edge_connector",
+		"ZPN_STATUS_SOMETHING_ELSE_ENTIRELY": "",
+		"":                                   "",
+	} {
+		if got := closestCode(value, codes); got != want {
+			t.Errorf("closestCode(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestLSSCatalog_OfflineFallback(t *testing.T) {
+	c := lssCatalogOf(context.Background(), nil)
+	if c.live {
+		t.Fatal("expected the built-in catalog without a client")
+	}
+	if !c.clientTypes["zpn_client_type_zapp"] {
+		t.Error("expected the built-in client types")
+	}
+	if !c.statusCodes["zpn_auth_log"]["ZPN_STATUS_AUTHENTICATED"] {
+		t.Error("expected the built-in zpn_auth_log status codes")
+	}
+
+	err := c.check("client type", "zpn_client_type_zap", c.clientTypes)
+	if err == nil || !strings.Contains(err.Error(), "the built-in list") || !strings.Contains(err.Error(), "did you mean zpn_client_type_zapp?") {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestLSSCatalog_LiveListsReplaceBuiltInLists(t *testing.T) {
+	c := liveLSSCatalog(
+		map[string]interface{}{
+			"zpn_auth_log":    map[string]interface{}{"ZPN_STATUS_NEW_CODE": map[string]interface{}{"name": "New"}},
+			"zpn_new_log":     map[string]interface{}{"ZPN_STATUS_OTHER": "Other"},
+			"zpn_trans_log":   map[string]interface{}{},
+			"zpn_unknown_log": "not an object",
+		},
+		map[string]interface{}{"zpn_client_type_new": "New Client"},
+	)
+	if !c.live {
+		t.Error("expected a live catalog")
+	}
+	if !c.statusCodes["zpn_auth_log"]["ZPN_STATUS_NEW_CODE"] {
+		t.Error("expected the fetched zpn_auth_log status codes")
+	}
+	if c.statusCodes["zpn_auth_log"]["ZPN_STATUS_AUTHENTICATED"] {
+		t.Error("expected the built-in zpn_auth_log status codes to be left out")
+	}
+	if !c.statusCodes["zpn_new_log"]["ZPN_STATUS_OTHER"] {
+		t.Error("expected the status codes of log types missing from the built-in lists")
+	}
+	if codes, ok := c.statusCodes["zpn_trans_log"]; !ok || len(codes) != 0 {
+		t.Error("expected no zpn_trans_log status codes")
+	}
+	if _, ok := c.statusCodes["zpn_sys_auth_log"]; ok {
+		t.Error("expected the built-in log types missing from the fetched lists to be left out")
+	}
+	if _, ok := c.statusCodes["zpn_unknown_log"]; ok {
+		t.Error("expected malformed log types to be skipped")
+	}
+	if !c.clientTypes["zpn_client_type_new"] || c.clientTypes["zpn_client_type_zapp"] {
+		t.Error("expected only the fetched client types")
+	}
+
+	err := c.check("client type", "zpn_client_type_zapp", c.clientTypes)
+	if err == nil || !strings.Contains(err.Error(), "the ZPA catalog") {
+		t.Errorf("unexpected error %v", err)
+	}
+}
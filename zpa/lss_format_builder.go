@@ -0,0 +1,313 @@
+package zpa
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/lssconfigcontroller"
+)
+
+// Encodings of the LSS format builder.
+const (
+	lssEncodingJSON     = "JSON"
+	lssEncodingCSV      = "CSV"
+	lssEncodingTSV      = "TSV"
+	lssEncodingKeyValue = "KEY_VALUE"
+)
+
+var lssEncodings = []string{lssEncodingJSON, lssEncodingCSV, lssEncodingTSV, lssEncodingKeyValue}
+
+var (
+	// lssFormatDirective matches the placeholders of LSS formats, such as
+	// %j{LogTimestamp:time} or %d{ServerPort}.
+	lssFormatDirective = regexp.MustCompile(`%[a-z]\{([A-Za-z0-9_.]+)(?::[^}]*)?\}`)
+	// lssFormatJSONPair matches the fields of JSON formats, as in
+	// "Customer": %j{Customer}.
+	lssFormatJSONPair = regexp.MustCompile(`"([^"]+)"\s*:\s*(%[a-z]\{[^}]+\})`)
+	// lssFormatKeyValuePair matches the fields of key=value formats, as in
+	// Customer=%j{Customer}.
+	lssFormatKeyValuePair = regexp.MustCompile(`([A-Za-z0-9_.]+)=(%[a-z]\{[^}]+\})`)
+)
+
+func lssFormatBuilderSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeList,
+		Optional:     true,
+		MaxItems:     1,
+		ExactlyOneOf: []string{"config.0.format", "config.0.format_builder"},
+		Description:  "Builds format from the published template of source_log_type, instead of setting format.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"encoding": {
+					Type:         schema.TypeString,
+					Required:     true,
+					Description:  "Encoding of the log records: JSON, CSV, TSV or KEY_VALUE.",
+					ValidateFunc: validation.StringInSlice(lssEncodings, false),
+				},
+				"fields": {
+					Type:        schema.TypeList,
+					Required:    true,
+					MinItems:    1,
+					Description: "Fields of the log records, in order, named as in the JSON template of source_log_type.",
+					Elem: &schema.Schema{
+						Type:         schema.TypeString,
+						ValidateFunc: validation.StringIsNotEmpty,
+					},
+				},
+			},
+		},
+	}
+}
+
+// lssFormatTemplate is the published template of a log type, split into the
+// placeholder of every field for each encoding.
+type lssFormatTemplate struct {
+	logType    string
+	fields     []string                     // Field names, in the order of the JSON template
+	directives map[string]map[string]string // Placeholders by encoding and field
+	separators map[string]string            // Field separators of the CSV and TSV templates
+	suffix     string                       // What follows the last field, usually a newline
+	variables  map[string]bool              // Names of the placeholders of all the templates
+}
+
+func fetchLSSFormatTemplate(ctx context.Context, service *zscaler.Service, logType string) (*lssFormatTemplate, error) {
+	resp, _, err := lssconfigcontroller.GetFormats(ctx, service, logType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the format templates of %s: %v", logType, err)
+	}
+	return parseLSSFormatTemplate(logType, html.UnescapeString(resp.Json), html.UnescapeString(resp.Csv), html.UnescapeString(resp.Tsv))
+}
+
+func parseLSSFormatTemplate(logType, jsonTemplate, csvTemplate, tsvTemplate string) (*lssFormatTemplate, error) {
+	t := &lssFormatTemplate{
+		logType: logType,
+		directives: map[string]map[string]string{
+			lssEncodingJSON: {},
+			lssEncodingCSV:  {},
+			lssEncodingTSV:  {},
+		},
+		separators: map[string]string{},
+		variables:  map[string]bool{},
+	}
+	for _, tmpl := range []string{jsonTemplate, csvTemplate, tsvTemplate} {
+		for _, m := range lssFormatDirective.FindAllStringSubmatch(tmpl, -1) {
+			t.variables[m[1]] = true
+		}
+	}
+	pairs := lssFormatJSONPair.FindAllStringSubmatch(jsonTemplate, -1)
+	if len(pairs) == 0 {
+		return nil, fmt.Errorf("the JSON template of %s has no fields", logType)
+	}
+	if end := strings.LastIndex(jsonTemplate, "}"); end >= 0 {
+		t.suffix = jsonTemplate[end+1:]
+	}
+	for _, pair := range pairs {
+		t.fields = append(t.fields, pair[1])
+		t.directives[lssEncodingJSON][pair[1]] = pair[2]
+	}
+
+	// The CSV and TSV templates have no field names: their placeholders are
+	// matched with the JSON fields by position, or by the name of the
+	// placeholder when the templates do not have the same fields.
+	for encoding, tmpl := range map[string]string{lssEncodingCSV: csvTemplate, lssEncodingTSV: tsvTemplate} {
+		matches := lssFormatDirective.FindAllStringSubmatchIndex(tmpl, -1)
+		if len(matches) > 1 {
+			t.separators[encoding] = tmpl[matches[0][1]:matches[1][0]]
+		}
+		byName := map[string]string{}
+		for _, m := range matches {
+			byName[tmpl[m[2]:m[3]]] = tmpl[m[0]:m[1]]
+		}
+		for i, field := range t.fields {
+			if len(matches) == len(t.fields) {
+				t.directives[encoding][field] = tmpl[matches[i][0]:matches[i][1]]
+				continue
+			}
+			// JSON placeholders with names lssFormatDirective does not
+			// accept cannot be matched by name.
+			inner := lssFormatDirective.FindStringSubmatch(t.directives[lssEncodingJSON][field])
+			if inner == nil {
+				continue
+			}
+			if directive, ok := byName[inner[1]]; ok {
+				t.directives[encoding][field] = directive
+			}
+		}
+	}
+	if t.separators[lssEncodingCSV] == "" {
+		t.separators[lssEncodingCSV] = ","
+	}
+	if t.separators[lssEncodingTSV] == "" {
+		t.separators[lssEncodingTSV] = "\t"
+	}
+	return t, nil
+}
+
+// build returns the format of fields in encoding. Field names are checked
+// against the template.
+func (t *lssFormatTemplate) build(encoding string, fields []string) (string, error) {
+	directives := t.directives[encoding]
+	if encoding == lssEncodingKeyValue {
+		// Values are JSON encoded so that they cannot contain separators.
+		directives = t.directives[lssEncodingJSON]
+	}
+	var problems []string
+	seen := map[string]bool{}
+	parts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if seen[field] {
+			problems = append(problems, fmt.Sprintf("%s is listed more than once", field))
+			continue
+		}
+		seen[field] = true
+		directive, ok := directives[field]
+		if !ok {
+			if _, known := t.directives[lssEncodingJSON][field]; known {
+				problems = append(problems, fmt.Sprintf("%s is not part of the %s template of %s", field, encoding, t.logType))
+			} else {
+				problems = append(problems, fmt.Sprintf("%s is not a field of %s", field, t.logType))
+			}
+			continue
+		}
+		switch encoding {
+		case lssEncodingJSON:
+			parts = append(parts, fmt.Sprintf("%q: %s", field, directive))
+		case lssEncodingKeyValue:
+			parts = append(parts, field+"="+directive)
+		default:
+			parts = append(parts, directive)
+		}
+	}
+	if len(problems) > 0 {
+		return "", fmt.Errorf("invalid format_builder fields:\n  - %s\n\nThe fields of %s are %s", strings.Join(problems, "\n  - "), t.logType, strings.Join(t.fields, ", "))
+	}
+
+	switch encoding {
+	case lssEncodingJSON:
+		return "{" + strings.Join(parts, ",") + "}" + t.suffix, nil
+	case lssEncodingKeyValue:
+		return strings.Join(parts, " ") + t.suffix, nil
+	default:
+		return strings.Join(parts, t.separators[encoding]) + t.suffix, nil
+	}
+}
+
+// fieldsOf returns the fields of a format read from the API, when the format
+// is exactly what build returns for them in encoding.
+func (t *lssFormatTemplate) fieldsOf(encoding, format string) ([]string, bool) {
+	var fields []string
+	switch encoding {
+	case lssEncodingJSON:
+		for _, pair := range lssFormatJSONPair.FindAllStringSubmatch(format, -1) {
+			fields = append(fields, pair[1])
+		}
+	case lssEncodingKeyValue:
+		for _, pair := range lssFormatKeyValuePair.FindAllStringSubmatch(format, -1) {
+			fields = append(fields, pair[1])
+		}
+	default:
+		byDirective := map[string]string{}
+		for field, directive := range t.directives[encoding] {
+			byDirective[directive] = field
+		}
+		for _, directive := range lssFormatDirective.FindAllString(format, -1) {
+			field, ok := byDirective[directive]
+			if !ok {
+				return nil, false
+			}
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) == 0 {
+		return nil, false
+	}
+	built, err := t.build(encoding, fields)
+	if err != nil || built != format {
+		return nil, false
+	}
+	return fields, true
+}
+
+func expandLSSFormatBuilder(config map[string]interface{}) (string, []string, bool) {
+	builders, _ := config["format_builder"].([]interface{})
+	if len(builders) == 0 || builders[0] == nil {
+		return "", nil, false
+	}
+	builder := builders[0].(map[string]interface{})
+	return builder["encoding"].(string), ListToStringSlice(builder["fields"].([]interface{})), true
+}
+
+// buildLSSConfigFormat returns the format generated by the format_builder of
+// the LSS configuration, if any.
+func buildLSSConfigFormat(ctx context.Context, service *zscaler.Service, d *schema.ResourceData) (string, bool, error) {
+	config, _ := d.Get("config.0").(map[string]interface{})
+	encoding, fields, ok := expandLSSFormatBuilder(config)
+	if !ok {
+		return "", false, nil
+	}
+	t, err := fetchLSSFormatTemplate(ctx, service, config["source_log_type"].(string))
+	if err != nil {
+		return "", true, err
+	}
+	format, err := t.build(encoding, fields)
+	return format, true, err
+}
+
+// validateLSSFormatBuilder is the CustomizeDiff of zpa_lss_config_controller.
+// It checks the fields of format_builder against the template of the log type
+// during plan.
+func validateLSSFormatBuilder(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("config") {
+		return nil
+	}
+	configs, _ := d.Get("config").([]interface{})
+	if len(configs) == 0 || configs[0] == nil {
+		return nil
+	}
+	config := configs[0].(map[string]interface{})
+	encoding, fields, ok := expandLSSFormatBuilder(config)
+	if !ok {
+		return nil
+	}
+	zClient, ok := meta.(*Client)
+	if !ok || zClient == nil {
+		return nil
+	}
+	t, err := fetchLSSFormatTemplate(ctx, zClient.Service, config["source_log_type"].(string))
+	if err != nil {
+		log.Printf("[WARN] Could not validate format_builder at plan time: %v", err)
+		return nil
+	}
+	_, err = t.build(encoding, fields)
+	return err
+}
+
+// flattenLSSFormatBuilder maps the format read from the API back to the
+// fields of the format_builder of the state. The builder is dropped when the
+// format no longer matches, so that the plan shows the drift.
+func flattenLSSFormatBuilder(ctx context.Context, service *zscaler.Service, d *schema.ResourceData, lssConfig *lssconfigcontroller.LSSConfig) []interface{} {
+	config, _ := d.Get("config.0").(map[string]interface{})
+	encoding, fields, ok := expandLSSFormatBuilder(config)
+	if !ok {
+		return nil
+	}
+	state := []interface{}{map[string]interface{}{"encoding": encoding, "fields": fields}}
+	t, err := fetchLSSFormatTemplate(ctx, service, lssConfig.SourceLogType)
+	if err != nil {
+		log.Printf("[WARN] Keeping format_builder of lss config controller %s: %v", d.Id(), err)
+		return state
+	}
+	readFields, ok := t.fieldsOf(encoding, html.UnescapeString(lssConfig.Format))
+	if !ok {
+		log.Printf("[WARN] The format of lss config controller %s does not match its format_builder", d.Id())
+		return nil
+	}
+	return []interface{}{map[string]interface{}{"encoding": encoding, "fields": readFields}}
+}
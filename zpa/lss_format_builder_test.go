@@ -0,0 +1,151 @@
+package zpa
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const (
+	testLSSJSONTemplate = `{"LogTimestamp": %j{LogTimestamp:time},"Customer": %j{Customer},"SessionID": %j{SessionID},"ServerPort": %d{ServerPort}}` + "\n"
+	testLSSCSVTemplate  = `%s{LogTimestamp:time},%s{Customer},%s{SessionID},%d{ServerPort}` + "\n"
+	testLSSTSVTemplate  = "%s{LogTimestamp:time}\t%s{Customer}\t%s{SessionID}\t%d{ServerPort}\n"
+)
+
+func testLSSFormatTemplate(t *testing.T, jsonTemplate, csvTemplate, tsvTemplate string) *lssFormatTemplate {
+	t.Helper()
+	tmpl, err := parseLSSFormatTemplate("zpn_trans_log", jsonTemplate, csvTemplate, tsvTemplate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tmpl
+}
+
+func TestLSSFormatTemplate_Build(t *testing.T) {
+	tmpl := testLSSFormatTemplate(t, testLSSJSONTemplate, testLSSCSVTemplate, testLSSTSVTemplate)
+	if want := []string{"LogTimestamp", "Customer", "SessionID", "ServerPort"}; !reflect.DeepEqual(tmpl.fields, want) {
+		t.Fatalf("fields %v, want %v", tmpl.fields, want)
+	}
+
+	fields := []string{"SessionID", "LogTimestamp", "ServerPort"}
+	for encoding, want := range map[string]string{
+		lssEncodingJSON:     `{"SessionID": %j{SessionID},"LogTimestamp": %j{LogTimestamp:time},"ServerPort": %d{ServerPort}}` + "\n",
+		lssEncodingCSV:      "%s{SessionID},%s{LogTimestamp:time},%d{ServerPort}\n",
+		lssEncodingTSV:      "%s{SessionID}\t%s{LogTimestamp:time}\t%d{ServerPort}\n",
+		lssEncodingKeyValue: "SessionID=%j{SessionID} LogTimestamp=%j{LogTimestamp:time} ServerPort=%d{ServerPort}\n",
+	} {
+		got, err := tmpl.build(encoding, fields)
+		if err != nil {
+			t.Errorf("%s: %v", encoding, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("%s: got %q, want %q", encoding, got, want)
+		}
+	}
+}
+
+func TestLSSFormatTemplate_BuildRejectsDuplicateAndUnknownFields(t *testing.T) {
+	tmpl := testLSSFormatTemplate(t, testLSSJSONTemplate, testLSSCSVTemplate, testLSSTSVTemplate)
+
+	_, err := tmpl.build(lssEncodingJSON, []string{"Customer", "Customer", "Costumer"})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, want := range []string{
+		"Customer is listed more than once",
+		"Costumer is not a field of zpn_trans_log",
+		"The fields of zpn_trans_log are LogTimestamp, Customer, SessionID, ServerPort",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err, want)
+		}
+	}
+}
+
+func TestLSSFormatTemplate_CSVMatchedByPosition(t *testing.T) {
+	// Same number of fields: the placeholders are matched by position, even
+	// when they are named differently.
+	tmpl := testLSSFormatTemplate(t,
+		`{"Customer": %j{Customer},"ConnectionID": %j{ConnectionID}}`+"\n",
+		"%s{Customer},%s{ConnID}\n",
+		"%s{Customer}\t%s{ConnID}\n",
+	)
+	got, err := tmpl.build(lssEncodingCSV, []string{"ConnectionID", "Customer"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "%s{ConnID},%s{Customer}\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestLSSFormatTemplate_CSVMatchedByName(t *testing.T) {
+	// The CSV template lacks a field: the placeholders are matched by name,
+	// and the missing field cannot be used in CSV.
+	tmpl := testLSSFormatTemplate(t,
+		testLSSJSONTemplate,
+		"%s{Customer}|%d{ServerPort}|%s{LogTimestamp:time}\n",
+		testLSSTSVTemplate,
+	)
+	got, err := tmpl.build(lssEncodingCSV, []string{"LogTimestamp", "ServerPort"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "%s{LogTimestamp:time}|%d{ServerPort}\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	_, err = tmpl.build(lssEncodingCSV, []string{"SessionID"})
+	if err == nil || !strings.Contains(err.Error(), "SessionID is not part of the CSV template of zpn_trans_log") {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestLSSFormatTemplate_UnmatchableJSONPlaceholder(t *testing.T) {
+	// A JSON placeholder name that is not matched by lssFormatDirective is
+	// only usable in JSON and KEY_VALUE.
+	tmpl := testLSSFormatTemplate(t,
+		`{"Customer": %j{Customer},"Odd": %j{odd-name}}`+"\n",
+		"%s{Customer}\n",
+		"%s{Customer}\n",
+	)
+	if _, err := tmpl.build(lssEncodingJSON, []string{"Odd"}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if _, err := tmpl.build(lssEncodingCSV, []string{"Odd"}); err == nil {
+		t.Error("expected an error for a field missing from the CSV template")
+	}
+}
+
+func TestLSSFormatTemplate_NoJSONFields(t *testing.T) {
+	if _, err := parseLSSFormatTemplate("zpn_trans_log", "{}", "", ""); err == nil {
+		t.Error("expected an error for a JSON template without fields")
+	}
+}
+
+func TestLSSFormatTemplate_FieldsOfRoundTrip(t *testing.T) {
+	tmpl := testLSSFormatTemplate(t, testLSSJSONTemplate, testLSSCSVTemplate, testLSSTSVTemplate)
+	fields := []string{"ServerPort", "Customer"}
+	for _, encoding := range lssEncodings {
+		format, err := tmpl.build(encoding, fields)
+		if err != nil {
+			t.Fatalf("%s: %v", encoding, err)
+		}
+		got, ok := tmpl.fieldsOf(encoding, format)
+		if !ok || !reflect.DeepEqual(got, fields) {
+			t.Errorf("%s: fieldsOf(%q) = %v, %t, want %v", encoding, format, got, ok, fields)
+		}
+
+		// A format changed outside of Terraform no longer maps to fields.
+		if _, ok := tmpl.fieldsOf(encoding, strings.Replace(format, "Customer", "SessionID", -1)+" "); ok {
+			t.Errorf("%s: expected a changed format not to match", encoding)
+		}
+	}
+	if _, ok := tmpl.fieldsOf(lssEncodingCSV, "%s{Unknown}\n"); ok {
+		t.Error("expected a CSV format with an unknown placeholder not to match")
+	}
+	if _, ok := tmpl.fieldsOf(lssEncodingJSON, "\n"); ok {
+		t.Error("expected a format without fields not to match")
+	}
+}
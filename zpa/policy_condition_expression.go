@@ -0,0 +1,705 @@
+package zpa
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontrollerv2"
+)
+
+// Condition expressions are an alternative to the conditions blocks of the v2
+// policy rule resources, for example:
+//
+//	APP_GROUP in ["72058304855015574"] and (SCIM_GROUP(idp="72058304855015425") in ["295"] or COUNTRY_CODE in ["US", "CA"])
+//
+// The grammar is:
+//
+//	expression = and { "or" and }
+//	and        = primary { "and" primary }
+//	primary    = "(" expression ")" | comparison
+//	comparison = OBJECT_TYPE [ "(" [ key "=" ] string ")" ] ( "in" list | "==" string )
+//	list       = "[" [ string { "," string } [ "," ] ] "]"
+//
+// The terms of the top level become the conditions of the rule and the
+// top-level operator becomes the rule operator. A parenthesized group
+// becomes a single condition with the operator of the group, so groups can
+// only contain comparisons.
+
+// policyExpressionKeys are the names of the argument of entry operands, which
+// is the lhs of their entry values. "lhs" is accepted for every entry operand.
+var policyExpressionKeys = map[string]string{
+	"SAML":            "attribute",
+	"SCIM":            "attribute",
+	"SCIM_GROUP":      "idp",
+	"POSTURE":         "udid",
+	"TRUSTED_NETWORK": "network",
+}
+
+// policyExpressionFlagTypes are entry operands whose rhs is always "true". In
+// expressions their values are the lhs, as in COUNTRY_CODE in ["US", "CA"].
+var policyExpressionFlagTypes = map[string]bool{
+	"COUNTRY_CODE": true,
+	"PLATFORM":     true,
+}
+
+// conditionExpressionError is an error at a column of an expression. Columns
+// start at 1 and count characters, not bytes.
+type conditionExpressionError struct {
+	column  int
+	message string
+}
+
+func (e *conditionExpressionError) Error() string {
+	return fmt.Sprintf("column %d: %s", e.column, e.message)
+}
+
+func conditionExpressionErrorf(column int, format string, args ...interface{}) error {
+	return &conditionExpressionError{column: column, message: fmt.Sprintf(format, args...)}
+}
+
+type conditionExprTokenKind int
+
+const (
+	conditionExprEOF conditionExprTokenKind = iota
+	conditionExprIdent
+	conditionExprString
+	conditionExprPunct
+)
+
+type conditionExprToken struct {
+	kind   conditionExprTokenKind
+	text   string
+	column int
+}
+
+func (t conditionExprToken) String() string {
+	switch t.kind {
+	case conditionExprEOF:
+		return "end of expression"
+	case conditionExprString:
+		return "string " + strconv.Quote(t.text)
+	}
+	return strconv.Quote(t.text)
+}
+
+// isKeyword reports whether t is the keyword "and", "or" or "in", which are
+// case-insensitive.
+func (t conditionExprToken) isKeyword(keyword string) bool {
+	return t.kind == conditionExprIdent && strings.EqualFold(t.text, keyword)
+}
+
+func (t conditionExprToken) isPunct(punct string) bool {
+	return t.kind == conditionExprPunct && t.text == punct
+}
+
+func lexConditionExpression(expression string) ([]conditionExprToken, error) {
+	runes := []rune(expression)
+	var tokens []conditionExprToken
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		column := i + 1
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '_' || unicode.IsLetter(r):
+			start := i
+			for i < len(runes) && (runes[i] == '_' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			tokens = append(tokens, conditionExprToken{kind: conditionExprIdent, text: string(runes[start:i]), column: column})
+		case r == '"':
+			start := i
+			for i++; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' {
+					i++
+				}
+			}
+			if i >= len(runes) {
+				return nil, conditionExpressionErrorf(column, "unterminated string")
+			}
+			i++
+			value, err := strconv.Unquote(string(runes[start:i]))
+			if err != nil {
+				return nil, conditionExpressionErrorf(column, "invalid string %s", string(runes[start:i]))
+			}
+			tokens = append(tokens, conditionExprToken{kind: conditionExprString, text: value, column: column})
+		case r == '=' && i+1 < len(runes) && runes[i+1] == '=':
+			tokens = append(tokens, conditionExprToken{kind: conditionExprPunct, text: "==", column: column})
+			i += 2
+		case strings.ContainsRune("()[],=", r):
+			tokens = append(tokens, conditionExprToken{kind: conditionExprPunct, text: string(r), column: column})
+			i++
+		default:
+			return nil, conditionExpressionErrorf(column, "unexpected character %q", r)
+		}
+	}
+	return append(tokens, conditionExprToken{kind: conditionExprEOF, column: len(runes) + 1}), nil
+}
+
+// conditionExprValue is a string of an expression and its column.
+type conditionExprValue struct {
+	value  string
+	column int
+}
+
+// conditionExprComparison is a single comparison, such as
+// SCIM_GROUP(idp="1") in ["2", "3"].
+type conditionExprComparison struct {
+	objectType string
+	column     int
+	// key and lhs are the optional argument. key is empty when the argument
+	// is given without a name.
+	key    conditionExprValue
+	lhs    *conditionExprValue
+	values []conditionExprValue
+}
+
+// conditionExprNode is either a comparison or an "AND" or "OR" of at least
+// two nodes.
+type conditionExprNode struct {
+	operator   string
+	column     int
+	children   []*conditionExprNode
+	comparison *conditionExprComparison
+}
+
+type conditionExprParser struct {
+	tokens []conditionExprToken
+	pos    int
+}
+
+func (p *conditionExprParser) peek() conditionExprToken {
+	return p.tokens[p.pos]
+}
+
+func (p *conditionExprParser) next() conditionExprToken {
+	t := p.tokens[p.pos]
+	if t.kind != conditionExprEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *conditionExprParser) expectPunct(punct string) (conditionExprToken, error) {
+	t := p.next()
+	if !t.isPunct(punct) {
+		return t, conditionExpressionErrorf(t.column, "expected %q, got %s", punct, t)
+	}
+	return t, nil
+}
+
+func (p *conditionExprParser) expectString() (conditionExprValue, error) {
+	t := p.next()
+	if t.kind != conditionExprString {
+		return conditionExprValue{}, conditionExpressionErrorf(t.column, "expected a string, got %s", t)
+	}
+	return conditionExprValue{value: t.text, column: t.column}, nil
+}
+
+// parseConditionExpression parses expression into a tree in which nested
+// groups with the operator of their parent are merged into it.
+func parseConditionExpression(expression string) (*conditionExprNode, error) {
+	tokens, err := lexConditionExpression(expression)
+	if err != nil {
+		return nil, err
+	}
+	p := &conditionExprParser{tokens: tokens}
+	if p.peek().kind == conditionExprEOF {
+		return nil, conditionExpressionErrorf(1, "empty expression")
+	}
+	node, err := p.parseOperator("OR")
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != conditionExprEOF {
+		return nil, conditionExpressionErrorf(t.column, "unexpected %s, expected \"and\", \"or\" or the end of the expression", t)
+	}
+	return node, nil
+}
+
+// parseOperator parses the terms joined by operator, "OR" binding looser than
+// "AND".
+func (p *conditionExprParser) parseOperator(operator string) (*conditionExprNode, error) {
+	parseTerm := func() (*conditionExprNode, error) {
+		if operator == "OR" {
+			return p.parseOperator("AND")
+		}
+		return p.parsePrimary()
+	}
+
+	first, err := parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	node := &conditionExprNode{operator: operator, column: first.column}
+	add := func(term *conditionExprNode) {
+		if term.operator == operator {
+			node.children = append(node.children, term.children...)
+			return
+		}
+		node.children = append(node.children, term)
+	}
+	add(first)
+	for p.peek().isKeyword(operator) {
+		p.next()
+		term, err := parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		add(term)
+	}
+	if len(node.children) == 1 {
+		return node.children[0], nil
+	}
+	return node, nil
+}
+
+func (p *conditionExprParser) parsePrimary() (*conditionExprNode, error) {
+	t := p.peek()
+	if t.isPunct("(") {
+		p.next()
+		node, err := p.parseOperator("OR")
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expectPunct(")"); err != nil {
+			return nil, err
+		}
+		return node, nil
+	}
+	comparison, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	return &conditionExprNode{column: comparison.column, comparison: comparison}, nil
+}
+
+func (p *conditionExprParser) parseComparison() (*conditionExprComparison, error) {
+	t := p.next()
+	if t.kind != conditionExprIdent || t.isKeyword("and") || t.isKeyword("or") || t.isKeyword("in") {
+		return nil, conditionExpressionErrorf(t.column, "expected an object type, got %s", t)
+	}
+	c := &conditionExprComparison{objectType: t.text, column: t.column}
+
+	if p.peek().isPunct("(") {
+		p.next()
+		if key := p.peek(); key.kind == conditionExprIdent {
+			p.next()
+			if _, err := p.expectPunct("="); err != nil {
+				return nil, err
+			}
+			c.key = conditionExprValue{value: key.text, column: key.column}
+		}
+		lhs, err := p.expectString()
+		if err != nil {
+			return nil, err
+		}
+		c.lhs = &lhs
+		if _, err := p.expectPunct(")"); err != nil {
+			return nil, err
+		}
+	}
+
+	switch op := p.next(); {
+	case op.isPunct("=="):
+		value, err := p.expectString()
+		if err != nil {
+			return nil, err
+		}
+		c.values = []conditionExprValue{value}
+	case op.isKeyword("in"):
+		open, err := p.expectPunct("[")
+		if err != nil {
+			return nil, err
+		}
+		for !p.peek().isPunct("]") {
+			value, err := p.expectString()
+			if err != nil {
+				return nil, err
+			}
+			c.values = append(c.values, value)
+			if !p.peek().isPunct(",") {
+				break
+			}
+			p.next()
+		}
+		if _, err := p.expectPunct("]"); err != nil {
+			return nil, err
+		}
+		if len(c.values) == 0 {
+			return nil, conditionExpressionErrorf(open.column, "empty list of values")
+		}
+	default:
+		return nil, conditionExpressionErrorf(op.column, "expected \"in\" or \"==\" after %s, got %s", c.objectType, op)
+	}
+	return c, nil
+}
+
+// compileConditionExpression compiles expression into the rule operator and
+// the conditions ExpandPolicyConditionsV2 would produce for the equivalent
+// conditions blocks. Values are checked offline with the operand registry.
+// When policyType is empty, object types are not checked against it.
+//
+// Rules of policy types without a rule operator always AND their conditions,
+// so a top-level "or" can only join comparisons, which become one condition.
+func compileConditionExpression(expression, policyType string) (string, []policysetcontrollerv2.PolicyRuleResourceConditions, error) {
+	root, err := parseConditionExpression(expression)
+	if err != nil {
+		return "", nil, err
+	}
+
+	operator := "AND"
+	terms := []*conditionExprNode{root}
+	if root.comparison == nil && (root.operator == "AND" || policyRuleHasOperator(policyType)) {
+		operator = root.operator
+		terms = root.children
+	}
+	if root.comparison == nil && operator != root.operator {
+		for _, child := range root.children {
+			if child.comparison == nil {
+				return "", nil, conditionExpressionErrorf(child.column, "%s rules always AND their conditions, a top-level \"or\" can only join comparisons", policyType)
+			}
+		}
+	}
+
+	var conditions []policysetcontrollerv2.PolicyRuleResourceConditions
+	for _, term := range terms {
+		if term.comparison != nil {
+			condition, err := compileConditionExprComparisons("OR", []*conditionExprComparison{term.comparison}, policyType)
+			if err != nil {
+				return "", nil, err
+			}
+			conditions = append(conditions, condition)
+			continue
+		}
+
+		comparisons := make([]*conditionExprComparison, 0, len(term.children))
+		for _, child := range term.children {
+			if child.comparison == nil {
+				return "", nil, conditionExpressionErrorf(child.column, "groups can only contain comparisons, conditions cannot be nested more than one level deep")
+			}
+			comparisons = append(comparisons, child.comparison)
+		}
+		condition, err := compileConditionExprComparisons(term.operator, comparisons, policyType)
+		if err != nil {
+			return "", nil, err
+		}
+		conditions = append(conditions, condition)
+	}
+	return operator, conditions, nil
+}
+
+// compileConditionExprComparisons compiles comparisons joined by operator
+// into a single condition, with one operand per object type.
+func compileConditionExprComparisons(operator string, comparisons []*conditionExprComparison, policyType string) (policysetcontrollerv2.PolicyRuleResourceConditions, error) {
+	condition := policysetcontrollerv2.PolicyRuleResourceConditions{Operator: operator}
+	operandIndex := map[string]int{}
+	seen := map[string]bool{}
+
+	for _, c := range comparisons {
+		t, ok := lookupPolicyOperandType(c.objectType)
+		if !ok {
+			return condition, conditionExpressionErrorf(c.column, "unknown object type %q", c.objectType)
+		}
+		if policyType != "" && !t.allowedIn(policyType) {
+			return condition, conditionExpressionErrorf(c.column, "object type %s is not supported by %s rules", c.objectType, policyType)
+		}
+		if operator == "AND" && len(c.values) > 1 {
+			return condition, conditionExpressionErrorf(c.column, "%s matches any of a list of values and cannot be used in an \"and\" group, compare each value with == instead", c.objectType)
+		}
+
+		index, ok := operandIndex[c.objectType]
+		if !ok {
+			index = len(condition.Operands)
+			operandIndex[c.objectType] = index
+			condition.Operands = append(condition.Operands, policysetcontrollerv2.PolicyRuleResourceOperands{ObjectType: c.objectType})
+		}
+		operand := &condition.Operands[index]
+
+		if t.valueKind != operandValueEntry || policyExpressionFlagTypes[c.objectType] {
+			if c.lhs != nil {
+				return condition, conditionExpressionErrorf(c.lhs.column, "%s does not take an argument", c.objectType)
+			}
+		}
+
+		switch {
+		case t.valueKind != operandValueEntry:
+			for _, v := range c.values {
+				if err := t.validateValue(operandValue{rhs: v.value}); err != nil {
+					return condition, conditionExpressionErrorf(v.column, "%s %v", c.objectType, err)
+				}
+				if key := c.objectType + "|" + v.value; !seen[key] {
+					seen[key] = true
+					operand.Values = append(operand.Values, v.value)
+				}
+			}
+		case policyExpressionFlagTypes[c.objectType]:
+			for _, v := range c.values {
+				if err := t.lhs.validate(v.value); err != nil {
+					return condition, conditionExpressionErrorf(v.column, "%s value %v", c.objectType, err)
+				}
+				if key := c.objectType + "|" + v.value + "|true"; !seen[key] {
+					seen[key] = true
+					operand.EntryValuesLHSRHS = append(operand.EntryValuesLHSRHS, policysetcontrollerv2.OperandsResourceLHSRHSValue{LHS: v.value, RHS: "true"})
+				}
+			}
+		default:
+			lhs, err := conditionExprComparisonLHS(t, c)
+			if err != nil {
+				return condition, err
+			}
+			for _, v := range c.values {
+				if err := t.rhs.validate(v.value); err != nil {
+					return condition, conditionExpressionErrorf(v.column, "%s value %v", c.objectType, err)
+				}
+				if key := c.objectType + "|" + lhs + "|" + v.value; !seen[key] {
+					seen[key] = true
+					operand.EntryValuesLHSRHS = append(operand.EntryValuesLHSRHS, policysetcontrollerv2.OperandsResourceLHSRHSValue{LHS: lhs, RHS: v.value})
+				}
+			}
+		}
+	}
+	return condition, nil
+}
+
+// conditionExprComparisonLHS returns the lhs of the entry values of an entry
+// comparison: its argument, or the only lhs the object type accepts.
+func conditionExprComparisonLHS(t *policyOperandType, c *conditionExprComparison) (string, error) {
+	key := policyExpressionKeys[c.objectType]
+	if key == "" {
+		key = "lhs"
+	}
+	if c.lhs == nil {
+		if len(t.lhs.allowed) == 1 {
+			return t.lhs.allowed[0], nil
+		}
+		return "", conditionExpressionErrorf(c.column, "%s requires %s as argument, as in %s(%s=\"...\")", c.objectType, t.lhs.description, c.objectType, key)
+	}
+	if c.key.value != "" && c.key.value != key && c.key.value != "lhs" {
+		return "", conditionExpressionErrorf(c.key.column, "unknown argument %q of %s, expected %q", c.key.value, c.objectType, key)
+	}
+	if err := t.lhs.validate(c.lhs.value); err != nil {
+		return "", conditionExpressionErrorf(c.lhs.column, "%s argument %v", c.objectType, err)
+	}
+	return c.lhs.value, nil
+}
+
+// formatConditionExpression returns the canonical expression of a rule. Terms
+// and conditions are sorted so that equivalent rules format the same way.
+func formatConditionExpression(operator string, conditions []policysetcontrollerv2.PolicyRuleResourceConditions) string {
+	var formatted []string
+	for _, condition := range conditions {
+		conditionOperator := strings.ToUpper(condition.Operator)
+		if conditionOperator == "" {
+			conditionOperator = "OR"
+		}
+		var terms []string
+		for _, operand := range condition.Operands {
+			terms = append(terms, formatConditionExprOperand(conditionOperator, operand)...)
+		}
+		if len(terms) == 0 {
+			continue
+		}
+		sort.Strings(terms)
+		formatted = append(formatted, strings.Join(terms, " "+strings.ToLower(conditionOperator)+" "))
+	}
+	if len(formatted) > 1 {
+		for i, condition := range formatted {
+			if strings.Contains(condition, " and ") || strings.Contains(condition, " or ") {
+				formatted[i] = "(" + condition + ")"
+			}
+		}
+	}
+	sort.Strings(formatted)
+
+	operator = strings.ToLower(operator)
+	if operator == "" {
+		operator = "and"
+	}
+	return strings.Join(formatted, " "+operator+" ")
+}
+
+// formatConditionExprOperand returns the comparisons of an operand. In OR
+// conditions the values sharing an lhs are listed in one comparison, in AND
+// conditions each value is compared on its own.
+func formatConditionExprOperand(operator string, operand policysetcontrollerv2.PolicyRuleResourceOperands) []string {
+	t, _ := lookupPolicyOperandType(operand.ObjectType)
+	format := func(arg string, values []string) []string {
+		head := operand.ObjectType
+		if arg != "" {
+			head += "(" + arg + ")"
+		}
+		sort.Strings(values)
+		if operator == "AND" {
+			terms := make([]string, len(values))
+			for i, v := range values {
+				terms[i] = head + " == " + strconv.Quote(v)
+			}
+			return terms
+		}
+		if len(values) == 1 {
+			return []string{head + " == " + strconv.Quote(values[0])}
+		}
+		return []string{head + " in [" + strings.Join(quoteAll(values), ", ") + "]"}
+	}
+
+	var terms []string
+	if len(operand.Values) > 0 {
+		terms = append(terms, format("", append([]string(nil), operand.Values...))...)
+	}
+
+	key := policyExpressionKeys[operand.ObjectType]
+	if key == "" {
+		key = "lhs"
+	}
+	var flags []string
+	byLHS := map[string][]string{}
+	var lhsOrder []string
+	for _, ev := range operand.EntryValuesLHSRHS {
+		if policyExpressionFlagTypes[operand.ObjectType] && ev.RHS == "true" {
+			flags = append(flags, ev.LHS)
+			continue
+		}
+		if _, ok := byLHS[ev.LHS]; !ok {
+			lhsOrder = append(lhsOrder, ev.LHS)
+		}
+		byLHS[ev.LHS] = append(byLHS[ev.LHS], ev.RHS)
+	}
+	if len(flags) > 0 {
+		terms = append(terms, format("", flags)...)
+	}
+	for _, lhs := range lhsOrder {
+		arg := key + "=" + strconv.Quote(lhs)
+		if t != nil && len(t.lhs.allowed) == 1 && t.lhs.allowed[0] == lhs {
+			arg = ""
+		}
+		terms = append(terms, format(arg, byLHS[lhs])...)
+	}
+	return terms
+}
+
+// canonicalConditionExpression returns the canonical form of expression, or
+// expression itself when it does not compile.
+func canonicalConditionExpression(expression string) string {
+	operator, conditions, err := compileConditionExpression(expression, "")
+	if err != nil {
+		return expression
+	}
+	return formatConditionExpression(operator, conditions)
+}
+
+// policyTypesWithOperator are the policy types whose v2 rule resource has a
+// top-level operator joining the conditions of the rule.
+var policyTypesWithOperator = map[string]bool{
+	"ACCESS_POLICY": true,
+}
+
+// policyRuleHasOperator reports whether the v2 rule resource of policyType has
+// a top-level operator. An empty policyType accepts any expression.
+func policyRuleHasOperator(policyType string) bool {
+	return policyType == "" || policyTypesWithOperator[policyType]
+}
+
+// conditionExpressionSchema returns the condition_expression attribute of the
+// v2 policy rule resources of policyType.
+func conditionExpressionSchema(policyType string) *schema.Schema {
+	description := "The conditions of the rule as an expression, for example `APP_GROUP in [\"123\"] and (SCIM_GROUP(idp=\"456\") in [\"789\"] or COUNTRY_CODE in [\"US\", \"CA\"])`."
+	conflictsWith := []string{"conditions"}
+	if policyRuleHasOperator(policyType) {
+		description += " The rule operator is derived from the expression."
+		conflictsWith = append(conflictsWith, "operator")
+	} else {
+		description += " The conditions are always joined with AND, so a top-level OR can only join comparisons."
+	}
+	return &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		ConflictsWith: conflictsWith,
+		Description:   description,
+		ValidateFunc: func(v interface{}, key string) (warnings []string, errs []error) {
+			if _, _, err := compileConditionExpression(v.(string), policyType); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %v", key, err))
+			}
+			return
+		},
+		DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+			return old != "" && canonicalConditionExpression(old) == canonicalConditionExpression(new)
+		},
+	}
+}
+
+// expandPolicyRuleConditionsV2 expands the conditions of a v2 policy rule
+// resource, from condition_expression when it is set. Name references are
+// replaced with the IDs planned in resolved_names, country aliases with the
+// countries planned in expanded_country_aliases, and the values planned in
+// pruned_references are left out.
+func expandPolicyRuleConditionsV2(d *schema.ResourceData, policyType string) ([]policysetcontrollerv2.PolicyRuleResourceConditions, error) {
+	var conditions []policysetcontrollerv2.PolicyRuleResourceConditions
+	if expression := GetString(d.Get("condition_expression")); expression != "" {
+		_, compiled, err := compileConditionExpression(expression, policyType)
+		if err != nil {
+			return nil, fmt.Errorf("condition_expression: %v", err)
+		}
+		conditions = compiled
+	} else {
+		expanded, err := ExpandPolicyConditionsV2(d)
+		if err != nil {
+			return nil, err
+		}
+		conditions = expanded
+	}
+	if err := replacePolicyOperandNames(d, conditions); err != nil {
+		return nil, err
+	}
+	if err := expandCountryAliases(d, conditions); err != nil {
+		return nil, err
+	}
+	if err := pruneDanglingReferences(d, conditions); err != nil {
+		return nil, err
+	}
+	return conditions, nil
+}
+
+// setPolicyRuleConditionsV2 sets the conditions of a v2 policy rule resource,
+// as a canonical expression when the rule is configured with
+// condition_expression. IDs resolved from name references are set back to
+// the names, countries expanded from aliases back to the aliases, and pruned
+// values are added back.
+func setPolicyRuleConditionsV2(d *schema.ResourceData, rule policysetcontrollerv2.PolicyRule) {
+	restorePolicyOperandNames(d, rule.Conditions)
+	collapseCountryAliases(d, rule.Conditions)
+	restorePrunedReferences(d, rule.Conditions)
+	if GetString(d.Get("condition_expression")) == "" {
+		_ = d.Set("conditions", flattenConditionsV2(rule.Conditions))
+		return
+	}
+	_ = d.Set("condition_expression", formatConditionExpression(rule.Operator, rule.Conditions))
+	_ = d.Set("conditions", nil)
+}
+
+// customizeConditionExpression plans the rule operator derived from
+// condition_expression, for the resources that have one.
+func customizeConditionExpression(policyType string) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		if !d.NewValueKnown("condition_expression") {
+			return nil
+		}
+		expression := GetString(d.Get("condition_expression"))
+		if expression == "" {
+			return nil
+		}
+		operator, _, err := compileConditionExpression(expression, policyType)
+		if err != nil {
+			return fmt.Errorf("condition_expression: %v", err)
+		}
+		if policyRuleHasOperator(policyType) && GetString(d.Get("operator")) != operator {
+			return d.SetNew("operator", operator)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,398 @@
+package zpa
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontrollerv2"
+)
+
+func TestLexConditionExpression(t *testing.T) {
+	tokens, err := lexConditionExpression(`SCIM_GROUP(idp="ü") in ["1", "2",] and SAML(lhs="3") == "say \"hi\""`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, token := range tokens {
+		got = append(got, token.String()+"@"+strconv.Itoa(token.column))
+	}
+	want := []string{
+		`"SCIM_GROUP"@1`, `"("@11`, `"idp"@12`, `"="@15`, `string "ü"@16`, `")"@19`,
+		`"in"@21`, `"["@24`, `string "1"@25`, `","@28`, `string "2"@30`, `","@33`, `"]"@34`,
+		`"and"@36`, `"SAML"@40`, `"("@44`, `"lhs"@45`, `"="@48`, `string "3"@49`, `")"@52`,
+		`"=="@54`, `string "say \"hi\""@57`, `end of expression@69`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got tokens\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestParseConditionExpression(t *testing.T) {
+	for _, tc := range []struct {
+		expression string
+		want       string
+	}{
+		{
+			expression: `APP in ["1"]`,
+			want:       `APP@1`,
+		},
+		{
+			expression: `((APP in ["1"]))`,
+			want:       `APP@3`,
+		},
+		// and binds tighter than or
+		{
+			expression: `APP in ["1"] or APP_GROUP in ["2"] and CLIENT_TYPE == "zpn_client_type_zapp"`,
+			want:       `OR@1(APP@1, AND@17(APP_GROUP@17, CLIENT_TYPE@40))`,
+		},
+		{
+			expression: `APP in ["1"] and APP_GROUP in ["2"] or CLIENT_TYPE == "zpn_client_type_zapp"`,
+			want:       `OR@1(AND@1(APP@1, APP_GROUP@18), CLIENT_TYPE@40)`,
+		},
+		{
+			expression: `APP in ["1"] and (APP_GROUP in ["2"] or CLIENT_TYPE == "zpn_client_type_zapp")`,
+			want:       `AND@1(APP@1, OR@19(APP_GROUP@19, CLIENT_TYPE@41))`,
+		},
+		// Keywords are case-insensitive
+		{
+			expression: `APP IN ["1"] Or APP_GROUP in ["2"]`,
+			want:       `OR@1(APP@1, APP_GROUP@17)`,
+		},
+		// Nested groups with the operator of their parent are merged into it
+		{
+			expression: `APP in ["1"] and (APP_GROUP in ["2"] and CLIENT_TYPE == "zpn_client_type_zapp")`,
+			want:       `AND@1(APP@1, APP_GROUP@19, CLIENT_TYPE@42)`,
+		},
+		{
+			expression: `(APP in ["1"] or (APP_GROUP in ["2"] or IDP == "3")) or CLIENT_TYPE == "zpn_client_type_zapp"`,
+			want:       `OR@2(APP@2, APP_GROUP@19, IDP@41, CLIENT_TYPE@57)`,
+		},
+		{
+			expression: `APP in ["1"] or (APP_GROUP in ["2"] and (IDP == "3" and CLIENT_TYPE == "zpn_client_type_zapp"))`,
+			want:       `OR@1(APP@1, AND@18(APP_GROUP@18, IDP@42, CLIENT_TYPE@57))`,
+		},
+	} {
+		node, err := parseConditionExpression(tc.expression)
+		if err != nil {
+			t.Errorf("parseConditionExpression(%s): %v", tc.expression, err)
+			continue
+		}
+		if got := formatConditionExprTree(node); got != tc.want {
+			t.Errorf("parseConditionExpression(%s) = %s, want %s", tc.expression, got, tc.want)
+		}
+	}
+}
+
+func TestParseConditionExpression_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		expression string
+		column     int
+		message    string
+	}{
+		{``, 1, `empty expression`},
+		{`   `, 1, `empty expression`},
+		{`APP in []`, 8, `empty list of values`},
+		{`APP = "1"`, 5, `expected "in" or "==" after APP, got "="`},
+		{`APP in ["1"] and`, 17, `expected an object type, got end of expression`},
+		{`(APP in ["1"]`, 14, `expected ")", got end of expression`},
+		{`APP in ["1"] APP_GROUP in ["2"]`, 14, `unexpected "APP_GROUP", expected "and", "or" or the end of the expression`},
+		{`APP in ["1" "2"]`, 13, `expected "]", got string "2"`},
+		{`SCIM_GROUP(idp=) in ["1"]`, 16, `expected a string, got ")"`},
+		// Columns count characters, not bytes
+		{`SAML(attribute="1") == "Zürich" $`, 33, `unexpected character '$'`},
+		{`SAML(attribute="ü") == "Zürich" and APP in ["1"] or`, 52, `expected an object type, got end of expression`},
+		{`APP == "é`, 8, `unterminated string`},
+		{`APP == "é\q"`, 8, `invalid string "é\q"`},
+	} {
+		_, err := parseConditionExpression(tc.expression)
+		checkConditionExpressionError(t, "parseConditionExpression", tc.expression, err, tc.column, tc.message)
+	}
+}
+
+func TestCompileConditionExpression(t *testing.T) {
+	for _, tc := range []struct {
+		expression   string
+		policyType   string
+		wantOperator string
+		want         []policysetcontrollerv2.PolicyRuleResourceConditions
+	}{
+		{
+			expression:   `APP_GROUP in ["1"] and (SCIM_GROUP(idp="2") in ["3"] or COUNTRY_CODE in ["US", "CA"])`,
+			policyType:   policyTypeAccess,
+			wantOperator: "AND",
+			want: []policysetcontrollerv2.PolicyRuleResourceConditions{
+				{Operator: "OR", Operands: []policysetcontrollerv2.PolicyRuleResourceOperands{
+					{ObjectType: "APP_GROUP", Values: []string{"1"}},
+				}},
+				{Operator: "OR", Operands: []policysetcontrollerv2.PolicyRuleResourceOperands{
+					{ObjectType: "SCIM_GROUP", EntryValuesLHSRHS: []policysetcontrollerv2.OperandsResourceLHSRHSValue{{LHS: "2", RHS: "3"}}},
+					{ObjectType: "COUNTRY_CODE", EntryValuesLHSRHS: []policysetcontrollerv2.OperandsResourceLHSRHSValue{{LHS: "US", RHS: "true"}, {LHS: "CA", RHS: "true"}}},
+				}},
+			},
+		},
+		// The top-level or becomes the rule operator
+		{
+			expression:   `APP in ["1"] or APP_GROUP == "2" and CLIENT_TYPE == "zpn_client_type_zapp"`,
+			policyType:   policyTypeAccess,
+			wantOperator: "OR",
+			want: []policysetcontrollerv2.PolicyRuleResourceConditions{
+				{Operator: "OR", Operands: []policysetcontrollerv2.PolicyRuleResourceOperands{
+					{ObjectType: "APP", Values: []string{"1"}},
+				}},
+				{Operator: "AND", Operands: []policysetcontrollerv2.PolicyRuleResourceOperands{
+					{ObjectType: "APP_GROUP", Values: []string{"2"}},
+					{ObjectType: "CLIENT_TYPE", Values: []string{"zpn_client_type_zapp"}},
+				}},
+			},
+		},
+		// Without a rule operator, a top-level or of comparisons is one condition
+		{
+			expression:   `APP in ["1"] or APP_GROUP in ["2"] or APP == "3"`,
+			policyType:   policyTypeTimeout,
+			wantOperator: "AND",
+			want: []policysetcontrollerv2.PolicyRuleResourceConditions{
+				{Operator: "OR", Operands: []policysetcontrollerv2.PolicyRuleResourceOperands{
+					{ObjectType: "APP", Values: []string{"1", "3"}},
+					{ObjectType: "APP_GROUP", Values: []string{"2"}},
+				}},
+			},
+		},
+		// Repeated values are listed once, an lhs the object type allows alone
+		// can be left out
+		{
+			expression:   `RISK_FACTOR_TYPE in ["HIGH", "HIGH"] or RISK_FACTOR_TYPE(lhs="ZIA") == "LOW" or SAML(attribute="4") == "say \"hi\""`,
+			policyType:   policyTypeAccess,
+			wantOperator: "OR",
+			want: []policysetcontrollerv2.PolicyRuleResourceConditions{
+				{Operator: "OR", Operands: []policysetcontrollerv2.PolicyRuleResourceOperands{
+					{ObjectType: "RISK_FACTOR_TYPE", EntryValuesLHSRHS: []policysetcontrollerv2.OperandsResourceLHSRHSValue{{LHS: "ZIA", RHS: "HIGH"}}},
+				}},
+				{Operator: "OR", Operands: []policysetcontrollerv2.PolicyRuleResourceOperands{
+					{ObjectType: "RISK_FACTOR_TYPE", EntryValuesLHSRHS: []policysetcontrollerv2.OperandsResourceLHSRHSValue{{LHS: "ZIA", RHS: "LOW"}}},
+				}},
+				{Operator: "OR", Operands: []policysetcontrollerv2.PolicyRuleResourceOperands{
+					{ObjectType: "SAML", EntryValuesLHSRHS: []policysetcontrollerv2.OperandsResourceLHSRHSValue{{LHS: "4", RHS: `say "hi"`}}},
+				}},
+			},
+		},
+	} {
+		operator, conditions, err := compileConditionExpression(tc.expression, tc.policyType)
+		if err != nil {
+			t.Errorf("compileConditionExpression(%s, %s): %v", tc.expression, tc.policyType, err)
+			continue
+		}
+		if operator != tc.wantOperator {
+			t.Errorf("compileConditionExpression(%s, %s) operator = %s, want %s", tc.expression, tc.policyType, operator, tc.wantOperator)
+		}
+		if !reflect.DeepEqual(conditions, tc.want) {
+			t.Errorf("compileConditionExpression(%s, %s) conditions =\n%+v\nwant\n%+v", tc.expression, tc.policyType, conditions, tc.want)
+		}
+	}
+}
+
+func TestCompileConditionExpression_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		expression string
+		policyType string
+		column     int
+		message    string
+	}{
+		{
+			expression: `APP in ["1"] and (APP_GROUP in ["2"] or (CLIENT_TYPE == "zpn_client_type_zapp" and APP in ["3"]))`,
+			policyType: policyTypeAccess,
+			column:     42,
+			message:    `groups can only contain comparisons, conditions cannot be nested more than one level deep`,
+		},
+		{
+			expression: `APP_GROUP in ["1"] or (APP in ["1", "2"] and CLIENT_TYPE == "zpn_client_type_zapp")`,
+			policyType: policyTypeAccess,
+			column:     24,
+			message:    `APP matches any of a list of values and cannot be used in an "and" group, compare each value with == instead`,
+		},
+		{
+			expression: `APP in ["1"] or (APP_GROUP in ["2"] and APP in ["3"])`,
+			policyType: policyTypeTimeout,
+			column:     18,
+			message:    `TIMEOUT_POLICY rules always AND their conditions, a top-level "or" can only join comparisons`,
+		},
+		{
+			expression: `USER_PORTAL in ["1"]`,
+			policyType: policyTypeAccess,
+			column:     1,
+			message:    `object type USER_PORTAL is not supported by ACCESS_POLICY rules`,
+		},
+		{
+			expression: `APP(lhs="1") in ["2"]`,
+			column:     9,
+			message:    `APP does not take an argument`,
+		},
+		{
+			expression: `SCIM_GROUP in ["1"]`,
+			column:     1,
+			message:    `SCIM_GROUP requires an IdP ID as argument, as in SCIM_GROUP(idp="...")`,
+		},
+		{
+			expression: `SCIM_GROUP(attribute="1") in ["2"]`,
+			column:     12,
+			message:    `unknown argument "attribute" of SCIM_GROUP, expected "idp"`,
+		},
+		// Columns count characters, not bytes
+		{
+			expression: `SAML(attribute="ü") == "x" or ÉTAT == "1"`,
+			column:     31,
+			message:    `unknown object type "ÉTAT"`,
+		},
+		{
+			expression: `SAML(attribute="ü") == "x" and CLIENT_TYPE == "ünknown"`,
+			column:     47,
+			message:    `CLIENT_TYPE value must be one of`,
+		},
+		{
+			expression: `SAML(attribute="ü") == "x" and COUNTRY_CODE in ["Ü"]`,
+			column:     49,
+			message:    `COUNTRY_CODE value 'Ü' is not a valid ISO-3166 Alpha-2 country code`,
+		},
+	} {
+		_, _, err := compileConditionExpression(tc.expression, tc.policyType)
+		checkConditionExpressionError(t, "compileConditionExpression", tc.expression, err, tc.column, tc.message)
+	}
+}
+
+func TestFormatConditionExpression(t *testing.T) {
+	for _, tc := range []struct {
+		operator   string
+		conditions []policysetcontrollerv2.PolicyRuleResourceConditions
+		want       string
+	}{
+		{
+			operator: "AND",
+			conditions: []policysetcontrollerv2.PolicyRuleResourceConditions{
+				{Operator: "OR", Operands: []policysetcontrollerv2.PolicyRuleResourceOperands{
+					{ObjectType: "APP_GROUP", Values: []string{"1"}},
+				}},
+				{Operator: "OR", Operands: []policysetcontrollerv2.PolicyRuleResourceOperands{
+					{ObjectType: "SCIM_GROUP", EntryValuesLHSRHS: []policysetcontrollerv2.OperandsResourceLHSRHSValue{{LHS: "2", RHS: "3"}}},
+					{ObjectType: "COUNTRY_CODE", EntryValuesLHSRHS: []policysetcontrollerv2.OperandsResourceLHSRHSValue{{LHS: "US", RHS: "true"}, {LHS: "CA", RHS: "true"}}},
+				}},
+			},
+			want: `(COUNTRY_CODE in ["CA", "US"] or SCIM_GROUP(idp="2") == "3") and APP_GROUP == "1"`,
+		},
+		// In and conditions each value is compared on its own, a condition
+		// alone is not parenthesized and the rule operator defaults to and
+		{
+			conditions: []policysetcontrollerv2.PolicyRuleResourceConditions{
+				{Operator: "and", Operands: []policysetcontrollerv2.PolicyRuleResourceOperands{
+					{ObjectType: "APP", Values: []string{"2", "1"}},
+				}},
+			},
+			want: `APP == "1" and APP == "2"`,
+		},
+		// Conditions without an operator are or, an lhs the object type
+		// allows alone is left out, empty conditions are skipped
+		{
+			operator: "OR",
+			conditions: []policysetcontrollerv2.PolicyRuleResourceConditions{
+				{Operands: []policysetcontrollerv2.PolicyRuleResourceOperands{
+					{ObjectType: "RISK_FACTOR_TYPE", EntryValuesLHSRHS: []policysetcontrollerv2.OperandsResourceLHSRHSValue{{LHS: "ZIA", RHS: "LOW"}, {LHS: "ZIA", RHS: "HIGH"}}},
+					{ObjectType: "SAML", EntryValuesLHSRHS: []policysetcontrollerv2.OperandsResourceLHSRHSValue{{LHS: "5", RHS: "b"}, {LHS: "4", RHS: "a"}}},
+				}},
+				{Operator: "OR"},
+				{Operator: "OR", Operands: []policysetcontrollerv2.PolicyRuleResourceOperands{
+					{ObjectType: "PLATFORM", EntryValuesLHSRHS: []policysetcontrollerv2.OperandsResourceLHSRHSValue{{LHS: "windows", RHS: "true"}, {LHS: "mac", RHS: "true"}}},
+				}},
+			},
+			want: `(RISK_FACTOR_TYPE in ["HIGH", "LOW"] or SAML(attribute="4") == "a" or SAML(attribute="5") == "b") or PLATFORM in ["mac", "windows"]`,
+		},
+	} {
+		if got := formatConditionExpression(tc.operator, tc.conditions); got != tc.want {
+			t.Errorf("formatConditionExpression(%s, %+v) =\n%s\nwant\n%s", tc.operator, tc.conditions, got, tc.want)
+		}
+	}
+}
+
+// The DiffSuppressFunc of condition_expression compares canonical forms, so
+// formatting a compiled expression must give an expression that compiles and
+// formats to itself.
+func TestFormatConditionExpression_RoundTrip(t *testing.T) {
+	for _, tc := range []struct {
+		expression string
+		equivalent []string
+	}{
+		{
+			expression: `APP_GROUP in ["1"] and (SCIM_GROUP(idp="2") in ["3"] or COUNTRY_CODE in ["US", "CA"])`,
+			equivalent: []string{
+				`(COUNTRY_CODE in ["CA", "US"] or SCIM_GROUP(idp="2") == "3") and APP_GROUP == "1"`,
+				`(SCIM_GROUP(lhs="2") in ["3"] or COUNTRY_CODE == "CA" or COUNTRY_CODE == "US") AND APP_GROUP in ["1",]`,
+			},
+		},
+		{
+			expression: `(APP == "1" and APP == "2") or CLIENT_TYPE == "zpn_client_type_zapp"`,
+			equivalent: []string{
+				`CLIENT_TYPE in ["zpn_client_type_zapp"] or (APP == "2" and APP == "1")`,
+			},
+		},
+		{
+			expression: `APP in ["1", "2"] and POSTURE(udid="3") == "false" and TRUSTED_NETWORK(network="4") == "true"`,
+			equivalent: []string{
+				`TRUSTED_NETWORK(network="4") == "true" and APP in ["2", "1"] and POSTURE(udid="3") == "false"`,
+			},
+		},
+		{
+			expression: `RISK_FACTOR_TYPE in ["HIGH", "LOW"] or SAML(attribute="4") == "R and D" or PLATFORM == "mac"`,
+		},
+		{
+			expression: `APP in ["1"] or (APP_GROUP == "2" and CHROME_ENTERPRISE(lhs="managed") == "true")`,
+		},
+	} {
+		operator, conditions, err := compileConditionExpression(tc.expression, "")
+		if err != nil {
+			t.Errorf("compileConditionExpression(%s): %v", tc.expression, err)
+			continue
+		}
+		formatted := formatConditionExpression(operator, conditions)
+		operator, conditions, err = compileConditionExpression(formatted, "")
+		if err != nil {
+			t.Errorf("compileConditionExpression(%s), formatted from %s: %v", formatted, tc.expression, err)
+			continue
+		}
+		if again := formatConditionExpression(operator, conditions); again != formatted {
+			t.Errorf("%s formats to\n%s\nwhich formats to\n%s", tc.expression, formatted, again)
+		}
+		if got := canonicalConditionExpression(tc.expression); got != formatted {
+			t.Errorf("canonicalConditionExpression(%s) = %s, want %s", tc.expression, got, formatted)
+		}
+		for _, expression := range tc.equivalent {
+			if got := canonicalConditionExpression(expression); got != formatted {
+				t.Errorf("canonicalConditionExpression(%s) = %s, want %s", expression, got, formatted)
+			}
+		}
+	}
+}
+
+func checkConditionExpressionError(t *testing.T, function, expression string, err error, column int, message string) {
+	t.Helper()
+	var exprErr *conditionExpressionError
+	if !errors.As(err, &exprErr) {
+		t.Errorf("%s(%s): got error %v, want an error at column %d", function, expression, err, column)
+		return
+	}
+	if exprErr.column != column || !strings.HasPrefix(exprErr.message, message) {
+		t.Errorf("%s(%s): got error %q, want %q at column %d", function, expression, err, message, column)
+	}
+}
+
+// formatConditionExprTree returns node as OPERATOR@column(children...), with
+// comparisons as OBJECT_TYPE@column.
+func formatConditionExprTree(node *conditionExprNode) string {
+	if node.comparison != nil {
+		return node.comparison.objectType + "@" + strconv.Itoa(node.comparison.column)
+	}
+	children := make([]string, len(node.children))
+	for i, child := range node.children {
+		children[i] = formatConditionExprTree(child)
+	}
+	return node.operator + "@" + strconv.Itoa(node.column) + "(" + strings.Join(children, ", ") + ")"
+}
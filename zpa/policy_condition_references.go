@@ -0,0 +1,455 @@
+package zpa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/errorx"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/idpcontroller"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontrollerv2"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/scimattributeheader"
+)
+
+// maxConcurrentReferenceLookups bounds the number of lookups a single plan of
+// a policy rule sends in parallel.
+const maxConcurrentReferenceLookups = 5
+
+// policyReference is a single tenant object referenced by a v2 policy
+// condition operand. key identifies the lookup for deduplication and caching,
+// location the operand in the rule, as in conditions[1].operands[0].
+type policyReference struct {
+	key        string
+	objectType string
+	field      string
+	value      string
+	location   string
+	lookup     func(ctx context.Context, c *Client, cache *referenceLookupCache) error
+}
+
+func (r policyReference) describe() string {
+	location := r.location
+	if location == "" {
+		location = "conditions.operands"
+	}
+	return fmt.Sprintf("%s (object_type = %q) %s = %q", location, r.objectType, r.field, r.value)
+}
+
+// policyOperandLocation returns the location of an operand of the conditions
+// of a policy rule, as in conditions[1].operands[0].
+func policyOperandLocation(condition, operand int) string {
+	return fmt.Sprintf("conditions[%d].operands[%d]", condition, operand)
+}
+
+// referenceLookupCache memoizes reference lookups for the lifetime of the
+// provider process, so that rules sharing the same IdP, SCIM groups or
+// segments only look each of them up once per plan. Objects that can be
+// listed are listed once per object type and microtenant.
+type referenceLookupCache struct {
+	mu       sync.Mutex
+	lookups  map[string]*referenceLookup
+	listings map[string]*referenceListing
+	// Owning IdP of each SCIM attribute header resolved so far
+	scimAttributeIdPs map[string]string
+	// IDs of the name references of operands resolved so far
+	resolvedNames map[string]string
+}
+
+type referenceLookup struct {
+	ready chan struct{}
+	err   error
+}
+
+type referenceListing struct {
+	ready chan struct{}
+	ids   map[string]bool
+	err   error
+}
+
+func newReferenceLookupCache() *referenceLookupCache {
+	return &referenceLookupCache{
+		lookups:           make(map[string]*referenceLookup),
+		listings:          make(map[string]*referenceListing),
+		scimAttributeIdPs: make(map[string]string),
+		resolvedNames:     make(map[string]string),
+	}
+}
+
+func (c *referenceLookupCache) setSCIMAttributeIdP(attributeID, idpID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.scimAttributeIdPs[attributeID] = idpID
+}
+
+func (c *referenceLookupCache) scimAttributeIdP(attributeID string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.scimAttributeIdPs[attributeID]
+}
+
+func (c *referenceLookupCache) setResolvedName(key, id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.resolvedNames[key] = id
+}
+
+func (c *referenceLookupCache) resolvedName(key string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.resolvedNames[key]
+}
+
+func (c *referenceLookupCache) do(key string, lookup func() error) error {
+	c.mu.Lock()
+	entry, found := c.lookups[key]
+	if !found {
+		entry = &referenceLookup{ready: make(chan struct{})}
+		c.lookups[key] = entry
+	}
+	c.mu.Unlock()
+
+	if found {
+		<-entry.ready
+		return entry.err
+	}
+
+	entry.err = lookup()
+	if entry.err != nil && !isPolicyReferenceNotFound(entry.err) {
+		// Only remember objects that exist or do not exist, not failures
+		// such as rate limits, timeouts or cancellations.
+		c.mu.Lock()
+		delete(c.lookups, key)
+		c.mu.Unlock()
+	}
+	close(entry.ready)
+	return entry.err
+}
+
+// listing returns the listing of key, listing the objects with list unless
+// another lookup already did or is doing it. fresh reports whether this call
+// listed them.
+func (c *referenceLookupCache) listing(key string, list func() ([]string, error)) (l *referenceListing, fresh bool) {
+	c.mu.Lock()
+	l, found := c.listings[key]
+	if !found {
+		l = &referenceListing{ready: make(chan struct{})}
+		c.listings[key] = l
+	}
+	c.mu.Unlock()
+
+	if found {
+		<-l.ready
+		return l, false
+	}
+
+	ids, err := list()
+	if err != nil {
+		// Listing failures are not remembered, see do.
+		c.mu.Lock()
+		if c.listings[key] == l {
+			delete(c.listings, key)
+		}
+		c.mu.Unlock()
+		l.err = err
+	} else {
+		l.ids = make(map[string]bool, len(ids))
+		for _, id := range ids {
+			l.ids[id] = true
+		}
+	}
+	close(l.ready)
+	return l, true
+}
+
+// listed reports whether id is one of the objects listed by list. Lookups
+// share the listing of key. A listing made before the lookup that misses id is
+// made again, as the object may have been created since, for example earlier
+// in the same apply.
+func (c *referenceLookupCache) listed(key, id string, list func() ([]string, error)) (bool, error) {
+	l, fresh := c.listing(key, list)
+	if l.err == nil && !l.ids[id] && !fresh {
+		c.mu.Lock()
+		if c.listings[key] == l {
+			delete(c.listings, key)
+		}
+		c.mu.Unlock()
+		l, _ = c.listing(key, list)
+	}
+	if l.err != nil {
+		// A listing that fails, even with "not found", says nothing about
+		// the referenced object.
+		return false, fmt.Errorf("could not list the objects: %v", l.err)
+	}
+	return l.ids[id], nil
+}
+
+// errPolicyReferenceNotFound marks lookup errors that say the referenced
+// object does not exist.
+var errPolicyReferenceNotFound = errors.New("not found")
+
+// isPolicyReferenceNotFound reports whether err says that the referenced
+// object does not exist: a "not found" API error, or a lookup that listed the
+// objects without finding it. Any other failure, such as a rate limit, a
+// server error, a timeout or a cancelled context, says nothing about the
+// object.
+func isPolicyReferenceNotFound(err error) bool {
+	var respErr *errorx.ErrorResponse
+	if errors.As(err, &respErr) {
+		return respErr.IsObjectNotFound()
+	}
+	return errors.Is(err, errPolicyReferenceNotFound)
+}
+
+// policyReferenceSearchError returns the error of an SDK lookup that searches
+// a list of objects, such as GetByName. Those report a missing object with a
+// plain error, which is marked as not found; API, network and context errors
+// are returned as is.
+func policyReferenceSearchError(err error) error {
+	var respErr *errorx.ErrorResponse
+	var netErr net.Error
+	if err == nil || errors.As(err, &respErr) || errors.As(err, &netErr) ||
+		errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	return fmt.Errorf("%w: %v", errPolicyReferenceNotFound, err)
+}
+
+// exists checks that value, on this side of v, references an existing tenant
+// object. Sides with a listing are checked against a single listing of the
+// objects per scope, shared through cache when set; scope is the microtenant
+// of microtenant scoped operands.
+func (s operandSide) exists(ctx context.Context, c *Client, cache *referenceLookupCache, scope string, v operandValue, value string) error {
+	if s.list == nil {
+		return s.lookup(ctx, c, scope, v)
+	}
+	if cache == nil {
+		cache = newReferenceLookupCache()
+	}
+	// Listings are shared by the lookups of other rules, so they are not
+	// cancelled with the plan of this one.
+	listCtx := context.WithoutCancel(ctx)
+	listed, err := cache.listed(strings.Join([]string{"LIST", s.description, scope}, "|"), value, func() ([]string, error) {
+		return s.list(listCtx, c, scope)
+	})
+	if err != nil {
+		return err
+	}
+	if !listed {
+		return fmt.Errorf("%w: %q is not %s of the tenant", errPolicyReferenceNotFound, value, s.description)
+	}
+	return nil
+}
+
+// operandValueReferences returns the tenant references of a single operand
+// value, using the lookups of the operand registry. field is "values" for ID
+// and enum operands and "entry_values" for entry operands.
+func operandValueReferences(t *policyOperandType, v operandValue, microTenantID string) []policyReference {
+	var refs []policyReference
+	add := func(side operandSide, field, value string) {
+		// Name references are looked up when they are resolved.
+		if !side.validatedAgainstTenant() || value == "" || isPolicyOperandName(value) {
+			return
+		}
+		scope := ""
+		if t.microtenantScoped {
+			scope = microTenantID
+		}
+		key := strings.Join([]string{t.objectType, field, scope, v.lhs, strings.ToLower(v.rhs)}, "|")
+		if field == "entry_values.lhs" {
+			key = strings.Join([]string{t.objectType, field, scope, v.lhs}, "|")
+		}
+		refs = append(refs, policyReference{
+			key:        key,
+			objectType: t.objectType,
+			field:      field,
+			value:      value,
+			lookup: func(ctx context.Context, c *Client, cache *referenceLookupCache) error {
+				return side.exists(ctx, c, cache, scope, v, value)
+			},
+		})
+	}
+
+	if t.valueKind != operandValueEntry {
+		add(t.rhs, "values", v.rhs)
+		return refs
+	}
+	add(t.lhs, "entry_values.lhs", v.lhs)
+	if v.lhs != "" {
+		add(t.rhs, "entry_values.rhs", v.rhs)
+	}
+	return refs
+}
+
+// collectPolicyReferences returns the deduplicated tenant references of the
+// conditions of a v2 policy rule. A reference used by several operands is
+// located at the first of them. Offline checks are left to
+// ValidatePolicyRuleConditions.
+func collectPolicyReferences(conditions []policysetcontrollerv2.PolicyRuleResourceConditions, microTenantID string) []policyReference {
+	seen := map[string]bool{}
+	var refs []policyReference
+	add := func(t *policyOperandType, v operandValue, location string) {
+		for _, ref := range operandValueReferences(t, v, microTenantID) {
+			if seen[ref.key] {
+				continue
+			}
+			seen[ref.key] = true
+			ref.location = location
+			refs = append(refs, ref)
+		}
+	}
+
+	for i, condition := range conditions {
+		for j, operand := range condition.Operands {
+			t, ok := lookupPolicyOperandType(operand.ObjectType)
+			if !ok {
+				continue
+			}
+			location := policyOperandLocation(i, j)
+			if t.valueKind != operandValueEntry {
+				for _, id := range operand.Values {
+					add(t, operandValue{lhs: "id", rhs: id}, location)
+				}
+				continue
+			}
+			for _, ev := range operand.EntryValuesLHSRHS {
+				add(t, operandValue{lhs: ev.LHS, rhs: ev.RHS}, location)
+			}
+		}
+	}
+	return refs
+}
+
+// scimAttributeIdP returns the ID of the IdP that owns the SCIM attribute
+// header. v2 SCIM operands only carry the attribute ID, so each IdP is tried
+// in turn. The result is memoized like every other lookup.
+func (c *Client) scimAttributeIdP(ctx context.Context, attributeID string) (string, error) {
+	cache := c.referenceCache
+	if cache == nil {
+		cache = newReferenceLookupCache()
+	}
+	err := cache.do("SCIM_ATTRIBUTE_IDP|"+attributeID, func() error {
+		idps, _, err := idpcontroller.GetAll(ctx, c.Service)
+		if err != nil {
+			return err
+		}
+		for _, idp := range idps {
+			_, _, err := scimattributeheader.Get(ctx, c.Service, idp.ID, attributeID)
+			if err == nil {
+				cache.setSCIMAttributeIdP(attributeID, idp.ID)
+				return nil
+			}
+			if !isPolicyReferenceNotFound(err) {
+				return err
+			}
+		}
+		return fmt.Errorf("%w: no IdP has a SCIM attribute with ID %s", errPolicyReferenceNotFound, attributeID)
+	})
+	if err != nil {
+		return "", err
+	}
+	return cache.scimAttributeIdP(attributeID), nil
+}
+
+// validatePolicyConditionReferences is the CustomizeDiff of the v2 policy
+// rule resources. It resolves every ID referenced in conditions or
+// condition_expression against the tenant during plan, so that a typo fails
+// the plan instead of the apply.
+func validatePolicyConditionReferences(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	zClient, ok := meta.(*Client)
+	if !ok || zClient == nil || zClient.referenceCache == nil {
+		return nil
+	}
+	// Values that depend on resources not created yet are checked on the
+	// next plan.
+	if !d.NewValueKnown("conditions") || !d.NewValueKnown("condition_expression") || !d.NewValueKnown("microtenant_id") {
+		return nil
+	}
+	if d.Id() != "" && !d.HasChange("conditions") && !d.HasChange("condition_expression") && !d.HasChange("microtenant_id") {
+		return nil
+	}
+
+	conditions, err := plannedPolicyConditionsV2(d)
+	if err != nil || len(conditions) == 0 {
+		return err
+	}
+	// Values planned in pruned_references are not sent to the API.
+	if err := removePrunedValues(conditions, prunedReferenceSet(d.Get("pruned_references"))); err != nil {
+		return err
+	}
+
+	return checkPolicyReferences(ctx, zClient, collectPolicyReferences(conditions, GetString(d.Get("microtenant_id"))))
+}
+
+// checkPolicyReferences looks up refs with the reference cache of the client
+// and returns an error listing the references to objects that do not exist.
+// Other lookup failures are only logged.
+func checkPolicyReferences(ctx context.Context, zClient *Client, refs []policyReference) error {
+	if len(refs) == 0 {
+		return nil
+	}
+	log.Printf("[DEBUG] Validating %d policy condition references at plan time", len(refs))
+
+	errs := resolvePolicyReferences(ctx, zClient, zClient.referenceCache, refs)
+	var problems []string
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		if !isPolicyReferenceNotFound(err) {
+			log.Printf("[WARN] Could not validate %s at plan time: %v", refs[i].describe(), err)
+			continue
+		}
+		problems = append(problems, fmt.Sprintf("%s does not reference an existing object: %v", refs[i].describe(), err))
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return fmt.Errorf("invalid policy rule conditions:\n  - %s", strings.Join(problems, "\n  - "))
+}
+
+// plannedPolicyConditionsV2 returns the planned conditions of a v2 policy
+// rule resource, from conditions or condition_expression. Expressions that do
+// not compile are reported by the validation of the attribute and return no
+// conditions.
+func plannedPolicyConditionsV2(d *schema.ResourceDiff) ([]policysetcontrollerv2.PolicyRuleResourceConditions, error) {
+	if expression := GetString(d.Get("condition_expression")); expression != "" {
+		_, conditions, err := compileConditionExpression(expression, "")
+		if err != nil {
+			return nil, nil
+		}
+		return conditions, nil
+	}
+	conditionsSet, ok := d.Get("conditions").(*schema.Set)
+	if !ok || conditionsSet.Len() == 0 {
+		return nil, nil
+	}
+	return expandPolicyConditionSetV2(conditionsSet)
+}
+
+// resolvePolicyReferences looks up refs with bounded concurrency and returns
+// the error of each of them, in the order of refs.
+func resolvePolicyReferences(ctx context.Context, zClient *Client, cache *referenceLookupCache, refs []policyReference) []error {
+	errs := make([]error, len(refs))
+	sem := make(chan struct{}, maxConcurrentReferenceLookups)
+	var wg sync.WaitGroup
+	for i, ref := range refs {
+		wg.Add(1)
+		go func(i int, ref policyReference) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			errs[i] = cache.do(ref.key, func() error {
+				return ref.lookup(ctx, zClient, cache)
+			})
+		}(i, ref)
+	}
+	wg.Wait()
+	return errs
+}
@@ -0,0 +1,195 @@
+package zpa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontrollerv2"
+)
+
+func TestPolicyReferenceSearchError(t *testing.T) {
+	timeout := &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{IsTimeout: true}}
+	for name, tc := range map[string]struct {
+		err      error
+		notFound bool
+	}{
+		"missing object":    {err: errors.New("no application named 'finance' was found"), notFound: true},
+		"network error":     {err: timeout},
+		"wrapped network":   {err: fmt.Errorf("request failed: %w", timeout)},
+		"cancelled context": {err: context.Canceled},
+		"deadline exceeded": {err: fmt.Errorf("request failed: %w", context.DeadlineExceeded)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if got := isPolicyReferenceNotFound(policyReferenceSearchError(tc.err)); got != tc.notFound {
+				t.Errorf("isPolicyReferenceNotFound = %v, want %v", got, tc.notFound)
+			}
+		})
+	}
+	if policyReferenceSearchError(nil) != nil {
+		t.Error("expected no error")
+	}
+}
+
+func TestReferenceLookupCache_OnlyRemembersResults(t *testing.T) {
+	cache := newReferenceLookupCache()
+	calls := 0
+	lookup := func(err error) func() error {
+		return func() error {
+			calls++
+			return err
+		}
+	}
+
+	for _, err := range []error{context.DeadlineExceeded, errors.New("unexpected failure")} {
+		calls = 0
+		key := err.Error()
+		cache.do(key, lookup(err))
+		cache.do(key, lookup(err))
+		if calls != 2 {
+			t.Errorf("%v: expected the failure not to be cached, got %d lookups", err, calls)
+		}
+	}
+
+	calls = 0
+	missing := fmt.Errorf("%w: no IdP has a SCIM attribute with ID 1", errPolicyReferenceNotFound)
+	cache.do("missing", lookup(missing))
+	if err := cache.do("missing", lookup(nil)); !isPolicyReferenceNotFound(err) || calls != 1 {
+		t.Errorf("expected the missing object to be cached, got %v after %d lookups", err, calls)
+	}
+
+	calls = 0
+	cache.do("found", lookup(nil))
+	if err := cache.do("found", lookup(missing)); err != nil || calls != 1 {
+		t.Errorf("expected the object to be cached, got %v after %d lookups", err, calls)
+	}
+}
+
+func TestReferenceLookupCache_Listed(t *testing.T) {
+	cache := newReferenceLookupCache()
+	var mu sync.Mutex
+	lists := 0
+	ids := []string{"1", "2"}
+	list := func() ([]string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		lists++
+		return ids, nil
+	}
+
+	// Concurrent lookups share a single listing
+	var wg sync.WaitGroup
+	for _, id := range []string{"1", "2", "1", "2"} {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			if listed, err := cache.listed("APP", id, list); !listed || err != nil {
+				t.Errorf("%s: got %v, %v", id, listed, err)
+			}
+		}(id)
+	}
+	wg.Wait()
+	if lists != 1 {
+		t.Errorf("expected a single listing, got %d", lists)
+	}
+
+	// A missing ID is listed again, to find objects created since
+	ids = []string{"1", "2", "3"}
+	if listed, err := cache.listed("APP", "3", list); !listed || err != nil || lists != 2 {
+		t.Errorf("got %v, %v after %d listings, want the new object to be found", listed, err, lists)
+	}
+	if listed, err := cache.listed("APP", "4", list); listed || err != nil || lists != 3 {
+		t.Errorf("got %v, %v after %d listings, want a missing object", listed, err, lists)
+	}
+	if listed, _ := cache.listed("APP", "1", list); !listed || lists != 3 {
+		t.Errorf("expected the listing to be reused, got %d listings", lists)
+	}
+
+	// Failed listings are not remembered, and say nothing about the object
+	failure := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	_, err := cache.listed("APP_GROUP", "1", func() ([]string, error) { return nil, failure })
+	if err == nil || isPolicyReferenceNotFound(err) {
+		t.Errorf("got %v, want a listing failure", err)
+	}
+	if listed, err := cache.listed("APP_GROUP", "1", list); !listed || err != nil {
+		t.Errorf("expected the failed listing to be made again, got %v, %v", listed, err)
+	}
+}
+
+func TestOperandSideExists(t *testing.T) {
+	cache := newReferenceLookupCache()
+	var scopes []string
+	side := operandSide{description: "an application segment ID", list: func(ctx context.Context, c *Client, microTenantID string) ([]string, error) {
+		scopes = append(scopes, microTenantID)
+		if microTenantID == "" {
+			return []string{"101", "102"}, nil
+		}
+		return []string{"201"}, nil
+	}}
+
+	for _, tc := range []struct {
+		scope, value string
+		found        bool
+	}{
+		{value: "101", found: true},
+		{value: "102", found: true},
+		{value: "201"},
+		{scope: "216199618143320419", value: "201", found: true},
+		{scope: "216199618143320419", value: "101"},
+	} {
+		err := side.exists(context.Background(), nil, cache, tc.scope, operandValue{rhs: tc.value}, tc.value)
+		if tc.found && err != nil {
+			t.Errorf("%s in %q: unexpected error %v", tc.value, tc.scope, err)
+		}
+		if !tc.found && !isPolicyReferenceNotFound(err) {
+			t.Errorf("%s in %q: got %v, want a missing object", tc.value, tc.scope, err)
+		}
+	}
+	// Missing IDs list again, the others share one listing per microtenant
+	if want := []string{"", "", "216199618143320419", "216199618143320419"}; fmt.Sprint(scopes) != fmt.Sprint(want) {
+		t.Errorf("listed %q, want %q", scopes, want)
+	}
+}
+
+func TestCheckPolicyReferences_Location(t *testing.T) {
+	conditions := []policysetcontrollerv2.PolicyRuleResourceConditions{
+		{Operator: "OR", Operands: []policysetcontrollerv2.PolicyRuleResourceOperands{
+			{ObjectType: "APP", Values: []string{"101"}},
+		}},
+		{Operator: "OR", Operands: []policysetcontrollerv2.PolicyRuleResourceOperands{
+			{ObjectType: "APP_GROUP", Values: []string{"201"}},
+			{ObjectType: "APP", Values: []string{"101", "102"}},
+		}},
+	}
+	refs := collectPolicyReferences(conditions, "")
+	for i := range refs {
+		found := refs[i].value == "201"
+		refs[i].lookup = func(ctx context.Context, c *Client, cache *referenceLookupCache) error {
+			if found {
+				return nil
+			}
+			return fmt.Errorf("%w: no application with this ID", errPolicyReferenceNotFound)
+		}
+	}
+
+	err := checkPolicyReferences(context.Background(), &Client{referenceCache: newReferenceLookupCache()}, refs)
+	if err == nil {
+		t.Fatal("expected an error for the missing applications")
+	}
+	for _, want := range []string{
+		// A value used by several operands is reported at the first of them
+		`conditions[0].operands[0] (object_type = "APP") values = "101" does not reference an existing object`,
+		`conditions[1].operands[1] (object_type = "APP") values = "102" does not reference an existing object`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %v", want, err)
+		}
+	}
+	if strings.Contains(err.Error(), `"201"`) {
+		t.Errorf("expected no error for the existing application segment group, got %v", err)
+	}
+}
@@ -0,0 +1,348 @@
+package zpa
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontrollerv2"
+)
+
+// countryAliasPrefix marks COUNTRY_CODE values that stand for a list of
+// countries, as in lhs = "@EU".
+const countryAliasPrefix = "@"
+
+// countryAliasesJSON is the dataset of the built-in country aliases. Bump its
+// version whenever the countries of an alias change: rules using the alias
+// are updated on the next apply.
+//
+//go:embed country_aliases.json
+var countryAliasesJSON []byte
+
+type countryAliasDataset struct {
+	Version string `json:"version"`
+	Aliases map[string]struct {
+		Description string   `json:"description"`
+		Countries   []string `json:"countries"`
+	} `json:"aliases"`
+}
+
+var builtinCountryAliases = loadCountryAliases(countryAliasesJSON)
+
+var countryAliasNamePattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+func loadCountryAliases(b []byte) countryAliasDataset {
+	var dataset countryAliasDataset
+	if err := json.Unmarshal(b, &dataset); err != nil {
+		panic(fmt.Sprintf("invalid country alias dataset: %v", err))
+	}
+	for name, alias := range dataset.Aliases {
+		if !countryAliasNamePattern.MatchString(name) {
+			panic(fmt.Sprintf("invalid country alias name %q", name))
+		}
+		for _, code := range alias.Countries {
+			if !isValidAlpha2(code) {
+				panic(fmt.Sprintf("invalid country code %q in country alias %s", code, name))
+			}
+		}
+	}
+	return dataset
+}
+
+func isCountryAlias(value string) bool {
+	return strings.HasPrefix(value, countryAliasPrefix)
+}
+
+// checkCountryCodeOperand checks the lhs of a COUNTRY_CODE entry value
+// offline. Aliases are only checked for their syntax here, whether they are
+// defined is checked during plan.
+func checkCountryCodeOperand(value string) error {
+	if isCountryAlias(value) {
+		if !countryAliasNamePattern.MatchString(strings.TrimPrefix(value, countryAliasPrefix)) {
+			return fmt.Errorf("'%s' is not a valid country alias, aliases are %s followed by upper case letters, digits and underscores", value, countryAliasPrefix)
+		}
+		return nil
+	}
+	if !isValidAlpha2(value) {
+		return fmt.Errorf("'%s' is not a valid ISO-3166 Alpha-2 country code. Please visit the following site for reference: https://en.wikipedia.org/wiki/List_of_ISO_3166_country_codes", value)
+	}
+	return nil
+}
+
+// rejectCountryAlias reports country aliases used where the provider does
+// not expand them, such as v1 policy rules and raw rule JSON.
+func rejectCountryAlias(objectType, lhs string) error {
+	if objectType == "COUNTRY_CODE" && isCountryAlias(lhs) {
+		return fmt.Errorf("country alias %s is only supported by the conditions of zpa_policy_access_rule_v2", lhs)
+	}
+	return nil
+}
+
+// mergeCountryAliases returns the built-in country aliases together with the
+// aliases of the provider configuration, which cannot redefine built-in ones.
+func mergeCountryAliases(custom map[string][]string) (map[string][]string, error) {
+	aliases := make(map[string][]string, len(builtinCountryAliases.Aliases)+len(custom))
+	for name, alias := range builtinCountryAliases.Aliases {
+		aliases[name] = alias.Countries
+	}
+	for name, countries := range custom {
+		name = strings.TrimPrefix(name, countryAliasPrefix)
+		if !countryAliasNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid country alias name %q, names are upper case letters, digits and underscores", name)
+		}
+		if _, ok := builtinCountryAliases.Aliases[name]; ok {
+			return nil, fmt.Errorf("country alias %s%s is built in and cannot be redefined", countryAliasPrefix, name)
+		}
+		if len(countries) == 0 {
+			return nil, fmt.Errorf("country alias %s%s has no countries", countryAliasPrefix, name)
+		}
+		for _, code := range countries {
+			if !isValidAlpha2(code) {
+				return nil, fmt.Errorf("country alias %s%s: '%s' is not a valid ISO-3166 Alpha-2 country code", countryAliasPrefix, name, code)
+			}
+		}
+		aliases[name] = countries
+	}
+	return aliases, nil
+}
+
+func countryAliasesOf(meta interface{}) map[string][]string {
+	if zClient, ok := meta.(*Client); ok && zClient != nil && zClient.countryAliases != nil {
+		return zClient.countryAliases
+	}
+	aliases, _ := mergeCountryAliases(nil)
+	return aliases
+}
+
+func expandedCountryAliasesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeMap,
+		Computed:    true,
+		Description: "The country codes the country aliases of the COUNTRY_CODE operands expanded to during the last plan.",
+		Elem:        &schema.Schema{Type: schema.TypeString},
+	}
+}
+
+// countryAliasesInConditions returns the countries of every alias used by the
+// COUNTRY_CODE operands of conditions, as comma separated codes by alias.
+// Codes and aliases that are listed next to an alias that already contains
+// them are rejected, as the rule read back could not tell them apart.
+func countryAliasesInConditions(conditions []policysetcontrollerv2.PolicyRuleResourceConditions, aliases map[string][]string) (map[string]string, error) {
+	expanded := map[string]string{}
+	var problems []string
+	for _, condition := range conditions {
+		for _, operand := range condition.Operands {
+			if operand.ObjectType != "COUNTRY_CODE" {
+				continue
+			}
+			covered := map[string]string{}
+			used := map[string][]string{}
+			for _, ev := range operand.EntryValuesLHSRHS {
+				if !isCountryAlias(ev.LHS) {
+					continue
+				}
+				countries, ok := aliases[strings.TrimPrefix(ev.LHS, countryAliasPrefix)]
+				if !ok {
+					known := make([]string, 0, len(aliases))
+					for name := range aliases {
+						known = append(known, countryAliasPrefix+name)
+					}
+					sort.Strings(known)
+					problems = append(problems, fmt.Sprintf("COUNTRY_CODE: unknown country alias %s, known aliases are %s", ev.LHS, strings.Join(known, ", ")))
+					continue
+				}
+				codes := append([]string(nil), countries...)
+				sort.Strings(codes)
+				expanded[ev.LHS] = strings.Join(codes, ",")
+				used[ev.LHS] = codes
+				for _, code := range codes {
+					covered[code] = ev.LHS
+				}
+			}
+			for _, ev := range operand.EntryValuesLHSRHS {
+				if alias, ok := covered[ev.LHS]; ok {
+					problems = append(problems, fmt.Sprintf("COUNTRY_CODE: %s is already part of %s", ev.LHS, alias))
+				}
+			}
+			for alias, codes := range used {
+				for other, otherCodes := range used {
+					// Of two aliases with the same countries, the second
+					// one by name is reported.
+					if other == alias || len(otherCodes) < len(codes) || (len(otherCodes) == len(codes) && other > alias) {
+						continue
+					}
+					if containsAll(otherCodes, codes) {
+						problems = append(problems, fmt.Sprintf("COUNTRY_CODE: %s is already part of %s", alias, other))
+					}
+				}
+			}
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, fmt.Errorf("invalid policy rule conditions:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+	return expanded, nil
+}
+
+// containsAll reports whether values contains every element of subset.
+func containsAll(values, subset []string) bool {
+	for _, v := range subset {
+		if !contains(values, v) {
+			return false
+		}
+	}
+	return true
+}
+
+// planCountryAliases is part of the CustomizeDiff of the v2 policy rule
+// resources whose conditions accept COUNTRY_CODE. It plans the countries of
+// the aliases in expanded_country_aliases, so that an alias whose countries
+// change, in the built-in dataset or in the provider configuration, shows up
+// as a change of expanded_country_aliases and updates the rule.
+func planCountryAliases(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("conditions") || !d.NewValueKnown("condition_expression") {
+		return d.SetNewComputed("expanded_country_aliases")
+	}
+	conditions, err := plannedPolicyConditionsV2(d)
+	if err != nil {
+		return err
+	}
+	expanded, err := countryAliasesInConditions(conditions, countryAliasesOf(meta))
+	if err != nil {
+		return err
+	}
+
+	previous := map[string]string{}
+	for alias, codes := range d.Get("expanded_country_aliases").(map[string]interface{}) {
+		previous[alias], _ = codes.(string)
+	}
+	if reflect.DeepEqual(previous, expanded) {
+		return nil
+	}
+	for alias, codes := range expanded {
+		if old, ok := previous[alias]; ok && old != codes {
+			log.Printf("[WARN] Policy rule %s: the countries of %s changed from %s to %s, the rule will be updated", d.Id(), alias, old, codes)
+		}
+	}
+	newValue := make(map[string]interface{}, len(expanded))
+	for alias, codes := range expanded {
+		newValue[alias] = codes
+	}
+	return d.SetNew("expanded_country_aliases", newValue)
+}
+
+// expandCountryAliases replaces the country aliases of conditions with the
+// countries planned in expanded_country_aliases.
+func expandCountryAliases(d *schema.ResourceData, conditions []policysetcontrollerv2.PolicyRuleResourceConditions) error {
+	expanded, _ := d.Get("expanded_country_aliases").(map[string]interface{})
+	for i := range conditions {
+		for j := range conditions[i].Operands {
+			operand := &conditions[i].Operands[j]
+			if operand.ObjectType != "COUNTRY_CODE" {
+				continue
+			}
+			var entries []policysetcontrollerv2.OperandsResourceLHSRHSValue
+			seen := map[string]bool{}
+			add := func(ev policysetcontrollerv2.OperandsResourceLHSRHSValue) {
+				if key := ev.LHS + "|" + ev.RHS; !seen[key] {
+					seen[key] = true
+					entries = append(entries, ev)
+				}
+			}
+			for _, ev := range operand.EntryValuesLHSRHS {
+				if !isCountryAlias(ev.LHS) {
+					add(ev)
+					continue
+				}
+				codes, _ := expanded[ev.LHS].(string)
+				if codes == "" {
+					return fmt.Errorf("conditions.operands (object_type = \"COUNTRY_CODE\") lhs %q could not be expanded, the alias is not defined", ev.LHS)
+				}
+				for _, code := range strings.Split(codes, ",") {
+					add(policysetcontrollerv2.OperandsResourceLHSRHSValue{LHS: code, RHS: ev.RHS})
+				}
+			}
+			operand.EntryValuesLHSRHS = entries
+		}
+	}
+	return nil
+}
+
+// collapseCountryAliases replaces the countries of conditions read from the
+// API with the aliases of expanded_country_aliases they were expanded from,
+// so that the conditions match the configuration. An alias is only restored
+// when all of its countries are present in the same operand. Larger aliases
+// are restored first, and an alias whose countries are all part of restored
+// ones, such as @EU within @EEA, is not restored next to them.
+func collapseCountryAliases(d *schema.ResourceData, conditions []policysetcontrollerv2.PolicyRuleResourceConditions) {
+	expanded, _ := d.Get("expanded_country_aliases").(map[string]interface{})
+	if len(expanded) == 0 {
+		return
+	}
+	aliases := make([]string, 0, len(expanded))
+	aliasCodes := make(map[string][]string, len(expanded))
+	for alias, codes := range expanded {
+		aliases = append(aliases, alias)
+		aliasCodes[alias] = strings.Split(fmt.Sprint(codes), ",")
+	}
+	sort.Slice(aliases, func(i, j int) bool {
+		if len(aliasCodes[aliases[i]]) != len(aliasCodes[aliases[j]]) {
+			return len(aliasCodes[aliases[i]]) > len(aliasCodes[aliases[j]])
+		}
+		return aliases[i] < aliases[j]
+	})
+
+	for i := range conditions {
+		for j := range conditions[i].Operands {
+			operand := &conditions[i].Operands[j]
+			if operand.ObjectType != "COUNTRY_CODE" {
+				continue
+			}
+			present := map[string]bool{}
+			for _, ev := range operand.EntryValuesLHSRHS {
+				if ev.RHS == "true" {
+					present[ev.LHS] = true
+				}
+			}
+			collapsed := map[string]bool{}
+			var matched []string
+			for _, alias := range aliases {
+				all, restored := true, true
+				for _, code := range aliasCodes[alias] {
+					all = all && present[code]
+					restored = restored && collapsed[code]
+				}
+				if !all || restored {
+					continue
+				}
+				matched = append(matched, alias)
+				for _, code := range aliasCodes[alias] {
+					collapsed[code] = true
+				}
+			}
+			if len(matched) == 0 {
+				continue
+			}
+			sort.Strings(matched)
+			var entries []policysetcontrollerv2.OperandsResourceLHSRHSValue
+			for _, ev := range operand.EntryValuesLHSRHS {
+				if ev.RHS == "true" && collapsed[ev.LHS] {
+					continue
+				}
+				entries = append(entries, ev)
+			}
+			for _, alias := range matched {
+				entries = append(entries, policysetcontrollerv2.OperandsResourceLHSRHSValue{LHS: alias, RHS: "true"})
+			}
+			operand.EntryValuesLHSRHS = entries
+		}
+	}
+}
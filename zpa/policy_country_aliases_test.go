@@ -0,0 +1,242 @@
+package zpa
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontrollerv2"
+)
+
+// countryCodeCondition returns a condition with one COUNTRY_CODE operand per
+// list of lhs values.
+func countryCodeCondition(operands ...[]string) policysetcontrollerv2.PolicyRuleResourceConditions {
+	condition := policysetcontrollerv2.PolicyRuleResourceConditions{Operator: "OR"}
+	for _, lhs := range operands {
+		operand := policysetcontrollerv2.PolicyRuleResourceOperands{ObjectType: "COUNTRY_CODE"}
+		for _, v := range lhs {
+			operand.EntryValuesLHSRHS = append(operand.EntryValuesLHSRHS, policysetcontrollerv2.OperandsResourceLHSRHSValue{LHS: v, RHS: "true"})
+		}
+		condition.Operands = append(condition.Operands, operand)
+	}
+	return condition
+}
+
+// operandLHS returns the sorted lhs values of every operand of conditions.
+func operandLHS(conditions []policysetcontrollerv2.PolicyRuleResourceConditions) [][]string {
+	var values [][]string
+	for _, condition := range conditions {
+		for _, operand := range condition.Operands {
+			lhs := []string{}
+			for _, ev := range operand.EntryValuesLHSRHS {
+				lhs = append(lhs, ev.LHS)
+			}
+			sort.Strings(lhs)
+			values = append(values, lhs)
+		}
+	}
+	return values
+}
+
+func TestMergeCountryAliases(t *testing.T) {
+	for name, tc := range map[string]struct {
+		custom  map[string][]string
+		want    map[string][]string // aliases expected in the result
+		wantErr string
+	}{
+		"built in only": {
+			want: map[string][]string{"EU": builtinCountryAliases.Aliases["EU"].Countries},
+		},
+		"custom alias": {
+			custom: map[string][]string{"NORDIC": {"DK", "FI", "IS", "NO", "SE"}},
+			want: map[string][]string{
+				"NORDIC": {"DK", "FI", "IS", "NO", "SE"},
+				"EEA":    builtinCountryAliases.Aliases["EEA"].Countries,
+			},
+		},
+		"custom alias with prefix": {
+			custom: map[string][]string{"@DACH": {"DE", "AT", "CH"}},
+			want:   map[string][]string{"DACH": {"DE", "AT", "CH"}},
+		},
+		"redefined built-in alias": {
+			custom:  map[string][]string{"EU": {"DE", "FR"}},
+			wantErr: "country alias @EU is built in and cannot be redefined",
+		},
+		"redefined built-in alias with prefix": {
+			custom:  map[string][]string{"@EEA": {"NO"}},
+			wantErr: "country alias @EEA is built in and cannot be redefined",
+		},
+		"invalid country code": {
+			custom:  map[string][]string{"NORDIC": {"DK", "XX", "SE"}},
+			wantErr: "country alias @NORDIC: 'XX' is not a valid ISO-3166 Alpha-2 country code",
+		},
+		"invalid name": {
+			custom:  map[string][]string{"nordic": {"DK"}},
+			wantErr: `invalid country alias name "nordic"`,
+		},
+		"no countries": {
+			custom:  map[string][]string{"EMPTY": {}},
+			wantErr: "country alias @EMPTY has no countries",
+		},
+	} {
+		aliases, err := mergeCountryAliases(tc.custom)
+		if tc.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("%s: got error %v, want %q", name, err, tc.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		for alias, countries := range tc.want {
+			if !reflect.DeepEqual(aliases[alias], countries) {
+				t.Errorf("%s: alias %s = %v, want %v", name, alias, aliases[alias], countries)
+			}
+		}
+		if len(aliases) != len(builtinCountryAliases.Aliases)+len(tc.custom) {
+			t.Errorf("%s: got %d aliases, want %d", name, len(aliases), len(builtinCountryAliases.Aliases)+len(tc.custom))
+		}
+	}
+}
+
+func TestCountryAliasesInConditions(t *testing.T) {
+	aliases, err := mergeCountryAliases(map[string][]string{"NORDIC": {"DK", "FI", "IS", "NO", "SE"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	eu := append([]string(nil), aliases["EU"]...)
+	sort.Strings(eu)
+	eea := append([]string(nil), aliases["EEA"]...)
+	sort.Strings(eea)
+
+	for name, tc := range map[string]struct {
+		conditions []policysetcontrollerv2.PolicyRuleResourceConditions
+		want       map[string]string
+		wantErr    string
+	}{
+		"alias and codes": {
+			conditions: []policysetcontrollerv2.PolicyRuleResourceConditions{countryCodeCondition([]string{"@EU", "CH"})},
+			want:       map[string]string{"@EU": strings.Join(eu, ",")},
+		},
+		"partially overlapping aliases": {
+			conditions: []policysetcontrollerv2.PolicyRuleResourceConditions{countryCodeCondition([]string{"@EU", "@NORDIC"})},
+			want:       map[string]string{"@EU": strings.Join(eu, ","), "@NORDIC": "DK,FI,IS,NO,SE"},
+		},
+		"nested aliases in different operands": {
+			conditions: []policysetcontrollerv2.PolicyRuleResourceConditions{
+				countryCodeCondition([]string{"@EEA"}),
+				countryCodeCondition([]string{"@EU"}),
+			},
+			want: map[string]string{"@EU": strings.Join(eu, ","), "@EEA": strings.Join(eea, ",")},
+		},
+		"no aliases": {
+			conditions: []policysetcontrollerv2.PolicyRuleResourceConditions{countryCodeCondition([]string{"DE", "CH"})},
+			want:       map[string]string{},
+		},
+		"code already part of an alias": {
+			conditions: []policysetcontrollerv2.PolicyRuleResourceConditions{countryCodeCondition([]string{"@EU", "DE"})},
+			wantErr:    "COUNTRY_CODE: DE is already part of @EU",
+		},
+		"alias already part of an alias": {
+			conditions: []policysetcontrollerv2.PolicyRuleResourceConditions{countryCodeCondition([]string{"@EU", "@EEA"})},
+			wantErr:    "COUNTRY_CODE: @EU is already part of @EEA",
+		},
+		"unknown alias": {
+			conditions: []policysetcontrollerv2.PolicyRuleResourceConditions{countryCodeCondition([]string{"@NOWHERE"})},
+			wantErr:    "COUNTRY_CODE: unknown country alias @NOWHERE, known aliases are @APAC, @EEA, @EU, @NORDIC, @OFAC_SANCTIONED",
+		},
+	} {
+		expanded, err := countryAliasesInConditions(tc.conditions, aliases)
+		if tc.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("%s: got error %v, want %q", name, err, tc.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(expanded, tc.want) {
+			t.Errorf("%s: got %v, want %v", name, expanded, tc.want)
+		}
+	}
+}
+
+func TestExpandCollapseCountryAliases(t *testing.T) {
+	aliases, err := mergeCountryAliases(map[string][]string{"NORDIC": {"DK", "FI", "IS", "NO", "SE"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	configured := []policysetcontrollerv2.PolicyRuleResourceConditions{
+		countryCodeCondition([]string{"@EEA"}, []string{"@EU", "CH"}),
+		// @EU and @NORDIC share countries, NO is not part of @EU
+		countryCodeCondition([]string{"@EU", "@NORDIC"}, []string{"@EU", "NO"}),
+		countryCodeCondition([]string{"@OFAC_SANCTIONED"}, []string{"DE", "FR"}),
+		{
+			Operator: "OR",
+			Operands: []policysetcontrollerv2.PolicyRuleResourceOperands{{ObjectType: "PLATFORM", EntryValuesLHSRHS: []policysetcontrollerv2.OperandsResourceLHSRHSValue{{LHS: "linux", RHS: "true"}}}},
+		},
+	}
+	expanded, err := countryAliasesInConditions(configured, aliases)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := schema.TestResourceDataRaw(t, map[string]*schema.Schema{"expanded_country_aliases": expandedCountryAliasesSchema()}, nil)
+	planned := map[string]interface{}{}
+	for alias, codes := range expanded {
+		planned[alias] = codes
+	}
+	if err := d.Set("expanded_country_aliases", planned); err != nil {
+		t.Fatal(err)
+	}
+
+	// The conditions sent to the API only have country codes
+	conditions := copyPolicyConditionsV2(configured)
+	if err := expandCountryAliases(d, conditions); err != nil {
+		t.Fatal(err)
+	}
+	wantSizes := []int{30, 28, 29, 28, 4, 2, 1}
+	for i, lhs := range operandLHS(conditions) {
+		if len(lhs) != wantSizes[i] {
+			t.Errorf("operand %d: expanded to %d values, want %d", i, len(lhs), wantSizes[i])
+		}
+		for _, v := range lhs {
+			if isCountryAlias(v) {
+				t.Errorf("operand %d: alias %s was not expanded", i, v)
+			}
+		}
+	}
+
+	// Read back, the conditions match the configuration again
+	collapseCountryAliases(d, conditions)
+	if got, want := operandLHS(conditions), operandLHS(configured); !reflect.DeepEqual(got, want) {
+		t.Errorf("collapsed conditions = %v, want %v", got, want)
+	}
+
+	// An alias that is not planned cannot be expanded
+	undefined := []policysetcontrollerv2.PolicyRuleResourceConditions{countryCodeCondition([]string{"@APAC"})}
+	if err := expandCountryAliases(d, undefined); err == nil || !strings.Contains(err.Error(), `lhs "@APAC" could not be expanded`) {
+		t.Errorf("expected an error for an alias that is not planned, got %v", err)
+	}
+}
+
+// copyPolicyConditionsV2 returns a deep copy of conditions.
+func copyPolicyConditionsV2(conditions []policysetcontrollerv2.PolicyRuleResourceConditions) []policysetcontrollerv2.PolicyRuleResourceConditions {
+	copied := make([]policysetcontrollerv2.PolicyRuleResourceConditions, len(conditions))
+	for i, condition := range conditions {
+		copied[i] = condition
+		copied[i].Operands = make([]policysetcontrollerv2.PolicyRuleResourceOperands, len(condition.Operands))
+		for j, operand := range condition.Operands {
+			copied[i].Operands[j] = operand
+			copied[i].Operands[j].Values = append([]string(nil), operand.Values...)
+			copied[i].Operands[j].EntryValuesLHSRHS = append([]policysetcontrollerv2.OperandsResourceLHSRHSValue(nil), operand.EntryValuesLHSRHS...)
+		}
+	}
+	return copied
+}
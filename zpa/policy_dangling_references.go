@@ -0,0 +1,351 @@
+package zpa
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontroller"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontrollerv2"
+)
+
+// danglingPolicyReferences looks up refs with the reference cache of the
+// client and returns the keys of the references to objects that no longer
+// exist. It returns nothing when reference validation is disabled.
+func danglingPolicyReferences(ctx context.Context, zClient *Client, refs []policyReference) map[string]policyReference {
+	if zClient == nil || zClient.referenceCache == nil || len(refs) == 0 {
+		return nil
+	}
+	dangling := map[string]policyReference{}
+	for i, err := range resolvePolicyReferences(ctx, zClient, zClient.referenceCache, refs) {
+		if err == nil {
+			continue
+		}
+		if !isPolicyReferenceNotFound(err) {
+			log.Printf("[WARN] Could not check %s: %v", refs[i].describe(), err)
+			continue
+		}
+		dangling[refs[i].key] = refs[i]
+	}
+	return dangling
+}
+
+// danglingPolicyReferenceWarnings returns a warning naming the references of
+// a rule read from the API to objects that were deleted outside Terraform.
+func danglingPolicyReferenceWarnings(ctx context.Context, meta interface{}, refs []policyReference) diag.Diagnostics {
+	zClient, _ := meta.(*Client)
+	dangling := danglingPolicyReferences(ctx, zClient, refs)
+	if len(dangling) == 0 {
+		return nil
+	}
+	problems := make([]string, 0, len(dangling))
+	for _, ref := range dangling {
+		problems = append(problems, ref.describe())
+	}
+	sort.Strings(problems)
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  "Policy rule references objects that no longer exist",
+		Detail: fmt.Sprintf("The following operand values reference objects that were not found, so they no longer match anything:\n  - %s\n\n"+
+			"Remove them from the configuration, or enable the prune_dangling_references provider attribute.", strings.Join(problems, "\n  - ")),
+	}}
+}
+
+// collectPolicyReferencesV1 returns the deduplicated tenant references of
+// the conditions of a v1 policy rule, with the lookups validateOperand uses.
+func collectPolicyReferencesV1(conditions []policysetcontroller.Conditions, microTenantID string) []policyReference {
+	seen := map[string]bool{}
+	var refs []policyReference
+	for i, condition := range conditions {
+		for j, operand := range condition.Operands {
+			t, ok := lookupPolicyOperandType(operand.ObjectType)
+			if !ok {
+				continue
+			}
+			v := operandValue{lhs: operand.LHS, rhs: operand.RHS, idpID: operand.IdpID}
+			for _, ref := range operandValueReferences(t, v, microTenantID) {
+				if !seen[ref.key] {
+					seen[ref.key] = true
+					ref.location = policyOperandLocation(i, j)
+					refs = append(refs, ref)
+				}
+			}
+		}
+	}
+	return refs
+}
+
+// prunedReferencesSchema returns the pruned_references attribute of the v2
+// policy rule resources.
+func prunedReferencesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeSet,
+		Computed:    true,
+		Description: "The operand values of the conditions that reference objects that no longer exist, and are not sent to the API. Only set when the prune_dangling_references provider attribute is enabled.",
+		Elem:        &schema.Schema{Type: schema.TypeString},
+	}
+}
+
+// prunedReferenceKey identifies an operand value in pruned_references, as in
+// APP.values:72058304855015574 or SCIM_GROUP.entry_values:72058304855015425=295.
+func prunedReferenceKey(objectType string, v operandValue, entry bool) string {
+	if entry {
+		return objectType + ".entry_values:" + v.lhs + "=" + v.rhs
+	}
+	return objectType + ".values:" + v.rhs
+}
+
+// operandValueKeys returns the pruned_references key of every value of
+// conditions, along with the references of the value.
+func operandValueKeys(conditions []policysetcontrollerv2.PolicyRuleResourceConditions, microTenantID string) map[string][]policyReference {
+	keys := map[string][]policyReference{}
+	for i, condition := range conditions {
+		for j, operand := range condition.Operands {
+			t, ok := lookupPolicyOperandType(operand.ObjectType)
+			if !ok {
+				continue
+			}
+			located := func(v operandValue) []policyReference {
+				refs := operandValueReferences(t, v, microTenantID)
+				for k := range refs {
+					refs[k].location = policyOperandLocation(i, j)
+				}
+				return refs
+			}
+			if t.valueKind != operandValueEntry {
+				for _, id := range operand.Values {
+					v := operandValue{lhs: "id", rhs: id}
+					keys[prunedReferenceKey(t.objectType, v, false)] = located(v)
+				}
+				continue
+			}
+			for _, ev := range operand.EntryValuesLHSRHS {
+				v := operandValue{lhs: ev.LHS, rhs: ev.RHS}
+				keys[prunedReferenceKey(t.objectType, v, true)] = located(v)
+			}
+		}
+	}
+	return keys
+}
+
+// priorPolicyConditionsV2 returns the conditions of a v2 policy rule resource
+// in the state, before the planned change.
+func priorPolicyConditionsV2(d *schema.ResourceDiff) ([]policysetcontrollerv2.PolicyRuleResourceConditions, error) {
+	oldExpression, _ := d.GetChange("condition_expression")
+	if expression := GetString(oldExpression); expression != "" {
+		_, conditions, err := compileConditionExpression(expression, "")
+		if err != nil {
+			return nil, nil
+		}
+		return conditions, nil
+	}
+	oldConditions, _ := d.GetChange("conditions")
+	conditionsSet, ok := oldConditions.(*schema.Set)
+	if !ok || conditionsSet.Len() == 0 {
+		return nil, nil
+	}
+	return expandPolicyConditionSetV2(conditionsSet)
+}
+
+// planPrunedReferences is part of the CustomizeDiff of the v2 policy rule
+// resources. When prune_dangling_references is enabled, it plans in
+// pruned_references the configured values that were already part of the
+// rule and now reference objects that no longer exist. These values are left
+// out of the rule on apply, and the rule is updated when the set changes.
+// Values that are new in the configuration are never pruned, so that typos
+// still fail the plan.
+func planPrunedReferences(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	zClient, ok := meta.(*Client)
+	if !ok || zClient == nil {
+		return nil
+	}
+	previous := SetToStringSlice(d.Get("pruned_references").(*schema.Set))
+	if !zClient.pruneDanglingReferences || d.Id() == "" {
+		if len(previous) > 0 {
+			return d.SetNew("pruned_references", []string{})
+		}
+		return nil
+	}
+	if !d.NewValueKnown("conditions") || !d.NewValueKnown("condition_expression") || !d.NewValueKnown("microtenant_id") {
+		return d.SetNewComputed("pruned_references")
+	}
+
+	microTenantID := GetString(d.Get("microtenant_id"))
+	planned, err := plannedPolicyConditionsV2(d)
+	if err != nil {
+		return err
+	}
+	prior, err := priorPolicyConditionsV2(d)
+	if err != nil {
+		return err
+	}
+	priorKeys := operandValueKeys(prior, microTenantID)
+	candidates := map[string][]policyReference{}
+	var refs []policyReference
+	seen := map[string]bool{}
+	for key, valueRefs := range operandValueKeys(planned, microTenantID) {
+		if _, ok := priorKeys[key]; !ok {
+			continue
+		}
+		candidates[key] = valueRefs
+		for _, ref := range valueRefs {
+			if !seen[ref.key] {
+				seen[ref.key] = true
+				refs = append(refs, ref)
+			}
+		}
+	}
+	dangling := danglingPolicyReferences(ctx, zClient, refs)
+
+	pruned := []string{}
+	for key, valueRefs := range candidates {
+		for _, ref := range valueRefs {
+			if _, ok := dangling[ref.key]; ok {
+				log.Printf("[WARN] Policy rule %s: pruning %s, it references an object that no longer exists", d.Id(), ref.describe())
+				pruned = append(pruned, key)
+				break
+			}
+		}
+	}
+	sort.Strings(pruned)
+	prunedSet := map[string]bool{}
+	for _, key := range pruned {
+		prunedSet[key] = true
+	}
+	if err := removePrunedValues(planned, prunedSet); err != nil {
+		return err
+	}
+	sort.Strings(previous)
+	if reflect.DeepEqual(previous, pruned) {
+		return nil
+	}
+	return d.SetNew("pruned_references", pruned)
+}
+
+// removePrunedValues removes the values listed in pruned from conditions. An
+// operand cannot lose all of its values, as it would then match more than
+// configured.
+func removePrunedValues(conditions []policysetcontrollerv2.PolicyRuleResourceConditions, pruned map[string]bool) error {
+	if len(pruned) == 0 {
+		return nil
+	}
+	for i := range conditions {
+		for j := range conditions[i].Operands {
+			operand := &conditions[i].Operands[j]
+			valueCount := len(operand.Values) + len(operand.EntryValuesLHSRHS)
+			values := operand.Values[:0:0]
+			for _, id := range operand.Values {
+				if !pruned[prunedReferenceKey(operand.ObjectType, operandValue{lhs: "id", rhs: id}, false)] {
+					values = append(values, id)
+				}
+			}
+			entries := operand.EntryValuesLHSRHS[:0:0]
+			for _, ev := range operand.EntryValuesLHSRHS {
+				if !pruned[prunedReferenceKey(operand.ObjectType, operandValue{lhs: ev.LHS, rhs: ev.RHS}, true)] {
+					entries = append(entries, ev)
+				}
+			}
+			if valueCount > 0 && len(values)+len(entries) == 0 {
+				return fmt.Errorf("conditions.operands (object_type = %q): every value references an object that no longer exists, remove the operand from the configuration", operand.ObjectType)
+			}
+			operand.Values = values
+			operand.EntryValuesLHSRHS = entries
+		}
+	}
+	return nil
+}
+
+func prunedReferenceSet(v interface{}) map[string]bool {
+	set, ok := v.(*schema.Set)
+	if !ok {
+		return nil
+	}
+	pruned := map[string]bool{}
+	for _, key := range SetToStringSlice(set) {
+		pruned[key] = true
+	}
+	return pruned
+}
+
+// pruneDanglingReferences removes the values planned in pruned_references
+// from the conditions sent to the API.
+func pruneDanglingReferences(d *schema.ResourceData, conditions []policysetcontrollerv2.PolicyRuleResourceConditions) error {
+	return removePrunedValues(conditions, prunedReferenceSet(d.Get("pruned_references")))
+}
+
+// restorePrunedReferences adds the values of pruned_references back to the
+// conditions read from the API, so that the conditions match the
+// configuration. A value is added to an operand when the operand of the
+// state it was pruned from holds the same values otherwise.
+func restorePrunedReferences(d *schema.ResourceData, conditions []policysetcontrollerv2.PolicyRuleResourceConditions) {
+	pruned := prunedReferenceSet(d.Get("pruned_references"))
+	if len(pruned) == 0 {
+		return
+	}
+	var prior []policysetcontrollerv2.PolicyRuleResourceConditions
+	if expression := GetString(d.Get("condition_expression")); expression != "" {
+		_, prior, _ = compileConditionExpression(expression, "")
+	} else if conditionsSet, ok := d.Get("conditions").(*schema.Set); ok {
+		prior, _ = expandPolicyConditionSetV2(conditionsSet)
+	}
+
+	for i := range conditions {
+		for j := range conditions[i].Operands {
+			operand := &conditions[i].Operands[j]
+			if priorOperand, ok := prunedOperandOf(prior, *operand, pruned); ok {
+				for _, id := range priorOperand.Values {
+					if pruned[prunedReferenceKey(operand.ObjectType, operandValue{lhs: "id", rhs: id}, false)] {
+						operand.Values = append(operand.Values, id)
+					}
+				}
+				for _, ev := range priorOperand.EntryValuesLHSRHS {
+					if pruned[prunedReferenceKey(operand.ObjectType, operandValue{lhs: ev.LHS, rhs: ev.RHS}, true)] {
+						operand.EntryValuesLHSRHS = append(operand.EntryValuesLHSRHS, ev)
+					}
+				}
+			}
+		}
+	}
+}
+
+// prunedOperandOf returns the operand of prior that operand was read back
+// from after its pruned values were left out.
+func prunedOperandOf(prior []policysetcontrollerv2.PolicyRuleResourceConditions, operand policysetcontrollerv2.PolicyRuleResourceOperands, pruned map[string]bool) (policysetcontrollerv2.PolicyRuleResourceOperands, bool) {
+	current := operandKeys(operand)
+	for _, condition := range prior {
+		for _, priorOperand := range condition.Operands {
+			if priorOperand.ObjectType != operand.ObjectType {
+				continue
+			}
+			kept := []string{}
+			for _, key := range operandKeys(priorOperand) {
+				if !pruned[key] {
+					kept = append(kept, key)
+				}
+			}
+			if reflect.DeepEqual(kept, current) {
+				return priorOperand, true
+			}
+		}
+	}
+	return policysetcontrollerv2.PolicyRuleResourceOperands{}, false
+}
+
+// operandKeys returns the sorted pruned_references keys of the values of
+// operand.
+func operandKeys(operand policysetcontrollerv2.PolicyRuleResourceOperands) []string {
+	keys := []string{}
+	for _, id := range operand.Values {
+		keys = append(keys, prunedReferenceKey(operand.ObjectType, operandValue{lhs: "id", rhs: id}, false))
+	}
+	for _, ev := range operand.EntryValuesLHSRHS {
+		keys = append(keys, prunedReferenceKey(operand.ObjectType, operandValue{lhs: ev.LHS, rhs: ev.RHS}, true))
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,130 @@
+package zpa
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontrollerv2"
+)
+
+func prunedTestOperand(objectType string, values []string, entries ...string) policysetcontrollerv2.PolicyRuleResourceOperands {
+	operand := policysetcontrollerv2.PolicyRuleResourceOperands{ObjectType: objectType, Values: values}
+	for _, entry := range entries {
+		lhs, rhs, _ := strings.Cut(entry, "=")
+		operand.EntryValuesLHSRHS = append(operand.EntryValuesLHSRHS, policysetcontrollerv2.OperandsResourceLHSRHSValue{LHS: lhs, RHS: rhs})
+	}
+	return operand
+}
+
+func TestRemovePrunedValues(t *testing.T) {
+	conditions := []policysetcontrollerv2.PolicyRuleResourceConditions{{
+		Operator: "OR",
+		Operands: []policysetcontrollerv2.PolicyRuleResourceOperands{
+			prunedTestOperand("APP_GROUP", []string{"1", "2", "3"}),
+			prunedTestOperand("SCIM_GROUP", nil, "idp=10", "idp=11"),
+		},
+	}}
+	pruned := map[string]bool{
+		"APP_GROUP.values:2":             true,
+		"SCIM_GROUP.entry_values:idp=11": true,
+		// Values of other object types are not touched
+		"APP.values:1": true,
+	}
+	if err := removePrunedValues(conditions, pruned); err != nil {
+		t.Fatal(err)
+	}
+	want := []policysetcontrollerv2.PolicyRuleResourceOperands{
+		prunedTestOperand("APP_GROUP", []string{"1", "3"}),
+		prunedTestOperand("SCIM_GROUP", nil, "idp=10"),
+	}
+	if !reflect.DeepEqual(conditions[0].Operands, want) {
+		t.Errorf("got operands %+v, want %+v", conditions[0].Operands, want)
+	}
+
+	conditions = []policysetcontrollerv2.PolicyRuleResourceConditions{{
+		Operands: []policysetcontrollerv2.PolicyRuleResourceOperands{prunedTestOperand("APP_GROUP", []string{"1", "2"})},
+	}}
+	if err := removePrunedValues(conditions, nil); err != nil || len(conditions[0].Operands[0].Values) != 2 {
+		t.Errorf("expected nothing to be pruned, got %v, %+v", err, conditions[0].Operands[0])
+	}
+	err := removePrunedValues(conditions, map[string]bool{"APP_GROUP.values:1": true, "APP_GROUP.values:2": true})
+	if err == nil || !strings.Contains(err.Error(), "every value references an object that no longer exists") {
+		t.Errorf("expected an operand left without values to be rejected, got %v", err)
+	}
+}
+
+func TestPrunedOperandOf(t *testing.T) {
+	prior := []policysetcontrollerv2.PolicyRuleResourceConditions{
+		{Operands: []policysetcontrollerv2.PolicyRuleResourceOperands{prunedTestOperand("APP", []string{"5"})}},
+		{Operands: []policysetcontrollerv2.PolicyRuleResourceOperands{
+			prunedTestOperand("APP_GROUP", []string{"1", "2"}),
+			prunedTestOperand("SCIM_GROUP", nil, "idp=10", "idp=11"),
+		}},
+	}
+	pruned := map[string]bool{
+		"APP_GROUP.values:2":             true,
+		"SCIM_GROUP.entry_values:idp=11": true,
+	}
+	for name, tc := range map[string]struct {
+		operand policysetcontrollerv2.PolicyRuleResourceOperands
+		want    string // the object type of the matched prior operand, empty when none
+	}{
+		"pruned value":          {operand: prunedTestOperand("APP_GROUP", []string{"1"}), want: "APP_GROUP"},
+		"pruned entry value":    {operand: prunedTestOperand("SCIM_GROUP", nil, "idp=10"), want: "SCIM_GROUP"},
+		"nothing pruned":        {operand: prunedTestOperand("APP", []string{"5"}), want: "APP"},
+		"changed outside":       {operand: prunedTestOperand("APP_GROUP", []string{"1", "3"})},
+		"other object type":     {operand: prunedTestOperand("APP", []string{"1"})},
+		"pruned value kept":     {operand: prunedTestOperand("APP_GROUP", []string{"1", "2"})},
+		"unmatched object type": {operand: prunedTestOperand("CLIENT_TYPE", []string{"1"})},
+	} {
+		t.Run(name, func(t *testing.T) {
+			operand, ok := prunedOperandOf(prior, tc.operand, pruned)
+			if ok != (tc.want != "") || operand.ObjectType != tc.want {
+				t.Errorf("prunedOperandOf = %+v, %v, want %s", operand, ok, tc.want)
+			}
+		})
+	}
+}
+
+func TestRestorePrunedReferences(t *testing.T) {
+	r := resourcePolicyAccessRuleV2()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"conditions": []interface{}{
+			map[string]interface{}{
+				"operator": "OR",
+				"operands": []interface{}{
+					map[string]interface{}{"object_type": "APP_GROUP", "values": []interface{}{"1", "2"}},
+					map[string]interface{}{"object_type": "APP", "values": []interface{}{"5", "6"}},
+				},
+			},
+		},
+	})
+	_ = d.Set("pruned_references", []string{"APP_GROUP.values:2", "APP.values:7"})
+
+	// Read back without the pruned value, and with APP changed outside
+	// Terraform.
+	conditions := []policysetcontrollerv2.PolicyRuleResourceConditions{{
+		Operator: "OR",
+		Operands: []policysetcontrollerv2.PolicyRuleResourceOperands{
+			prunedTestOperand("APP_GROUP", []string{"1"}),
+			prunedTestOperand("APP", []string{"5"}),
+		},
+	}}
+	restorePrunedReferences(d, conditions)
+
+	values := map[string][]string{}
+	for _, operand := range conditions[0].Operands {
+		values[operand.ObjectType] = append([]string(nil), operand.Values...)
+		sort.Strings(values[operand.ObjectType])
+	}
+	want := map[string][]string{
+		"APP_GROUP": {"1", "2"},
+		"APP":       {"5"},
+	}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("got values %v, want %v", values, want)
+	}
+}
@@ -0,0 +1,194 @@
+package zpa
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontrollerv2"
+)
+
+// policyAtom is a single operand of a policy rule condition. ID and enum
+// operands have lhs "id" and the value in rhs, like v1 operands.
+type policyAtom struct {
+	objectType string
+	lhs        string
+	rhs        string
+}
+
+func (a policyAtom) String() string {
+	if a.lhs == "id" {
+		return fmt.Sprintf("%s=%s", a.objectType, a.rhs)
+	}
+	return fmt.Sprintf("%s[%s]=%s", a.objectType, a.lhs, a.rhs)
+}
+
+// policyConditionExpr is a condition of a policy rule. With the OR operator
+// the condition holds when any of its atoms holds, with AND when all of them
+// hold.
+type policyConditionExpr struct {
+	operator string
+	atoms    []policyAtom
+}
+
+// policyRuleExpr is the boolean expression of a policy rule. The conditions of
+// a rule are combined with the rule operator, which defaults to AND.
+type policyRuleExpr struct {
+	id         string
+	name       string
+	action     string
+	order      int
+	disabled   bool
+	operator   string
+	conditions []policyConditionExpr
+}
+
+func newPolicyRuleExpr(rule policysetcontrollerv2.PolicyRuleResource) policyRuleExpr {
+	order, err := strconv.Atoi(rule.RuleOrder)
+	if err != nil {
+		order = 0
+	}
+	disabled := fmt.Sprint(rule.Disabled)
+	expr := policyRuleExpr{
+		id:       rule.ID,
+		name:     rule.Name,
+		action:   rule.Action,
+		order:    order,
+		disabled: disabled == "1" || disabled == "true",
+		operator: strings.ToUpper(rule.Operator),
+	}
+	for _, condition := range rule.Conditions {
+		c := policyConditionExpr{operator: strings.ToUpper(condition.Operator)}
+		for _, operand := range condition.Operands {
+			c.atoms = append(c.atoms, policyAtom{objectType: operand.ObjectType, lhs: operand.LHS, rhs: operand.RHS})
+		}
+		expr.conditions = append(expr.conditions, c)
+	}
+	return expr
+}
+
+// fetchOrderedPolicyRules returns the rules of policyType in evaluation order.
+func fetchOrderedPolicyRules(ctx context.Context, service *zscaler.Service, policyType string) ([]policyRuleExpr, error) {
+	rules, _, err := policysetcontrollerv2.GetAllByType(ctx, service, policyType)
+	if err != nil {
+		return nil, err
+	}
+	exprs := make([]policyRuleExpr, 0, len(rules))
+	for _, rule := range rules {
+		exprs = append(exprs, newPolicyRuleExpr(rule))
+	}
+	sort.SliceStable(exprs, func(i, j int) bool {
+		return exprs[i].order < exprs[j].order
+	})
+	return exprs, nil
+}
+
+// policyClientContext is the simulated context a policy is evaluated for.
+type policyClientContext struct {
+	applicationSegmentID string
+	segmentGroupID       string
+	idpID                string
+	clientType           string
+	platform             string
+	countryCode          string
+	riskLevel            string
+	samlAttributes       map[string][]string // SAML attribute ID to values
+	scimAttributes       map[string][]string // SCIM attribute header ID to values
+	scimGroupIDs         []string
+	postureResults       map[string]bool // posture profile UDID to result
+	trustedNetworkIDs    []string
+	// Operands of other object types, keyed by policyAtom.String()
+	otherOperands map[string]bool
+}
+
+func (c *policyClientContext) matches(a policyAtom) bool {
+	switch a.objectType {
+	case "APP":
+		return a.rhs == c.applicationSegmentID
+	case "APP_GROUP":
+		return a.rhs == c.segmentGroupID
+	case "IDP":
+		return a.rhs == c.idpID
+	case "CLIENT_TYPE":
+		return a.rhs == c.clientType
+	case "PLATFORM":
+		return strings.EqualFold(a.lhs, c.platform)
+	case "COUNTRY_CODE":
+		return strings.EqualFold(a.lhs, c.countryCode)
+	case "RISK_FACTOR_TYPE":
+		return strings.EqualFold(a.rhs, c.riskLevel)
+	case "SAML":
+		return contains(c.samlAttributes[a.lhs], a.rhs)
+	case "SCIM":
+		for _, v := range c.scimAttributes[a.lhs] {
+			if strings.EqualFold(v, a.rhs) {
+				return true
+			}
+		}
+		return false
+	case "SCIM_GROUP":
+		return contains(c.scimGroupIDs, a.rhs)
+	case "POSTURE":
+		return strconv.FormatBool(c.postureResults[a.lhs]) == a.rhs
+	case "TRUSTED_NETWORK":
+		return strconv.FormatBool(contains(c.trustedNetworkIDs, a.lhs)) == a.rhs
+	}
+	return c.otherOperands[a.String()]
+}
+
+// evaluate reports whether the rule matches the client context, and if not,
+// why.
+func (r policyRuleExpr) evaluate(c *policyClientContext) (bool, string) {
+	if r.disabled {
+		return false, "rule is disabled"
+	}
+	if len(r.conditions) == 0 {
+		return true, "rule has no conditions"
+	}
+
+	var failed []string
+	matchedAny := false
+	for i, condition := range r.conditions {
+		ok, reason := condition.evaluate(c)
+		if ok {
+			matchedAny = true
+			continue
+		}
+		failed = append(failed, fmt.Sprintf("condition %d: %s", i+1, reason))
+	}
+
+	if r.operator == "OR" {
+		if matchedAny {
+			return true, "at least one condition matched"
+		}
+		return false, strings.Join(failed, "; ")
+	}
+	if len(failed) == 0 {
+		return true, "all conditions matched"
+	}
+	return false, strings.Join(failed, "; ")
+}
+
+func (c policyConditionExpr) evaluate(ctx *policyClientContext) (bool, string) {
+	var matched, unmatched []string
+	for _, atom := range c.atoms {
+		if ctx.matches(atom) {
+			matched = append(matched, atom.String())
+		} else {
+			unmatched = append(unmatched, atom.String())
+		}
+	}
+	if c.operator == "AND" {
+		if len(unmatched) == 0 {
+			return true, ""
+		}
+		return false, fmt.Sprintf("AND condition not satisfied by %s", strings.Join(unmatched, ", "))
+	}
+	if len(matched) > 0 || len(c.atoms) == 0 {
+		return true, ""
+	}
+	return false, fmt.Sprintf("none of %s matched", strings.Join(unmatched, ", "))
+}
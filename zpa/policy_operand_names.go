@@ -0,0 +1,284 @@
+package zpa
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontrollerv2"
+)
+
+// policyOperandNamePrefix marks operand values that reference a tenant object
+// by name instead of by ID, as in values = ["name:Finance Apps"].
+const policyOperandNamePrefix = "name:"
+
+func isPolicyOperandName(value string) bool {
+	return strings.HasPrefix(value, policyOperandNamePrefix)
+}
+
+// policyOperandNameKey is the key of a name reference in resolved_names.
+// Names on the rhs of entry operands are scoped by the resolved lhs, for
+// example the IdP of a SCIM group.
+func policyOperandNameKey(objectType, field, scope, name string) string {
+	if scope != "" {
+		field += "(" + scope + ")"
+	}
+	return objectType + "." + field + ":" + name
+}
+
+func policyOperandNamesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeMap,
+		Computed:    true,
+		Description: "The IDs the name references of the conditions resolved to during the last plan.",
+		Elem:        &schema.Schema{Type: schema.TypeString},
+	}
+}
+
+// policyOperandNameRef is a single name reference of an operand value.
+type policyOperandNameRef struct {
+	t     *policyOperandType
+	side  operandSide
+	field string
+	scope string
+	name  string
+	value operandValue
+}
+
+func (r policyOperandNameRef) key() string {
+	return policyOperandNameKey(r.t.objectType, r.field, r.scope, r.name)
+}
+
+func (r policyOperandNameRef) describe() string {
+	return fmt.Sprintf("conditions.operands (object_type = %q) %s %q", r.t.objectType, r.field, policyOperandNamePrefix+r.name)
+}
+
+// resolvePolicyOperandNames resolves every name reference of conditions. The
+// lhs of entry operands is resolved before their rhs, which may depend on it.
+// pending is true when a name was not found, for example because the object
+// is created by the same apply, or could not be looked up; such names are not
+// part of resolved.
+func resolvePolicyOperandNames(ctx context.Context, zClient *Client, conditions []policysetcontrollerv2.PolicyRuleResourceConditions, microTenantID string) (resolved map[string]string, pending bool, err error) {
+	cache := zClient.referenceCache
+	if cache == nil {
+		cache = newReferenceLookupCache()
+	}
+
+	resolved = map[string]string{}
+	var problems []string
+	resolve := func(ref policyOperandNameRef) (string, bool) {
+		if ref.side.resolve == nil {
+			problems = append(problems, fmt.Sprintf("%s: %s does not support name references, use %s", ref.describe(), ref.t.objectType, ref.side.description))
+			return "", false
+		}
+		scope := ""
+		if ref.t.microtenantScoped {
+			scope = microTenantID
+		}
+		key := ref.key()
+		// Only successful resolutions are cached: a name that is not found
+		// yet may be created later in the same apply.
+		cacheKey := "NAME|" + scope + "|" + key
+		id := cache.resolvedName(cacheKey)
+		if id == "" {
+			var err error
+			id, err = ref.side.resolve(ctx, zClient, microTenantID, ref.value, ref.name)
+			if err != nil {
+				if isPolicyReferenceNotFound(err) {
+					log.Printf("[WARN] %s was not found, it will be resolved again on apply: %v", ref.describe(), err)
+				} else {
+					log.Printf("[WARN] %s could not be resolved at plan time, it will be resolved again on apply: %v", ref.describe(), err)
+				}
+				pending = true
+				return "", false
+			}
+			if id == "" {
+				problems = append(problems, fmt.Sprintf("%s could not be resolved: no ID returned", ref.describe()))
+				return "", false
+			}
+			cache.setResolvedName(cacheKey, id)
+		}
+		resolved[key] = id
+		return id, true
+	}
+
+	for _, condition := range conditions {
+		for _, operand := range condition.Operands {
+			t, ok := lookupPolicyOperandType(operand.ObjectType)
+			if !ok {
+				continue
+			}
+			for _, value := range operand.Values {
+				if isPolicyOperandName(value) {
+					resolve(policyOperandNameRef{t: t, side: t.rhs, field: "values", name: strings.TrimPrefix(value, policyOperandNamePrefix)})
+				}
+			}
+			for _, ev := range operand.EntryValuesLHSRHS {
+				lhs := ev.LHS
+				if isPolicyOperandName(lhs) {
+					id, ok := resolve(policyOperandNameRef{t: t, side: t.lhs, field: "lhs", name: strings.TrimPrefix(lhs, policyOperandNamePrefix)})
+					if !ok {
+						continue
+					}
+					lhs = id
+				}
+				if isPolicyOperandName(ev.RHS) {
+					resolve(policyOperandNameRef{
+						t:     t,
+						side:  t.rhs,
+						field: "rhs",
+						scope: lhs,
+						name:  strings.TrimPrefix(ev.RHS, policyOperandNamePrefix),
+						value: operandValue{lhs: lhs},
+					})
+				}
+			}
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, false, fmt.Errorf("invalid policy rule conditions:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+	return resolved, pending, nil
+}
+
+// planPolicyOperandNames is part of the CustomizeDiff of the v2 policy rule
+// resources. It resolves the name references of the conditions on every plan
+// and plans resolved_names, so that a name resolving to a different ID shows
+// up as a change of resolved_names and updates the rule. Names that are not
+// found leave resolved_names unknown until Terraform plans the resource again
+// on apply.
+func planPolicyOperandNames(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	zClient, ok := meta.(*Client)
+	if !ok || zClient == nil {
+		return nil
+	}
+	if !d.NewValueKnown("conditions") || !d.NewValueKnown("condition_expression") || !d.NewValueKnown("microtenant_id") {
+		return d.SetNewComputed("resolved_names")
+	}
+
+	conditions, err := plannedPolicyConditionsV2(d)
+	if err != nil {
+		return err
+	}
+	return planResolvedPolicyOperandNames(ctx, d, zClient, conditions, GetString(d.Get("microtenant_id")))
+}
+
+// planResolvedPolicyOperandNames resolves the name references of conditions
+// and plans resolved_names from them.
+func planResolvedPolicyOperandNames(ctx context.Context, d *schema.ResourceDiff, zClient *Client, conditions []policysetcontrollerv2.PolicyRuleResourceConditions, microTenantID string) error {
+	resolved, pending, err := resolvePolicyOperandNames(ctx, zClient, conditions, microTenantID)
+	if err != nil {
+		return err
+	}
+	if pending {
+		return d.SetNewComputed("resolved_names")
+	}
+
+	previous := map[string]string{}
+	for key, id := range d.Get("resolved_names").(map[string]interface{}) {
+		previous[key], _ = id.(string)
+	}
+	if reflect.DeepEqual(previous, resolved) {
+		return nil
+	}
+	for key, id := range resolved {
+		if old, ok := previous[key]; ok && old != id {
+			log.Printf("[WARN] Policy rule %s: %s now resolves to %s instead of %s, the rule will be updated", d.Id(), key, id, old)
+		}
+	}
+	newValue := make(map[string]interface{}, len(resolved))
+	for key, id := range resolved {
+		newValue[key] = id
+	}
+	return d.SetNew("resolved_names", newValue)
+}
+
+// replacePolicyOperandNames replaces the name references of conditions with
+// the IDs planned in resolved_names.
+func replacePolicyOperandNames(d *schema.ResourceData, conditions []policysetcontrollerv2.PolicyRuleResourceConditions) error {
+	resolved, _ := d.Get("resolved_names").(map[string]interface{})
+	lookup := func(objectType, field, scope, value string) (string, error) {
+		if !isPolicyOperandName(value) {
+			return value, nil
+		}
+		key := policyOperandNameKey(objectType, field, scope, strings.TrimPrefix(value, policyOperandNamePrefix))
+		id, _ := resolved[key].(string)
+		if id == "" {
+			return "", fmt.Errorf("conditions.operands (object_type = %q) %s %q could not be resolved, no object with this name was found", objectType, field, value)
+		}
+		return id, nil
+	}
+
+	for i := range conditions {
+		for j := range conditions[i].Operands {
+			operand := &conditions[i].Operands[j]
+			for k, value := range operand.Values {
+				id, err := lookup(operand.ObjectType, "values", "", value)
+				if err != nil {
+					return err
+				}
+				operand.Values[k] = id
+			}
+			for k, ev := range operand.EntryValuesLHSRHS {
+				lhs, err := lookup(operand.ObjectType, "lhs", "", ev.LHS)
+				if err != nil {
+					return err
+				}
+				rhs, err := lookup(operand.ObjectType, "rhs", lhs, ev.RHS)
+				if err != nil {
+					return err
+				}
+				operand.EntryValuesLHSRHS[k] = policysetcontrollerv2.OperandsResourceLHSRHSValue{LHS: lhs, RHS: rhs}
+			}
+		}
+	}
+	return nil
+}
+
+// restorePolicyOperandNames replaces the IDs of conditions read from the API
+// with the name references that resolved to them, so that the conditions
+// match the configuration. IDs no name resolved to are left as they are.
+func restorePolicyOperandNames(d *schema.ResourceData, conditions []policysetcontrollerv2.PolicyRuleResourceConditions) {
+	resolved, _ := d.Get("resolved_names").(map[string]interface{})
+	if len(resolved) == 0 {
+		return
+	}
+	// Names by the key prefix, which identifies the object type, field and
+	// scope, and the ID they resolved to.
+	type resolvedID struct{ prefix, id string }
+	names := map[resolvedID]string{}
+	for key, id := range resolved {
+		prefix, name, ok := strings.Cut(key, ":")
+		if !ok {
+			continue
+		}
+		names[resolvedID{prefix: prefix, id: fmt.Sprint(id)}] = policyOperandNamePrefix + name
+	}
+	restore := func(objectType, field, scope, value string) string {
+		prefix := strings.TrimSuffix(policyOperandNameKey(objectType, field, scope, ""), ":")
+		if name, ok := names[resolvedID{prefix: prefix, id: value}]; ok {
+			return name
+		}
+		return value
+	}
+
+	for i := range conditions {
+		for j := range conditions[i].Operands {
+			operand := &conditions[i].Operands[j]
+			for k, value := range operand.Values {
+				operand.Values[k] = restore(operand.ObjectType, "values", "", value)
+			}
+			for k, ev := range operand.EntryValuesLHSRHS {
+				operand.EntryValuesLHSRHS[k] = policysetcontrollerv2.OperandsResourceLHSRHSValue{
+					LHS: restore(operand.ObjectType, "lhs", "", ev.LHS),
+					RHS: restore(operand.ObjectType, "rhs", ev.LHS, ev.RHS),
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package zpa
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontrollerv2"
+)
+
+// stubPolicyOperandResolvers replaces the name resolvers of the operand
+// registry for the duration of the test. Names resolve to the IDs of ids,
+// keyed by name, or by the resolved lhs and name for the rhs of entry
+// operands. Other names are not found.
+func stubPolicyOperandResolvers(t *testing.T, ids map[string]string) {
+	registry := policyOperandRegistry
+	t.Cleanup(func() { policyOperandRegistry = registry })
+
+	resolve := func(ctx context.Context, c *Client, microTenantID string, v operandValue, name string) (string, error) {
+		key := name
+		if v.lhs != "" {
+			key = v.lhs + "/" + name
+		}
+		if id, ok := ids[key]; ok {
+			return id, nil
+		}
+		return "", fmt.Errorf("%w: no object named %q", errPolicyReferenceNotFound, name)
+	}
+	policyOperandRegistry = make([]*policyOperandType, len(registry))
+	for i, operandType := range registry {
+		stub := *operandType
+		if stub.lhs.resolve != nil {
+			stub.lhs.resolve = resolve
+		}
+		if stub.rhs.resolve != nil {
+			stub.rhs.resolve = resolve
+		}
+		policyOperandRegistry[i] = &stub
+	}
+}
+
+// testPolicyOperandNameIDs are the IDs of the stubbed resolvers: two IdPs with
+// a SCIM group of the same name.
+var testPolicyOperandNameIDs = map[string]string{
+	"Finance Apps":    "101",
+	"Okta":            "11",
+	"Azure":           "12",
+	"11/Engineering":  "1001",
+	"12/Engineering":  "2002",
+	"11/Finance Team": "1002",
+}
+
+func entryValues(pairs ...string) []policysetcontrollerv2.OperandsResourceLHSRHSValue {
+	var values []policysetcontrollerv2.OperandsResourceLHSRHSValue
+	for i := 0; i+1 < len(pairs); i += 2 {
+		values = append(values, policysetcontrollerv2.OperandsResourceLHSRHSValue{LHS: pairs[i], RHS: pairs[i+1]})
+	}
+	return values
+}
+
+func TestResolvePolicyOperandNames(t *testing.T) {
+	stubPolicyOperandResolvers(t, testPolicyOperandNameIDs)
+
+	for name, tc := range map[string]struct {
+		operands    []policysetcontrollerv2.PolicyRuleResourceOperands
+		want        map[string]string
+		wantPending bool
+		wantErr     string
+	}{
+		"values and entries": {
+			operands: []policysetcontrollerv2.PolicyRuleResourceOperands{
+				{ObjectType: "APP", Values: []string{"name:Finance Apps", "72"}},
+				{ObjectType: "SCIM_GROUP", EntryValuesLHSRHS: entryValues("name:Okta", "name:Engineering", "name:Okta", "name:Finance Team")},
+			},
+			want: map[string]string{
+				"APP.values:Finance Apps":         "101",
+				"SCIM_GROUP.lhs:Okta":             "11",
+				"SCIM_GROUP.rhs(11):Engineering":  "1001",
+				"SCIM_GROUP.rhs(11):Finance Team": "1002",
+			},
+		},
+		"rhs scoped by the resolved lhs": {
+			operands: []policysetcontrollerv2.PolicyRuleResourceOperands{
+				{ObjectType: "SCIM_GROUP", EntryValuesLHSRHS: entryValues("name:Okta", "name:Engineering", "name:Azure", "name:Engineering", "12", "name:Engineering")},
+			},
+			want: map[string]string{
+				"SCIM_GROUP.lhs:Okta":            "11",
+				"SCIM_GROUP.lhs:Azure":           "12",
+				"SCIM_GROUP.rhs(11):Engineering": "1001",
+				"SCIM_GROUP.rhs(12):Engineering": "2002",
+			},
+		},
+		"name not found": {
+			operands: []policysetcontrollerv2.PolicyRuleResourceOperands{
+				{ObjectType: "APP", Values: []string{"name:Finance Apps", "name:Created Later"}},
+			},
+			want:        map[string]string{"APP.values:Finance Apps": "101"},
+			wantPending: true,
+		},
+		"lhs not found": {
+			operands: []policysetcontrollerv2.PolicyRuleResourceOperands{
+				{ObjectType: "SCIM_GROUP", EntryValuesLHSRHS: entryValues("name:Created Later", "name:Engineering")},
+			},
+			want:        map[string]string{},
+			wantPending: true,
+		},
+		"rhs not found for the lhs": {
+			operands: []policysetcontrollerv2.PolicyRuleResourceOperands{
+				{ObjectType: "SCIM_GROUP", EntryValuesLHSRHS: entryValues("name:Azure", "name:Finance Team")},
+			},
+			want:        map[string]string{"SCIM_GROUP.lhs:Azure": "12"},
+			wantPending: true,
+		},
+		"no names": {
+			operands: []policysetcontrollerv2.PolicyRuleResourceOperands{
+				{ObjectType: "APP", Values: []string{"72"}},
+			},
+			want: map[string]string{},
+		},
+		"unsupported object type": {
+			operands: []policysetcontrollerv2.PolicyRuleResourceOperands{
+				{ObjectType: "APP", Values: []string{"name:Finance Apps"}},
+				{ObjectType: "CLIENT_TYPE", Values: []string{"name:Zscaler Client Connector"}},
+			},
+			wantErr: `conditions.operands (object_type = "CLIENT_TYPE") values "name:Zscaler Client Connector": CLIENT_TYPE does not support name references, use a client type`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			conditions := []policysetcontrollerv2.PolicyRuleResourceConditions{{Operator: "OR", Operands: tc.operands}}
+			resolved, pending, err := resolvePolicyOperandNames(context.Background(), &Client{}, conditions, "")
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("got error %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pending != tc.wantPending {
+				t.Errorf("pending = %t, want %t", pending, tc.wantPending)
+			}
+			if !reflect.DeepEqual(resolved, tc.want) {
+				t.Errorf("resolved = %v, want %v", resolved, tc.want)
+			}
+		})
+	}
+}
+
+func testPolicyOperandNamesData(t *testing.T, resolved map[string]interface{}) *schema.ResourceData {
+	d := schema.TestResourceDataRaw(t, map[string]*schema.Schema{"resolved_names": policyOperandNamesSchema()}, nil)
+	if err := d.Set("resolved_names", resolved); err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestReplaceAndRestorePolicyOperandNames(t *testing.T) {
+	d := testPolicyOperandNamesData(t, map[string]interface{}{
+		"APP.values:Finance Apps":        "101",
+		"SCIM_GROUP.lhs:Okta":            "11",
+		"SCIM_GROUP.rhs(11):Engineering": "1001",
+		"SCIM_GROUP.rhs(12):Engineering": "2002",
+	})
+
+	configured := []policysetcontrollerv2.PolicyRuleResourceConditions{{Operator: "OR", Operands: []policysetcontrollerv2.PolicyRuleResourceOperands{
+		{ObjectType: "APP", Values: []string{"name:Finance Apps", "72"}},
+		{ObjectType: "SCIM_GROUP", EntryValuesLHSRHS: entryValues("name:Okta", "name:Engineering", "12", "name:Engineering")},
+	}}}
+	sent := []policysetcontrollerv2.PolicyRuleResourceConditions{{Operator: "OR", Operands: []policysetcontrollerv2.PolicyRuleResourceOperands{
+		{ObjectType: "APP", Values: []string{"101", "72"}},
+		{ObjectType: "SCIM_GROUP", EntryValuesLHSRHS: entryValues("11", "1001", "12", "2002")},
+	}}}
+
+	conditions := copyPolicyConditionsV2(configured)
+	if err := replacePolicyOperandNames(d, conditions); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(conditions, sent) {
+		t.Errorf("replaced conditions = %+v, want %+v", conditions, sent)
+	}
+
+	restorePolicyOperandNames(d, conditions)
+	if !reflect.DeepEqual(conditions, configured) {
+		t.Errorf("restored conditions = %+v, want %+v", conditions, configured)
+	}
+
+	// IDs no name resolved to are left as they are, including an ID a name
+	// resolved to for another object type, field or lhs
+	read := []policysetcontrollerv2.PolicyRuleResourceConditions{{Operator: "OR", Operands: []policysetcontrollerv2.PolicyRuleResourceOperands{
+		{ObjectType: "APP", Values: []string{"73"}},
+		{ObjectType: "APP_GROUP", Values: []string{"101"}},
+		{ObjectType: "SCIM_GROUP", EntryValuesLHSRHS: entryValues("13", "1001", "11", "1003", "12", "11")},
+	}}}
+	want := []policysetcontrollerv2.PolicyRuleResourceConditions{{Operator: "OR", Operands: []policysetcontrollerv2.PolicyRuleResourceOperands{
+		{ObjectType: "APP", Values: []string{"73"}},
+		{ObjectType: "APP_GROUP", Values: []string{"101"}},
+		{ObjectType: "SCIM_GROUP", EntryValuesLHSRHS: entryValues("13", "1001", "name:Okta", "1003", "12", "11")},
+	}}}
+	restorePolicyOperandNames(d, read)
+	if !reflect.DeepEqual(read, want) {
+		t.Errorf("restored conditions = %+v, want %+v", read, want)
+	}
+
+	// A name that was not planned cannot be replaced
+	missing := []policysetcontrollerv2.PolicyRuleResourceConditions{{Operator: "OR", Operands: []policysetcontrollerv2.PolicyRuleResourceOperands{
+		{ObjectType: "APP", Values: []string{"name:Unknown"}},
+	}}}
+	if err := replacePolicyOperandNames(d, missing); err == nil || !strings.Contains(err.Error(), `values "name:Unknown" could not be resolved`) {
+		t.Errorf("expected an error for a name that was not planned, got %v", err)
+	}
+}
+
+func TestPlanPolicyOperandNames(t *testing.T) {
+	ids := map[string]string{"Finance Apps": "101"}
+	stubPolicyOperandResolvers(t, ids)
+
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"condition_expression": {Type: schema.TypeString, Optional: true},
+			"conditions":           {Type: schema.TypeSet, Optional: true, Elem: &schema.Schema{Type: schema.TypeString}},
+			"microtenant_id":       {Type: schema.TypeString, Optional: true},
+			"resolved_names":       policyOperandNamesSchema(),
+		},
+		CustomizeDiff: planPolicyOperandNames,
+	}
+	const key = "APP.values:Finance Apps"
+	state := &terraform.InstanceState{ID: "1", Attributes: map[string]string{
+		"id":                    "1",
+		"condition_expression":  `APP in ["name:Finance Apps"]`,
+		"resolved_names.%":      "1",
+		"resolved_names." + key: "101",
+	}}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{"condition_expression": `APP in ["name:Finance Apps"]`})
+
+	for _, tc := range []struct {
+		name         string
+		id           string // the ID the name resolves to, not found when empty
+		wantNew      string // the planned ID, unchanged when empty
+		wantComputed bool
+	}{
+		{name: "unchanged", id: "101"},
+		{name: "resolves to another object", id: "102", wantNew: "102"},
+		{name: "not found", wantComputed: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			delete(ids, "Finance Apps")
+			if tc.id != "" {
+				ids["Finance Apps"] = tc.id
+			}
+			// Each plan uses a new client, whose cache is empty
+			diff, err := r.Diff(context.Background(), state, config, &Client{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff == nil {
+				diff = terraform.NewInstanceDiff()
+			}
+
+			computed := false
+			for attribute, attrDiff := range diff.Attributes {
+				if strings.HasPrefix(attribute, "resolved_names") && attrDiff.NewComputed {
+					computed = true
+				}
+			}
+			if computed != tc.wantComputed {
+				t.Errorf("resolved_names computed = %t, want %t", computed, tc.wantComputed)
+			}
+			attrDiff := diff.Attributes["resolved_names."+key]
+			switch {
+			case tc.wantNew == "" && attrDiff != nil && !tc.wantComputed:
+				t.Errorf("expected no change of %s, got %q -> %q", key, attrDiff.Old, attrDiff.New)
+			case tc.wantNew != "" && (attrDiff == nil || attrDiff.Old != "101" || attrDiff.New != tc.wantNew):
+				t.Errorf("expected %s to change from 101 to %s, got %+v", key, tc.wantNew, attrDiff)
+			}
+		})
+	}
+}
@@ -0,0 +1,538 @@
+package zpa
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/applicationsegment"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/branch_connector_group"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/cloud_connector_group"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/idpcontroller"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/location_controller"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/machinegroup"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/managed_browser"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/postureprofile"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/samlattribute"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/scimattributeheader"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/scimgroup"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/segmentgroup"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/trustednetwork"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/userportal/portal_controller"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/workload_tag_group"
+)
+
+// Value kinds of policy operands. In v2 resources, ID and enum operands are
+// set with "values" and entry operands with "entry_values". In v1 resources
+// ID and enum operands use lhs = "id" and the value in rhs.
+const (
+	operandValueID    = "id"
+	operandValueEnum  = "enum"
+	operandValueEntry = "entry"
+)
+
+// Policy types that accept conditions. allPolicyTypes marks operand types
+// accepted by every one of them.
+const (
+	policyTypeAccess            = "ACCESS_POLICY"
+	policyTypeTimeout           = "TIMEOUT_POLICY"
+	policyTypeForwarding        = "CLIENT_FORWARDING_POLICY"
+	policyTypeInspection        = "INSPECTION_POLICY"
+	policyTypeIsolation         = "ISOLATION_POLICY"
+	policyTypeRedirection       = "REDIRECTION_POLICY"
+	policyTypeSessionProtection = "CLIENTLESS_SESSION_PROTECTION_POLICY"
+	allPolicyTypes              = "*"
+)
+
+const operandBooleanDescription = "\"true\" or \"false\""
+
+// operandValue is a single lhs/rhs pair of an operand. idpID is only set by
+// v1 SCIM operands.
+type operandValue struct {
+	lhs   string
+	rhs   string
+	idpID string
+}
+
+// operandLookup checks that the tenant object referenced by one side of an
+// operand value exists.
+type operandLookup func(ctx context.Context, c *Client, microTenantID string, v operandValue) error
+
+// operandListing returns the IDs of every tenant object that can be used on
+// one side of an operand. The values of sides with a listing are checked
+// against a single listing per object type and microtenant instead of one
+// lookup per value.
+type operandListing func(ctx context.Context, c *Client, microTenantID string) ([]string, error)
+
+// operandResolver returns the ID of the tenant object with the given name on
+// one side of an operand value. For the rhs of entry operands, v.lhs holds the
+// resolved lhs.
+type operandResolver func(ctx context.Context, c *Client, microTenantID string, v operandValue, name string) (string, error)
+
+// operandSide describes what is accepted on one side of an operand value.
+// allowed and check are offline checks; list, or lookup when the objects
+// cannot be listed, is run against the tenant. Sides with resolve accept
+// "name:" references.
+type operandSide struct {
+	description string
+	allowed     []string
+	check       func(value string) error
+	list        operandListing
+	lookup      operandLookup
+	resolve     operandResolver
+}
+
+// validatedAgainstTenant reports whether the values of the side are looked up
+// in the tenant.
+func (s operandSide) validatedAgainstTenant() bool {
+	return s.list != nil || s.lookup != nil
+}
+
+func (s operandSide) validate(value string) error {
+	if value == "" {
+		return fmt.Errorf("must be %s", s.description)
+	}
+	if len(s.allowed) > 0 && !contains(s.allowed, value) {
+		return fmt.Errorf("must be one of %s, got %q", strings.Join(quoteAll(s.allowed), ", "), value)
+	}
+	if s.check != nil {
+		return s.check(value)
+	}
+	return nil
+}
+
+// policyOperandType is the single definition of an operand object type, used
+// by the v1 and v2 policy rule validators, the conditions schemas and the
+// zpa_policy_operand_types data source.
+type policyOperandType struct {
+	objectType string
+	valueKind  string
+	// For ID and enum operands only rhs is used.
+	lhs operandSide
+	rhs operandSide
+	// v1rhs replaces rhs in v1 operands, which have always been checked more
+	// strictly than v2 operands for some object types.
+	v1rhs *operandSide
+	// v1RequiresIdP is set when v1 operands must carry idp_id.
+	v1RequiresIdP bool
+	// microtenantScoped lookups are sent with the microtenant of the rule.
+	microtenantScoped bool
+	policyTypes       []string
+}
+
+func (t *policyOperandType) allowedIn(policyType string) bool {
+	return contains(t.policyTypes, allPolicyTypes) || contains(t.policyTypes, policyType)
+}
+
+// validateValue checks a single operand value offline.
+func (t *policyOperandType) validateValue(v operandValue) error {
+	if t.valueKind == operandValueEntry {
+		if err := t.lhs.validate(v.lhs); err != nil {
+			return fmt.Errorf("lhs %v", err)
+		}
+	}
+	if err := t.rhs.validate(v.rhs); err != nil {
+		if t.valueKind == operandValueEntry {
+			return fmt.Errorf("rhs %v", err)
+		}
+		return fmt.Errorf("value %v", err)
+	}
+	return nil
+}
+
+var policyOperandRegistry = []*policyOperandType{
+	{
+		objectType:        "APP",
+		valueKind:         operandValueID,
+		rhs:               operandSide{description: "an application segment ID", list: listApplicationSegments, resolve: resolveApplicationSegment},
+		microtenantScoped: true,
+		policyTypes:       []string{policyTypeAccess, policyTypeTimeout, policyTypeForwarding, policyTypeInspection, policyTypeIsolation, policyTypeSessionProtection},
+	},
+	{
+		objectType:        "APP_GROUP",
+		valueKind:         operandValueID,
+		rhs:               operandSide{description: "a segment group ID", list: listSegmentGroups, resolve: resolveSegmentGroup},
+		microtenantScoped: true,
+		policyTypes:       []string{policyTypeAccess, policyTypeTimeout, policyTypeForwarding, policyTypeInspection, policyTypeIsolation, policyTypeSessionProtection},
+	},
+	{
+		objectType:  "LOCATION",
+		valueKind:   operandValueID,
+		rhs:         operandSide{description: "a location ID", list: listLocations},
+		policyTypes: []string{policyTypeAccess},
+	},
+	{
+		objectType:  "IDP",
+		valueKind:   operandValueID,
+		rhs:         operandSide{description: "an IdP ID", list: listIdPs, resolve: resolveIdP},
+		policyTypes: []string{policyTypeAccess, policyTypeTimeout, policyTypeForwarding, policyTypeInspection, policyTypeIsolation},
+	},
+	{
+		objectType:  "SAML",
+		valueKind:   operandValueEntry,
+		lhs:         operandSide{description: "a SAML attribute ID", list: listSAMLAttributes},
+		rhs:         operandSide{description: "a SAML attribute value, for example an email address, department or group"},
+		policyTypes: []string{policyTypeAccess, policyTypeTimeout, policyTypeForwarding, policyTypeInspection, policyTypeIsolation, policyTypeSessionProtection},
+	},
+	{
+		objectType:    "SCIM",
+		valueKind:     operandValueEntry,
+		lhs:           operandSide{description: "a SCIM attribute header ID", lookup: lookupSCIMAttribute},
+		rhs:           operandSide{description: "a SCIM attribute value", lookup: lookupSCIMAttributeValue},
+		v1RequiresIdP: true,
+		policyTypes:   []string{policyTypeAccess, policyTypeTimeout, policyTypeForwarding, policyTypeInspection, policyTypeIsolation, policyTypeSessionProtection},
+	},
+	{
+		objectType:  "SCIM_GROUP",
+		valueKind:   operandValueEntry,
+		lhs:         operandSide{description: "an IdP ID", list: listIdPs, resolve: resolveIdP},
+		rhs:         operandSide{description: "a SCIM group ID", lookup: lookupSCIMGroup, resolve: resolveSCIMGroup},
+		policyTypes: []string{policyTypeAccess, policyTypeTimeout, policyTypeForwarding, policyTypeInspection, policyTypeIsolation, policyTypeSessionProtection},
+	},
+	{
+		objectType: "CLIENT_TYPE",
+		valueKind:  operandValueEnum,
+		rhs: operandSide{description: "a client type", allowed: []string{
+			"zpn_client_type_exporter",
+			"zpn_client_type_exporter_noauth",
+			"zpn_client_type_machine_tunnel",
+			"zpn_client_type_edge_connector",
+			"zpn_client_type_zia_inspection",
+			"zpn_client_type_vdi",
+			"zpn_client_type_zapp",
+			"zpn_client_type_slogger",
+			"zpn_client_type_browser_isolation",
+			"zpn_client_type_ip_anchoring",
+			"zpn_client_type_zapp_partner",
+			"zpn_client_type_branch_connector",
+		}},
+		policyTypes: []string{allPolicyTypes},
+	},
+	{
+		objectType:  "POSTURE",
+		valueKind:   operandValueEntry,
+		lhs:         operandSide{description: "a posture profile UDID", lookup: lookupPostureProfile, resolve: resolvePostureProfile},
+		rhs:         operandSide{description: operandBooleanDescription, allowed: []string{"true", "false"}},
+		policyTypes: []string{policyTypeAccess, policyTypeTimeout, policyTypeForwarding, policyTypeInspection},
+	},
+	{
+		objectType:  "TRUSTED_NETWORK",
+		valueKind:   operandValueEntry,
+		lhs:         operandSide{description: "a trusted network ID", lookup: lookupTrustedNetwork, resolve: resolveTrustedNetwork},
+		rhs:         operandSide{description: operandBooleanDescription, allowed: []string{"true", "false"}},
+		v1rhs:       &operandSide{description: "\"true\"", allowed: []string{"true"}},
+		policyTypes: []string{policyTypeAccess, policyTypeForwarding, policyTypeInspection},
+	},
+	{
+		objectType:  "BRANCH_CONNECTOR_GROUP",
+		valueKind:   operandValueID,
+		rhs:         operandSide{description: "a branch connector group ID", list: listBranchConnectorGroups},
+		policyTypes: []string{policyTypeAccess, policyTypeForwarding},
+	},
+	{
+		objectType:  "EDGE_CONNECTOR_GROUP",
+		valueKind:   operandValueID,
+		rhs:         operandSide{description: "a cloud connector group ID", list: listCloudConnectorGroups, resolve: resolveCloudConnectorGroup},
+		policyTypes: []string{policyTypeAccess, policyTypeForwarding, policyTypeInspection, policyTypeIsolation},
+	},
+	{
+		objectType:        "MACHINE_GRP",
+		valueKind:         operandValueID,
+		rhs:               operandSide{description: "a machine group ID", list: listMachineGroups, resolve: resolveMachineGroup},
+		microtenantScoped: true,
+		policyTypes:       []string{policyTypeAccess, policyTypeForwarding},
+	},
+	{
+		objectType:  "COUNTRY_CODE",
+		valueKind:   operandValueEntry,
+		lhs:         operandSide{description: "an ISO-3166 Alpha-2 country code, or a country alias such as @EU", check: checkCountryCodeOperand},
+		rhs:         operandSide{description: "\"true\"", allowed: []string{"true"}},
+		policyTypes: []string{policyTypeAccess},
+	},
+	{
+		objectType:  "PLATFORM",
+		valueKind:   operandValueEntry,
+		lhs:         operandSide{description: "a platform", allowed: []string{"mac", "linux", "ios", "windows", "android"}},
+		rhs:         operandSide{description: "\"true\""},
+		v1rhs:       &operandSide{description: "\"true\"", allowed: []string{"true"}},
+		policyTypes: []string{policyTypeAccess, policyTypeTimeout, policyTypeForwarding, policyTypeIsolation},
+	},
+	{
+		objectType:  "RISK_FACTOR_TYPE",
+		valueKind:   operandValueEntry,
+		lhs:         operandSide{description: "\"ZIA\"", allowed: []string{"ZIA"}},
+		rhs:         operandSide{description: "a risk score", allowed: []string{"UNKNOWN", "LOW", "MEDIUM", "HIGH", "CRITICAL"}},
+		policyTypes: []string{policyTypeAccess},
+	},
+	{
+		objectType:  "CHROME_ENTERPRISE",
+		valueKind:   operandValueEntry,
+		lhs:         operandSide{description: "\"managed\"", allowed: []string{"managed"}},
+		rhs:         operandSide{description: operandBooleanDescription, allowed: []string{"true", "false"}},
+		policyTypes: []string{policyTypeAccess, policyTypeIsolation},
+	},
+	{
+		objectType:  "CHROME_POSTURE_PROFILE",
+		valueKind:   operandValueID,
+		rhs:         operandSide{description: "a Chrome posture profile ID", list: listChromePostureProfiles},
+		policyTypes: []string{policyTypeAccess, policyTypeIsolation},
+	},
+	{
+		objectType:  "WORKLOAD_TAG_GROUP",
+		valueKind:   operandValueID,
+		rhs:         operandSide{description: "a workload tag group ID", list: listWorkloadTagGroups},
+		policyTypes: []string{policyTypeAccess},
+	},
+	{
+		objectType:        "USER_PORTAL",
+		valueKind:         operandValueID,
+		rhs:               operandSide{description: "a user portal ID", lookup: lookupUserPortal},
+		microtenantScoped: true,
+		policyTypes:       []string{policyTypeSessionProtection},
+	},
+}
+
+// lookupPolicyOperandType returns the registry entry of objectType.
+func lookupPolicyOperandType(objectType string) (*policyOperandType, bool) {
+	for _, t := range policyOperandRegistry {
+		if t.objectType == objectType {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// policyOperandObjectTypes returns the object types accepted by conditions of
+// the given policy type, in registry order.
+func policyOperandObjectTypes(policyType string) []string {
+	var objectTypes []string
+	for _, t := range policyOperandRegistry {
+		if t.allowedIn(policyType) {
+			objectTypes = append(objectTypes, t.objectType)
+		}
+	}
+	return objectTypes
+}
+
+func quoteAll(values []string) []string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = fmt.Sprintf("%q", v)
+	}
+	return quoted
+}
+
+func listApplicationSegments(ctx context.Context, c *Client, microTenantID string) ([]string, error) {
+	segments, _, err := applicationsegment.GetAll(ctx, c.Service.WithMicroTenant(microTenantID))
+	ids := make([]string, len(segments))
+	for i, segment := range segments {
+		ids[i] = segment.ID
+	}
+	return ids, err
+}
+
+func listSegmentGroups(ctx context.Context, c *Client, microTenantID string) ([]string, error) {
+	groups, _, err := segmentgroup.GetAll(ctx, c.Service.WithMicroTenant(microTenantID))
+	ids := make([]string, len(groups))
+	for i, group := range groups {
+		ids[i] = group.ID
+	}
+	return ids, err
+}
+
+func listMachineGroups(ctx context.Context, c *Client, microTenantID string) ([]string, error) {
+	groups, _, err := machinegroup.GetAll(ctx, c.Service.WithMicroTenant(microTenantID))
+	ids := make([]string, len(groups))
+	for i, group := range groups {
+		ids[i] = group.ID
+	}
+	return ids, err
+}
+
+func listCloudConnectorGroups(ctx context.Context, c *Client, _ string) ([]string, error) {
+	groups, _, err := cloud_connector_group.GetAll(ctx, c.Service)
+	ids := make([]string, len(groups))
+	for i, group := range groups {
+		ids[i] = group.ID
+	}
+	return ids, err
+}
+
+func listBranchConnectorGroups(ctx context.Context, c *Client, _ string) ([]string, error) {
+	groups, _, err := branch_connector_group.GetBranchConnectorGroupSummary(ctx, c.Service)
+	ids := make([]string, len(groups))
+	for i, group := range groups {
+		ids[i] = group.ID
+	}
+	return ids, err
+}
+
+func listLocations(ctx context.Context, c *Client, _ string) ([]string, error) {
+	locations, _, err := location_controller.GetLocationSummary(ctx, c.Service)
+	ids := make([]string, len(locations))
+	for i, location := range locations {
+		ids[i] = location.ID
+	}
+	return ids, err
+}
+
+func listChromePostureProfiles(ctx context.Context, c *Client, _ string) ([]string, error) {
+	profiles, _, err := managed_browser.GetAll(ctx, c.Service)
+	ids := make([]string, len(profiles))
+	for i, profile := range profiles {
+		ids[i] = profile.ID
+	}
+	return ids, err
+}
+
+func listWorkloadTagGroups(ctx context.Context, c *Client, _ string) ([]string, error) {
+	groups, _, err := workload_tag_group.GetWorkloadTagGroup(ctx, c.Service)
+	ids := make([]string, len(groups))
+	for i, group := range groups {
+		ids[i] = group.ID
+	}
+	return ids, err
+}
+
+func listIdPs(ctx context.Context, c *Client, _ string) ([]string, error) {
+	idps, _, err := idpcontroller.GetAll(ctx, c.Service)
+	ids := make([]string, len(idps))
+	for i, idp := range idps {
+		ids[i] = idp.ID
+	}
+	return ids, err
+}
+
+func listSAMLAttributes(ctx context.Context, c *Client, _ string) ([]string, error) {
+	attributes, _, err := samlattribute.GetAll(ctx, c.Service)
+	ids := make([]string, len(attributes))
+	for i, attribute := range attributes {
+		ids[i] = attribute.ID
+	}
+	return ids, err
+}
+
+// lookupUserPortal looks user portals up one by one, as they are only
+// searched by name.
+func lookupUserPortal(ctx context.Context, c *Client, microTenantID string, v operandValue) error {
+	_, _, err := portal_controller.Get(ctx, c.Service.WithMicroTenant(microTenantID), v.rhs)
+	return err
+}
+
+func lookupPostureProfile(ctx context.Context, c *Client, _ string, v operandValue) error {
+	_, _, err := postureprofile.GetByPostureUDID(ctx, c.Service, v.lhs)
+	return policyReferenceSearchError(err)
+}
+
+func lookupTrustedNetwork(ctx context.Context, c *Client, _ string, v operandValue) error {
+	_, _, err := trustednetwork.GetByNetID(ctx, c.Service, v.lhs)
+	return policyReferenceSearchError(err)
+}
+
+func lookupSCIMGroup(ctx context.Context, c *Client, _ string, v operandValue) error {
+	_, _, err := scimgroup.Get(ctx, c.Service, v.rhs)
+	return err
+}
+
+// scimIdP returns the IdP that owns the SCIM attribute of v. v1 operands carry
+// it in idp_id, v2 operands only carry the attribute ID.
+func scimIdP(ctx context.Context, c *Client, v operandValue) (string, error) {
+	if v.idpID != "" {
+		return v.idpID, nil
+	}
+	return c.scimAttributeIdP(ctx, v.lhs)
+}
+
+func lookupSCIMAttribute(ctx context.Context, c *Client, _ string, v operandValue) error {
+	idpID, err := scimIdP(ctx, c, v)
+	if err != nil {
+		return err
+	}
+	_, _, err = scimattributeheader.Get(ctx, c.Service, idpID, v.lhs)
+	return err
+}
+
+func lookupSCIMAttributeValue(ctx context.Context, c *Client, _ string, v operandValue) error {
+	idpID, err := scimIdP(ctx, c, v)
+	if err != nil {
+		// Reported on the lhs of the operand.
+		return nil
+	}
+	values, err := scimattributeheader.GetValues(ctx, c.Service, idpID, v.lhs)
+	if err != nil {
+		return err
+	}
+	// SCIM attribute values (emails, names, etc.) are case-insensitive per
+	// RFC 7643, so "adam@example.com" matches "Adam@example.com".
+	for _, value := range values {
+		if strings.EqualFold(value, v.rhs) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: not one of the values of SCIM attribute %s: %v", errPolicyReferenceNotFound, v.lhs, values)
+}
+
+func resolveApplicationSegment(ctx context.Context, c *Client, microTenantID string, _ operandValue, name string) (string, error) {
+	res, _, err := applicationsegment.GetByName(ctx, c.Service.WithMicroTenant(microTenantID), name)
+	if err != nil {
+		return "", policyReferenceSearchError(err)
+	}
+	return res.ID, nil
+}
+
+func resolveSegmentGroup(ctx context.Context, c *Client, microTenantID string, _ operandValue, name string) (string, error) {
+	res, _, err := segmentgroup.GetByName(ctx, c.Service.WithMicroTenant(microTenantID), name)
+	if err != nil {
+		return "", policyReferenceSearchError(err)
+	}
+	return res.ID, nil
+}
+
+func resolveMachineGroup(ctx context.Context, c *Client, microTenantID string, _ operandValue, name string) (string, error) {
+	res, _, err := machinegroup.GetByName(ctx, c.Service.WithMicroTenant(microTenantID), name)
+	if err != nil {
+		return "", policyReferenceSearchError(err)
+	}
+	return res.ID, nil
+}
+
+func resolveCloudConnectorGroup(ctx context.Context, c *Client, _ string, _ operandValue, name string) (string, error) {
+	res, _, err := cloud_connector_group.GetByName(ctx, c.Service, name)
+	if err != nil {
+		return "", policyReferenceSearchError(err)
+	}
+	return res.ID, nil
+}
+
+func resolveIdP(ctx context.Context, c *Client, _ string, _ operandValue, name string) (string, error) {
+	res, _, err := idpcontroller.GetByName(ctx, c.Service, name)
+	if err != nil {
+		return "", policyReferenceSearchError(err)
+	}
+	return res.ID, nil
+}
+
+func resolveSCIMGroup(ctx context.Context, c *Client, _ string, v operandValue, name string) (string, error) {
+	res, _, err := scimgroup.GetByName(ctx, c.Service, name, v.lhs)
+	if err != nil {
+		return "", policyReferenceSearchError(err)
+	}
+	return strconv.FormatInt(int64(res.ID), 10), nil
+}
+
+func resolvePostureProfile(ctx context.Context, c *Client, _ string, _ operandValue, name string) (string, error) {
+	res, _, err := postureprofile.GetByName(ctx, c.Service, name)
+	if err != nil {
+		return "", policyReferenceSearchError(err)
+	}
+	return res.PostureudID, nil
+}
+
+func resolveTrustedNetwork(ctx context.Context, c *Client, _ string, _ operandValue, name string) (string, error) {
+	res, _, err := trustednetwork.GetByName(ctx, c.Service, name)
+	if err != nil {
+		return "", policyReferenceSearchError(err)
+	}
+	return res.NetworkID, nil
+}
@@ -0,0 +1,46 @@
+package zpa
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontroller"
+)
+
+// v1 operands keep the checks the v1 policy rule resources have always
+// applied, which are stricter than those of v2 operands.
+func TestValidateOperand_V1Checks(t *testing.T) {
+	for _, tc := range []struct {
+		operand policysetcontroller.Operands
+		wantErr string
+	}{
+		{
+			operand: policysetcontroller.Operands{ObjectType: "PLATFORM", LHS: "windows", RHS: "true"},
+		},
+		{
+			operand: policysetcontroller.Operands{ObjectType: "PLATFORM", LHS: "windows", RHS: "false"},
+			wantErr: `RHS must be "\"true\""`,
+		},
+		{
+			operand: policysetcontroller.Operands{ObjectType: "SCIM", LHS: "1", RHS: "Engineering"},
+			wantErr: "Idp ID must be set",
+		},
+	} {
+		err := validateOperand(context.Background(), tc.operand, nil, "")
+		switch {
+		case tc.wantErr == "" && err != nil:
+			t.Errorf("validateOperand(%+v): %v", tc.operand, err)
+		case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+			t.Errorf("validateOperand(%+v) = %v, want an error containing %q", tc.operand, err, tc.wantErr)
+		}
+	}
+
+	trustedNetwork, _ := lookupPolicyOperandType("TRUSTED_NETWORK")
+	if err := trustedNetwork.v1rhs.validate("false"); err == nil {
+		t.Error("v1 TRUSTED_NETWORK operands accept rhs \"false\"")
+	}
+	if err := trustedNetwork.validateValue(operandValue{lhs: "1", rhs: "false"}); err != nil {
+		t.Errorf("v2 TRUSTED_NETWORK operands reject rhs \"false\": %v", err)
+	}
+}
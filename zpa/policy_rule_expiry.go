@@ -0,0 +1,210 @@
+package zpa
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/errorx"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontrollerv2"
+)
+
+// Values of the expired_policy_rule_action provider attribute.
+const (
+	expiredPolicyRuleDisable = "disable"
+	expiredPolicyRuleDelete  = "delete"
+)
+
+// expiringPolicyTypes are the policy types whose v2 resources support
+// expires_at.
+var expiringPolicyTypes = []string{"ACCESS_POLICY", "CLIENT_FORWARDING_POLICY"}
+
+// The expiry is kept in ZPA at the end of the rule description, so that it
+// survives imports and can be found by the zpa_expired_policy_rules data
+// source.
+var policyRuleExpiryMarker = regexp.MustCompile(`\s*\[expires_at=([^\]]+)\]$`)
+
+// maxPolicyRuleDescriptionLength is the number of characters the API
+// accepts in a rule description, including the expiry marker.
+const maxPolicyRuleDescriptionLength = 10240
+
+func policyRuleExpiresAtSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validation.IsRFC3339Time,
+		Description:  "RFC3339 time after which the rule is disabled or deleted on the next apply, depending on the expired_policy_rule_action provider attribute.",
+	}
+}
+
+func policyRuleExpiredSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Computed:    true,
+		Description: "Whether expires_at has passed and the rule has been disabled or deleted.",
+	}
+}
+
+// splitPolicyRuleExpiry returns the description without the expiry marker,
+// and the expiry time of the marker, if any.
+func splitPolicyRuleExpiry(description string) (string, string) {
+	m := policyRuleExpiryMarker.FindStringSubmatchIndex(description)
+	if m == nil {
+		return description, ""
+	}
+	return description[:m[0]], description[m[2]:m[3]]
+}
+
+func joinPolicyRuleExpiry(description, expiresAt string) string {
+	if expiresAt == "" {
+		return description
+	}
+	return strings.TrimSpace(description + " [expires_at=" + expiresAt + "]")
+}
+
+// validatePolicyRuleDescriptionLength checks that the description still fits
+// the API once the expiry marker has been appended to it.
+func validatePolicyRuleDescriptionLength(description, expiresAt string) error {
+	if n := utf8.RuneCountInString(joinPolicyRuleExpiry(description, expiresAt)); n > maxPolicyRuleDescriptionLength {
+		if expiresAt == "" {
+			return fmt.Errorf("description is %d characters long, the API accepts at most %d", n, maxPolicyRuleDescriptionLength)
+		}
+		return fmt.Errorf("description is %d characters long with the expires_at marker, the API accepts at most %d", n, maxPolicyRuleDescriptionLength)
+	}
+	return nil
+}
+
+func policyRuleExpiryPassed(expiresAt string, now time.Time) bool {
+	if expiresAt == "" {
+		return false
+	}
+	t, err := time.Parse(time.RFC3339, expiresAt)
+	return err == nil && !now.Before(t)
+}
+
+func expiredPolicyRuleAction(meta interface{}) string {
+	if zClient, ok := meta.(*Client); ok && zClient != nil && zClient.expiredRuleAction != "" {
+		return zClient.expiredRuleAction
+	}
+	return expiredPolicyRuleDisable
+}
+
+// withPolicyRuleExpiry plans the expiry of the rule before running next. A
+// rule whose expires_at has passed shows expired going to true, which the
+// update turns into disabling or deleting the rule. Moving expires_at back
+// into the future re-enables a disabled rule and recreates a deleted one.
+func withPolicyRuleExpiry(next schema.CustomizeDiffFunc) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		if d.NewValueKnown("description") && d.NewValueKnown("expires_at") {
+			description, _ := d.Get("description").(string)
+			if err := validatePolicyRuleDescriptionLength(description, d.Get("expires_at").(string)); err != nil {
+				return err
+			}
+		}
+		if d.NewValueKnown("expires_at") {
+			expiresAt := d.Get("expires_at").(string)
+			passed := policyRuleExpiryPassed(expiresAt, time.Now())
+			expired := d.Get("expired").(bool)
+			switch {
+			case d.Id() == "":
+				if passed {
+					return fmt.Errorf("expires_at %s is in the past", expiresAt)
+				}
+			case passed != expired:
+				if err := d.SetNew("expired", passed); err != nil {
+					return err
+				}
+				if expired && expiredPolicyRuleAction(meta) == expiredPolicyRuleDelete && d.HasChange("expires_at") {
+					if err := d.ForceNew("expires_at"); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return next(ctx, d, meta)
+	}
+}
+
+// readPolicyRuleExpiry sets description, expires_at and expired from a rule
+// read from ZPA. It warns when the rule had an expiry in the state but its
+// marker was removed or edited outside Terraform, since the plan then only
+// shows expires_at and description changing back.
+func readPolicyRuleExpiry(d *schema.ResourceData, meta interface{}, description, disabled string) diag.Diagnostics {
+	var diags diag.Diagnostics
+	description, expiresAt := splitPolicyRuleExpiry(description)
+	if previous := d.Get("expires_at").(string); previous != "" && expiresAt == "" {
+		log.Printf("[WARN] Policy rule %s lost its expiry marker [expires_at=%s] in ZPA", d.Id(), previous)
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Policy rule expiry marker missing",
+			Detail: fmt.Sprintf("Policy rule %s had expires_at %s, but the marker [expires_at=%s] is no longer at the end of its description in ZPA; "+
+				"it was removed or edited outside Terraform. The rule no longer expires until the next apply writes the marker back. "+
+				"Description read from ZPA: %q", d.Id(), previous, previous, description),
+		})
+	}
+	passed := policyRuleExpiryPassed(expiresAt, time.Now())
+	if passed && disabled != "1" {
+		log.Printf("[WARN] Policy rule %s expired at %s and is still enabled", d.Id(), expiresAt)
+	}
+	_ = d.Set("description", description)
+	_ = d.Set("expires_at", expiresAt)
+	_ = d.Set("expired", passed && disabled == "1" && expiredPolicyRuleAction(meta) == expiredPolicyRuleDisable)
+	return diags
+}
+
+// keepExpiredPolicyRule reports whether a rule that no longer exists in ZPA
+// was deleted because it expired, in which case it stays in the state so
+// that it isn't created again.
+func keepExpiredPolicyRule(d *schema.ResourceData, meta interface{}) bool {
+	if expiredPolicyRuleAction(meta) != expiredPolicyRuleDelete || !policyRuleExpiryPassed(d.Get("expires_at").(string), time.Now()) {
+		return false
+	}
+	log.Printf("[INFO] Keeping policy rule %s in state because it was deleted when it expired", d.Id())
+	_ = d.Set("expired", true)
+	return true
+}
+
+// expirePolicyRule applies expired_policy_rule_action to a rule whose
+// expires_at has passed. It reports whether the rule was deleted, in which
+// case there is nothing left to update; otherwise req is marked disabled.
+func expirePolicyRule(ctx context.Context, service *zscaler.Service, meta interface{}, policySetID, ruleID string, req *policysetcontrollerv2.PolicyRule) (bool, error) {
+	if expiredPolicyRuleAction(meta) == expiredPolicyRuleDelete {
+		log.Printf("[INFO] Deleting expired policy rule %s\n", ruleID)
+		if _, err := policysetcontrollerv2.Delete(ctx, service, policySetID, ruleID); err != nil {
+			if errResp, ok := err.(*errorx.ErrorResponse); !ok || !errResp.IsObjectNotFound() {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+	log.Printf("[INFO] Disabling expired policy rule %s\n", ruleID)
+	req.Disabled = "1"
+	return false, nil
+}
+
+// reenablePolicyRule marks req enabled when expires_at has moved back into
+// the future, rather than relying on ZPA to enable a rule whose update does
+// not set disabled.
+func reenablePolicyRule(d *schema.ResourceData, req *policysetcontrollerv2.PolicyRule) {
+	if expired, _ := d.GetChange("expired"); expired.(bool) {
+		log.Printf("[INFO] Re-enabling policy rule %s, its expires_at moved to %s\n", d.Id(), d.Get("expires_at"))
+		req.Disabled = "0"
+	}
+}
+
+// ignoreExpiredPolicyRuleNotFound drops the error of deleting a rule that
+// was already deleted when it expired.
+func ignoreExpiredPolicyRuleNotFound(d *schema.ResourceData, err error) error {
+	if errResp, ok := err.(*errorx.ErrorResponse); ok && errResp.IsObjectNotFound() && d.Get("expired").(bool) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,237 @@
+package zpa
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestSplitPolicyRuleExpiry(t *testing.T) {
+	for _, tc := range []struct {
+		description     string
+		wantDescription string
+		wantExpiresAt   string
+	}{
+		{"vendor access [expires_at=2026-11-01T00:00:00Z]", "vendor access", "2026-11-01T00:00:00Z"},
+		{"vendor access  \n[expires_at=2026-11-01T00:00:00+02:00]", "vendor access", "2026-11-01T00:00:00+02:00"},
+		{"[expires_at=2026-11-01T00:00:00Z]", "", "2026-11-01T00:00:00Z"},
+		{"vendor access", "vendor access", ""},
+		{"", "", ""},
+		// Only a marker at the end of the description is an expiry
+		{"[expires_at=2026-11-01T00:00:00Z] vendor access", "[expires_at=2026-11-01T00:00:00Z] vendor access", ""},
+		{"vendor access [expires_at=2026-11-01T00:00:00Z].", "vendor access [expires_at=2026-11-01T00:00:00Z].", ""},
+	} {
+		description, expiresAt := splitPolicyRuleExpiry(tc.description)
+		if description != tc.wantDescription || expiresAt != tc.wantExpiresAt {
+			t.Errorf("splitPolicyRuleExpiry(%q) = %q, %q, want %q, %q", tc.description, description, expiresAt, tc.wantDescription, tc.wantExpiresAt)
+		}
+	}
+}
+
+func TestJoinPolicyRuleExpiry(t *testing.T) {
+	for _, tc := range []struct {
+		description string
+		expiresAt   string
+		want        string
+	}{
+		{"vendor access", "2026-11-01T00:00:00Z", "vendor access [expires_at=2026-11-01T00:00:00Z]"},
+		{"", "2026-11-01T00:00:00Z", "[expires_at=2026-11-01T00:00:00Z]"},
+		{"vendor access", "", "vendor access"},
+		{"", "", ""},
+	} {
+		joined := joinPolicyRuleExpiry(tc.description, tc.expiresAt)
+		if joined != tc.want {
+			t.Errorf("joinPolicyRuleExpiry(%q, %q) = %q, want %q", tc.description, tc.expiresAt, joined, tc.want)
+		}
+		if description, expiresAt := splitPolicyRuleExpiry(joined); description != tc.description || expiresAt != tc.expiresAt {
+			t.Errorf("splitPolicyRuleExpiry(%q) = %q, %q, want %q, %q", joined, description, expiresAt, tc.description, tc.expiresAt)
+		}
+	}
+}
+
+func TestWithPolicyRuleExpiry(t *testing.T) {
+	const (
+		past   = "2020-01-01T00:00:00Z"
+		future = "2099-01-01T00:00:00Z"
+	)
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"description": {Type: schema.TypeString, Optional: true},
+			"expires_at":  policyRuleExpiresAtSchema(),
+			"expired":     policyRuleExpiredSchema(),
+		},
+		CustomizeDiff: withPolicyRuleExpiry(func(context.Context, *schema.ResourceDiff, interface{}) error {
+			return nil
+		}),
+	}
+	state := func(expiresAt string, expired bool) *terraform.InstanceState {
+		s := &terraform.InstanceState{ID: "1", Attributes: map[string]string{"id": "1", "expires_at": expiresAt, "expired": "false"}}
+		if expired {
+			s.Attributes["expired"] = "true"
+		}
+		return s
+	}
+
+	for _, tc := range []struct {
+		name            string
+		state           *terraform.InstanceState
+		description     string
+		expiresAt       string
+		action          string
+		wantErr         *regexp.Regexp
+		wantExpired     string // the planned expired, empty when it does not change
+		wantRequiresNew bool
+	}{
+		{
+			name:      "create in the future",
+			expiresAt: future,
+		},
+		{
+			name:      "create in the past",
+			expiresAt: past,
+			wantErr:   regexp.MustCompile("expires_at 2020-01-01T00:00:00Z is in the past"),
+		},
+		{
+			name:        "description too long with the marker",
+			description: strings.Repeat("x", maxPolicyRuleDescriptionLength-10),
+			expiresAt:   future,
+			wantErr:     regexp.MustCompile("description is 10264 characters long with the expires_at marker, the API accepts at most 10240"),
+		},
+		{
+			name:      "not expired yet",
+			state:     state(future, false),
+			expiresAt: future,
+		},
+		{
+			name:        "expires",
+			state:       state(future, false),
+			expiresAt:   past,
+			wantExpired: "true",
+		},
+		{
+			name:        "passed since the last apply",
+			state:       state(past, false),
+			expiresAt:   past,
+			wantExpired: "true",
+		},
+		{
+			name:      "already expired",
+			state:     state(past, true),
+			expiresAt: past,
+		},
+		{
+			name:        "disabled rule moved into the future",
+			state:       state(past, true),
+			expiresAt:   future,
+			action:      expiredPolicyRuleDisable,
+			wantExpired: "false",
+		},
+		{
+			name:            "deleted rule moved into the future",
+			state:           state(past, true),
+			expiresAt:       future,
+			action:          expiredPolicyRuleDelete,
+			wantExpired:     "unknown",
+			wantRequiresNew: true,
+		},
+	} {
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{"description": tc.description, "expires_at": tc.expiresAt})
+		diff, err := r.Diff(context.Background(), tc.state, config, &Client{expiredRuleAction: tc.action})
+		if tc.wantErr != nil {
+			if err == nil || !tc.wantErr.MatchString(err.Error()) {
+				t.Errorf("%s: got error %v, want %s", tc.name, err, tc.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+			continue
+		}
+		if diff == nil {
+			diff = terraform.NewInstanceDiff()
+		}
+
+		expired := ""
+		if attr := diff.Attributes["expired"]; attr != nil {
+			expired = attr.New
+			if attr.NewComputed {
+				expired = "unknown"
+			}
+		}
+		if tc.state == nil {
+			// expired is computed on create
+			expired = ""
+		}
+		if expired != tc.wantExpired {
+			t.Errorf("%s: planned expired %q, want %q", tc.name, expired, tc.wantExpired)
+		}
+		if requiresNew := diff.RequiresNew(); requiresNew != tc.wantRequiresNew {
+			t.Errorf("%s: requires new %t, want %t", tc.name, requiresNew, tc.wantRequiresNew)
+		}
+	}
+}
+
+func TestValidatePolicyRuleDescriptionLength(t *testing.T) {
+	const expiresAt = "2026-11-01T00:00:00Z" // the marker takes 33 characters and a space
+	for _, tc := range []struct {
+		description string
+		expiresAt   string
+		wantErr     bool
+	}{
+		{strings.Repeat("x", maxPolicyRuleDescriptionLength), "", false},
+		{strings.Repeat("x", maxPolicyRuleDescriptionLength+1), "", true},
+		{strings.Repeat("x", maxPolicyRuleDescriptionLength-34), expiresAt, false},
+		{strings.Repeat("x", maxPolicyRuleDescriptionLength-33), expiresAt, true},
+		{"", expiresAt, false},
+		// Characters, not bytes
+		{strings.Repeat("é", maxPolicyRuleDescriptionLength-34), expiresAt, false},
+	} {
+		err := validatePolicyRuleDescriptionLength(tc.description, tc.expiresAt)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("validatePolicyRuleDescriptionLength(%d characters, %q) = %v, want error %t", len([]rune(tc.description)), tc.expiresAt, err, tc.wantErr)
+		}
+	}
+}
+
+func TestReadPolicyRuleExpiry(t *testing.T) {
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"description": {Type: schema.TypeString, Optional: true},
+			"expires_at":  policyRuleExpiresAtSchema(),
+			"expired":     policyRuleExpiredSchema(),
+		},
+	}
+	for _, tc := range []struct {
+		name            string
+		stateExpiresAt  string
+		description     string
+		wantDescription string
+		wantExpiresAt   string
+		wantWarning     bool
+	}{
+		{"marker kept", "2099-01-01T00:00:00Z", "vendor access [expires_at=2099-01-01T00:00:00Z]", "vendor access", "2099-01-01T00:00:00Z", false},
+		{"marker changed", "2099-01-01T00:00:00Z", "vendor access [expires_at=2099-02-01T00:00:00Z]", "vendor access", "2099-02-01T00:00:00Z", false},
+		{"marker removed", "2099-01-01T00:00:00Z", "vendor access", "vendor access", "", true},
+		{"marker edited", "2099-01-01T00:00:00Z", "vendor access [expires_at=2099-01-01T00:00:00Z] until March", "vendor access [expires_at=2099-01-01T00:00:00Z] until March", "", true},
+		{"no expiry", "", "vendor access", "vendor access", "", false},
+	} {
+		d := r.Data(&terraform.InstanceState{ID: "1", Attributes: map[string]string{"id": "1", "expires_at": tc.stateExpiresAt}})
+		diags := readPolicyRuleExpiry(d, &Client{}, tc.description, "0")
+		if diags.HasError() {
+			t.Errorf("%s: unexpected error %v", tc.name, diags)
+		}
+		if warned := len(diags) > 0; warned != tc.wantWarning {
+			t.Errorf("%s: warned %t, want %t", tc.name, warned, tc.wantWarning)
+		}
+		if description := d.Get("description").(string); description != tc.wantDescription {
+			t.Errorf("%s: description %q, want %q", tc.name, description, tc.wantDescription)
+		}
+		if expiresAt := d.Get("expires_at").(string); expiresAt != tc.wantExpiresAt {
+			t.Errorf("%s: expires_at %q, want %q", tc.name, expiresAt, tc.wantExpiresAt)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package zpa
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontrollerv2"
+)
+
+// policyRuleJSONServerFields are the attributes of rules, conditions and
+// operands that are set by the API. They are left out of exported rule JSON
+// and ignored in rule JSON given to the provider.
+var policyRuleJSONServerFields = []string{
+	"id",
+	"creationTime",
+	"modifiedBy",
+	"modifiedTime",
+	"policySetId",
+	"ruleOrder",
+	"priority",
+	"microtenantId",
+	"microtenantName",
+}
+
+// stripPolicyRuleJSONServerFields removes the attributes set by the API from
+// a decoded rule, its conditions and their operands, and sorts conditions and
+// operands.
+func stripPolicyRuleJSONServerFields(ruleMap map[string]interface{}) {
+	strip := func(m map[string]interface{}) {
+		for _, field := range policyRuleJSONServerFields {
+			delete(m, field)
+		}
+	}
+	sortKey := func(v interface{}) string {
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+
+	strip(ruleMap)
+	conditions, _ := ruleMap["conditions"].([]interface{})
+	for _, condition := range conditions {
+		conditionMap, ok := condition.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		strip(conditionMap)
+		operands, _ := conditionMap["operands"].([]interface{})
+		for _, operand := range operands {
+			if operandMap, ok := operand.(map[string]interface{}); ok {
+				strip(operandMap)
+			}
+		}
+		sort.SliceStable(operands, func(i, j int) bool {
+			return sortKey(operands[i]) < sortKey(operands[j])
+		})
+	}
+	sort.SliceStable(conditions, func(i, j int) bool {
+		return sortKey(conditions[i]) < sortKey(conditions[j])
+	})
+}
+
+func decodePolicyRuleJSON(data []byte) (map[string]interface{}, error) {
+	var ruleMap map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&ruleMap); err != nil {
+		return nil, err
+	}
+	return ruleMap, nil
+}
+
+// parsePolicyRuleJSON parses rule JSON in the format of the rules returned by
+// the API and shown by the admin portal, and converts it to a v2 request with
+// ConvertV1ResponseToV2Request. Attributes set by the API are ignored, other
+// unknown attributes are rejected.
+func parsePolicyRuleJSON(ruleJSON string) (policysetcontrollerv2.PolicyRule, error) {
+	ruleMap, err := decodePolicyRuleJSON([]byte(ruleJSON))
+	if err != nil {
+		if syntaxErr, ok := err.(*json.SyntaxError); ok {
+			return policysetcontrollerv2.PolicyRule{}, fmt.Errorf("invalid JSON at offset %d: %v", syntaxErr.Offset, err)
+		}
+		return policysetcontrollerv2.PolicyRule{}, fmt.Errorf("invalid rule JSON: %v", err)
+	}
+	stripPolicyRuleJSONServerFields(ruleMap)
+	stripped, err := json.Marshal(ruleMap)
+	if err != nil {
+		return policysetcontrollerv2.PolicyRule{}, err
+	}
+	// Unknown attributes, such as misspelled ones, are rejected rather than
+	// silently left out of the rule.
+	var rule policysetcontrollerv2.PolicyRuleResource
+	decoder := json.NewDecoder(bytes.NewReader(stripped))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&rule); err != nil {
+		return policysetcontrollerv2.PolicyRule{}, fmt.Errorf("invalid rule JSON: %v", err)
+	}
+	if rule.Name == "" {
+		return policysetcontrollerv2.PolicyRule{}, fmt.Errorf("invalid rule JSON: name is required")
+	}
+
+	// Operands of object types the v2 API does not aggregate are dropped by
+	// the conversion. Fail instead of silently creating a broader rule.
+	req := ConvertV1ResponseToV2Request(rule)
+	converted := map[string]bool{}
+	for _, condition := range req.Conditions {
+		for _, operand := range condition.Operands {
+			converted[operand.ObjectType] = true
+		}
+	}
+	var dropped []string
+	for _, condition := range rule.Conditions {
+		for _, operand := range condition.Operands {
+			if !converted[operand.ObjectType] && !contains(dropped, operand.ObjectType) {
+				dropped = append(dropped, operand.ObjectType)
+			}
+		}
+	}
+	if len(dropped) > 0 {
+		sort.Strings(dropped)
+		return policysetcontrollerv2.PolicyRule{}, fmt.Errorf("invalid rule JSON: operands with object type %s are not supported by v2 policy rules", strings.Join(dropped, ", "))
+	}
+
+	return req, nil
+}
+
+// validatePolicyRuleJSONConditions checks the operands of a converted rule
+// offline against the operand registry.
+func validatePolicyRuleJSONConditions(req policysetcontrollerv2.PolicyRule, policyType string) error {
+	for _, condition := range req.Conditions {
+		for _, operand := range condition.Operands {
+			t, ok := lookupPolicyOperandType(operand.ObjectType)
+			if !ok {
+				return fmt.Errorf("unknown object type %s", operand.ObjectType)
+			}
+			if !t.allowedIn(policyType) {
+				return fmt.Errorf("object type %s is not supported by %s rules", operand.ObjectType, policyType)
+			}
+			for _, value := range operand.Values {
+				if err := t.validateValue(operandValue{rhs: value}); err != nil {
+					return fmt.Errorf("invalid operand with object type %s: %v", operand.ObjectType, err)
+				}
+			}
+			for _, ev := range operand.EntryValuesLHSRHS {
+				if err := rejectCountryAlias(operand.ObjectType, ev.LHS); err != nil {
+					return err
+				}
+				if err := t.validateValue(operandValue{lhs: ev.LHS, rhs: ev.RHS}); err != nil {
+					return fmt.Errorf("invalid operand with object type %s: %v", operand.ObjectType, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// policyRuleJSONDerivedFields are the attributes the API adds to the objects
+// a rule references, such as the names of server groups, and the converted
+// request attributes that are derived from other attributes.
+var policyRuleJSONDerivedFields = []string{
+	"name",
+	"creationTime",
+	"modifiedBy",
+	"modifiedTime",
+	"microtenantId",
+	"microtenantName",
+	"policyType",
+	"zpnInspectionProfileName",
+}
+
+// canonicalPolicyRuleJSON returns a representation of the v2 request rule
+// JSON converts to, without the attributes set by the API. It is used to
+// suppress diffs between the configured JSON and the JSON read back from the
+// API, which carries more details, such as the names of server groups.
+func canonicalPolicyRuleJSON(ruleJSON string) (string, error) {
+	req, err := parsePolicyRuleJSON(ruleJSON)
+	if err != nil {
+		return "", err
+	}
+	raw, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	ruleMap, err := decodePolicyRuleJSON(raw)
+	if err != nil {
+		return "", err
+	}
+	for _, field := range policyRuleJSONServerFields {
+		delete(ruleMap, field)
+	}
+	for _, field := range policyRuleJSONDerivedFields {
+		if field != "name" {
+			delete(ruleMap, field)
+		}
+	}
+	delete(ruleMap, "conditions")
+
+	canonical := map[string]interface{}{}
+	for key, value := range ruleMap {
+		if value = canonicalPolicyRuleJSONValue(value); value != nil {
+			canonical[key] = value
+		}
+	}
+	operator, _ := canonical["operator"].(string)
+	if operator == "" {
+		operator = "AND"
+	}
+	canonical["operator"] = strings.ToUpper(operator)
+	canonical["conditions"] = canonicalPolicyConditionsV2(req.Conditions)
+
+	b, err := json.Marshal(canonical)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// canonicalPolicyRuleJSONValue returns value without empty attributes, with
+// referenced objects reduced to the attributes the provider sends and lists
+// sorted. Empty values are returned as nil.
+func canonicalPolicyRuleJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		m := map[string]interface{}{}
+		for key, item := range v {
+			if _, ok := v["id"]; ok && contains(policyRuleJSONDerivedFields, key) {
+				continue
+			}
+			if item = canonicalPolicyRuleJSONValue(item); item != nil {
+				m[key] = item
+			}
+		}
+		if len(m) == 0 {
+			return nil
+		}
+		return m
+	case []interface{}:
+		var items []interface{}
+		for _, item := range v {
+			if item = canonicalPolicyRuleJSONValue(item); item != nil {
+				items = append(items, item)
+			}
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		sortKey := func(item interface{}) string {
+			b, _ := json.Marshal(item)
+			return string(b)
+		}
+		sort.SliceStable(items, func(i, j int) bool {
+			return sortKey(items[i]) < sortKey(items[j])
+		})
+		return items
+	case string:
+		if v == "" {
+			return nil
+		}
+	case bool:
+		if !v {
+			return nil
+		}
+	case nil:
+		return nil
+	}
+	return value
+}
+
+// exportPolicyRuleJSON returns the JSON of a rule read from the API, without
+// the attributes set by the API and with conditions and operands sorted, so
+// that it can be given back to zpa_policy_rule_json.
+func exportPolicyRuleJSON(rule policysetcontrollerv2.PolicyRuleResource) (string, error) {
+	raw, err := json.Marshal(rule)
+	if err != nil {
+		return "", err
+	}
+	ruleMap, err := decodePolicyRuleJSON(raw)
+	if err != nil {
+		return "", err
+	}
+	stripPolicyRuleJSONServerFields(ruleMap)
+
+	b, err := json.MarshalIndent(ruleMap, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,175 @@
+package zpa
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontrollerv2"
+)
+
+// testPolicyRuleAPIJSON is a rule as returned by the API, with the attributes
+// it sets and the names it adds to referenced objects.
+const testPolicyRuleAPIJSON = `{
+  "id": "216196257331370181",
+  "name": "Finance",
+  "description": "Finance applications",
+  "action": "ALLOW",
+  "operator": "AND",
+  "policySetId": "216196257331281920",
+  "ruleOrder": "3",
+  "priority": "3",
+  "creationTime": "1717000000",
+  "modifiedBy": "216196257331281921",
+  "modifiedTime": "1717000001",
+  "appServerGroups": [{"id": "216196257331370100", "name": "Finance servers"}],
+  "conditions": [
+    {
+      "id": "1",
+      "operator": "OR",
+      "operands": [
+        {"id": "2", "objectType": "APP", "lhs": "id", "rhs": "216196257331370200"},
+        {"id": "3", "objectType": "APP", "lhs": "id", "rhs": "216196257331370201"}
+      ]
+    },
+    {
+      "id": "4",
+      "operator": "OR",
+      "operands": [
+        {"id": "5", "objectType": "PLATFORM", "lhs": "linux", "rhs": "true"},
+        {"id": "6", "objectType": "PLATFORM", "lhs": "windows", "rhs": "true"}
+      ]
+    }
+  ]
+}`
+
+// testPolicyRuleConfigJSON is the rule of testPolicyRuleAPIJSON as written in
+// a configuration: other key order and whitespace, conditions and operands in
+// another order, and without the attributes set by the API.
+const testPolicyRuleConfigJSON = `{"conditions":[{"operands":[{"rhs":"true","lhs":"windows","objectType":"PLATFORM"},{"objectType":"PLATFORM","lhs":"linux","rhs":"true"}],"operator":"or"},
+	{"operator":"OR","operands":[{"objectType":"APP","lhs":"id","rhs":"216196257331370201"},{"objectType":"APP","lhs":"id","rhs":"216196257331370200"}]}],
+	"appServerGroups":[{"id":"216196257331370100"}], "operator":"AND","action":"ALLOW","description":"Finance applications","name":"Finance"}`
+
+func canonicalTestPolicyRuleJSON(t *testing.T, ruleJSON string) string {
+	t.Helper()
+	canonical, err := canonicalPolicyRuleJSON(ruleJSON)
+	if err != nil {
+		t.Fatalf("canonicalPolicyRuleJSON: %v", err)
+	}
+	return canonical
+}
+
+func TestPolicyRuleJSON_RoundTrip(t *testing.T) {
+	var rule policysetcontrollerv2.PolicyRuleResource
+	if err := json.Unmarshal([]byte(testPolicyRuleAPIJSON), &rule); err != nil {
+		t.Fatal(err)
+	}
+	exported, err := exportPolicyRuleJSON(rule)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var exportedMap map[string]interface{}
+	if err := json.Unmarshal([]byte(exported), &exportedMap); err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{"id", "policySetId", "ruleOrder", "priority", "creationTime", "modifiedBy", "modifiedTime"} {
+		if _, ok := exportedMap[field]; ok {
+			t.Errorf("exported JSON has %s:\n%s", field, exported)
+		}
+	}
+	for _, condition := range exportedMap["conditions"].([]interface{}) {
+		if _, ok := condition.(map[string]interface{})["id"]; ok {
+			t.Errorf("exported condition has an id:\n%s", exported)
+		}
+		for _, operand := range condition.(map[string]interface{})["operands"].([]interface{}) {
+			if _, ok := operand.(map[string]interface{})["id"]; ok {
+				t.Errorf("exported operand has an id:\n%s", exported)
+			}
+		}
+	}
+
+	req, err := parsePolicyRuleJSON(exported)
+	if err != nil {
+		t.Fatalf("the exported JSON does not parse: %v\n%s", err, exported)
+	}
+	if req.Name != "Finance" || req.Action != "ALLOW" || len(req.Conditions) != 2 {
+		t.Errorf("unexpected rule %+v", req)
+	}
+	for _, condition := range req.Conditions {
+		if len(condition.Operands) != 1 {
+			t.Errorf("expected the operands of a condition to be aggregated, got %+v", condition.Operands)
+		}
+	}
+
+	// The exported JSON, the JSON read from the API and the configured JSON
+	// are the same rule.
+	want := canonicalTestPolicyRuleJSON(t, testPolicyRuleAPIJSON)
+	for name, ruleJSON := range map[string]string{"exported": exported, "configured": testPolicyRuleConfigJSON} {
+		if got := canonicalTestPolicyRuleJSON(t, ruleJSON); got != want {
+			t.Errorf("%s: canonical JSON\n%s\nwant\n%s", name, got, want)
+		}
+	}
+
+	// Exporting the rule again gives the same JSON
+	again, err := exportPolicyRuleJSON(rule)
+	if err != nil || again != exported {
+		t.Errorf("export is not stable: %v\n%s\n%s", err, again, exported)
+	}
+}
+
+func TestCanonicalPolicyRuleJSON_Changes(t *testing.T) {
+	original := canonicalTestPolicyRuleJSON(t, testPolicyRuleConfigJSON)
+	for name, tc := range map[string]struct {
+		old, new string
+	}{
+		"operand value": {old: `"rhs":"216196257331370201"`, new: `"rhs":"216196257331370202"`},
+		"entry value":   {old: `"lhs":"windows"`, new: `"lhs":"mac"`},
+		"object type":   {old: `{"objectType":"PLATFORM","lhs":"linux","rhs":"true"}`, new: `{"objectType":"POSTURE","lhs":"linux","rhs":"true"}`},
+		"operator":      {old: `"operator":"or"`, new: `"operator":"AND"`},
+		"server group":  {old: `"id":"216196257331370100"`, new: `"id":"216196257331370101"`},
+		"action":        {old: `"action":"ALLOW"`, new: `"action":"DENY"`},
+		"description":   {old: `"description":"Finance applications"`, new: `"description":"Finance"`},
+	} {
+		changed := strings.Replace(testPolicyRuleConfigJSON, tc.old, tc.new, 1)
+		if changed == testPolicyRuleConfigJSON {
+			t.Fatalf("%s: %s is not part of the test rule", name, tc.old)
+		}
+		if canonicalTestPolicyRuleJSON(t, changed) == original {
+			t.Errorf("%s: the change is suppressed", name)
+		}
+	}
+}
+
+func TestParsePolicyRuleJSON_Errors(t *testing.T) {
+	for name, tc := range map[string]struct {
+		ruleJSON string
+		wantErr  string
+	}{
+		"syntax error":          {ruleJSON: `{"name": "a",}`, wantErr: "invalid JSON at offset 14"},
+		"not an object":         {ruleJSON: `["a"]`, wantErr: "invalid rule JSON"},
+		"no name":               {ruleJSON: `{"action": "ALLOW"}`, wantErr: "name is required"},
+		"unknown attribute":     {ruleJSON: `{"name": "a", "acton": "ALLOW"}`, wantErr: `unknown field "acton"`},
+		"unknown operand field": {ruleJSON: `{"name": "a", "conditions": [{"operands": [{"objectType": "APP", "lhs": "id", "rhs": "1", "value": "1"}]}]}`, wantErr: `unknown field "value"`},
+		"invalid type":          {ruleJSON: `{"name": 5}`, wantErr: "invalid rule JSON"},
+		"invalid conditions":    {ruleJSON: `{"name": "a", "conditions": {"operator": "OR"}}`, wantErr: "invalid rule JSON"},
+		"unsupported object type": {
+			ruleJSON: `{"name": "a", "conditions": [{"operands": [{"objectType": "FOO", "lhs": "id", "rhs": "1"}]}]}`,
+			wantErr:  "operands with object type FOO are not supported by v2 policy rules",
+		},
+	} {
+		_, err := parsePolicyRuleJSON(tc.ruleJSON)
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("%s: got error %v, want %q", name, err, tc.wantErr)
+		}
+		if _, err := canonicalPolicyRuleJSON(tc.ruleJSON); err == nil {
+			t.Errorf("%s: expected canonicalPolicyRuleJSON to fail", name)
+		}
+	}
+
+	// Attributes set by the API are ignored, also when they are unknown to
+	// the rule format, at every level.
+	if _, err := parsePolicyRuleJSON(`{"name": "a", "microtenantName": "Default", "conditions": [{"id": "1", "modifiedTime": "2", "operands": [{"id": "3", "creationTime": "4", "objectType": "APP", "lhs": "id", "rhs": "1"}]}]}`); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,264 @@
+package zpa
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontrollerv2"
+)
+
+// Kinds of problems reported by the policy rule linter.
+const (
+	policyLintShadowed    = "shadowed"
+	policyLintDuplicate   = "duplicate"
+	policyLintUnreachable = "unreachable"
+)
+
+// policyClause is a disjunction of atoms, keyed by policyAtom.String().
+type policyClause map[string]policyAtom
+
+func (c policyClause) subsetOf(other policyClause) bool {
+	for key := range c {
+		if _, ok := other[key]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// lintPolicyRule is a rule prepared for linting. Its expression is built
+// from the same v2 conditions flattenConditionsV2 produces for the v2 policy
+// rule resources.
+type lintPolicyRule struct {
+	policyRuleExpr
+	// clauses is the rule in conjunctive normal form. It is only set when
+	// normalized is true.
+	clauses    []policyClause
+	normalized bool
+}
+
+func newLintPolicyRule(rule policysetcontrollerv2.PolicyRuleResource) lintPolicyRule {
+	expr := newPolicyRuleExpr(rule)
+	raw := 0
+	for _, condition := range expr.conditions {
+		raw += len(condition.atoms)
+	}
+
+	v2 := ConvertV1ResponseToV2Request(rule)
+	expr.conditions = policyConditionExprsV2(flattenConditionsV2(v2.Conditions))
+	converted := 0
+	for _, condition := range expr.conditions {
+		converted += len(condition.atoms)
+	}
+
+	r := lintPolicyRule{policyRuleExpr: expr}
+	// Operands the v2 conversion does not know about are dropped by it. Such
+	// rules look broader than they are, so they are left out of the
+	// shadowing analysis.
+	if raw == converted {
+		r.clauses, r.normalized = expr.normalize()
+	}
+	return r
+}
+
+// policyConditionExprsV2 builds condition expressions from flattened v2
+// conditions. ID and enum values become atoms with lhs "id".
+func policyConditionExprsV2(conditions []interface{}) []policyConditionExpr {
+	var exprs []policyConditionExpr
+	for _, condition := range conditions {
+		conditionMap, _ := condition.(map[string]interface{})
+		operator, _ := conditionMap["operator"].(string)
+		c := policyConditionExpr{operator: strings.ToUpper(operator)}
+		operands, _ := conditionMap["operands"].([]interface{})
+		for _, operand := range operands {
+			operandMap, _ := operand.(map[string]interface{})
+			objectType, _ := operandMap["object_type"].(string)
+			if values, ok := operandMap["values"].([]string); ok {
+				for _, v := range values {
+					c.atoms = append(c.atoms, policyAtom{objectType: objectType, lhs: "id", rhs: v})
+				}
+			}
+			entryValues, _ := operandMap["entry_values"].([]interface{})
+			for _, ev := range entryValues {
+				evMap, _ := ev.(map[string]interface{})
+				lhs, _ := evMap["lhs"].(string)
+				rhs, _ := evMap["rhs"].(string)
+				c.atoms = append(c.atoms, policyAtom{objectType: objectType, lhs: lhs, rhs: rhs})
+			}
+		}
+		exprs = append(exprs, c)
+	}
+	return exprs
+}
+
+// normalize returns the rule as a conjunction of clauses. An empty result
+// means the rule matches everything. Rules that OR together AND conditions
+// are not normalized.
+func (r policyRuleExpr) normalize() ([]policyClause, bool) {
+	clausesOf := func(c policyConditionExpr) []policyClause {
+		if c.operator == "AND" {
+			var clauses []policyClause
+			for _, atom := range c.atoms {
+				clauses = append(clauses, policyClause{atom.String(): atom})
+			}
+			return clauses
+		}
+		clause := policyClause{}
+		for _, atom := range c.atoms {
+			clause[atom.String()] = atom
+		}
+		return []policyClause{clause}
+	}
+
+	if r.operator != "OR" || len(r.conditions) == 1 {
+		var clauses []policyClause
+		for _, condition := range r.conditions {
+			if len(condition.atoms) == 0 {
+				continue
+			}
+			clauses = append(clauses, clausesOf(condition)...)
+		}
+		return clauses, true
+	}
+
+	union := policyClause{}
+	for _, condition := range r.conditions {
+		if len(condition.atoms) == 0 {
+			return nil, true
+		}
+		if condition.operator == "AND" && len(condition.atoms) > 1 {
+			return nil, false
+		}
+		for _, atom := range condition.atoms {
+			union[atom.String()] = atom
+		}
+	}
+	return []policyClause{union}, true
+}
+
+// policyClausesImply reports whether every client matching a also matches b.
+// The check is syntactic: each clause of b must contain a clause of a.
+func policyClausesImply(a, b []policyClause) bool {
+	for _, cb := range b {
+		implied := false
+		for _, ca := range a {
+			if ca.subsetOf(cb) {
+				implied = true
+				break
+			}
+		}
+		if !implied {
+			return false
+		}
+	}
+	return true
+}
+
+// unsatisfiable reports whether the rule can never match once the atoms for
+// which dead returns true are false.
+func (r policyRuleExpr) unsatisfiable(dead func(policyAtom) bool) bool {
+	conditionDead := func(c policyConditionExpr) bool {
+		deadAtoms := 0
+		for _, atom := range c.atoms {
+			if dead(atom) {
+				deadAtoms++
+			}
+		}
+		if c.operator == "AND" {
+			return deadAtoms > 0
+		}
+		return len(c.atoms) > 0 && deadAtoms == len(c.atoms)
+	}
+
+	if len(r.conditions) == 0 {
+		return false
+	}
+	if r.operator == "OR" {
+		for _, condition := range r.conditions {
+			if !conditionDead(condition) {
+				return false
+			}
+		}
+		return true
+	}
+	for _, condition := range r.conditions {
+		if conditionDead(condition) {
+			return true
+		}
+	}
+	return false
+}
+
+// policyLintFinding is a single problem reported for a rule.
+type policyLintFinding struct {
+	kind        string
+	rule        *lintPolicyRule
+	relatedRule *lintPolicyRule
+	message     string
+}
+
+// lintPolicyRules reports rules shadowed by or duplicating an earlier rule,
+// and rules that can never match because of dead atoms. rules must be in
+// evaluation order. dead may be nil.
+func lintPolicyRules(rules []lintPolicyRule, dead func(policyAtom) bool) []policyLintFinding {
+	var findings []policyLintFinding
+	for i := range rules {
+		rule := &rules[i]
+		if rule.disabled {
+			continue
+		}
+
+		if dead != nil && rule.unsatisfiable(dead) {
+			var missing []string
+			for _, condition := range rule.conditions {
+				for _, atom := range condition.atoms {
+					if dead(atom) {
+						missing = append(missing, atom.String())
+					}
+				}
+			}
+			sort.Strings(missing)
+			findings = append(findings, policyLintFinding{
+				kind:    policyLintUnreachable,
+				rule:    rule,
+				message: fmt.Sprintf("rule can never match, it references objects that do not exist: %s", strings.Join(missing, ", ")),
+			})
+			continue
+		}
+
+		if !rule.normalized {
+			continue
+		}
+		for j := 0; j < i; j++ {
+			earlier := &rules[j]
+			if earlier.disabled || !earlier.normalized {
+				continue
+			}
+			if !policyClausesImply(rule.clauses, earlier.clauses) {
+				continue
+			}
+			if policyClausesImply(earlier.clauses, rule.clauses) && strings.EqualFold(earlier.action, rule.action) {
+				findings = append(findings, policyLintFinding{
+					kind:        policyLintDuplicate,
+					rule:        rule,
+					relatedRule: earlier,
+					message:     fmt.Sprintf("rule has the same conditions and action as rule %q (order %d)", earlier.name, earlier.order),
+				})
+				break
+			}
+			message := fmt.Sprintf("rule is never evaluated, every request it matches is matched first by rule %q (order %d)", earlier.name, earlier.order)
+			if !strings.EqualFold(earlier.action, rule.action) {
+				message += fmt.Sprintf(" with action %s instead of %s", earlier.action, rule.action)
+			}
+			findings = append(findings, policyLintFinding{
+				kind:        policyLintShadowed,
+				rule:        rule,
+				relatedRule: earlier,
+				message:     message,
+			})
+			break
+		}
+	}
+	return findings
+}
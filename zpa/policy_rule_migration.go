@@ -0,0 +1,637 @@
+package zpa
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontrollerv2"
+)
+
+// policyRuleV2Migration is the v2 counterpart of a v1 policy rule resource.
+type policyRuleV2Migration struct {
+	v2Type     string
+	policyType string
+}
+
+var policyRuleV2Migrations = map[string]policyRuleV2Migration{
+	"zpa_policy_access_rule":      {v2Type: "zpa_policy_access_rule_v2", policyType: policyTypeAccess},
+	"zpa_policy_timeout_rule":     {v2Type: "zpa_policy_timeout_rule_v2", policyType: policyTypeTimeout},
+	"zpa_policy_forwarding_rule":  {v2Type: "zpa_policy_forwarding_rule_v2", policyType: policyTypeForwarding},
+	"zpa_policy_inspection_rule":  {v2Type: "zpa_policy_inspection_rule_v2", policyType: policyTypeInspection},
+	"zpa_policy_isolation_rule":   {v2Type: "zpa_policy_isolation_rule_v2", policyType: policyTypeIsolation},
+	"zpa_policy_redirection_rule": {v2Type: "zpa_policy_redirection_rule_v2", policyType: policyTypeRedirection},
+}
+
+// Meta-arguments and meta-blocks of resources, which are kept as they are.
+var (
+	policyRuleMigrationMetaArguments = []string{"count", "for_each", "provider", "depends_on"}
+	policyRuleMigrationMetaBlocks    = []string{"lifecycle", "provisioner", "connection"}
+)
+
+const policyRuleMigrationUsage = `Usage: terraform-provider-zpa migrate-policy-rules-v2 [-state FILE] [-write] PATH...
+
+Rewrites the v1 policy rule resources (zpa_policy_access_rule,
+zpa_policy_timeout_rule, zpa_policy_forwarding_rule, zpa_policy_inspection_rule,
+zpa_policy_isolation_rule and zpa_policy_redirection_rule) of the .tf files of
+PATH, files or directories, to their v2 counterparts. Conditions are converted from lhs/rhs operands to
+values and entry_values, and references to the resources are renamed.
+
+For every migrated resource, an import block and a removed block are added, so
+that the next apply moves the existing rules to the v2 resources without
+recreating them. The IDs of the rules are read from the state given with
+-state, as written by "terraform state pull". Without -state, only resources
+without count or for_each and with a literal name are migrated; the rules are
+looked up by name in the tenant, with the provider credentials of the
+environment, and the rules that exist are imported by name.
+
+The rewritten files are printed unless -write is given.
+
+Options:
+`
+
+// MigratePolicyRulesV2 implements the migrate-policy-rules-v2 subcommand of
+// the provider binary and returns its exit code.
+func MigratePolicyRulesV2(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("migrate-policy-rules-v2", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	statePath := flags.String("state", "", "The state of the configuration, as written by \"terraform state pull\".")
+	write := flags.Bool("write", false, "Rewrite the files in place instead of printing them.")
+	flags.Usage = func() {
+		fmt.Fprint(stderr, policyRuleMigrationUsage)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	var state *policyRuleMigrationState
+	if *statePath != "" {
+		var err error
+		if state, err = readPolicyRuleMigrationState(*statePath); err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+	}
+
+	paths, err := policyRuleMigrationFiles(flags.Args())
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	sources := make(map[string][]byte, len(paths))
+	for _, path := range paths {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+		sources[path] = src
+	}
+
+	provider := ZPAProvider()
+	var lookup policyRuleMigrationLookup
+	if state == nil {
+		if diags := provider.Configure(context.Background(), terraform.NewResourceConfigRaw(nil)); diags.HasError() {
+			fmt.Fprintf(stderr, "Error: without -state, the rules are looked up by name in the tenant, which needs the provider credentials in the environment: %v\n", diags)
+			return 1
+		}
+		lookup = tenantPolicyRuleMigrationLookup(context.Background(), provider.Meta().(*Client))
+	}
+	result, err := migratePolicyRules(sources, state, lookup, func(resourceType string) map[string]*schema.Schema {
+		return provider.ResourcesMap[resourceType].Schema
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	for _, message := range result.messages {
+		fmt.Fprintln(stderr, message)
+	}
+	for _, path := range paths {
+		out, ok := result.files[path]
+		if !ok {
+			continue
+		}
+		if *write {
+			if err := os.WriteFile(path, out, 0o644); err != nil {
+				fmt.Fprintf(stderr, "Error: %v\n", err)
+				return 1
+			}
+			continue
+		}
+		fmt.Fprintf(stdout, "# %s\n%s\n", path, out)
+	}
+	fmt.Fprintf(stderr, "Migrated %d resources, skipped %d.\n", result.migrated, result.skipped)
+	if result.skipped > 0 {
+		return 1
+	}
+	return 0
+}
+
+// policyRuleMigrationFiles returns the .tf files of paths. Directories are
+// not searched recursively, as every directory is a separate module.
+func policyRuleMigrationFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(path, "*.tf"))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// policyRuleMigrationState holds the IDs of the rules of the root module in a
+// state file, by resource address and instance key.
+type policyRuleMigrationState struct {
+	instances map[string][]policyRuleMigrationInstance
+}
+
+type policyRuleMigrationInstance struct {
+	// key is nil, a string or a float64, as in the state file.
+	key interface{}
+	id  string
+}
+
+func readPolicyRuleMigrationState(path string) (*policyRuleMigrationState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw struct {
+		Resources []struct {
+			Module    string `json:"module"`
+			Mode      string `json:"mode"`
+			Type      string `json:"type"`
+			Name      string `json:"name"`
+			Instances []struct {
+				IndexKey   interface{}            `json:"index_key"`
+				Attributes map[string]interface{} `json:"attributes"`
+			} `json:"instances"`
+		} `json:"resources"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid state file %s: %v", path, err)
+	}
+
+	state := &policyRuleMigrationState{instances: map[string][]policyRuleMigrationInstance{}}
+	for _, r := range raw.Resources {
+		if r.Module != "" || r.Mode != "managed" {
+			continue
+		}
+		if _, ok := policyRuleV2Migrations[r.Type]; !ok {
+			continue
+		}
+		address := r.Type + "." + r.Name
+		for _, instance := range r.Instances {
+			id, _ := instance.Attributes["id"].(string)
+			if id == "" {
+				return nil, fmt.Errorf("invalid state file %s: %s has an instance without ID", path, address)
+			}
+			state.instances[address] = append(state.instances[address], policyRuleMigrationInstance{key: instance.IndexKey, id: id})
+		}
+	}
+	return state, nil
+}
+
+// policyRuleMigrationLookup reports whether a rule of the policy type with
+// the name exists in the tenant or microtenant.
+type policyRuleMigrationLookup func(policyType, microTenantID, name string) (bool, error)
+
+// tenantPolicyRuleMigrationLookup looks up rules by name in the tenant of
+// client, reading the rules of each policy once.
+func tenantPolicyRuleMigrationLookup(ctx context.Context, client *Client) policyRuleMigrationLookup {
+	names := map[string]map[string]bool{}
+	return func(policyType, microTenantID, name string) (bool, error) {
+		key := policySetCacheKey(policyType, microTenantID)
+		if names[key] == nil {
+			service := client.Service
+			if microTenantID != "" {
+				service = service.WithMicroTenant(microTenantID)
+			}
+			rules, err := fetchPolicySetRules(ctx, service, policyType)
+			if err != nil {
+				return false, err
+			}
+			names[key] = map[string]bool{}
+			for _, rule := range rules {
+				names[key][rule.Name] = true
+			}
+		}
+		return names[key][name], nil
+	}
+}
+
+type policyRuleMigrationResult struct {
+	// files are the rewritten files, only for the files that changed.
+	files    map[string][]byte
+	messages []string
+	migrated int
+	skipped  int
+}
+
+// policyRuleMigrationResource is a v1 resource block that is migrated.
+type policyRuleMigrationResource struct {
+	path      string
+	v1Type    string
+	name      string
+	migration policyRuleV2Migration
+	importIDs []policyRuleMigrationInstance
+	// inState is false for resources that were never applied, which only
+	// need their configuration rewritten.
+	inState bool
+}
+
+// migratePolicyRules rewrites the v1 policy rule resources of sources. All
+// files are rewritten together, so that references to migrated resources are
+// renamed in every file of the module. Resources that cannot be converted are
+// left as they are and reported in the messages. Without state, lookup tells
+// which rules exist and are imported by name.
+func migratePolicyRules(sources map[string][]byte, state *policyRuleMigrationState, lookup policyRuleMigrationLookup, v2Schema func(resourceType string) map[string]*schema.Schema) (*policyRuleMigrationResult, error) {
+	paths := make([]string, 0, len(sources))
+	for path := range sources {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	files := make(map[string]*hclwrite.File, len(sources))
+	for _, path := range paths {
+		f, diags := hclwrite.ParseConfig(sources[path], path, hcl.InitialPos)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		files[path] = f
+	}
+
+	result := &policyRuleMigrationResult{files: map[string][]byte{}}
+	var migrated []policyRuleMigrationResource
+	for _, path := range paths {
+		for _, block := range files[path].Body().Blocks() {
+			if block.Type() != "resource" || len(block.Labels()) != 2 {
+				continue
+			}
+			v1Type, name := block.Labels()[0], block.Labels()[1]
+			migration, ok := policyRuleV2Migrations[v1Type]
+			if !ok {
+				continue
+			}
+			address := v1Type + "." + name
+
+			r := policyRuleMigrationResource{path: path, v1Type: v1Type, name: name, migration: migration}
+			switch {
+			case state != nil:
+				r.importIDs = state.instances[address]
+				r.inState = len(r.importIDs) > 0
+				if !r.inState {
+					result.messages = append(result.messages, fmt.Sprintf("Note: %s: the resource is not in the state and is not imported", address))
+				}
+			case block.Body().GetAttribute("count") != nil || block.Body().GetAttribute("for_each") != nil:
+				result.skip(address, "the rule IDs of resources with count or for_each are read from the state, use -state")
+				continue
+			default:
+				ruleName, ok := policyRuleMigrationLiteral(block.Body().GetAttribute("name"))
+				if !ok {
+					result.skip(address, "name is not a literal string, use -state to import the rule by ID")
+					continue
+				}
+				microTenantID := ""
+				if attr := block.Body().GetAttribute("microtenant_id"); attr != nil {
+					if microTenantID, ok = policyRuleMigrationLiteral(attr); !ok {
+						result.skip(address, "microtenant_id is not a literal string, use -state to import the rule by ID")
+						continue
+					}
+				}
+				exists, err := lookup(migration.policyType, microTenantID, ruleName)
+				if err != nil {
+					return nil, err
+				}
+				if exists {
+					r.importIDs = []policyRuleMigrationInstance{{id: ruleName}}
+					r.inState = true
+				} else {
+					result.messages = append(result.messages, fmt.Sprintf("Note: %s: there is no %s rule named %q, the resource is not imported", address, migration.policyType, ruleName))
+				}
+			}
+
+			warnings, err := migratePolicyRuleBlock(block, migration, v2Schema(migration.v2Type))
+			if err != nil {
+				result.skip(address, err.Error())
+				continue
+			}
+			for _, warning := range warnings {
+				result.messages = append(result.messages, fmt.Sprintf("Warning: %s: %s", address, warning))
+			}
+			migrated = append(migrated, r)
+			result.migrated++
+		}
+	}
+
+	// References are renamed before the import and removed blocks are added,
+	// as removed blocks refer to the v1 resources.
+	for _, r := range migrated {
+		for _, path := range paths {
+			renamePolicyRuleReferences(files[path].Body(), []string{r.v1Type, r.name}, []string{r.migration.v2Type, r.name})
+		}
+	}
+	commented := map[string]bool{}
+	for _, r := range migrated {
+		if !r.inState {
+			continue
+		}
+		body := files[r.path].Body()
+		if !commented[r.path] {
+			commented[r.path] = true
+			body.AppendNewline()
+			body.AppendUnstructuredTokens(hclwrite.Tokens{{
+				Type:  hclsyntax.TokenComment,
+				Bytes: []byte("# Move the existing rules to the v2 resources without recreating them.\n# These blocks can be removed after the next apply."),
+			}})
+		}
+		for _, instance := range r.importIDs {
+			to := hcl.Traversal{hcl.TraverseRoot{Name: r.migration.v2Type}, hcl.TraverseAttr{Name: r.name}}
+			switch key := instance.key.(type) {
+			case string:
+				to = append(to, hcl.TraverseIndex{Key: cty.StringVal(key)})
+			case float64:
+				to = append(to, hcl.TraverseIndex{Key: cty.NumberIntVal(int64(key))})
+			}
+			body.AppendNewline()
+			importBlock := body.AppendNewBlock("import", nil)
+			importBlock.Body().SetAttributeTraversal("to", to)
+			importBlock.Body().SetAttributeValue("id", cty.StringVal(instance.id))
+		}
+		body.AppendNewline()
+		removed := body.AppendNewBlock("removed", nil)
+		removed.Body().SetAttributeTraversal("from", hcl.Traversal{hcl.TraverseRoot{Name: r.v1Type}, hcl.TraverseAttr{Name: r.name}})
+		removed.Body().AppendNewBlock("lifecycle", nil).Body().SetAttributeValue("destroy", cty.False)
+	}
+
+	for _, path := range paths {
+		out := hclwrite.Format(files[path].Bytes())
+		if string(out) != string(sources[path]) {
+			result.files[path] = out
+		}
+	}
+	return result, nil
+}
+
+func (r *policyRuleMigrationResult) skip(address, reason string) {
+	r.messages = append(r.messages, fmt.Sprintf("Skipped %s: %s", address, reason))
+	r.skipped++
+}
+
+// migratePolicyRuleBlock converts a v1 resource block to the v2 resource in
+// place. The block is only modified when it can be converted completely.
+func migratePolicyRuleBlock(block *hclwrite.Block, migration policyRuleV2Migration, v2Schema map[string]*schema.Schema) (warnings []string, err error) {
+	settable := func(name string) bool {
+		s, ok := v2Schema[name]
+		return ok && (s.Optional || s.Required)
+	}
+
+	body := block.Body()
+	var removeAttributes []string
+	for name := range body.Attributes() {
+		if contains(policyRuleMigrationMetaArguments, name) || settable(name) {
+			continue
+		}
+		removeAttributes = append(removeAttributes, name)
+	}
+	sort.Strings(removeAttributes)
+
+	var conditions, removeBlocks []*hclwrite.Block
+	var converted []*policyRuleMigrationConditions
+	for _, nested := range body.Blocks() {
+		switch {
+		case nested.Type() == "conditions":
+			c, err := migratePolicyRuleConditions(nested, migration.policyType)
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, nested)
+			converted = append(converted, c)
+		case nested.Type() == "dynamic" && len(nested.Labels()) == 1 && nested.Labels()[0] == "conditions":
+			return nil, fmt.Errorf("dynamic conditions blocks cannot be converted")
+		case contains(policyRuleMigrationMetaBlocks, nested.Type()) || settable(nested.Type()):
+		case nested.Type() == "dynamic" && len(nested.Labels()) == 1 && settable(nested.Labels()[0]):
+		default:
+			removeBlocks = append(removeBlocks, nested)
+			warnings = append(warnings, fmt.Sprintf("removed the %s block, it is not supported by %s", nested.Type(), migration.v2Type))
+		}
+	}
+
+	for _, name := range removeAttributes {
+		body.RemoveAttribute(name)
+		if name == "rule_order" {
+			warnings = append(warnings, fmt.Sprintf("removed rule_order, use priority or the place_before and place_after attributes of %s to order rules", migration.v2Type))
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("removed %s, it is not supported by %s", name, migration.v2Type))
+	}
+	for _, nested := range removeBlocks {
+		body.RemoveBlock(nested)
+	}
+	// The conditions blocks are rewritten in place, so that they keep their
+	// position and spacing.
+	for i, nested := range conditions {
+		converted[i].writeTo(nested.Body())
+	}
+	block.SetLabels([]string{migration.v2Type, block.Labels()[1]})
+	return warnings, nil
+}
+
+// policyRuleMigrationConditions is a converted conditions block.
+type policyRuleMigrationConditions struct {
+	operator hclwrite.Tokens
+	operands []*policyRuleMigrationOperand
+}
+
+// policyRuleMigrationOperand aggregates the v1 operands of one object type.
+type policyRuleMigrationOperand struct {
+	objectType string
+	values     []hclwrite.Tokens
+	lists      []hclwrite.Tokens
+	entries    []*hclwrite.Block
+}
+
+// migratePolicyRuleConditions converts a v1 conditions block. Operands are
+// aggregated by object type, in the same way as ConvertV1ResponseToV2Request
+// converts the conditions of rules read from the API: the rhs of ID and enum
+// operands become values, and the lhs and rhs of other operands become
+// entry_values.
+func migratePolicyRuleConditions(conditions *hclwrite.Block, policyType string) (*policyRuleMigrationConditions, error) {
+	converted := &policyRuleMigrationConditions{}
+	if operator := conditions.Body().GetAttribute("operator"); operator != nil {
+		converted.operator = operator.Expr().BuildTokens(nil)
+	}
+	byType := map[string]*policyRuleMigrationOperand{}
+
+	for _, nested := range conditions.Body().Blocks() {
+		if nested.Type() != "operands" {
+			return nil, fmt.Errorf("%s blocks in conditions cannot be converted", describePolicyRuleMigrationBlock(nested))
+		}
+		body := nested.Body()
+		objectType, ok := policyRuleMigrationLiteral(body.GetAttribute("object_type"))
+		if !ok {
+			return nil, fmt.Errorf("operands with an object_type that is not a literal string cannot be converted")
+		}
+		t, ok := lookupPolicyOperandType(objectType)
+		if !ok || !t.allowedIn(policyType) || !policyOperandConvertsToV2(objectType) {
+			return nil, fmt.Errorf("operands with object type %s are not supported by v2 %s rules", objectType, policyType)
+		}
+
+		operand := byType[objectType]
+		if operand == nil {
+			operand = &policyRuleMigrationOperand{objectType: objectType}
+			byType[objectType] = operand
+			converted.operands = append(converted.operands, operand)
+		}
+		rhs := body.GetAttribute("rhs")
+		if t.valueKind == operandValueEntry {
+			lhs := body.GetAttribute("lhs")
+			if lhs == nil || rhs == nil {
+				return nil, fmt.Errorf("operands with object type %s need both lhs and rhs", objectType)
+			}
+			entry := hclwrite.NewBlock("entry_values", nil)
+			entry.Body().SetAttributeRaw("lhs", lhs.Expr().BuildTokens(nil))
+			entry.Body().SetAttributeRaw("rhs", rhs.Expr().BuildTokens(nil))
+			operand.entries = append(operand.entries, entry)
+			continue
+		}
+		// rhs_list is ignored by v1 resources when rhs is set.
+		switch rhsList := body.GetAttribute("rhs_list"); {
+		case rhs != nil:
+			operand.values = append(operand.values, rhs.Expr().BuildTokens(nil))
+		case rhsList != nil:
+			operand.lists = append(operand.lists, rhsList.Expr().BuildTokens(nil))
+		default:
+			return nil, fmt.Errorf("operands with object type %s need rhs or rhs_list", objectType)
+		}
+	}
+	return converted, nil
+}
+
+// writeTo replaces the content of the conditions block body with the
+// converted conditions.
+func (c *policyRuleMigrationConditions) writeTo(body *hclwrite.Body) {
+	// Clear alone leaves the removed attributes in the lookup of
+	// GetAttribute, which SetAttributeRaw would then update.
+	for name := range body.Attributes() {
+		body.RemoveAttribute(name)
+	}
+	for _, nested := range body.Blocks() {
+		body.RemoveBlock(nested)
+	}
+	body.Clear()
+	body.AppendNewline()
+	if c.operator != nil {
+		body.SetAttributeRaw("operator", c.operator)
+	}
+	for _, operand := range c.operands {
+		block := body.AppendNewBlock("operands", nil)
+		block.Body().SetAttributeValue("object_type", cty.StringVal(operand.objectType))
+		if len(operand.entries) > 0 {
+			for _, entry := range operand.entries {
+				block.Body().AppendBlock(entry)
+			}
+			continue
+		}
+		var args []hclwrite.Tokens
+		if len(operand.values) > 0 || len(operand.lists) == 0 {
+			args = append(args, hclwrite.TokensForTuple(operand.values))
+		}
+		args = append(args, operand.lists...)
+		if len(args) == 1 {
+			block.Body().SetAttributeRaw("values", args[0])
+		} else {
+			block.Body().SetAttributeRaw("values", hclwrite.TokensForFunctionCall("setunion", args...))
+		}
+	}
+}
+
+func describePolicyRuleMigrationBlock(block *hclwrite.Block) string {
+	if len(block.Labels()) == 0 {
+		return block.Type()
+	}
+	return block.Type() + " " + strings.Join(quoteAll(block.Labels()), " ")
+}
+
+// policyRuleMigrationLiteral returns the value of an attribute whose value is
+// a string without references or function calls.
+func policyRuleMigrationLiteral(attr *hclwrite.Attribute) (string, bool) {
+	if attr == nil {
+		return "", false
+	}
+	expr, diags := hclsyntax.ParseExpression(attr.Expr().BuildTokens(nil).Bytes(), "", hcl.InitialPos)
+	if diags.HasErrors() || len(expr.Variables()) > 0 {
+		return "", false
+	}
+	v, diags := expr.Value(nil)
+	if diags.HasErrors() || !v.IsWhollyKnown() || v.IsNull() || v.Type() != cty.String {
+		return "", false
+	}
+	return v.AsString(), true
+}
+
+// renamePolicyRuleReferences renames the references with the prefix search in
+// every expression of body and its nested blocks.
+func renamePolicyRuleReferences(body *hclwrite.Body, search, replacement []string) {
+	for _, attr := range body.Attributes() {
+		attr.Expr().RenameVariablePrefix(search, replacement)
+	}
+	for _, block := range body.Blocks() {
+		renamePolicyRuleReferences(block.Body(), search, replacement)
+	}
+}
+
+// policyOperandConvertsToV2 reports whether ConvertV1ResponseToV2Request
+// keeps operands of the object type. Other operands are dropped by the v2
+// resources when they read rules, so rules using them cannot be migrated.
+func policyOperandConvertsToV2(objectType string) bool {
+	probe, err := json.Marshal(map[string]interface{}{
+		"conditions": []interface{}{map[string]interface{}{
+			"operator": "OR",
+			"operands": []interface{}{map[string]interface{}{"objectType": objectType, "lhs": "id", "rhs": "1"}},
+		}},
+	})
+	if err != nil {
+		return false
+	}
+	var rule policysetcontrollerv2.PolicyRuleResource
+	if err := json.Unmarshal(probe, &rule); err != nil {
+		return false
+	}
+	for _, condition := range ConvertV1ResponseToV2Request(rule).Conditions {
+		if len(condition.Operands) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package zpa
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// The cases are directories of testdata/policy_rule_migration. input holds
+// the .tf files of the module, output the expected content of the files that
+// are rewritten, and state.json, when present, the state given with -state.
+func TestMigratePolicyRules(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		// existing are the rule names found in the tenant without -state.
+		existing []string
+		messages []string
+		migrated int
+		skipped  int
+	}{
+		{
+			name:     "values",
+			existing: []string{"Finance", "HR"},
+			migrated: 2,
+		},
+		{
+			name:     "entry_values",
+			existing: []string{"Contractors"},
+			messages: []string{
+				"Warning: zpa_policy_timeout_rule.contractors: removed rule_order, use priority or the place_before and place_after attributes of zpa_policy_timeout_rule_v2 to order rules",
+			},
+			migrated: 1,
+		},
+		{
+			name: "count_and_for_each_with_state",
+			messages: []string{
+				"Note: zpa_policy_access_rule.new: the resource is not in the state and is not imported",
+			},
+			migrated: 3,
+		},
+		{
+			name:     "count_without_state",
+			existing: []string{"Not applied yet"},
+			messages: []string{
+				"Skipped zpa_policy_access_rule.regions: the rule IDs of resources with count or for_each are read from the state, use -state",
+				"Skipped zpa_policy_forwarding_rule.bypass: the rule IDs of resources with count or for_each are read from the state, use -state",
+			},
+			migrated: 1,
+			skipped:  2,
+		},
+		{
+			name:     "dynamic_conditions",
+			existing: []string{"Dynamic", "IdP"},
+			messages: []string{
+				"Skipped zpa_policy_access_rule.dynamic: dynamic conditions blocks cannot be converted",
+				"Skipped zpa_policy_access_rule.idp: operands with object type IDP are not supported by v2 ACCESS_POLICY rules",
+			},
+			skipped: 2,
+		},
+		{
+			name:     "references",
+			existing: []string{"Allow", "Deny"},
+			migrated: 2,
+		},
+		{
+			name:     "not_in_tenant",
+			existing: []string{"Existing"},
+			messages: []string{
+				"Note: zpa_policy_inspection_rule.planned: there is no INSPECTION_POLICY rule named \"Planned\", the resource is not imported",
+				"Skipped zpa_policy_isolation_rule.computed_name: name is not a literal string, use -state to import the rule by ID",
+			},
+			migrated: 2,
+			skipped:  1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := filepath.Join("testdata", "policy_rule_migration", tc.name)
+			inputs, err := filepath.Glob(filepath.Join(dir, "input", "*.tf"))
+			if err != nil || len(inputs) == 0 {
+				t.Fatalf("no input files in %s: %v", dir, err)
+			}
+			sources := map[string][]byte{}
+			for _, path := range inputs {
+				src, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				sources[filepath.Base(path)] = src
+			}
+
+			var state *policyRuleMigrationState
+			if statePath := filepath.Join(dir, "state.json"); fileExistsForTest(statePath) {
+				if state, err = readPolicyRuleMigrationState(statePath); err != nil {
+					t.Fatal(err)
+				}
+			}
+			lookup := func(policyType, microTenantID, name string) (bool, error) {
+				if state != nil {
+					t.Errorf("unexpected lookup of %s rule %q with -state", policyType, name)
+				}
+				return contains(tc.existing, name), nil
+			}
+
+			result, err := migratePolicyRules(sources, state, lookup, func(resourceType string) map[string]*schema.Schema {
+				return ZPAProvider().ResourcesMap[resourceType].Schema
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(result.messages, tc.messages) {
+				t.Errorf("messages:\n%q\nwant:\n%q", result.messages, tc.messages)
+			}
+			if result.migrated != tc.migrated || result.skipped != tc.skipped {
+				t.Errorf("migrated %d and skipped %d, want %d and %d", result.migrated, result.skipped, tc.migrated, tc.skipped)
+			}
+			for name, src := range sources {
+				want := src
+				if expected, err := os.ReadFile(filepath.Join(dir, "output", name)); err == nil {
+					want = expected
+				}
+				got, rewritten := result.files[name]
+				if !rewritten {
+					got = src
+				}
+				if string(got) != string(want) {
+					t.Errorf("%s:\n%s\nwant:\n%s", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestReadPolicyRuleMigrationState_RejectsInstancesWithoutID(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	state := `{"resources": [{"mode": "managed", "type": "zpa_policy_access_rule", "name": "x", "instances": [{"attributes": {}}]}]}`
+	if err := os.WriteFile(path, []byte(state), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readPolicyRuleMigrationState(path); err == nil {
+		t.Error("expected an error for an instance without ID")
+	}
+}
+
+func fileExistsForTest(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,480 @@
+package zpa
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontroller"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontrollerv2"
+)
+
+// Relative placement attributes of the v2 policy rule resources. At most one
+// of them can be set.
+var policyRulePlacementKeys = []string{
+	"place_before_rule_id",
+	"place_after_rule_id",
+	"place_before_rule_name",
+	"place_after_rule_name",
+}
+
+func policyRulePlacementSchema(key string) *schema.Schema {
+	var conflicts []string
+	for _, k := range policyRulePlacementKeys {
+		if k != key {
+			conflicts = append(conflicts, k)
+		}
+	}
+	side, by, _ := strings.Cut(strings.TrimPrefix(key, "place_"), "_rule_")
+	return &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		ConflictsWith: conflicts,
+		Description:   fmt.Sprintf("Place the rule immediately %s the rule with this %s. The order is computed on apply.", side, by),
+	}
+}
+
+// policyRulePlacement is the configured relative placement of a rule.
+type policyRulePlacement struct {
+	key        string
+	before     bool
+	anchorID   string
+	anchorName string
+}
+
+func (p *policyRulePlacement) anchor() string {
+	if p.anchorID != "" {
+		return "ID " + p.anchorID
+	}
+	return fmt.Sprintf("name %q", p.anchorName)
+}
+
+func (p *policyRulePlacement) side() string {
+	if p.before {
+		return "before"
+	}
+	return "after"
+}
+
+func (p *policyRulePlacement) isAnchor(rule policysetcontrollerv2.PolicyRuleResource) bool {
+	if p.anchorID != "" {
+		return rule.ID == p.anchorID
+	}
+	return rule.Name == p.anchorName
+}
+
+// policyRuleOrderLock returns the lock that serializes the reorders of the
+// policy set of policyType in the microtenant, which rewrite the order of
+// every rule of the policy. It is keyed like policySetIDCache.
+func (c *Client) policyRuleOrderLock(policyType, microTenantID string) *sync.Mutex {
+	key := policySetCacheKey(policyType, microTenantID)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ruleOrderLocks == nil {
+		c.ruleOrderLocks = make(map[string]*sync.Mutex)
+	}
+	lock, ok := c.ruleOrderLocks[key]
+	if !ok {
+		lock = &sync.Mutex{}
+		c.ruleOrderLocks[key] = lock
+	}
+	return lock
+}
+
+type placementGetter interface {
+	Get(key string) interface{}
+}
+
+func expandPolicyRulePlacement(d placementGetter) *policyRulePlacement {
+	for _, key := range policyRulePlacementKeys {
+		value, _ := d.Get(key).(string)
+		if value == "" {
+			continue
+		}
+		p := &policyRulePlacement{key: key, before: strings.HasPrefix(key, "place_before")}
+		if strings.HasSuffix(key, "_id") {
+			p.anchorID = value
+		} else {
+			p.anchorName = value
+		}
+		return p
+	}
+	return nil
+}
+
+// applyPolicyRulePlacement moves the rule next to its anchor with a single
+// bulk reorder of the policy. Nothing is sent when the rule is already in
+// place.
+func applyPolicyRulePlacement(ctx context.Context, d *schema.ResourceData, zClient *Client, policyType string) error {
+	placement := expandPolicyRulePlacement(d)
+	if placement == nil {
+		return nil
+	}
+
+	service := zClient.Service
+	if microTenantID := GetString(d.Get("microtenant_id")); microTenantID != "" {
+		service = service.WithMicroTenant(microTenantID)
+	}
+
+	lock := zClient.policyRuleOrderLock(policyType, GetString(d.Get("microtenant_id")))
+	lock.Lock()
+	defer lock.Unlock()
+
+	rules, err := fetchPolicySetRules(ctx, service, policyType)
+	if err != nil {
+		return err
+	}
+
+	var ids []string
+	anchorIndex := -1
+	for _, rule := range rules {
+		if rule.ID == d.Id() {
+			continue
+		}
+		if placement.isAnchor(rule) {
+			anchorIndex = len(ids)
+		}
+		ids = append(ids, rule.ID)
+	}
+	if anchorIndex < 0 {
+		if placement.anchorName != "" {
+			return fmt.Errorf("%s: no %s rule with name %q, reference rules created in the same configuration with the _id attributes so that they are created first", placement.key, policyType, placement.anchorName)
+		}
+		return fmt.Errorf("%s: no %s rule with ID %s", placement.key, policyType, placement.anchorID)
+	}
+
+	position := anchorIndex + 1
+	if placement.before {
+		position = anchorIndex
+		if position == 0 && len(rules) > 0 && rules[0].Name == deceptionRuleName && rules[0].RuleOrder == "1" {
+			return fmt.Errorf("%s: rules cannot be placed before the %s rule", placement.key, deceptionRuleName)
+		}
+	}
+	ids = append(ids[:position], append([]string{d.Id()}, ids[position:]...)...)
+
+	currentOrders := map[string]string{}
+	for _, rule := range rules {
+		currentOrders[rule.ID] = rule.RuleOrder
+	}
+	ruleIDToOrder := map[string]int{}
+	inPlace := true
+	for i, id := range ids {
+		ruleIDToOrder[id] = i + 1
+		if currentOrders[id] != strconv.Itoa(i+1) {
+			inPlace = false
+		}
+	}
+	if inPlace {
+		return nil
+	}
+
+	log.Printf("[INFO] Placing %s rule %s %s the rule with %s at order %d", policyType, d.Id(), placement.side(), placement.anchor(), position+1)
+	if _, err := policysetcontroller.BulkReorder(ctx, service, policyType, ruleIDToOrder); err != nil {
+		return fmt.Errorf("failed to reorder %s rules: %v", policyType, err)
+	}
+	zClient.invalidatePolicyRulesPrefetch(policyType)
+	return nil
+}
+
+// policyRulePlacementNeighborSchema is the rule observed next to the rule on
+// the side of its relative placement.
+func policyRulePlacementNeighborSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The rule currently next to this rule on the side of its relative placement, by ID or by name like the placement attribute. Empty without a relative placement.",
+	}
+}
+
+// policyRuleNeighbors are the rules immediately before and after a rule of a
+// policy set.
+type policyRuleNeighbors struct {
+	before *policysetcontrollerv2.PolicyRuleResource
+	after  *policysetcontrollerv2.PolicyRuleResource
+}
+
+// policyRuleNeighborsByID returns the neighbors of every rule of rules, in
+// the order of the policy set, keyed by rule ID.
+func policyRuleNeighborsByID(rules []policysetcontrollerv2.PolicyRuleResource) map[string]interface{} {
+	neighbors := make(map[string]interface{}, len(rules))
+	for i := range rules {
+		n := &policyRuleNeighbors{}
+		if i > 0 {
+			n.before = &rules[i-1]
+		}
+		if i < len(rules)-1 {
+			n.after = &rules[i+1]
+		}
+		neighbors[rules[i].ID] = n
+	}
+	return neighbors
+}
+
+// readPolicyRulePlacement sets placement_neighbor to the rule that is
+// actually next to the rule on the side of its placement. The configured
+// placement attribute is left alone; planPolicyRulePlacement compares the
+// two. The rules of the policy set are listed once per refresh through the
+// read prefetch cache when it is enabled. Reads do not take the reorder lock
+// of the policy set, a reorder running meanwhile is reported as drift on the
+// next plan at worst.
+func readPolicyRulePlacement(ctx context.Context, d *schema.ResourceData, zClient *Client, policyType string) error {
+	placement := expandPolicyRulePlacement(d)
+	if placement == nil {
+		_ = d.Set("placement_neighbor", "")
+		return nil
+	}
+
+	microTenantID := GetString(d.Get("microtenant_id"))
+	service := zClient.Service
+	if microTenantID != "" {
+		service = service.WithMicroTenant(microTenantID)
+	}
+	neighbors := zClient.prefetchedPolicyRuleNeighbors(ctx, service, policyType, microTenantID, d.Id())
+	if neighbors == nil {
+		rules, err := fetchPolicySetRules(ctx, service, policyType)
+		if err != nil {
+			return err
+		}
+		item, ok := policyRuleNeighborsByID(rules)[d.Id()]
+		if !ok {
+			return nil
+		}
+		neighbors = item.(*policyRuleNeighbors)
+	}
+
+	neighbor := neighbors.before
+	if placement.before {
+		neighbor = neighbors.after
+	}
+	actual := ""
+	if neighbor != nil {
+		actual = neighbor.Name
+		if placement.anchorID != "" {
+			actual = neighbor.ID
+		}
+	}
+	if neighbor == nil || !placement.isAnchor(*neighbor) {
+		log.Printf("[WARN] %s rule %s is no longer next to the rule with %s, it will be moved back on the next apply", policyType, d.Id(), placement.anchor())
+	}
+	_ = d.Set("placement_neighbor", actual)
+	return nil
+}
+
+// planPolicyRulePlacement plans placement_neighbor back to the configured
+// anchor when the rule is no longer next to it, for example because another
+// rule was inserted in between, so that the update moves the rule back.
+func planPolicyRulePlacement(d *schema.ResourceDiff) error {
+	if d.Id() == "" {
+		return nil
+	}
+	for _, key := range policyRulePlacementKeys {
+		if !d.NewValueKnown(key) {
+			return d.SetNewComputed("placement_neighbor")
+		}
+	}
+	want := ""
+	if placement := expandPolicyRulePlacement(d); placement != nil {
+		want = placement.anchorName
+		if placement.anchorID != "" {
+			want = placement.anchorID
+		}
+	}
+	switch {
+	case d.Get("placement_neighbor").(string) == want:
+		return nil
+	case want == "":
+		// An empty computed value cannot be planned, the read empties it
+		// once the placement is removed.
+		return d.SetNewComputed("placement_neighbor")
+	}
+	return d.SetNew("placement_neighbor", want)
+}
+
+// policyRulePlacementNode is a rule of the configuration that is placed
+// relative to another rule.
+type policyRulePlacementNode struct {
+	id        string
+	name      string
+	placement policyRulePlacement
+}
+
+// policyRulePlacementGraph records the placements planned through a client,
+// per policy type and microtenant, to detect cycles between rules of the same
+// configuration.
+type policyRulePlacementGraph struct {
+	mu    sync.Mutex
+	nodes map[string]map[string]policyRulePlacementNode
+}
+
+func newPolicyRulePlacementGraph() *policyRulePlacementGraph {
+	return &policyRulePlacementGraph{nodes: map[string]map[string]policyRulePlacementNode{}}
+}
+
+// plannedPolicyRulePlacements returns the placement graph of the client.
+func (c *Client) plannedPolicyRulePlacements() *policyRulePlacementGraph {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.rulePlacements == nil {
+		c.rulePlacements = newPolicyRulePlacementGraph()
+	}
+	return c.rulePlacements
+}
+
+// remove forgets the placement of the rule with id or name, once the rule is
+// no longer placed relative to another rule.
+func (g *policyRulePlacementGraph) remove(scope, id, name string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for key, n := range g.nodes[scope] {
+		if key == name || (id != "" && n.id == id) {
+			delete(g.nodes[scope], key)
+		}
+	}
+}
+
+// add records node and returns an error if the placements of the scope can
+// no longer be satisfied together: two rules placed in the same slot, or
+// placements forming a cycle. The node replaces the previous node of the
+// rule, also when the rule was renamed.
+func (g *policyRulePlacementGraph) add(scope string, node policyRulePlacementNode) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	nodes, ok := g.nodes[scope]
+	if !ok {
+		nodes = map[string]policyRulePlacementNode{}
+		g.nodes[scope] = nodes
+	}
+	if node.id != "" {
+		for name, n := range nodes {
+			if n.id == node.id && name != node.name {
+				delete(nodes, name)
+			}
+		}
+	}
+	nodes[node.name] = node
+
+	// Each placement is an edge from a rule to the rule immediately after
+	// it. Rules are identified by name; anchors referenced by the ID of a
+	// rule that is not planned are identified by ID.
+	anchorKey := func(n policyRulePlacementNode) string {
+		if n.placement.anchorName != "" {
+			return n.placement.anchorName
+		}
+		for _, candidate := range nodes {
+			if candidate.id != "" && candidate.id == n.placement.anchorID {
+				return candidate.name
+			}
+		}
+		return "ID " + n.placement.anchorID
+	}
+	// Sorted, so that conflicts are reported the same way on every plan.
+	names := make([]string, 0, len(nodes))
+	for name := range nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	next := map[string]string{}
+	previous := map[string]string{}
+	for _, name := range names {
+		n := nodes[name]
+		from, to := anchorKey(n), n.name
+		if n.placement.before {
+			from, to = n.name, anchorKey(n)
+		}
+		if other, ok := next[from]; ok && other != to {
+			delete(nodes, node.name)
+			return fmt.Errorf("relative placements conflict: %q and %q are both placed immediately after %q", other, to, from)
+		}
+		if other, ok := previous[to]; ok && other != from {
+			delete(nodes, node.name)
+			return fmt.Errorf("relative placements conflict: %q and %q are both placed immediately before %q", other, from, to)
+		}
+		next[from] = to
+		previous[to] = from
+	}
+
+	chain := []string{node.name}
+	for current := node.name; ; {
+		following, ok := next[current]
+		if !ok {
+			return nil
+		}
+		chain = append(chain, following)
+		if following == node.name || len(chain) > len(next)+1 {
+			delete(nodes, node.name)
+			return fmt.Errorf("relative placements form a cycle: %s", strings.Join(quoteAll(chain), " -> "))
+		}
+		current = following
+	}
+}
+
+// validatePolicyRulePlacement checks at plan time that the placement of the
+// rule does not reference the rule itself, and does not form a cycle with the
+// placements of the other rules of the configuration.
+func validatePolicyRulePlacement(policyType string) schema.CustomizeDiffFunc {
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		for _, key := range policyRulePlacementKeys {
+			if !d.NewValueKnown(key) {
+				return nil
+			}
+		}
+		placement := expandPolicyRulePlacement(d)
+		name := d.Get("name").(string)
+		if placement != nil && ((placement.anchorID != "" && placement.anchorID == d.Id()) || (placement.anchorName != "" && d.NewValueKnown("name") && placement.anchorName == name)) {
+			return fmt.Errorf("%s: a rule cannot be placed relative to itself", placement.key)
+		}
+		zClient, ok := meta.(*Client)
+		if !ok || zClient == nil {
+			return nil
+		}
+		graph := zClient.plannedPolicyRulePlacements()
+		scope := policySetCacheKey(policyType, GetString(d.Get("microtenant_id")))
+		if placement == nil || name == "" {
+			graph.remove(scope, d.Id(), name)
+			return nil
+		}
+		return graph.add(scope, policyRulePlacementNode{
+			id:        d.Id(),
+			name:      name,
+			placement: *placement,
+		})
+	}
+}
+
+// customizePolicyRuleV2Diff is the CustomizeDiff of the v2 policy rule
+// resources.
+func customizePolicyRuleV2Diff(policyType string) schema.CustomizeDiffFunc {
+	validatePlacement := validatePolicyRulePlacement(policyType)
+	planExpression := customizeConditionExpression(policyType)
+	countryCode, _ := lookupPolicyOperandType("COUNTRY_CODE")
+	return func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		if err := planExpression(ctx, d, meta); err != nil {
+			return err
+		}
+		if err := planPolicyOperandNames(ctx, d, meta); err != nil {
+			return err
+		}
+		if countryCode.allowedIn(policyType) {
+			if err := planCountryAliases(ctx, d, meta); err != nil {
+				return err
+			}
+		}
+		if err := planPrunedReferences(ctx, d, meta); err != nil {
+			return err
+		}
+		if err := validatePolicyConditionReferences(ctx, d, meta); err != nil {
+			return err
+		}
+		if err := planPolicyRulePlacement(d); err != nil {
+			return err
+		}
+		return validatePlacement(ctx, d, meta)
+	}
+}
@@ -0,0 +1,366 @@
+package zpa
+
+import (
+	"context"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontrollerv2"
+)
+
+// placementNode returns the node of rule name placed by key relative to
+// anchor, an ID for the _id keys and a name otherwise.
+func placementNode(id, name, key, anchor string) policyRulePlacementNode {
+	node := policyRulePlacementNode{id: id, name: name}
+	node.placement = *expandPolicyRulePlacement(testPlacementGetter{key: anchor})
+	return node
+}
+
+type testPlacementGetter map[string]string
+
+func (g testPlacementGetter) Get(key string) interface{} {
+	return g[key]
+}
+
+func TestPolicyRulePlacementGraph_Add(t *testing.T) {
+	type step struct {
+		node    policyRulePlacementNode
+		wantErr string
+	}
+	for name, steps := range map[string][]step{
+		"chain": {
+			{node: placementNode("", "b", "place_after_rule_name", "a")},
+			{node: placementNode("", "c", "place_after_rule_name", "b")},
+			{node: placementNode("", "z", "place_before_rule_name", "a")},
+		},
+		"two-node cycle": {
+			{node: placementNode("", "a", "place_after_rule_name", "b")},
+			{node: placementNode("", "b", "place_after_rule_name", "a"), wantErr: `cycle: "b" -> "a" -> "b"`},
+		},
+		"three-node cycle": {
+			{node: placementNode("", "a", "place_after_rule_name", "c")},
+			{node: placementNode("", "b", "place_after_rule_name", "a")},
+			{node: placementNode("", "c", "place_after_rule_name", "b"), wantErr: `cycle: "c" -> "a" -> "b" -> "c"`},
+		},
+		"three-node cycle by ID": {
+			{node: placementNode("1", "a", "place_before_rule_id", "2")},
+			{node: placementNode("2", "b", "place_before_rule_id", "3")},
+			{node: placementNode("3", "c", "place_before_rule_name", "a"), wantErr: `cycle: "c" -> "a" -> "b" -> "c"`},
+		},
+		"same slot after an anchor": {
+			{node: placementNode("", "a", "place_after_rule_name", "x")},
+			{node: placementNode("", "b", "place_after_rule_name", "x"), wantErr: `"a" and "b" are both placed immediately after "x"`},
+		},
+		"same slot before an anchor": {
+			{node: placementNode("", "a", "place_before_rule_id", "9")},
+			{node: placementNode("", "b", "place_before_rule_id", "9"), wantErr: `"a" and "b" are both placed immediately before "ID 9"`},
+		},
+		"re-plan of the same rule": {
+			{node: placementNode("1", "a", "place_after_rule_name", "x")},
+			{node: placementNode("1", "a", "place_after_rule_name", "x")},
+			{node: placementNode("1", "a", "place_after_rule_name", "y")},
+			// The slot after x is free again
+			{node: placementNode("2", "b", "place_after_rule_name", "x")},
+		},
+		"renamed rule": {
+			{node: placementNode("1", "a", "place_after_rule_name", "x")},
+			{node: placementNode("1", "renamed", "place_after_rule_name", "x")},
+			// a no longer exists, so it does not form a cycle
+			{node: placementNode("2", "x", "place_after_rule_name", "a")},
+		},
+		"rejected placement is not recorded": {
+			{node: placementNode("", "a", "place_after_rule_name", "x")},
+			{node: placementNode("", "b", "place_after_rule_name", "x"), wantErr: "both placed immediately after"},
+			{node: placementNode("", "c", "place_after_rule_name", "b")},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			g := newPolicyRulePlacementGraph()
+			for i, s := range steps {
+				err := g.add("ACCESS_POLICY", s.node)
+				switch {
+				case s.wantErr == "" && err != nil:
+					t.Fatalf("step %d: unexpected error %v", i, err)
+				case s.wantErr != "" && (err == nil || !regexp.MustCompile(regexp.QuoteMeta(s.wantErr)).MatchString(err.Error())):
+					t.Fatalf("step %d: got error %v, want %s", i, err, s.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestPolicyRulePlacementGraph_Remove(t *testing.T) {
+	g := newPolicyRulePlacementGraph()
+	if err := g.add("ACCESS_POLICY", placementNode("1", "a", "place_after_rule_name", "x")); err != nil {
+		t.Fatal(err)
+	}
+	// Placements of other policy types and microtenants are separate
+	if err := g.add("ACCESS_POLICY:1", placementNode("2", "b", "place_after_rule_name", "x")); err != nil {
+		t.Fatal(err)
+	}
+	g.remove("ACCESS_POLICY", "1", "")
+	if err := g.add("ACCESS_POLICY", placementNode("3", "c", "place_after_rule_name", "x")); err != nil {
+		t.Errorf("expected the removed placement to be forgotten, got %v", err)
+	}
+}
+
+func TestClient_PlannedPolicyRulePlacements(t *testing.T) {
+	first, second := &Client{}, &Client{}
+	if first.plannedPolicyRulePlacements() != first.plannedPolicyRulePlacements() {
+		t.Error("expected the placements of a client to be kept")
+	}
+	if first.plannedPolicyRulePlacements() == second.plannedPolicyRulePlacements() {
+		t.Error("expected each client to have its own placements")
+	}
+}
+
+func TestClient_PolicyRuleOrderLock(t *testing.T) {
+	first, second := &Client{}, &Client{}
+	lock := first.policyRuleOrderLock("ACCESS_POLICY", "")
+	if first.policyRuleOrderLock("ACCESS_POLICY", "") != lock {
+		t.Error("expected the lock of a policy set to be kept")
+	}
+	for name, other := range map[string]*sync.Mutex{
+		"policy type":  first.policyRuleOrderLock("TIMEOUT_POLICY", ""),
+		"microtenant":  first.policyRuleOrderLock("ACCESS_POLICY", "216199618143320419"),
+		"other client": second.policyRuleOrderLock("ACCESS_POLICY", ""),
+	} {
+		if other == lock {
+			t.Errorf("%s: expected another lock", name)
+		}
+	}
+
+	// Holding the lock of a policy set does not block the others
+	lock.Lock()
+	defer lock.Unlock()
+	other := first.policyRuleOrderLock("TIMEOUT_POLICY", "")
+	if !other.TryLock() {
+		t.Fatal("expected the lock of another policy set to be free")
+	}
+	other.Unlock()
+}
+
+func TestValidatePolicyRulePlacement(t *testing.T) {
+	// unknown is the value of attributes computed on apply in raw configs.
+	const unknown = "74D93920-ED26-11E3-AC10-0800200C9A66"
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name":           {Type: schema.TypeString, Optional: true},
+			"microtenant_id": {Type: schema.TypeString, Optional: true},
+		},
+		CustomizeDiff: validatePolicyRulePlacement("ACCESS_POLICY"),
+	}
+	for _, key := range policyRulePlacementKeys {
+		r.Schema[key] = policyRulePlacementSchema(key)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		state   *terraform.InstanceState
+		config  map[string]interface{}
+		wantErr string
+	}{
+		{
+			name:   "by ID, name computed",
+			config: map[string]interface{}{"name": unknown, "place_after_rule_id": "2"},
+		},
+		{
+			name:   "by ID",
+			config: map[string]interface{}{"name": "a", "place_after_rule_id": "2"},
+		},
+		{
+			name:    "by ID, itself",
+			state:   &terraform.InstanceState{ID: "1", Attributes: map[string]string{"id": "1", "name": "a"}},
+			config:  map[string]interface{}{"name": "a", "place_before_rule_id": "1"},
+			wantErr: "place_before_rule_id: a rule cannot be placed relative to itself",
+		},
+		{
+			name:   "by name, name computed",
+			config: map[string]interface{}{"name": unknown, "place_after_rule_name": "a"},
+		},
+		{
+			name:    "by name, itself",
+			config:  map[string]interface{}{"name": "a", "place_after_rule_name": "a"},
+			wantErr: "place_after_rule_name: a rule cannot be placed relative to itself",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := r.Diff(context.Background(), tc.state, terraform.NewResourceConfigRaw(tc.config), nil)
+			if tc.wantErr == "" && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tc.wantErr != "" && (err == nil || !regexp.MustCompile(regexp.QuoteMeta(tc.wantErr)).MatchString(err.Error())) {
+				t.Errorf("got error %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestPolicyRuleNeighborsByID(t *testing.T) {
+	neighbors := policyRuleNeighborsByID([]policysetcontrollerv2.PolicyRuleResource{{ID: "1"}, {ID: "2"}, {ID: "3"}})
+	for id, want := range map[string][2]string{
+		"1": {"", "2"},
+		"2": {"1", "3"},
+		"3": {"2", ""},
+	} {
+		n := neighbors[id].(*policyRuleNeighbors)
+		var got [2]string
+		if n.before != nil {
+			got[0] = n.before.ID
+		}
+		if n.after != nil {
+			got[1] = n.after.ID
+		}
+		if got != want {
+			t.Errorf("neighbors of %s: got %v, want %v", id, got, want)
+		}
+	}
+}
+
+func TestPlanPolicyRulePlacement(t *testing.T) {
+	const unknown = "74D93920-ED26-11E3-AC10-0800200C9A66"
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"placement_neighbor": policyRulePlacementNeighborSchema(),
+		},
+		CustomizeDiff: func(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+			return planPolicyRulePlacement(d)
+		},
+	}
+	for _, key := range policyRulePlacementKeys {
+		r.Schema[key] = policyRulePlacementSchema(key)
+	}
+	state := func(attributes map[string]string) *terraform.InstanceState {
+		s := &terraform.InstanceState{ID: "1", Attributes: map[string]string{"id": "1"}}
+		for k, v := range attributes {
+			s.Attributes[k] = v
+		}
+		return s
+	}
+
+	for _, tc := range []struct {
+		name         string
+		state        *terraform.InstanceState
+		config       map[string]interface{}
+		wantNeighbor string // the planned placement_neighbor, "" when it does not change
+	}{
+		{
+			name:   "create",
+			config: map[string]interface{}{"place_after_rule_name": "a"},
+		},
+		{
+			name:   "in place by name",
+			state:  state(map[string]string{"place_after_rule_name": "a", "placement_neighbor": "a"}),
+			config: map[string]interface{}{"place_after_rule_name": "a"},
+		},
+		{
+			name:         "rule inserted in between",
+			state:        state(map[string]string{"place_after_rule_name": "a", "placement_neighbor": "b"}),
+			config:       map[string]interface{}{"place_after_rule_name": "a"},
+			wantNeighbor: "a",
+		},
+		{
+			name:         "anchor moved away",
+			state:        state(map[string]string{"place_before_rule_id": "9", "placement_neighbor": ""}),
+			config:       map[string]interface{}{"place_before_rule_id": "9"},
+			wantNeighbor: "9",
+		},
+		{
+			name:         "anchor changed",
+			state:        state(map[string]string{"place_after_rule_name": "a", "placement_neighbor": "a"}),
+			config:       map[string]interface{}{"place_after_rule_name": "b"},
+			wantNeighbor: "b",
+		},
+		{
+			name:         "anchor computed",
+			state:        state(map[string]string{"place_after_rule_id": "2", "placement_neighbor": "2"}),
+			config:       map[string]interface{}{"place_after_rule_id": unknown},
+			wantNeighbor: "unknown",
+		},
+		{
+			name:         "placement removed",
+			state:        state(map[string]string{"place_after_rule_name": "a", "placement_neighbor": "a"}),
+			config:       map[string]interface{}{},
+			wantNeighbor: "unknown",
+		},
+		{
+			name:   "no placement",
+			state:  state(nil),
+			config: map[string]interface{}{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			diff, err := r.Diff(context.Background(), tc.state, terraform.NewResourceConfigRaw(tc.config), nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			neighbor := ""
+			if diff != nil && tc.state != nil {
+				if attr := diff.Attributes["placement_neighbor"]; attr != nil {
+					neighbor = attr.New
+					if attr.NewComputed {
+						neighbor = "unknown"
+					}
+				}
+			}
+			if neighbor != tc.wantNeighbor {
+				t.Errorf("planned placement_neighbor %q, want %q", neighbor, tc.wantNeighbor)
+			}
+		})
+	}
+}
+
+func TestReadPolicyRulePlacement(t *testing.T) {
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"microtenant_id":     {Type: schema.TypeString, Optional: true},
+			"placement_neighbor": policyRulePlacementNeighborSchema(),
+		},
+	}
+	for _, key := range policyRulePlacementKeys {
+		r.Schema[key] = policyRulePlacementSchema(key)
+	}
+	// The rules of the policy set, listed once and shared by every read
+	zClient := &Client{prefetch: newReadPrefetchCache()}
+	loads := 0
+	zClient.prefetch.lookup(prefetchPolicyRules+":ACCESS_POLICY", "", "", func() (map[string]interface{}, error) {
+		loads++
+		return policyRuleNeighborsByID([]policysetcontrollerv2.PolicyRuleResource{
+			{ID: "1", Name: "a"}, {ID: "2", Name: "b"}, {ID: "3", Name: "c"},
+		}), nil
+	})
+
+	for _, tc := range []struct {
+		id           string
+		key, anchor  string
+		wantNeighbor string
+	}{
+		{"2", "place_after_rule_name", "a", "a"},
+		{"3", "place_after_rule_name", "a", "b"},
+		{"1", "place_before_rule_id", "2", "2"},
+		{"2", "place_before_rule_id", "1", "3"},
+		{"1", "place_after_rule_id", "3", ""},
+		{"2", "", "", ""},
+	} {
+		attributes := map[string]string{"id": tc.id, "placement_neighbor": "stale"}
+		if tc.key != "" {
+			attributes[tc.key] = tc.anchor
+		}
+		d := r.Data(&terraform.InstanceState{ID: tc.id, Attributes: attributes})
+		if err := readPolicyRulePlacement(context.Background(), d, zClient, "ACCESS_POLICY"); err != nil {
+			t.Fatalf("rule %s %s %s: %v", tc.id, tc.key, tc.anchor, err)
+		}
+		if neighbor := d.Get("placement_neighbor").(string); neighbor != tc.wantNeighbor {
+			t.Errorf("rule %s %s %s: placement_neighbor %q, want %q", tc.id, tc.key, tc.anchor, neighbor, tc.wantNeighbor)
+		}
+		if tc.key != "" && d.Get(tc.key).(string) != tc.anchor {
+			t.Errorf("rule %s: expected the configured %s %q to be kept, got %q", tc.id, tc.key, tc.anchor, d.Get(tc.key))
+		}
+	}
+	if loads != 1 {
+		t.Errorf("expected the rules to be listed once, got %d listings", loads)
+	}
+}
@@ -0,0 +1,291 @@
+package zpa
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontroller"
+)
+
+// policyRuleCreation is a rule created with a priority.
+type policyRuleCreation struct {
+	id       string
+	priority int
+	seq      int
+}
+
+// queuedPolicyRuleCreation is a rule creation waiting in a creation queue.
+type queuedPolicyRuleCreation struct {
+	ctx      context.Context
+	priority int
+	seq      int
+	create   func() (string, error)
+	started  bool // Taken off the queue, guarded by the mutex of the queue
+	id       string
+	done     chan error
+}
+
+// policyRuleCreationQueue serializes the creation of the rules of one policy
+// set. The API appends each new rule at the end of the policy, so the queue
+// creates the rules one at a time, the queued rule with the lowest priority
+// first. A rule queued while a rule with a higher priority is already being
+// created still ends up after it, so once the queue is empty the rules created
+// since it was last empty (the batch) are moved into priority order with a
+// single bulk reorder. The creations of a batch are held until then, so the
+// rules of the next batch are appended after it. The reorder therefore covers
+// every rule created with a priority through the queue, not only the batch.
+type policyRuleCreationQueue struct {
+	name    string
+	reorder func(ctx context.Context, created []policyRuleCreation) error
+
+	mu      sync.Mutex
+	seq     int
+	running bool
+	pending []*queuedPolicyRuleCreation
+	batch   []*queuedPolicyRuleCreation
+	// Rules created with a priority, only used by the running queue
+	created []policyRuleCreation
+}
+
+func newPolicyRuleCreationQueue(name string, reorder func(ctx context.Context, created []policyRuleCreation) error) *policyRuleCreationQueue {
+	return &policyRuleCreationQueue{name: name, reorder: reorder}
+}
+
+// policyRuleCreationQueue returns the creation queue of the policy set of
+// policyType in the microtenant, keyed like policySetIDCache.
+func (c *Client) policyRuleCreationQueue(service *zscaler.Service, policyType, microTenantID string) *policyRuleCreationQueue {
+	key := policySetCacheKey(policyType, microTenantID)
+	lock := c.policyRuleOrderLock(policyType, microTenantID)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ruleCreationQueues == nil {
+		c.ruleCreationQueues = make(map[string]*policyRuleCreationQueue)
+	}
+	q, ok := c.ruleCreationQueues[key]
+	if !ok {
+		q = newPolicyRuleCreationQueue(key, func(ctx context.Context, created []policyRuleCreation) error {
+			return reorderPolicyRulesByPriority(ctx, service, lock, policyType, key, created)
+		})
+		c.ruleCreationQueues[key] = q
+	}
+	return q
+}
+
+// policyRuleCreationPriority is the priority the rule is created with. Rules
+// with a relative placement are placed by it instead.
+func policyRuleCreationPriority(d placementGetter) int {
+	if expandPolicyRulePlacement(d) != nil {
+		return 0
+	}
+	priority, _ := d.Get("priority").(int)
+	return priority
+}
+
+// createPolicyRule runs create, which returns the ID of the new rule, through
+// the creation queue of the policy set and waits until the rules created with
+// a priority through the queue are in ascending priority. Rules are created
+// one at a time per policy set, in priority order. Rules without priority (0)
+// are created last and are not moved.
+func (c *Client) createPolicyRule(ctx context.Context, service *zscaler.Service, policyType, microTenantID string, priority int, create func() (string, error)) error {
+	defer c.invalidatePolicyRulesPrefetch(policyType)
+	return c.policyRuleCreationQueue(service, policyType, microTenantID).create(ctx, priority, create)
+}
+
+// create queues create and waits for it. The error is the error of create, or
+// of ctx; a failed reorder of the batch only logs a warning, since the rule
+// itself was created. When ctx is cancelled after create was started, create
+// is still waited for, as it may already have created the rule.
+func (q *policyRuleCreationQueue) create(ctx context.Context, priority int, create func() (string, error)) error {
+	item := &queuedPolicyRuleCreation{
+		ctx:      ctx,
+		priority: priority,
+		create:   create,
+		done:     make(chan error, 1),
+	}
+
+	q.mu.Lock()
+	item.seq = q.seq
+	q.seq++
+	q.pending = append(q.pending, item)
+	if !q.running {
+		q.running = true
+		go q.run()
+	}
+	q.mu.Unlock()
+
+	select {
+	case err := <-item.done:
+		return err
+	case <-ctx.Done():
+		q.mu.Lock()
+		started := item.started
+		q.mu.Unlock()
+		if !started {
+			return ctx.Err()
+		}
+		return <-item.done
+	}
+}
+
+func (q *policyRuleCreationQueue) run() {
+	for {
+		q.mu.Lock()
+		if len(q.pending) == 0 {
+			batch := q.batch
+			q.batch = nil
+			q.mu.Unlock()
+
+			q.finish(batch)
+
+			// Creations queued during the reorder start a new batch
+			q.mu.Lock()
+			if len(q.pending) == 0 {
+				q.running = false
+				q.mu.Unlock()
+				return
+			}
+			q.mu.Unlock()
+			continue
+		}
+		sort.SliceStable(q.pending, func(i, j int) bool {
+			return q.pending[i].before(q.pending[j])
+		})
+		item := q.pending[0]
+		item.started = true
+		q.pending = q.pending[1:]
+		remaining := len(q.pending)
+		q.mu.Unlock()
+
+		if err := item.ctx.Err(); err != nil {
+			item.done <- err
+			continue
+		}
+		log.Printf("[DEBUG] Creating queued %s rule with priority %d, %d rules left in the queue", q.name, item.priority, remaining)
+		id, err := item.create()
+		if err != nil {
+			item.done <- err
+			continue
+		}
+		item.id = id
+
+		q.mu.Lock()
+		q.batch = append(q.batch, item)
+		q.mu.Unlock()
+	}
+}
+
+// finish reorders the rules created with a priority, those of the batch
+// along with those of the earlier batches, and releases the creations of the
+// batch.
+func (q *policyRuleCreationQueue) finish(batch []*queuedPolicyRuleCreation) {
+	if len(batch) == 0 {
+		return
+	}
+	added := false
+	for _, item := range batch {
+		if item.priority > 0 {
+			q.created = append(q.created, policyRuleCreation{id: item.id, priority: item.priority, seq: item.seq})
+			added = true
+		}
+	}
+	if added && len(q.created) > 1 {
+		// The rules are created, so the reorder is not cut short by the
+		// cancellation of the creation that happened to be last.
+		ctx := context.WithoutCancel(batch[len(batch)-1].ctx)
+		if err := q.reorder(ctx, q.created); err != nil {
+			log.Printf("[WARN] The %d %s rules created with a priority are not in priority order: %v", len(q.created), q.name, err)
+		}
+	}
+	for _, item := range batch {
+		item.done <- nil
+	}
+}
+
+func (c *queuedPolicyRuleCreation) before(other *queuedPolicyRuleCreation) bool {
+	switch {
+	case c.priority == other.priority:
+		return c.seq < other.seq
+	case c.priority == 0:
+		return false
+	case other.priority == 0:
+		return true
+	}
+	return c.priority < other.priority
+}
+
+// reorderPolicyRulesByPriority moves the created rules into ascending
+// priority within the slots they occupy in the policy. Rules created with the
+// same priority keep the order they were created in. Nothing is sent when the
+// rules are already in order. lock is the order lock of the policy set.
+func reorderPolicyRulesByPriority(ctx context.Context, service *zscaler.Service, lock sync.Locker, policyType, name string, created []policyRuleCreation) error {
+	if len(created) < 2 {
+		return nil
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	rules, err := fetchPolicySetRules(ctx, service, policyType)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, len(rules))
+	for i, rule := range rules {
+		ids[i] = rule.ID
+	}
+	ordered := orderCreatedPolicyRules(ids, created)
+
+	ruleIDToOrder := map[string]int{}
+	inOrder := true
+	for i, id := range ordered {
+		ruleIDToOrder[id] = i + 1
+		if ids[i] != id {
+			inOrder = false
+		}
+	}
+	if inOrder {
+		return nil
+	}
+
+	log.Printf("[INFO] Reordering %d %s rules created in parallel by priority", len(created), name)
+	if _, err := policysetcontroller.BulkReorder(ctx, service, policyType, ruleIDToOrder); err != nil {
+		return fmt.Errorf("failed to order the created %s rules by priority: %v", policyType, err)
+	}
+	return nil
+}
+
+// orderCreatedPolicyRules returns ids, the rule IDs of a policy in order, with
+// the created rules moved into ascending priority within the slots they
+// occupy.
+func orderCreatedPolicyRules(ids []string, created []policyRuleCreation) []string {
+	byID := make(map[string]policyRuleCreation, len(created))
+	for _, c := range created {
+		byID[c.id] = c
+	}
+	var slots []int
+	var present []policyRuleCreation
+	for i, id := range ids {
+		if c, ok := byID[id]; ok {
+			slots = append(slots, i)
+			present = append(present, c)
+		}
+	}
+	sort.SliceStable(present, func(i, j int) bool {
+		if present[i].priority == present[j].priority {
+			return present[i].seq < present[j].seq
+		}
+		return present[i].priority < present[j].priority
+	})
+
+	ordered := append([]string(nil), ids...)
+	for i, slot := range slots {
+		ordered[slot] = present[i].id
+	}
+	return ordered
+}
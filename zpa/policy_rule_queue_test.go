@@ -0,0 +1,314 @@
+package zpa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testRuleQueue is a creation queue whose creations and reorders are recorded.
+// policy is the resulting order of the rules, created rules are appended to it
+// like the API does.
+type testRuleQueue struct {
+	*policyRuleCreationQueue
+	mu       sync.Mutex
+	order    []string
+	policy   []string
+	reorders [][]string
+}
+
+func newTestRuleQueue(reorderErr error) *testRuleQueue {
+	tq := &testRuleQueue{}
+	tq.policyRuleCreationQueue = newPolicyRuleCreationQueue("ACCESS_POLICY", func(ctx context.Context, created []policyRuleCreation) error {
+		var ids []string
+		for _, c := range created {
+			ids = append(ids, c.id)
+		}
+		tq.mu.Lock()
+		tq.reorders = append(tq.reorders, ids)
+		if reorderErr == nil {
+			tq.policy = orderCreatedPolicyRules(tq.policy, created)
+		}
+		tq.mu.Unlock()
+		return reorderErr
+	})
+	return tq
+}
+
+// start queues the creation of rule id and returns the channel its error is
+// sent to. The creation blocks until release is closed, if set.
+func (tq *testRuleQueue) start(ctx context.Context, id string, priority int, err error, release chan struct{}) chan error {
+	result := make(chan error, 1)
+	go func() {
+		result <- tq.create(ctx, priority, func() (string, error) {
+			tq.mu.Lock()
+			tq.order = append(tq.order, id)
+			tq.mu.Unlock()
+			if release != nil {
+				<-release
+			}
+			if err == nil {
+				tq.mu.Lock()
+				tq.policy = append(tq.policy, id)
+				tq.mu.Unlock()
+			}
+			return id, err
+		})
+	}()
+	return result
+}
+
+// waitPending waits until n creations are queued behind the running one.
+func (tq *testRuleQueue) waitPending(t *testing.T, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		tq.policyRuleCreationQueue.mu.Lock()
+		pending := len(tq.pending)
+		tq.policyRuleCreationQueue.mu.Unlock()
+		if pending == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("expected %d queued creations", n)
+}
+
+func (tq *testRuleQueue) waitStarted(t *testing.T, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		tq.mu.Lock()
+		started := len(tq.order)
+		tq.mu.Unlock()
+		if started == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("expected %d started creations", n)
+}
+
+func receive(t *testing.T, result chan error) error {
+	t.Helper()
+	select {
+	case err := <-result:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("creation did not return")
+		return nil
+	}
+}
+
+func TestPolicyRuleCreationQueue_CreatesInPriorityOrder(t *testing.T) {
+	tq := newTestRuleQueue(nil)
+	ctx := context.Background()
+
+	release := make(chan struct{})
+	results := []chan error{tq.start(ctx, "p5", 5, nil, release)}
+	tq.waitStarted(t, 1)
+	results = append(results,
+		tq.start(ctx, "none", 0, nil, nil),
+	)
+	tq.waitPending(t, 1)
+	for _, r := range []struct {
+		id       string
+		priority int
+	}{{"p3a", 3}, {"p1", 1}, {"p3b", 3}} {
+		results = append(results, tq.start(ctx, r.id, r.priority, nil, nil))
+		tq.waitPending(t, len(results)-1)
+	}
+	close(release)
+	for _, result := range results {
+		if err := receive(t, result); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+
+	if want := []string{"p5", "p1", "p3a", "p3b", "none"}; !reflect.DeepEqual(tq.order, want) {
+		t.Errorf("created %v, want %v", tq.order, want)
+	}
+	if want := [][]string{{"p5", "p1", "p3a", "p3b"}}; !reflect.DeepEqual(tq.reorders, want) {
+		t.Errorf("reordered %v, want %v", tq.reorders, want)
+	}
+}
+
+func TestPolicyRuleCreationQueue_ReordersEarlierBatches(t *testing.T) {
+	tq := newTestRuleQueue(nil)
+	ctx := context.Background()
+
+	// The priorities of the two batches interleave
+	for wave, priorities := range [][2]int{{50, 10}, {30, 5}} {
+		release := make(chan struct{})
+		first := tq.start(ctx, fmt.Sprintf("p%d", priorities[0]), priorities[0], nil, release)
+		tq.waitStarted(t, 2*wave+1)
+		second := tq.start(ctx, fmt.Sprintf("p%d", priorities[1]), priorities[1], nil, nil)
+		tq.waitPending(t, 1)
+		close(release)
+		if err := receive(t, first); err != nil {
+			t.Fatal(err)
+		}
+		if err := receive(t, second); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := [][]string{{"p50", "p10"}, {"p50", "p10", "p30", "p5"}}
+	if !reflect.DeepEqual(tq.reorders, want) {
+		t.Errorf("reordered %v, want %v", tq.reorders, want)
+	}
+	if want := []string{"p5", "p10", "p30", "p50"}; !reflect.DeepEqual(tq.policy, want) {
+		t.Errorf("policy %v, want %v", tq.policy, want)
+	}
+}
+
+func TestPolicyRuleCreationQueue_BatchWithoutPriorityIsNotReordered(t *testing.T) {
+	tq := newTestRuleQueue(nil)
+	ctx := context.Background()
+
+	release := make(chan struct{})
+	first := tq.start(ctx, "p2", 2, nil, release)
+	tq.waitStarted(t, 1)
+	second := tq.start(ctx, "p1", 1, nil, nil)
+	tq.waitPending(t, 1)
+	close(release)
+	for _, result := range []chan error{first, second} {
+		if err := receive(t, result); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := receive(t, tq.start(ctx, "none", 0, nil, nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := [][]string{{"p2", "p1"}}; !reflect.DeepEqual(tq.reorders, want) {
+		t.Errorf("reordered %v, want %v", tq.reorders, want)
+	}
+	if want := []string{"p1", "p2", "none"}; !reflect.DeepEqual(tq.policy, want) {
+		t.Errorf("policy %v, want %v", tq.policy, want)
+	}
+}
+
+func TestPolicyRuleCreationQueue_SingleCreationIsNotReordered(t *testing.T) {
+	tq := newTestRuleQueue(nil)
+	if err := receive(t, tq.start(context.Background(), "p1", 1, nil, nil)); err != nil {
+		t.Fatal(err)
+	}
+	if len(tq.reorders) != 0 {
+		t.Errorf("unexpected reorders %v", tq.reorders)
+	}
+}
+
+func TestPolicyRuleCreationQueue_ErrorsOnlyFailTheirRule(t *testing.T) {
+	tq := newTestRuleQueue(nil)
+	ctx := context.Background()
+	createErr := errors.New("create failed")
+
+	release := make(chan struct{})
+	first := tq.start(ctx, "p1", 1, nil, release)
+	tq.waitStarted(t, 1)
+	failed := tq.start(ctx, "p2", 2, createErr, nil)
+	tq.waitPending(t, 1)
+	third := tq.start(ctx, "p3", 3, nil, nil)
+	tq.waitPending(t, 2)
+	close(release)
+
+	if err := receive(t, failed); err != createErr {
+		t.Errorf("got %v, want %v", err, createErr)
+	}
+	for _, result := range []chan error{first, third} {
+		if err := receive(t, result); err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+	}
+	if want := [][]string{{"p1", "p3"}}; !reflect.DeepEqual(tq.reorders, want) {
+		t.Errorf("reordered %v, want %v", tq.reorders, want)
+	}
+}
+
+func TestPolicyRuleCreationQueue_ReorderErrorDoesNotFailCreatedRules(t *testing.T) {
+	tq := newTestRuleQueue(errors.New("reorder failed"))
+	ctx := context.Background()
+
+	release := make(chan struct{})
+	first := tq.start(ctx, "p2", 2, nil, release)
+	tq.waitStarted(t, 1)
+	second := tq.start(ctx, "p1", 1, nil, nil)
+	tq.waitPending(t, 1)
+	close(release)
+
+	for _, result := range []chan error{first, second} {
+		if err := receive(t, result); err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+	}
+	if len(tq.reorders) != 1 {
+		t.Errorf("expected one reorder, got %v", tq.reorders)
+	}
+}
+
+func TestPolicyRuleCreationQueue_CancelledCreationIsSkipped(t *testing.T) {
+	tq := newTestRuleQueue(nil)
+
+	release := make(chan struct{})
+	first := tq.start(context.Background(), "p1", 1, nil, release)
+	tq.waitStarted(t, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := tq.start(ctx, "p2", 2, nil, nil)
+	tq.waitPending(t, 1)
+	cancel()
+	if err := receive(t, cancelled); err != context.Canceled {
+		t.Errorf("got %v, want %v", err, context.Canceled)
+	}
+
+	close(release)
+	if err := receive(t, first); err != nil {
+		t.Fatal(err)
+	}
+	// The queue is drained once the next creation runs
+	if err := receive(t, tq.start(context.Background(), "p3", 3, nil, nil)); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"p1", "p3"}; !reflect.DeepEqual(tq.order, want) {
+		t.Errorf("created %v, want %v", tq.order, want)
+	}
+}
+
+func TestPolicyRuleCreationQueue_CancelledStartedCreationIsWaitedFor(t *testing.T) {
+	tq := newTestRuleQueue(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	release := make(chan struct{})
+	result := tq.start(ctx, "p1", 1, nil, release)
+	tq.waitStarted(t, 1)
+	cancel()
+	select {
+	case err := <-result:
+		t.Fatalf("returned %v while the rule was being created", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	if err := receive(t, result); err != nil {
+		t.Errorf("expected the created rule to be returned, got %v", err)
+	}
+}
+
+func TestOrderCreatedPolicyRules(t *testing.T) {
+	ids := []string{"x", "p5", "p1", "y", "p3a", "p3b", "none"}
+	created := []policyRuleCreation{
+		{id: "p5", priority: 5, seq: 0},
+		{id: "p1", priority: 1, seq: 1},
+		{id: "p3b", priority: 3, seq: 3},
+		{id: "p3a", priority: 3, seq: 2},
+	}
+	want := []string{"x", "p1", "p3a", "y", "p3b", "p5", "none"}
+	if got := orderCreatedPolicyRules(ids, created); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
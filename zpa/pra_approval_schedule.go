@@ -0,0 +1,543 @@
+package zpa
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/privilegedremoteaccess/praapproval"
+)
+
+// praWeekdays are the days of working_hours, in the order of the cron
+// expressions.
+var praWeekdays = []string{"MON", "TUE", "WED", "THU", "FRI", "SAT", "SUN"}
+
+// praApprovalMaxDuration is the longest approval accepted by ZPA.
+const praApprovalMaxDuration = 365 * 24 * time.Hour
+
+var (
+	// praApprovalDuration matches durations such as 8h, 3d or 1d12h.
+	praApprovalDuration     = regexp.MustCompile(`^(?:\d+[wdhm])+$`)
+	praApprovalDurationPart = regexp.MustCompile(`(\d+)([wdhm])`)
+	praApprovalDurationUnit = map[string]time.Duration{
+		"w": 7 * 24 * time.Hour,
+		"d": 24 * time.Hour,
+		"h": time.Hour,
+		"m": time.Minute,
+	}
+)
+
+func parsePRAApprovalDuration(s string) (time.Duration, error) {
+	if !praApprovalDuration.MatchString(s) {
+		return 0, fmt.Errorf("%q is not a valid duration, use weeks, days, hours and minutes such as 8h, 3d or 1d12h", s)
+	}
+	var total time.Duration
+	for _, m := range praApprovalDurationPart.FindAllStringSubmatch(s, -1) {
+		n, err := strconv.ParseInt(m[1], 10, 64)
+		unit := praApprovalDurationUnit[m[2]]
+		if err != nil || time.Duration(n) > praApprovalMaxDuration/unit {
+			return 0, fmt.Errorf("duration %s is longer than 365 days", s)
+		}
+		total += time.Duration(n) * unit
+	}
+	if total <= 0 {
+		return 0, fmt.Errorf("duration %s must be longer than zero", s)
+	}
+	if total > praApprovalMaxDuration {
+		return 0, fmt.Errorf("duration %s is longer than 365 days", s)
+	}
+	return total, nil
+}
+
+func validatePRAApprovalDuration(v interface{}, k string) (ws []string, errors []error) {
+	if _, err := parsePRAApprovalDuration(v.(string)); err != nil {
+		errors = append(errors, err)
+	}
+	return
+}
+
+// praApprovalEndTime returns the end time of an approval of duration starting
+// at startTime, formatted as the end_time read from the API.
+func praApprovalEndTime(startTime, duration string) (string, error) {
+	startTimeEpoch, err := convertToEpoch(startTime)
+	if err != nil {
+		return "", fmt.Errorf("start time conversion error: %s", err)
+	}
+	d, err := parsePRAApprovalDuration(duration)
+	if err != nil {
+		return "", err
+	}
+	return epochToRFC1123(strconv.FormatInt(startTimeEpoch+int64(d/time.Second), 10), false)
+}
+
+// workingHoursCrons derives the start and end cron expressions of working
+// hours. When the end time is not after the start time, the working hours end
+// on the next day.
+func workingHoursCrons(days []string, startTime, endTime string) (string, string, error) {
+	start, err := time.Parse("15:04", startTime)
+	if err != nil {
+		return "", "", fmt.Errorf("%q is not a valid start_time (expected HH:MM)", startTime)
+	}
+	end, err := time.Parse("15:04", endTime)
+	if err != nil {
+		return "", "", fmt.Errorf("%q is not a valid end_time (expected HH:MM)", endTime)
+	}
+	if start.Equal(end) {
+		return "", "", fmt.Errorf("start_time and end_time cannot both be %s", startTime)
+	}
+	shift := 0
+	if end.Before(start) {
+		shift = 1
+	}
+	startDays, err := cronDays(days, 0)
+	if err != nil {
+		return "", "", err
+	}
+	endDays, err := cronDays(days, shift)
+	if err != nil {
+		return "", "", err
+	}
+	return fmt.Sprintf("0 %d %d ? * %s", start.Minute(), start.Hour(), startDays),
+		fmt.Sprintf("0 %d %d ? * %s", end.Minute(), end.Hour(), endDays), nil
+}
+
+// cronDays returns the day of week field of days moved shift days later.
+func cronDays(days []string, shift int) (string, error) {
+	var indexes []int
+	for _, day := range days {
+		i := indexOf(praWeekdays, day)
+		if i < 0 {
+			return "", fmt.Errorf("%q is not a day, expected one of %s", day, strings.Join(praWeekdays, ", "))
+		}
+		indexes = append(indexes, (i+shift)%len(praWeekdays))
+	}
+	sort.Ints(indexes)
+	names := make([]string, 0, len(indexes))
+	for i, index := range indexes {
+		if i == 0 || index != indexes[i-1] {
+			names = append(names, praWeekdays[index])
+		}
+	}
+	return strings.Join(names, ","), nil
+}
+
+func indexOf(list []string, item string) int {
+	for i, v := range list {
+		if v == item {
+			return i
+		}
+	}
+	return -1
+}
+
+// sameCron reports whether a cron expression written in the configuration
+// fires at the same times as the derived one, so that equivalent day fields
+// such as MON-FRI do not warn.
+func sameCron(written, derived string) bool {
+	w, d := strings.Fields(strings.ToUpper(written)), strings.Fields(derived)
+	if len(w) == 7 && w[6] == "*" {
+		w = w[:6]
+	}
+	if len(w) != 6 || w[3] != "?" || w[4] != "*" {
+		return false
+	}
+	for i := 0; i < 3; i++ {
+		a, err := strconv.Atoi(w[i])
+		b, _ := strconv.Atoi(d[i])
+		if err != nil || a != b {
+			return false
+		}
+	}
+	var days []string
+	for _, part := range strings.Split(w[5], ",") {
+		from, to, isRange := strings.Cut(part, "-")
+		if !isRange {
+			to = from
+		}
+		i, j := indexOf(praWeekdays, from), indexOf(praWeekdays, to)
+		if i < 0 || j < 0 {
+			return false
+		}
+		for ; ; i = (i + 1) % len(praWeekdays) {
+			days = append(days, praWeekdays[i])
+			if i == j {
+				break
+			}
+		}
+	}
+	writtenDays, _ := cronDays(days, 0)
+	return writtenDays == d[5]
+}
+
+// hashWorkingHours hashes working_hours without the cron expressions, so that
+// leaving them out of the configuration does not replace the block.
+func hashWorkingHours(v interface{}) int {
+	m := v.(map[string]interface{})
+	var days []string
+	switch s := m["days"].(type) {
+	case *schema.Set:
+		days = SetToStringSlice(s)
+	case []interface{}:
+		days = ListToStringSlice(s)
+	}
+	sort.Strings(days)
+	return schema.HashString(fmt.Sprintf("%s|%v|%v|%v", strings.Join(days, ","), m["start_time"], m["end_time"], m["timezone"]))
+}
+
+// customizeDiffPRAApproval computes end_time from duration, plans the cron
+// expressions of working_hours and rejects approvals overlapping another
+// approval of the same user and applications.
+func customizeDiffPRAApproval(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if err := planPRAApprovalEndTime(d); err != nil {
+		return err
+	}
+	if err := planWorkingHoursCrons(d); err != nil {
+		return err
+	}
+	return validatePRAApprovalOverlap(ctx, d, meta)
+}
+
+func planPRAApprovalEndTime(d *schema.ResourceDiff) error {
+	if !d.NewValueKnown("duration") {
+		return d.SetNewComputed("end_time")
+	}
+	duration := d.Get("duration").(string)
+	if duration == "" {
+		return nil
+	}
+	if !d.NewValueKnown("start_time") {
+		return d.SetNewComputed("end_time")
+	}
+	endTime, err := praApprovalEndTime(d.Get("start_time").(string), duration)
+	if err != nil {
+		return err
+	}
+	return d.SetNew("end_time", endTime)
+}
+
+// planWorkingHoursCrons plans the cron expressions derived from days,
+// start_time and end_time, so that approvals whose cron expressions no longer
+// match their working hours are corrected. Cron expressions written in the
+// configuration are deprecated: they are still sent as written, with a
+// warning when they do not match the derived ones.
+func planWorkingHoursCrons(d *schema.ResourceDiff) error {
+	if !d.NewValueKnown("working_hours") {
+		return nil
+	}
+	written := writtenWorkingHoursCrons(d.GetRawConfig().GetAttr("working_hours"))
+	workingHoursSet, ok := d.Get("working_hours").(*schema.Set)
+	if !ok || workingHoursSet.Len() == 0 {
+		return nil
+	}
+
+	changed := false
+	var planned []interface{}
+	for _, v := range workingHoursSet.List() {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		element := make(map[string]interface{}, len(m))
+		for key, value := range m {
+			element[key] = value
+		}
+		var days []string
+		if daysSet, ok := m["days"].(*schema.Set); ok {
+			days = SetToStringSlice(daysSet)
+			element["days"] = daysSet.List()
+		}
+		startTime, _ := m["start_time"].(string)
+		endTime, _ := m["end_time"].(string)
+		if len(days) > 0 && startTime != "" && endTime != "" {
+			// Invalid working hours are left to the API.
+			if startCron, endCron, err := workingHoursCrons(days, startTime, endTime); err == nil {
+				for _, cron := range []struct{ key, derived string }{
+					{"start_time_cron", startCron},
+					{"end_time_cron", endCron},
+				} {
+					current, _ := m[cron.key].(string)
+					if written[current] {
+						if !sameCron(current, cron.derived) {
+							log.Printf("[WARN] working_hours %s %q does not match days, start_time and end_time, remove it to use the derived %q", cron.key, current, cron.derived)
+						}
+						continue
+					}
+					if current != cron.derived {
+						element[cron.key] = cron.derived
+						changed = true
+					}
+				}
+			}
+		}
+		planned = append(planned, element)
+	}
+	if !changed {
+		return nil
+	}
+	return d.SetNew("working_hours", planned)
+}
+
+// writtenWorkingHoursCrons returns the cron expressions written in the
+// working_hours blocks of the configuration.
+func writtenWorkingHoursCrons(workingHours cty.Value) map[string]bool {
+	written := map[string]bool{}
+	if workingHours.IsNull() || !workingHours.IsKnown() {
+		return written
+	}
+	for it := workingHours.ElementIterator(); it.Next(); {
+		_, v := it.Element()
+		if v.IsNull() || !v.IsKnown() {
+			continue
+		}
+		for _, key := range []string{"start_time_cron", "end_time_cron"} {
+			if cron := ctyString(v.GetAttr(key)); cron != "" {
+				written[cron] = true
+			}
+		}
+	}
+	return written
+}
+
+func ctyString(v cty.Value) string {
+	if v.IsNull() || !v.IsKnown() {
+		return ""
+	}
+	return v.AsString()
+}
+
+// praApprovalWindow is the time window of an approval for a set of email
+// addresses and applications.
+type praApprovalWindow struct {
+	// ID of the approval, or the planned values of an approval not created yet
+	key            string
+	description    string
+	emails         map[string]bool
+	applicationIDs []string
+	start, end     int64
+}
+
+func (w praApprovalWindow) overlaps(other praApprovalWindow) bool {
+	if !isSameSlice(w.applicationIDs, other.applicationIDs) || w.start >= other.end || other.start >= w.end {
+		return false
+	}
+	for email := range other.emails {
+		if w.emails[email] {
+			return true
+		}
+	}
+	return false
+}
+
+// praApprovalPlans records the approval windows planned through a client,
+// per microtenant, so that approvals of the same configuration are checked
+// against each other and not only against the approvals of the tenant.
+type praApprovalPlans struct {
+	mu      sync.Mutex
+	windows map[string]map[string]praApprovalWindow
+}
+
+// plannedPRAApprovals returns the planned approval windows of the client.
+func (c *Client) plannedPRAApprovals() *praApprovalPlans {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.praApprovalPlans == nil {
+		c.praApprovalPlans = &praApprovalPlans{windows: map[string]map[string]praApprovalWindow{}}
+	}
+	return c.praApprovalPlans
+}
+
+// add records window, replacing the previous window with the same key, and
+// returns the other planned windows it overlaps.
+func (p *praApprovalPlans) add(scope string, window praApprovalWindow) []praApprovalWindow {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	windows, ok := p.windows[scope]
+	if !ok {
+		windows = map[string]praApprovalWindow{}
+		p.windows[scope] = windows
+	}
+	windows[window.key] = window
+	var overlaps []praApprovalWindow
+	for key, other := range windows {
+		if key != window.key && window.overlaps(other) {
+			overlaps = append(overlaps, other)
+		}
+	}
+	return overlaps
+}
+
+// planned reports whether the approval with id has a planned window.
+func (p *praApprovalPlans) planned(scope, id string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, ok := p.windows[scope][id]
+	return ok
+}
+
+// plannedPRAApprovalWindow returns the planned start and end of the
+// approval, the end computed from duration when it is set. ok is false when
+// they are not known yet or invalid.
+func plannedPRAApprovalWindow(d *schema.ResourceDiff) (start, end int64, ok bool) {
+	if !d.NewValueKnown("start_time") || !d.NewValueKnown("duration") {
+		return 0, 0, false
+	}
+	// Invalid times are reported by validateStartTime on apply.
+	start, err := convertToEpoch(d.Get("start_time").(string))
+	if err != nil {
+		return 0, 0, false
+	}
+	if duration := d.Get("duration").(string); duration != "" {
+		length, err := parsePRAApprovalDuration(duration)
+		if err != nil {
+			return 0, 0, false
+		}
+		return start, start + int64(length/time.Second), true
+	}
+	if !d.NewValueKnown("end_time") {
+		return 0, 0, false
+	}
+	end, err = convertToEpoch(d.Get("end_time").(string))
+	if err != nil {
+		return 0, 0, false
+	}
+	return start, end, true
+}
+
+// validatePRAApprovalOverlap rejects approvals sharing an email with another
+// approval of the same applications, when their time windows overlap. The
+// approval is checked against the approvals of the tenant and the approvals
+// planned before it through the same client. Existing approvals that are
+// also planned are checked with their planned window, once they are planned.
+func validatePRAApprovalOverlap(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	for _, key := range []string{"email_ids", "applications", "microtenant_id"} {
+		if !d.NewValueKnown(key) {
+			return nil
+		}
+	}
+	if d.Id() != "" && !d.HasChanges("email_ids", "applications", "start_time", "end_time", "duration", "microtenant_id") {
+		return nil
+	}
+	zClient, ok := meta.(*Client)
+	if !ok || zClient == nil {
+		return nil
+	}
+	startTime, endTime, ok := plannedPRAApprovalWindow(d)
+	if !ok {
+		return nil
+	}
+	emails := map[string]bool{}
+	var emailIDs []string
+	for _, email := range SetToStringSlice(d.Get("email_ids").(*schema.Set)) {
+		emails[strings.ToLower(email)] = true
+		emailIDs = append(emailIDs, strings.ToLower(email))
+	}
+	var applicationIDs []string
+	for _, application := range d.Get("applications").(*schema.Set).List() {
+		if application, ok := application.(map[string]interface{}); ok {
+			applicationIDs = append(applicationIDs, ListToStringSlice(application["id"].([]interface{}))...)
+		}
+	}
+	applicationIDs = sortedUnique(applicationIDs)
+	if len(emails) == 0 || len(applicationIDs) == 0 {
+		return nil
+	}
+
+	microTenantID := GetString(d.Get("microtenant_id"))
+	emailIDs = sortedUnique(emailIDs)
+	window := praApprovalWindow{
+		key:            d.Id(),
+		description:    fmt.Sprintf("planned approval for %s", strings.Join(emailIDs, ", ")),
+		emails:         emails,
+		applicationIDs: applicationIDs,
+		start:          startTime,
+		end:            endTime,
+	}
+	if window.key == "" {
+		// A new approval has no ID yet, planning it again replaces it.
+		window.key = fmt.Sprintf("%s|%s|%d|%d", strings.Join(emailIDs, ","), strings.Join(applicationIDs, ","), startTime, endTime)
+	} else {
+		window.description = fmt.Sprintf("%s for %s (planned)", d.Id(), strings.Join(emailIDs, ", "))
+	}
+	plans := zClient.plannedPRAApprovals()
+	var overlaps []string
+	for _, other := range plans.add(microTenantID, window) {
+		overlaps = append(overlaps, praApprovalWindowDescription(other))
+	}
+
+	service := zClient.Service
+	if microTenantID != "" {
+		service = service.WithMicroTenant(microTenantID)
+	}
+	approvals, _, err := praapproval.GetAll(ctx, service)
+	if err != nil {
+		log.Printf("[WARN] Could not check privileged approvals for overlaps at plan time: %v", err)
+		approvals = nil
+	}
+	for _, approval := range approvals {
+		if approval.ID == d.Id() || plans.planned(microTenantID, approval.ID) {
+			continue
+		}
+		existing, ok := praApprovalWindowOf(approval)
+		if ok && window.overlaps(existing) {
+			overlaps = append(overlaps, praApprovalWindowDescription(existing))
+		}
+	}
+	if len(overlaps) > 0 {
+		sort.Strings(overlaps)
+		return fmt.Errorf("the approval overlaps other approvals of the same user and applications:\n  - %s\n\nChange start_time, end_time or duration so that the approvals do not overlap, or update the existing approval instead", strings.Join(overlaps, "\n  - "))
+	}
+	return nil
+}
+
+// praApprovalWindowOf returns the window of an approval read from the API.
+func praApprovalWindowOf(approval praapproval.PrivilegedApproval) (praApprovalWindow, bool) {
+	start, err := strconv.ParseInt(approval.StartTime, 10, 64)
+	if err != nil {
+		return praApprovalWindow{}, false
+	}
+	end, err := strconv.ParseInt(approval.EndTime, 10, 64)
+	if err != nil {
+		return praApprovalWindow{}, false
+	}
+	emails := map[string]bool{}
+	for _, email := range approval.EmailIDs {
+		emails[strings.ToLower(email)] = true
+	}
+	ids := make([]string, 0, len(approval.Applications))
+	for _, application := range approval.Applications {
+		ids = append(ids, application.ID)
+	}
+	return praApprovalWindow{
+		key:            approval.ID,
+		description:    fmt.Sprintf("%s for %s", approval.ID, strings.Join(approval.EmailIDs, ", ")),
+		emails:         emails,
+		applicationIDs: sortedUnique(ids),
+		start:          start,
+		end:            end,
+	}, true
+}
+
+func praApprovalWindowDescription(w praApprovalWindow) string {
+	return fmt.Sprintf("%s, from %s to %s", w.description,
+		time.Unix(w.start, 0).UTC().Format(time.RFC1123), time.Unix(w.end, 0).UTC().Format(time.RFC1123))
+}
+
+func sortedUnique(values []string) []string {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	unique := sorted[:0]
+	for i, value := range sorted {
+		if i == 0 || value != sorted[i-1] {
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
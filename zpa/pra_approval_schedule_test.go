@@ -0,0 +1,222 @@
+package zpa
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func praWindow(key string, start, end int64, applicationIDs []string, emails ...string) praApprovalWindow {
+	w := praApprovalWindow{key: key, description: key, emails: map[string]bool{}, applicationIDs: applicationIDs, start: start, end: end}
+	for _, email := range emails {
+		w.emails[email] = true
+	}
+	return w
+}
+
+func TestPRAApprovalPlans_Add(t *testing.T) {
+	apps := []string{"1", "2"}
+	plans := (&Client{}).plannedPRAApprovals()
+
+	if overlaps := plans.add("", praWindow("a", 100, 200, apps, "jdoe@acme.com")); len(overlaps) != 0 {
+		t.Fatalf("unexpected overlaps %v", overlaps)
+	}
+	// Planning the same approval again does not overlap itself
+	if overlaps := plans.add("", praWindow("a", 100, 200, apps, "jdoe@acme.com")); len(overlaps) != 0 {
+		t.Fatalf("unexpected overlaps %v", overlaps)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		scope   string
+		window  praApprovalWindow
+		overlap bool
+	}{
+		{name: "overlapping window", window: praWindow("b", 150, 250, apps, "jdoe@acme.com", "other@acme.com"), overlap: true},
+		{name: "adjacent window", window: praWindow("c", 200, 300, apps, "jdoe@acme.com")},
+		{name: "other user", window: praWindow("d", 100, 200, apps, "other@acme.com")},
+		{name: "other applications", window: praWindow("e", 100, 200, []string{"1"}, "jdoe@acme.com")},
+		{name: "other microtenant", scope: "216199618143320419", window: praWindow("f", 100, 200, apps, "jdoe@acme.com")},
+	} {
+		overlaps := plans.add(tc.scope, tc.window)
+		if tc.overlap != (len(overlaps) == 1 && overlaps[0].key == "a") {
+			t.Errorf("%s: got overlaps %v, want overlap with a: %v", tc.name, overlaps, tc.overlap)
+		}
+	}
+	if !plans.planned("", "a") || plans.planned("", "z") {
+		t.Error("expected only the planned approvals to be reported as planned")
+	}
+}
+
+func TestPlannedPRAApprovalWindow(t *testing.T) {
+	// unknown is the value of attributes computed on apply in raw configs.
+	const unknown = "74D93920-ED26-11E3-AC10-0800200C9A66"
+	const start = "Mon, 02 Jun 2025 08:00:00 +0000"
+	var got []int64
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"start_time": {Type: schema.TypeString, Optional: true},
+			"end_time":   {Type: schema.TypeString, Optional: true, Computed: true},
+			"duration":   {Type: schema.TypeString, Optional: true},
+		},
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			if err := planPRAApprovalEndTime(d); err != nil {
+				return err
+			}
+			got = nil
+			if start, end, ok := plannedPRAApprovalWindow(d); ok {
+				got = []int64{start, end}
+			}
+			return nil
+		},
+	}
+
+	for name, tc := range map[string]struct {
+		config map[string]interface{}
+		want   []int64
+	}{
+		"end_time":           {config: map[string]interface{}{"start_time": start, "end_time": "Mon, 02 Jun 2025 16:00:00 +0000"}, want: []int64{1748851200, 1748880000}},
+		"duration":           {config: map[string]interface{}{"start_time": start, "duration": "1d12h"}, want: []int64{1748851200, 1748851200 + 36*3600}},
+		"start_time unknown": {config: map[string]interface{}{"start_time": unknown, "duration": "8h"}},
+		"duration unknown":   {config: map[string]interface{}{"start_time": start, "duration": unknown}},
+		"invalid start_time": {config: map[string]interface{}{"start_time": "tomorrow", "end_time": "Mon, 02 Jun 2025 16:00:00 +0000"}},
+		"no end_time":        {config: map[string]interface{}{"start_time": start}},
+	} {
+		got = nil
+		if _, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigRaw(tc.config), nil); err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tc.want) {
+			t.Errorf("%s: got window %v, want %v", name, got, tc.want)
+		}
+	}
+}
+
+func TestParsePRAApprovalDuration(t *testing.T) {
+	for _, tc := range []struct {
+		in      string
+		want    time.Duration
+		wantErr string
+	}{
+		{in: "8h", want: 8 * time.Hour},
+		{in: "90m", want: 90 * time.Minute},
+		{in: "3d", want: 72 * time.Hour},
+		{in: "1d12h", want: 36 * time.Hour},
+		{in: "2w1d", want: 15 * 24 * time.Hour},
+		{in: "365d", want: 365 * 24 * time.Hour},
+		{in: "", wantErr: "is not a valid duration"},
+		{in: "8", wantErr: "is not a valid duration"},
+		{in: "8H", wantErr: "is not a valid duration"},
+		{in: "8s", wantErr: "is not a valid duration"},
+		{in: "-1h", wantErr: "is not a valid duration"},
+		{in: "1.5h", wantErr: "is not a valid duration"},
+		{in: "0h", wantErr: "must be longer than zero"},
+		{in: "366d", wantErr: "is longer than 365 days"},
+		{in: "52w2d", wantErr: "is longer than 365 days"},
+		{in: "99999999999999999999h", wantErr: "is longer than 365 days"},
+	} {
+		got, err := parsePRAApprovalDuration(tc.in)
+		if tc.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("%q: got error %v, want %q", tc.in, err, tc.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Errorf("%q: got %v, %v, want %v", tc.in, got, err, tc.want)
+		}
+	}
+}
+
+func TestWorkingHoursCrons(t *testing.T) {
+	for name, tc := range map[string]struct {
+		days               []string
+		start, end         string
+		wantStart, wantEnd string
+		wantErr            string
+	}{
+		"same day": {
+			days: []string{"MON", "FRI"}, start: "09:00", end: "17:30",
+			wantStart: "0 0 9 ? * MON,FRI", wantEnd: "0 30 17 ? * MON,FRI",
+		},
+		"overnight": {
+			days: []string{"FRI", "SAT", "SUN"}, start: "22:00", end: "06:15",
+			wantStart: "0 0 22 ? * FRI,SAT,SUN", wantEnd: "0 15 6 ? * MON,SAT,SUN",
+		},
+		"unordered days": {
+			days: []string{"SUN", "WED", "MON"}, start: "00:10", end: "09:15",
+			wantStart: "0 10 0 ? * MON,WED,SUN", wantEnd: "0 15 9 ? * MON,WED,SUN",
+		},
+		"invalid start_time": {days: []string{"MON"}, start: "9am", end: "17:00", wantErr: `"9am" is not a valid start_time`},
+		"invalid end_time":   {days: []string{"MON"}, start: "09:00", end: "25:00", wantErr: `"25:00" is not a valid end_time`},
+		"same times":         {days: []string{"MON"}, start: "09:00", end: "09:00", wantErr: "start_time and end_time cannot both be 09:00"},
+		"invalid day":        {days: []string{"MONDAY"}, start: "09:00", end: "17:00", wantErr: `"MONDAY" is not a day`},
+	} {
+		start, end, err := workingHoursCrons(tc.days, tc.start, tc.end)
+		if tc.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("%s: got error %v, want %q", name, err, tc.wantErr)
+			}
+			continue
+		}
+		if err != nil || start != tc.wantStart || end != tc.wantEnd {
+			t.Errorf("%s: got %q, %q, %v, want %q, %q", name, start, end, err, tc.wantStart, tc.wantEnd)
+		}
+	}
+}
+
+func TestCronDays(t *testing.T) {
+	for _, tc := range []struct {
+		days  []string
+		shift int
+		want  string
+	}{
+		{days: []string{"WED", "MON", "SUN"}, want: "MON,WED,SUN"},
+		{days: []string{"MON", "TUE", "MON"}, want: "MON,TUE"},
+		{days: []string{"SAT", "SUN"}, shift: 1, want: "MON,SUN"},
+		{days: []string{"MON", "TUE"}, shift: 1, want: "TUE,WED"},
+		{days: praWeekdays, shift: 1, want: strings.Join(praWeekdays, ",")},
+	} {
+		got, err := cronDays(tc.days, tc.shift)
+		if err != nil || got != tc.want {
+			t.Errorf("%v shifted by %d: got %q, %v, want %q", tc.days, tc.shift, got, err, tc.want)
+		}
+	}
+}
+
+func TestSameCron(t *testing.T) {
+	overnightStart, overnightEnd, err := workingHoursCrons([]string{"FRI", "SAT", "SUN"}, "22:00", "06:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		written, derived string
+		want             bool
+	}{
+		{written: "0 0 9 ? * MON,TUE,WED,THU,FRI", derived: "0 0 9 ? * MON,TUE,WED,THU,FRI", want: true},
+		{written: "0 0 9 ? * MON-FRI", derived: "0 0 9 ? * MON,TUE,WED,THU,FRI", want: true},
+		{written: "0 00 09 ? * fri,mon", derived: "0 0 9 ? * MON,FRI", want: true},
+		{written: "0 0 9 ? * MON,MON-TUE", derived: "0 0 9 ? * MON,TUE", want: true},
+		{written: "0 0 9 ? * MON *", derived: "0 0 9 ? * MON", want: true},
+		// Deprecated crons written like the derived ones of overnight hours
+		{written: "0 0 22 ? * FRI-SUN", derived: overnightStart, want: true},
+		{written: "0 0 6 ? * SAT-MON", derived: overnightEnd, want: true},
+		{written: "0 0 6 ? * FRI-SUN", derived: overnightEnd},
+		{written: "0 0 10 ? * MON", derived: "0 0 9 ? * MON"},
+		{written: "0 30 9 ? * MON", derived: "0 0 9 ? * MON"},
+		{written: "0 0 9 * * MON", derived: "0 0 9 ? * MON"},
+		{written: "0 0 9 ? * MON 2030", derived: "0 0 9 ? * MON"},
+		{written: "0 0 9 ? * MONDAY", derived: "0 0 9 ? * MON"},
+		{written: "0 0 9 ? *", derived: "0 0 9 ? * MON"},
+	} {
+		if got := sameCron(tc.written, tc.derived); got != tc.want {
+			t.Errorf("sameCron(%q, %q) = %v, want %v", tc.written, tc.derived, got, tc.want)
+		}
+	}
+}
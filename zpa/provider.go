@@ -96,15 +96,127 @@ func ZPAProvider() *schema.Provider {
 					return validation.StringInSlice([]string{"PRODUCTION", "ZPATWO", "BETA", "GOV", "GOVUS", "PREVIEW", "DEV", "QA", "QA2"}, true)(val, key)
 				},
 			},
+			"api_endpoint": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+				Description:  "Base URL that API requests are sent to instead of the endpoint derived from `zscaler_cloud` or `zpa_cloud`. Can also be sourced from the `ZSCALER_API_ENDPOINT` environment variable.",
+			},
+			"auth_endpoint": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+				Description:  "Base URL that authentication requests are sent to instead of the endpoint derived from `vanity_domain` and `zscaler_cloud`. Can also be sourced from the `ZSCALER_AUTH_ENDPOINT` environment variable.",
+			},
 			"use_legacy_client": {
 				Type:        schema.TypeBool,
 				Optional:    true,
 				Description: "Enables interaction with the ZPA legacy API framework",
 			},
+			"get_rate_limit": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ValidateDiagFunc: intBetween(0, 1000),
+				Description:      "Maximum number of GET requests sent per `rate_limit_interval_seconds`, shared by all resources and data sources. `0` (default) disables proactive throttling of GET requests. Take note of https://help.zscaler.com/zpa/understanding-rate-limiting.",
+			},
+			"write_rate_limit": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ValidateDiagFunc: intBetween(0, 1000),
+				Description:      "Maximum number of POST, PUT and DELETE requests sent per `rate_limit_interval_seconds`, shared by all resources and data sources. `0` (default) disables proactive throttling of write requests.",
+			},
+			"rate_limit_interval_seconds": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ValidateDiagFunc: intBetween(1, 3600),
+				Description:      "Interval, in seconds, over which `get_rate_limit` and `write_rate_limit` apply. The default is `10`.",
+			},
+			"read_prefetch": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Serve reads of application segments, server groups and application servers from a single GetAll per type and microtenant. Can also be sourced from the `ZSCALER_READ_PREFETCH` environment variable.",
+			},
+			"skip_policy_reference_validation": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Skip resolving the IDs referenced in the conditions of v2 policy rules against the tenant during plan. Can also be sourced from the `ZSCALER_SKIP_POLICY_REFERENCE_VALIDATION` environment variable.",
+			},
+			"prune_dangling_references": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Leave the values of the conditions of v2 policy rules that reference objects that no longer exist out of the rule, instead of failing the plan. Can also be sourced from the `ZSCALER_PRUNE_DANGLING_REFERENCES` environment variable.",
+			},
+			"expired_policy_rule_action": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{expiredPolicyRuleDisable, expiredPolicyRuleDelete}, false),
+				Description:  "What apply does with a v2 access or forwarding rule whose `expires_at` has passed: `disable` (the default) or `delete`. Can also be sourced from the `ZSCALER_EXPIRED_POLICY_RULE_ACTION` environment variable.",
+			},
+			"country_alias": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "Country aliases, in addition to the built-in ones, that can be used as `@NAME` in the COUNTRY_CODE operands of `zpa_policy_access_rule_v2`.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:         schema.TypeString,
+							Required:     true,
+							Description:  "Name of the alias, without the `@` prefix.",
+							ValidateFunc: validation.StringMatch(countryAliasNamePattern, "must consist of upper case letters, digits and underscores"),
+						},
+						"countries": {
+							Type:        schema.TypeSet,
+							Required:    true,
+							Description: "ISO-3166 Alpha-2 codes of the countries of the alias.",
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validateCountryCode,
+							},
+						},
+					},
+				},
+			},
+			"token_cache": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Persist the OneAPI access token in an encrypted file so that later provider processes with the same credentials reuse it until it expires. Can also be sourced from the `ZSCALER_TOKEN_CACHE` environment variable.",
+			},
+			"token_cache_dir": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Directory holding the token cache files. Defaults to `terraform-provider-zpa/tokens` under the user cache directory. Can also be sourced from the `ZSCALER_TOKEN_CACHE_DIR` environment variable.",
+			},
 			"http_proxy": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Description: "Alternate HTTP proxy of scheme://hostname or scheme://hostname:port format",
+				Description: "Alternate HTTP proxy of scheme://[user:password@]hostname[:port] format. Also used for HTTPS requests unless `https_proxy` is set.",
+			},
+			"https_proxy": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Proxy used for HTTPS requests, of scheme://[user:password@]hostname[:port] format. The scheme may be http, https or socks5. Can also be sourced from the `ZSCALER_HTTPS_PROXY` environment variable.",
+			},
+			"no_proxy": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Comma-separated list of hosts, domains and CIDR ranges that bypass the proxy. Can also be sourced from the `ZSCALER_NO_PROXY` environment variable.",
+			},
+			"ca_bundle_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Path to a PEM file of additional CA certificates trusted on top of the system roots, for example the certificate of a TLS-inspecting proxy. Can also be sourced from the `ZSCALER_CA_BUNDLE_FILE` environment variable.",
+			},
+			"client_cert_file": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"client_key_file"},
+				Description:  "Path to a PEM client certificate presented for mutual TLS. Can also be sourced from the `ZSCALER_CLIENT_CERT_FILE` environment variable.",
+			},
+			"client_key_file": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"client_cert_file"},
+				Description:  "Path to the PEM private key of `client_cert_file`. Can also be sourced from the `ZSCALER_CLIENT_KEY_FILE` environment variable.",
 			},
 			"backoff": {
 				Type:        schema.TypeBool,
@@ -161,6 +273,8 @@ func ZPAProvider() *schema.Provider {
 			"zpa_segment_group":                            resourceSegmentGroup(),
 			"zpa_server_group":                             resourceServerGroup(),
 			"zpa_policy_access_rule_reorder":               resourcePolicyAccessRuleReorder(),
+			"zpa_policy_set":                               resourcePolicySet(),
+			"zpa_policy_rule_json":                         resourcePolicyRuleJSON(),
 			"zpa_policy_access_rule":                       resourcePolicyAccessRule(),
 			"zpa_policy_browser_protection_rule":           resourcePolicyBrowserProtectionRule(),
 			"zpa_policy_inspection_rule":                   resourcePolicyInspectionRule(),
@@ -173,6 +287,7 @@ func ZPAProvider() *schema.Provider {
 			"zpa_policy_inspection_rule_v2":                resourcePolicyInspectionRuleV2(),
 			"zpa_policy_forwarding_rule_v2":                resourcePolicyForwardingRuleV2(),
 			"zpa_policy_timeout_rule_v2":                   resourcePolicyTimeoutRuleV2(),
+			"zpa_policy_redirection_rule_v2":               resourcePolicyRedirectionRuleV2(),
 			"zpa_policy_credential_rule":                   resourcePolicyCredentialAccessRule(),
 			"zpa_policy_capabilities_rule":                 resourcePolicyCapabilitiesAccessRule(),
 			"zpa_policy_portal_access_rule":                resourcePolicyPortalAccessRule(),
@@ -180,6 +295,7 @@ func ZPAProvider() *schema.Provider {
 			"zpa_service_edge_group":                       resourceServiceEdgeGroup(),
 			"zpa_service_edge_assistant_schedule":          resourceServiceEdgeAssistantSchedule(),
 			"zpa_lss_config_controller":                    resourceLSSConfigController(),
+			"zpa_lss_receiver":                             resourceLSSReceiver(),
 			"zpa_inspection_custom_controls":               resourceInspectionCustomControls(),
 			"zpa_inspection_profile":                       resourceInspectionProfile(),
 			"zpa_microtenant_controller":                   resourceMicrotenantController(),
@@ -243,11 +359,16 @@ func ZPAProvider() *schema.Provider {
 			"zpa_trusted_network":                          dataSourceTrustedNetwork(),
 			"zpa_access_policy_platforms":                  dataSourceAccessPolicyPlatforms(),
 			"zpa_access_policy_client_types":               dataSourceAccessPolicyClientTypes(),
+			"zpa_policy_operand_types":                     dataSourcePolicyOperandTypes(),
+			"zpa_policy_evaluation":                        dataSourcePolicyEvaluation(),
+			"zpa_policy_rule_lint":                         dataSourcePolicyRuleLint(),
+			"zpa_policy_rule_json":                         dataSourcePolicyRuleJSON(),
 			"zpa_risk_score_values":                        dataSourceRiskScoreValues(),
 			"zpa_lss_config_controller":                    dataSourceLSSConfigController(),
 			"zpa_lss_config_client_types":                  dataSourceLSSClientTypes(),
 			"zpa_lss_config_status_codes":                  dataSourceLSSStatusCodes(),
 			"zpa_lss_config_log_type_formats":              dataSourceLSSLogTypeFormats(),
+			"zpa_lss_log_preview":                          dataSourceLSSLogPreview(),
 			"zpa_inspection_predefined_controls":           dataSourceInspectionPredefinedControls(),
 			"zpa_inspection_all_predefined_controls":       dataSourceInspectionAllPredefinedControls(),
 			"zpa_inspection_custom_controls":               dataSourceInspectionCustomControls(),
@@ -268,6 +389,7 @@ func ZPAProvider() *schema.Provider {
 			"zpa_location_group_controller":                dataSourceLocationGroupController(),
 			"zpa_location_controller_summary":              dataSourceLocationControllerSummary(),
 			"zpa_c2c_ip_ranges":                            dataSourceC2CIPRanges(),
+			"zpa_expired_policy_rules":                     dataSourceExpiredPolicyRules(),
 			"zpa_extranet_resource_partner":                dataSourceExtranetResourcePartner(),
 			"zpa_managed_browser_profile":                  dataSourceManagedBrowserProfile(),
 			"zpa_browser_protection":                       dataSourceBrowserProtection(),
@@ -0,0 +1,248 @@
+package zpa
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/applicationsegment"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/appservercontroller"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/servergroup"
+)
+
+// Object types served by the read prefetch cache.
+const (
+	prefetchApplicationSegment = "application_segment"
+	prefetchServerGroup        = "server_group"
+	prefetchApplicationServer  = "application_server"
+	prefetchPolicyRules        = "policy_rule"
+)
+
+// readPrefetchCache serves resource reads from a single GetAll snapshot per
+// object type and microtenant. The first read of a type loads the snapshot,
+// later reads in the same run are answered from it, and any write to the
+// type drops it so the next read fetches a fresh one.
+//
+// A write that lands while a snapshot is loading may or may not be part of
+// it, so each invalidation bumps the generation of the object type, and a
+// snapshot whose load overlapped an invalidation is discarded instead of being
+// served.
+//
+// This is independent from the SDK response cache (WithCache/WithCacheTtl),
+// which caches individual GET responses for a fixed TTL.
+type readPrefetchCache struct {
+	mu          sync.Mutex
+	snapshots   map[string]*prefetchSnapshot
+	generations map[string]uint64 // Per object type, bumped by invalidate
+}
+
+type prefetchSnapshot struct {
+	ready      chan struct{}
+	generation uint64
+	items      map[string]interface{}
+	err        error
+}
+
+// errPrefetchInvalidated is the error of a snapshot that was invalidated while
+// it was loading.
+var errPrefetchInvalidated = errors.New("invalidated while loading")
+
+func newReadPrefetchCache() *readPrefetchCache {
+	return &readPrefetchCache{
+		snapshots:   make(map[string]*prefetchSnapshot),
+		generations: make(map[string]uint64),
+	}
+}
+
+func prefetchCacheKey(objectType, microTenantID string) string {
+	if microTenantID != "" {
+		return objectType + ":" + microTenantID
+	}
+	return objectType
+}
+
+// lookup returns the object with the given ID from the snapshot of objectType,
+// loading the snapshot on first use. It returns false when the snapshot could
+// not be loaded, was invalidated while loading or does not contain the ID, in
+// which case callers fall back to a regular Get. The snapshot is shared by
+// every read waiting for it, so callers load it with context.WithoutCancel:
+// cancelling the read that happened to start the load must not fail the
+// others.
+func (c *readPrefetchCache) lookup(objectType, microTenantID, id string, load func() (map[string]interface{}, error)) (interface{}, bool) {
+	key := prefetchCacheKey(objectType, microTenantID)
+
+	c.mu.Lock()
+	snapshot, found := c.snapshots[key]
+	if !found {
+		snapshot = &prefetchSnapshot{ready: make(chan struct{}), generation: c.generations[objectType]}
+		c.snapshots[key] = snapshot
+	}
+	c.mu.Unlock()
+
+	if !found {
+		log.Printf("[INFO] Prefetching all %s objects (microtenant: %q)", objectType, microTenantID)
+		items, err := load()
+		c.mu.Lock()
+		if err == nil && c.generations[objectType] != snapshot.generation {
+			err = errPrefetchInvalidated
+		}
+		if err != nil {
+			if c.snapshots[key] == snapshot {
+				delete(c.snapshots, key)
+			}
+		} else {
+			snapshot.items = items
+		}
+		snapshot.err = err
+		c.mu.Unlock()
+		if err != nil {
+			log.Printf("[WARN] Failed to prefetch %s objects, falling back to individual reads: %v", objectType, err)
+		} else {
+			log.Printf("[INFO] Prefetched %d %s objects (microtenant: %q)", len(items), objectType, microTenantID)
+		}
+		close(snapshot.ready)
+	} else {
+		<-snapshot.ready
+	}
+
+	if snapshot.err != nil {
+		return nil, false
+	}
+	item, ok := snapshot.items[id]
+	return item, ok
+}
+
+// invalidate drops the snapshots of the given object types for every
+// microtenant, including the snapshots that are still loading.
+func (c *readPrefetchCache) invalidate(objectTypes ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, objectType := range objectTypes {
+		c.generations[objectType]++
+	}
+	for key := range c.snapshots {
+		for _, objectType := range objectTypes {
+			if key == objectType || strings.HasPrefix(key, objectType+":") {
+				log.Printf("[DEBUG] Invalidating prefetched %s objects (cache key: %s)", objectType, key)
+				delete(c.snapshots, key)
+			}
+		}
+	}
+}
+
+type prefetchBypassKey struct{}
+
+// withoutPrefetch returns a context whose reads skip the prefetch cache. The
+// Read that follows a Create or Update uses it to fetch the written object
+// with a direct GET, instead of reloading the whole collection after every
+// write.
+func withoutPrefetch(ctx context.Context) context.Context {
+	return context.WithValue(ctx, prefetchBypassKey{}, true)
+}
+
+func prefetchBypassed(ctx context.Context) bool {
+	bypassed, _ := ctx.Value(prefetchBypassKey{}).(bool)
+	return bypassed
+}
+
+// invalidatePrefetch is called after every write to an object type served by
+// the prefetch cache. It is a no-op when read_prefetch is disabled.
+func (c *Client) invalidatePrefetch(objectTypes ...string) {
+	if c.prefetch == nil {
+		return
+	}
+	c.prefetch.invalidate(objectTypes...)
+}
+
+// invalidatePolicyRulesPrefetch is called after every write that adds,
+// removes or moves rules of the policy set of policyType.
+func (c *Client) invalidatePolicyRulesPrefetch(policyType string) {
+	c.invalidatePrefetch(prefetchPolicyRules + ":" + policyType)
+}
+
+func (c *Client) prefetchedApplicationSegment(ctx context.Context, service *zscaler.Service, microTenantID, id string) *applicationsegment.ApplicationSegmentResource {
+	if c.prefetch == nil || prefetchBypassed(ctx) {
+		return nil
+	}
+	item, ok := c.prefetch.lookup(prefetchApplicationSegment, microTenantID, id, func() (map[string]interface{}, error) {
+		all, _, err := applicationsegment.GetAll(context.WithoutCancel(ctx), service)
+		if err != nil {
+			return nil, err
+		}
+		items := make(map[string]interface{}, len(all))
+		for i := range all {
+			items[all[i].ID] = &all[i]
+		}
+		return items, nil
+	})
+	if !ok {
+		return nil
+	}
+	return item.(*applicationsegment.ApplicationSegmentResource)
+}
+
+func (c *Client) prefetchedServerGroup(ctx context.Context, service *zscaler.Service, microTenantID, id string) *servergroup.ServerGroup {
+	if c.prefetch == nil || prefetchBypassed(ctx) {
+		return nil
+	}
+	item, ok := c.prefetch.lookup(prefetchServerGroup, microTenantID, id, func() (map[string]interface{}, error) {
+		all, _, err := servergroup.GetAll(context.WithoutCancel(ctx), service)
+		if err != nil {
+			return nil, err
+		}
+		items := make(map[string]interface{}, len(all))
+		for i := range all {
+			items[all[i].ID] = &all[i]
+		}
+		return items, nil
+	})
+	if !ok {
+		return nil
+	}
+	return item.(*servergroup.ServerGroup)
+}
+
+func (c *Client) prefetchedApplicationServer(ctx context.Context, service *zscaler.Service, microTenantID, id string) *appservercontroller.ApplicationServer {
+	if c.prefetch == nil || prefetchBypassed(ctx) {
+		return nil
+	}
+	item, ok := c.prefetch.lookup(prefetchApplicationServer, microTenantID, id, func() (map[string]interface{}, error) {
+		all, _, err := appservercontroller.GetAll(context.WithoutCancel(ctx), service)
+		if err != nil {
+			return nil, err
+		}
+		items := make(map[string]interface{}, len(all))
+		for i := range all {
+			items[all[i].ID] = &all[i]
+		}
+		return items, nil
+	})
+	if !ok {
+		return nil
+	}
+	return item.(*appservercontroller.ApplicationServer)
+}
+
+// prefetchedPolicyRuleNeighbors returns the neighbors of a rule from a single
+// listing of the rules of the policy set of policyType, shared by the reads of
+// the rules placed relative to another rule.
+func (c *Client) prefetchedPolicyRuleNeighbors(ctx context.Context, service *zscaler.Service, policyType, microTenantID, id string) *policyRuleNeighbors {
+	if c.prefetch == nil || prefetchBypassed(ctx) {
+		return nil
+	}
+	item, ok := c.prefetch.lookup(prefetchPolicyRules+":"+policyType, microTenantID, id, func() (map[string]interface{}, error) {
+		rules, err := fetchPolicySetRules(context.WithoutCancel(ctx), service, policyType)
+		if err != nil {
+			return nil, err
+		}
+		return policyRuleNeighborsByID(rules), nil
+	})
+	if !ok {
+		return nil
+	}
+	return item.(*policyRuleNeighbors)
+}
@@ -0,0 +1,178 @@
+package zpa
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestReadPrefetchCache_LoadsOncePerTypeAndMicrotenant(t *testing.T) {
+	cache := newReadPrefetchCache()
+	var loads int32
+	load := func() (map[string]interface{}, error) {
+		atomic.AddInt32(&loads, 1)
+		return map[string]interface{}{"1": "one", "2": "two"}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if item, ok := cache.lookup(prefetchApplicationSegment, "", "1", load); !ok || item != "one" {
+				t.Errorf("lookup = %v, %v", item, ok)
+			}
+		}()
+	}
+	wg.Wait()
+	if loads != 1 {
+		t.Fatalf("expected 1 load for concurrent lookups, got %d", loads)
+	}
+
+	if _, ok := cache.lookup(prefetchApplicationSegment, "", "3", load); ok {
+		t.Error("expected IDs missing from the snapshot not to be found")
+	}
+	cache.lookup(prefetchApplicationSegment, "216196257331281920", "1", load)
+	cache.lookup(prefetchServerGroup, "", "1", load)
+	if loads != 3 {
+		t.Fatalf("expected 1 load per object type and microtenant, got %d", loads)
+	}
+}
+
+func TestReadPrefetchCache_FailedLoadIsNotCached(t *testing.T) {
+	cache := newReadPrefetchCache()
+	loads := 0
+	load := func() (map[string]interface{}, error) {
+		loads++
+		if loads == 1 {
+			return nil, errors.New("rate limited")
+		}
+		return map[string]interface{}{"1": "one"}, nil
+	}
+
+	if _, ok := cache.lookup(prefetchServerGroup, "", "1", load); ok {
+		t.Error("expected the lookup to fall back to a Get when the load fails")
+	}
+	if item, ok := cache.lookup(prefetchServerGroup, "", "1", load); !ok || item != "one" {
+		t.Errorf("lookup = %v, %v", item, ok)
+	}
+	if loads != 2 {
+		t.Fatalf("expected the failed load to be retried, got %d loads", loads)
+	}
+}
+
+func TestReadPrefetchCache_InvalidatedWhileLoading(t *testing.T) {
+	cache := newReadPrefetchCache()
+	loading, invalidated := make(chan struct{}), make(chan struct{})
+	loads := 0
+	load := func() (map[string]interface{}, error) {
+		loads++
+		if loads == 1 {
+			// A write lands while the first snapshot is loading
+			close(loading)
+			<-invalidated
+			return map[string]interface{}{"1": "stale"}, nil
+		}
+		return map[string]interface{}{"1": "fresh"}, nil
+	}
+
+	done := make(chan struct{})
+	var waited interface{}
+	var waitedOK bool
+	go func() {
+		defer close(done)
+		waited, waitedOK = cache.lookup(prefetchServerGroup, "", "1", load)
+	}()
+	<-loading
+	cache.invalidate(prefetchServerGroup)
+	// The snapshot of another object type is kept
+	if _, ok := cache.lookup(prefetchApplicationServer, "", "1", func() (map[string]interface{}, error) {
+		return map[string]interface{}{"1": "server"}, nil
+	}); !ok {
+		t.Error("expected the lookup of another object type to be served")
+	}
+	close(invalidated)
+	<-done
+
+	if waitedOK {
+		t.Errorf("expected the snapshot that overlapped the invalidation to be discarded, got %v", waited)
+	}
+	if item, ok := cache.lookup(prefetchServerGroup, "", "1", load); !ok || item != "fresh" {
+		t.Errorf("lookup = %v, %v, want a fresh snapshot", item, ok)
+	}
+	if loads != 2 {
+		t.Errorf("expected 2 loads, got %d", loads)
+	}
+}
+
+func TestReadPrefetchCache_Invalidate(t *testing.T) {
+	cache := newReadPrefetchCache()
+	loads := map[string]int{}
+	load := func(key string) func() (map[string]interface{}, error) {
+		return func() (map[string]interface{}, error) {
+			loads[key]++
+			return map[string]interface{}{}, nil
+		}
+	}
+	lookupAll := func() {
+		cache.lookup(prefetchApplicationSegment, "", "1", load("segment"))
+		cache.lookup(prefetchApplicationSegment, "216196257331281920", "1", load("segment:microtenant"))
+		cache.lookup(prefetchApplicationServer, "", "1", load("server"))
+	}
+
+	lookupAll()
+	cache.invalidate(prefetchApplicationSegment)
+	lookupAll()
+	if loads["segment"] != 2 || loads["segment:microtenant"] != 2 {
+		t.Errorf("expected the segment snapshots of every microtenant to be reloaded, got %v", loads)
+	}
+	if loads["server"] != 1 {
+		t.Errorf("expected other object types to be kept, got %v", loads)
+	}
+}
+
+func TestClient_InvalidatePolicyRulesPrefetch(t *testing.T) {
+	c := &Client{prefetch: newReadPrefetchCache()}
+	loads := map[string]int{}
+	load := func(key string) func() (map[string]interface{}, error) {
+		return func() (map[string]interface{}, error) {
+			loads[key]++
+			return map[string]interface{}{}, nil
+		}
+	}
+	lookupAll := func() {
+		c.prefetch.lookup(prefetchPolicyRules+":ACCESS_POLICY", "", "1", load("access"))
+		c.prefetch.lookup(prefetchPolicyRules+":ACCESS_POLICY", "216196257331281920", "1", load("access:microtenant"))
+		c.prefetch.lookup(prefetchPolicyRules+":TIMEOUT_POLICY", "", "1", load("timeout"))
+	}
+
+	lookupAll()
+	c.invalidatePolicyRulesPrefetch("ACCESS_POLICY")
+	lookupAll()
+	if loads["access"] != 2 || loads["access:microtenant"] != 2 {
+		t.Errorf("expected the rules of the policy type to be reloaded in every microtenant, got %v", loads)
+	}
+	if loads["timeout"] != 1 {
+		t.Errorf("expected the rules of other policy types to be kept, got %v", loads)
+	}
+}
+
+func TestReadPrefetch_BypassedAfterWrites(t *testing.T) {
+	ctx := context.Background()
+	if prefetchBypassed(ctx) {
+		t.Error("expected reads to use the prefetch cache by default")
+	}
+	if !prefetchBypassed(withoutPrefetch(ctx)) {
+		t.Error("expected the read after a write to skip the prefetch cache")
+	}
+
+	c := &Client{prefetch: newReadPrefetchCache()}
+	if resp := c.prefetchedApplicationSegment(withoutPrefetch(ctx), nil, "", "1"); resp != nil {
+		t.Errorf("expected no prefetched segment, got %v", resp)
+	}
+	if len(c.prefetch.snapshots) != 0 {
+		t.Error("expected the read after a write not to load a snapshot")
+	}
+}
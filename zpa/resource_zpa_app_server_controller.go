@@ -117,10 +117,11 @@ func resourceApplicationServerCreate(ctx context.Context, d *schema.ResourceData
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	zClient.invalidatePrefetch(prefetchApplicationServer, prefetchServerGroup)
 	log.Printf("[INFO] Created application server request. ID: %v\n", resp)
 	d.SetId(resp.ID)
 
-	return resourceApplicationServerRead(ctx, d, meta)
+	return resourceApplicationServerRead(withoutPrefetch(ctx), d, meta)
 }
 
 func resourceApplicationServerRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -132,15 +133,19 @@ func resourceApplicationServerRead(ctx context.Context, d *schema.ResourceData,
 		service = service.WithMicroTenant(microTenantID)
 	}
 
-	resp, _, err := appservercontroller.Get(ctx, service, d.Id())
-	if err != nil {
-		if respErr, ok := err.(*errorx.ErrorResponse); ok && respErr.IsObjectNotFound() {
-			log.Printf("[WARN] Removing application server %s from state because it no longer exists in ZPA", d.Id())
-			d.SetId("")
-			return nil
+	resp := zClient.prefetchedApplicationServer(ctx, service, microTenantID, d.Id())
+	if resp == nil {
+		var err error
+		resp, _, err = appservercontroller.Get(ctx, service, d.Id())
+		if err != nil {
+			if respErr, ok := err.(*errorx.ErrorResponse); ok && respErr.IsObjectNotFound() {
+				log.Printf("[WARN] Removing application server %s from state because it no longer exists in ZPA", d.Id())
+				d.SetId("")
+				return nil
+			}
+
+			return diag.FromErr(err)
 		}
-
-		return diag.FromErr(err)
 	}
 
 	log.Printf("[INFO] Getting application server:\n%+v\n", resp)
@@ -177,8 +182,9 @@ func resourceApplicationServerUpdate(ctx context.Context, d *schema.ResourceData
 	if _, err := appservercontroller.Update(ctx, service, id, req); err != nil {
 		return diag.FromErr(err)
 	}
+	zClient.invalidatePrefetch(prefetchApplicationServer, prefetchServerGroup)
 
-	return resourceApplicationServerRead(ctx, d, meta)
+	return resourceApplicationServerRead(withoutPrefetch(ctx), d, meta)
 }
 
 func resourceApplicationServerDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -194,6 +200,7 @@ func resourceApplicationServerDelete(ctx context.Context, d *schema.ResourceData
 	if _, err := appservercontroller.Delete(ctx, service, d.Id()); err != nil {
 		return diag.FromErr(err)
 	}
+	zClient.invalidatePrefetch(prefetchApplicationServer, prefetchServerGroup)
 
 	d.SetId("")
 	log.Printf("[INFO] application server deleted successfully")
@@ -302,6 +302,7 @@ func resourceApplicationSegmentCreate(ctx context.Context, d *schema.ResourceDat
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	zClient.invalidatePrefetch(prefetchApplicationSegment, prefetchServerGroup)
 
 	log.Printf("[INFO] Created application segment request. ID: %v\n", resp.ID)
 	d.SetId(resp.ID)
@@ -320,7 +321,7 @@ func resourceApplicationSegmentCreate(ctx context.Context, d *schema.ResourceDat
 		}
 	}
 
-	return resourceApplicationSegmentRead(ctx, d, meta)
+	return resourceApplicationSegmentRead(withoutPrefetch(ctx), d, meta)
 }
 
 func resourceApplicationSegmentRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -332,15 +333,19 @@ func resourceApplicationSegmentRead(ctx context.Context, d *schema.ResourceData,
 		service = service.WithMicroTenant(microTenantID)
 	}
 
-	resp, _, err := applicationsegment.Get(ctx, service, d.Id())
-	if err != nil {
-		if respErr, ok := err.(*errorx.ErrorResponse); ok && respErr.IsObjectNotFound() {
-			log.Printf("[WARN] Removing application segment %s from state because it no longer exists in ZPA", d.Id())
-			d.SetId("")
-			return nil
-		}
+	resp := zClient.prefetchedApplicationSegment(ctx, service, microTenantID, d.Id())
+	if resp == nil {
+		var err error
+		resp, _, err = applicationsegment.Get(ctx, service, d.Id())
+		if err != nil {
+			if respErr, ok := err.(*errorx.ErrorResponse); ok && respErr.IsObjectNotFound() {
+				log.Printf("[WARN] Removing application segment %s from state because it no longer exists in ZPA", d.Id())
+				d.SetId("")
+				return nil
+			}
 
-		return diag.FromErr(err)
+			return diag.FromErr(err)
+		}
 	}
 
 	log.Printf("[INFO] Reading application segment and settings states: %+v\n", resp)
@@ -428,6 +433,7 @@ func resourceApplicationSegmentUpdate(ctx context.Context, d *schema.ResourceDat
 	if _, err := applicationsegment.Update(ctx, service, id, req); err != nil {
 		return diag.FromErr(err)
 	}
+	zClient.invalidatePrefetch(prefetchApplicationSegment, prefetchServerGroup)
 
 	// Share if needed
 	shareTo := SetToStringList(d, "share_to_microtenants")
@@ -443,7 +449,7 @@ func resourceApplicationSegmentUpdate(ctx context.Context, d *schema.ResourceDat
 		}
 	}
 
-	return resourceApplicationSegmentRead(ctx, d, meta)
+	return resourceApplicationSegmentRead(withoutPrefetch(ctx), d, meta)
 }
 
 func resourceApplicationSegmentDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -464,6 +470,7 @@ func resourceApplicationSegmentDelete(ctx context.Context, d *schema.ResourceDat
 	if _, err := applicationsegment.Delete(ctx, service, d.Id()); err != nil {
 		return diag.FromErr(err)
 	}
+	zClient.invalidatePrefetch(prefetchApplicationSegment, prefetchServerGroup)
 
 	d.SetId("")
 	log.Printf("[INFO] Application segment deleted successfully")
@@ -95,12 +95,26 @@ func getPolicyRuleResourceSchema() map[string]*schema.Schema {
 	)
 }
 
+// lssSourceLogTypes are the log types an LSS configuration can stream.
+var lssSourceLogTypes = []string{
+	"zpn_trans_log",
+	"zpn_auth_log",
+	"zpn_ast_auth_log",
+	"zpn_http_trans_log",
+	"zpn_audit_log",
+	"zpn_ast_comprehensive_stats",
+	"zpn_sys_auth_log",
+	"zpn_waf_http_exchanges_log",
+	"zpn_pbroker_comprehensive_stats",
+}
+
 func resourceLSSConfigController() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceLSSConfigControllerCreate,
 		ReadContext:   resourceLSSConfigControllerRead,
 		UpdateContext: resourceLSSConfigControllerUpdate,
 		DeleteContext: resourceLSSConfigControllerDelete,
+		CustomizeDiff: validateLSSFormatBuilder,
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				zClient := meta.(*Client)
@@ -185,10 +199,13 @@ func resourceLSSConfigController() *schema.Resource {
 						},
 
 						"format": {
-							Type:        schema.TypeString,
-							Required:    true,
-							Description: "Format of the log type. Format given by the following API to get formats: /mgmtconfig/v2/admin/lssConfig/logType/formats",
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							ExactlyOneOf: []string{"config.0.format", "config.0.format_builder"},
+							Description:  "Format of the log type. Format given by the following API to get formats: /mgmtconfig/v2/admin/lssConfig/logType/formats",
 						},
+						"format_builder": lssFormatBuilderSchema(),
 						"id": {
 							Type:     schema.TypeString,
 							Computed: true,
@@ -210,20 +227,10 @@ func resourceLSSConfigController() *schema.Resource {
 							Description: "Port of the LSS configuration",
 						},
 						"source_log_type": {
-							Type:        schema.TypeString,
-							Required:    true,
-							Description: "Log type of the LSS configuration",
-							ValidateFunc: validation.StringInSlice([]string{
-								"zpn_trans_log",
-								"zpn_auth_log",
-								"zpn_ast_auth_log",
-								"zpn_http_trans_log",
-								"zpn_audit_log",
-								"zpn_ast_comprehensive_stats",
-								"zpn_sys_auth_log",
-								"zpn_waf_http_exchanges_log",
-								"zpn_pbroker_comprehensive_stats",
-							}, false),
+							Type:         schema.TypeString,
+							Required:     true,
+							Description:  "Log type of the LSS configuration",
+							ValidateFunc: validation.StringInSlice(lssSourceLogTypes, false),
 						},
 						"use_tls": {
 							Type:     schema.TypeBool,
@@ -242,9 +249,15 @@ func resourceLSSConfigControllerCreate(ctx context.Context, d *schema.ResourceDa
 	service := zClient.Service
 
 	req := expandLSSResource(d)
+	if format, ok, err := buildLSSConfigFormat(ctx, service, d); err != nil {
+		return diag.FromErr(err)
+	} else if ok {
+		req.LSSConfig.Format = format
+	}
 	log.Printf("[INFO] Creating zpa lss config controller with request\n%+v\n", req)
 
 	sourceLogType := d.Get("config.0.source_log_type").(string)
+	catalog := lssCatalogOf(ctx, zClient)
 
 	conditions := d.Get("policy_rule_resource.0.conditions").([]interface{})
 	for _, condition := range conditions {
@@ -285,7 +298,7 @@ func resourceLSSConfigControllerCreate(ctx context.Context, d *schema.ResourceDa
 
 		// Validate operand object types and values here
 		for _, operand := range operands {
-			err := validateLSSConfigControllerFilters(sourceLogType, operand.ObjectType, "", operand.Values, operands)
+			err := validateLSSConfigControllerFilters(catalog, sourceLogType, operand.ObjectType, "", operand.Values, operands)
 			if err != nil {
 				return diag.FromErr(err) // handle the error as appropriate
 			}
@@ -297,7 +310,7 @@ func resourceLSSConfigControllerCreate(ctx context.Context, d *schema.ResourceDa
 		for _, filter := range filterSet.(*schema.Set).List() {
 			filterStr := filter.(string)
 			// For filter validation, passing empty string as the objectType and nil for values and operands
-			err := validateLSSConfigControllerFilters(sourceLogType, "", filterStr, nil, nil)
+			err := validateLSSConfigControllerFilters(catalog, sourceLogType, "", filterStr, nil, nil)
 			if err != nil {
 				return diag.FromErr(err)
 			}
@@ -335,7 +348,9 @@ func resourceLSSConfigControllerRead(ctx context.Context, d *schema.ResourceData
 	if resp.PolicyRule != nil {
 		_ = d.Set("policy_rule_id", resp.PolicyRule.ID)
 	}
-	_ = d.Set("config", flattenLSSConfig(resp.LSSConfig))
+	config := flattenLSSConfig(resp.LSSConfig).([]map[string]interface{})
+	config[0]["format_builder"] = flattenLSSFormatBuilder(ctx, service, d, resp.LSSConfig)
+	_ = d.Set("config", config)
 	_ = d.Set("connector_groups", flattenConnectorGroupsSimple(resp.ConnectorGroups))
 	return nil
 }
@@ -346,9 +361,15 @@ func resourceLSSConfigControllerUpdate(ctx context.Context, d *schema.ResourceDa
 
 	id := d.Id()
 	req := expandLSSResource(d)
+	if format, ok, err := buildLSSConfigFormat(ctx, service, d); err != nil {
+		return diag.FromErr(err)
+	} else if ok {
+		req.LSSConfig.Format = format
+	}
 	log.Printf("[INFO] Updating zpa lss config controller with request\n%+v\n", req)
 
 	sourceLogType := d.Get("config.0.source_log_type").(string)
+	catalog := lssCatalogOf(ctx, zClient)
 
 	conditions := d.Get("policy_rule_resource.0.conditions").([]interface{})
 	for _, condition := range conditions {
@@ -389,7 +410,7 @@ func resourceLSSConfigControllerUpdate(ctx context.Context, d *schema.ResourceDa
 
 		// Validate operand object types and values here
 		for _, operand := range operands {
-			err := validateLSSConfigControllerFilters(sourceLogType, operand.ObjectType, "", operand.Values, operands)
+			err := validateLSSConfigControllerFilters(catalog, sourceLogType, operand.ObjectType, "", operand.Values, operands)
 			if err != nil {
 				return diag.FromErr(err) // handle the error as appropriate
 			}
@@ -401,7 +422,7 @@ func resourceLSSConfigControllerUpdate(ctx context.Context, d *schema.ResourceDa
 		for _, filter := range filterSet.(*schema.Set).List() {
 			filterStr := filter.(string)
 			// Pass empty string as objectType and nil for values and operands when validating filters
-			err := validateLSSConfigControllerFilters(sourceLogType, "", filterStr, nil, nil)
+			err := validateLSSConfigControllerFilters(catalog, sourceLogType, "", filterStr, nil, nil)
 			if err != nil {
 				return diag.FromErr(err)
 			}
@@ -555,24 +576,28 @@ func expandLSSConfigController(d *schema.ResourceData) *lssconfigcontroller.LSSC
 			return nil
 		}
 		config, _ := configList[0].(map[string]interface{})
-		filterSet, _ := config["filter"].(*schema.Set)
-		return &lssconfigcontroller.LSSConfig{
-			ID:            d.Get("id").(string),
-			AuditMessage:  config["audit_message"].(string),
-			Description:   config["description"].(string),
-			Enabled:       config["enabled"].(bool),
-			Filter:        SetToStringSlice(filterSet),
-			Format:        config["format"].(string),
-			Name:          config["name"].(string),
-			LSSHost:       config["lss_host"].(string),
-			LSSPort:       config["lss_port"].(string),
-			SourceLogType: config["source_log_type"].(string),
-			UseTLS:        config["use_tls"].(bool),
-		}
+		return expandLSSConfig(d.Get("id").(string), config)
 	}
 	return nil
 }
 
+func expandLSSConfig(id string, config map[string]interface{}) *lssconfigcontroller.LSSConfig {
+	filterSet, _ := config["filter"].(*schema.Set)
+	return &lssconfigcontroller.LSSConfig{
+		ID:            id,
+		AuditMessage:  config["audit_message"].(string),
+		Description:   config["description"].(string),
+		Enabled:       config["enabled"].(bool),
+		Filter:        SetToStringSlice(filterSet),
+		Format:        config["format"].(string),
+		Name:          config["name"].(string),
+		LSSHost:       config["lss_host"].(string),
+		LSSPort:       config["lss_port"].(string),
+		SourceLogType: config["source_log_type"].(string),
+		UseTLS:        config["use_tls"].(bool),
+	}
+}
+
 func expandConnectorGroups(d *schema.ResourceData) []lssconfigcontroller.ConnectorGroups {
 	appConnectorGroupsInterface, ok := d.GetOk("connector_groups")
 	if ok {
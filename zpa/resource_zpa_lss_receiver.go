@@ -0,0 +1,406 @@
+package zpa
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/errorx"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/lssconfigcontroller"
+)
+
+// resourceLSSReceiver manages one LSS configuration per log type streamed to
+// the same receiver.
+func resourceLSSReceiver() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceLSSReceiverCreate,
+		ReadContext:   resourceLSSReceiverRead,
+		UpdateContext: resourceLSSReceiverUpdate,
+		DeleteContext: resourceLSSReceiverDelete,
+		CustomizeDiff: customizeDiffLSSReceiver,
+		Importer: &schema.ResourceImporter{
+			// The import ID is the comma-separated IDs of the LSS
+			// configurations of the receiver.
+			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				zClient := meta.(*Client)
+				service := zClient.Service
+
+				configIDs := map[string]string{}
+				for _, configID := range strings.Split(d.Id(), ",") {
+					configID = strings.TrimSpace(configID)
+					if configID == "" {
+						return nil, fmt.Errorf("invalid import ID %q, expected the comma-separated IDs of the LSS configurations", d.Id())
+					}
+					resp, _, err := lssconfigcontroller.Get(ctx, service, configID)
+					if err != nil {
+						return nil, fmt.Errorf("failed to get lss config controller %s: %v", configID, err)
+					}
+					if resp.LSSConfig == nil {
+						return nil, fmt.Errorf("lss config controller %s has no LSS configuration", configID)
+					}
+					sourceLogType := resp.LSSConfig.SourceLogType
+					if other, ok := configIDs[sourceLogType]; ok {
+						return nil, fmt.Errorf("lss config controllers %s and %s both stream %s", other, configID, sourceLogType)
+					}
+					configIDs[sourceLogType] = configID
+				}
+				d.SetId(id.UniqueId())
+				_ = d.Set("lss_config_ids", configIDs)
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Name of the receiver. The LSS configuration of each log type is named after it.",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Description of the LSS configurations",
+			},
+			"enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether the LSS configurations are enabled or not. Supported values: true, false",
+			},
+			"lss_host": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Host of the receiver",
+			},
+			"lss_port": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Port of the receiver",
+			},
+			"use_tls": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"connector_groups": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "App Connector Group(s) to be added to the LSS configurations",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeList,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+							Optional: true,
+						},
+					},
+				},
+			},
+			"log_types": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    1,
+				Description: "The log types streamed to the receiver, one LSS configuration each.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"source_log_type": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice(lssSourceLogTypes, false),
+						},
+						"filter": {
+							Type:        schema.TypeSet,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Optional:    true,
+							Description: "Status codes of the logs to stream.",
+						},
+						"format": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "Format of the logs. Defaults to the JSON template of the log type.",
+						},
+					},
+				},
+			},
+			"lss_config_ids": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "The IDs of the LSS configurations, by source log type.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// customizeDiffLSSReceiver checks the log types during plan, and marks the
+// IDs of the LSS configurations unknown when log types are added or removed.
+func customizeDiffLSSReceiver(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("log_types") {
+		return d.SetNewComputed("lss_config_ids")
+	}
+	zClient, _ := meta.(*Client)
+	var catalog *lssCatalog
+	seen := map[string]bool{}
+	for _, v := range d.Get("log_types").(*schema.Set).List() {
+		logType := v.(map[string]interface{})
+		sourceLogType := logType["source_log_type"].(string)
+		if seen[sourceLogType] {
+			return fmt.Errorf("log_types: %s is listed more than once", sourceLogType)
+		}
+		seen[sourceLogType] = true
+
+		filters := SetToStringSlice(logType["filter"].(*schema.Set))
+		if len(filters) > 0 && catalog == nil {
+			catalog = lssCatalogOf(ctx, zClient)
+		}
+		for _, filter := range filters {
+			if err := validateLSSConfigControllerFilters(catalog, sourceLogType, "", filter, nil, nil); err != nil {
+				return fmt.Errorf("log_types (source_log_type = %q): %v", sourceLogType, err)
+			}
+		}
+	}
+
+	if d.Id() == "" {
+		return nil
+	}
+	configIDs := d.Get("lss_config_ids").(map[string]interface{})
+	if len(configIDs) != len(seen) {
+		return d.SetNewComputed("lss_config_ids")
+	}
+	for sourceLogType := range seen {
+		if _, ok := configIDs[sourceLogType]; !ok {
+			return d.SetNewComputed("lss_config_ids")
+		}
+	}
+	return nil
+}
+
+func resourceLSSReceiverCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	d.SetId(id.UniqueId())
+	log.Printf("[INFO] Creating lss receiver %s\n", d.Get("name"))
+	if err := reconcileLSSReceiver(ctx, d, meta); err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceLSSReceiverRead(ctx, d, meta)
+}
+
+func resourceLSSReceiverRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zClient := meta.(*Client)
+	service := zClient.Service
+
+	// Formats are only read back when they are configured, the default
+	// template of a log type is not part of the state.
+	configuredFormats := map[string]bool{}
+	for _, v := range d.Get("log_types").(*schema.Set).List() {
+		logType := v.(map[string]interface{})
+		if logType["format"].(string) != "" {
+			configuredFormats[logType["source_log_type"].(string)] = true
+		}
+	}
+
+	configIDs := lssReceiverConfigIDs(d)
+	sourceLogTypes := make([]string, 0, len(configIDs))
+	for sourceLogType := range configIDs {
+		sourceLogTypes = append(sourceLogTypes, sourceLogType)
+	}
+	sort.Strings(sourceLogTypes)
+
+	var logTypes []interface{}
+	var receiver *lssconfigcontroller.LSSResource
+	var receiverLogType string
+	for _, sourceLogType := range sourceLogTypes {
+		configID := configIDs[sourceLogType]
+		resp, _, err := lssconfigcontroller.Get(ctx, service, configID)
+		if err != nil {
+			if respErr, ok := err.(*errorx.ErrorResponse); ok && respErr.IsObjectNotFound() {
+				log.Printf("[WARN] LSS config controller %s of lss receiver %s no longer exists in ZPA", configID, d.Id())
+				delete(configIDs, sourceLogType)
+				continue
+			}
+			return diag.FromErr(err)
+		}
+		if resp.LSSConfig == nil {
+			continue
+		}
+		if receiver == nil {
+			receiver, receiverLogType = resp, sourceLogType
+		}
+		format := ""
+		if configuredFormats[sourceLogType] {
+			format = html.UnescapeString(resp.LSSConfig.Format)
+		}
+		logTypes = append(logTypes, map[string]interface{}{
+			"source_log_type": resp.LSSConfig.SourceLogType,
+			"filter":          resp.LSSConfig.Filter,
+			"format":          format,
+		})
+	}
+
+	if receiver == nil {
+		log.Printf("[WARN] Removing lss receiver %s from state because none of its LSS configurations exist in ZPA", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	log.Printf("[INFO] Getting lss receiver:\n%+v\n", receiver)
+	_ = d.Set("name", lssReceiverName(receiver.LSSConfig.Name, receiverLogType))
+	_ = d.Set("description", receiver.LSSConfig.Description)
+	_ = d.Set("enabled", receiver.LSSConfig.Enabled)
+	_ = d.Set("lss_host", receiver.LSSConfig.LSSHost)
+	_ = d.Set("lss_port", receiver.LSSConfig.LSSPort)
+	_ = d.Set("use_tls", receiver.LSSConfig.UseTLS)
+	_ = d.Set("connector_groups", flattenConnectorGroupsSimple(receiver.ConnectorGroups))
+	_ = d.Set("log_types", logTypes)
+	_ = d.Set("lss_config_ids", configIDs)
+	return nil
+}
+
+func resourceLSSReceiverUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[INFO] Updating lss receiver %s\n", d.Id())
+	if err := reconcileLSSReceiver(ctx, d, meta); err != nil {
+		return diag.FromErr(err)
+	}
+	return resourceLSSReceiverRead(ctx, d, meta)
+}
+
+func resourceLSSReceiverDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zClient := meta.(*Client)
+	service := zClient.Service
+
+	configIDs := lssReceiverConfigIDs(d)
+	for sourceLogType, configID := range configIDs {
+		log.Printf("[INFO] Deleting lss config controller ID: %v\n", configID)
+		if _, err := lssconfigcontroller.Delete(ctx, service, configID); err != nil {
+			if respErr, ok := err.(*errorx.ErrorResponse); !ok || !respErr.IsObjectNotFound() {
+				_ = d.Set("lss_config_ids", configIDs)
+				return diag.FromErr(err)
+			}
+		}
+		delete(configIDs, sourceLogType)
+	}
+	d.SetId("")
+	log.Printf("[INFO] lss receiver deleted")
+	return nil
+}
+
+// lssReceiverName returns the name of the receiver of the LSS configuration
+// named configName, which is named after the receiver and its log type.
+func lssReceiverName(configName, sourceLogType string) string {
+	return strings.TrimSuffix(configName, " "+sourceLogType)
+}
+
+func lssReceiverConfigIDs(d *schema.ResourceData) map[string]string {
+	configIDs := map[string]string{}
+	for sourceLogType, configID := range d.Get("lss_config_ids").(map[string]interface{}) {
+		configIDs[sourceLogType] = configID.(string)
+	}
+	return configIDs
+}
+
+// reconcileLSSReceiver deletes the LSS configurations of the log types
+// removed from the receiver, and creates or updates the others. The IDs are
+// saved after every change, so that a failure does not leave configurations
+// out of the state.
+func reconcileLSSReceiver(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
+	zClient := meta.(*Client)
+	service := zClient.Service
+
+	desired := expandLSSReceiverConfigs(d)
+	configIDs := lssReceiverConfigIDs(d)
+	defer func() {
+		_ = d.Set("lss_config_ids", configIDs)
+	}()
+
+	for sourceLogType, configID := range configIDs {
+		if _, ok := desired[sourceLogType]; ok {
+			continue
+		}
+		log.Printf("[INFO] Deleting lss config controller %s of log type %s\n", configID, sourceLogType)
+		if _, err := lssconfigcontroller.Delete(ctx, service, configID); err != nil {
+			if respErr, ok := err.(*errorx.ErrorResponse); !ok || !respErr.IsObjectNotFound() {
+				return err
+			}
+		}
+		delete(configIDs, sourceLogType)
+	}
+
+	sourceLogTypes := make([]string, 0, len(desired))
+	for sourceLogType := range desired {
+		sourceLogTypes = append(sourceLogTypes, sourceLogType)
+	}
+	sort.Strings(sourceLogTypes)
+
+	catalog := lssCatalogOf(ctx, zClient)
+	for _, sourceLogType := range sourceLogTypes {
+		config := expandLSSConfig(configIDs[sourceLogType], desired[sourceLogType])
+		for _, filter := range config.Filter {
+			if err := validateLSSConfigControllerFilters(catalog, sourceLogType, "", filter, nil, nil); err != nil {
+				return err
+			}
+		}
+		if config.Format == "" {
+			formats, _, err := lssconfigcontroller.GetFormats(ctx, service, sourceLogType)
+			if err != nil {
+				return fmt.Errorf("failed to get the format templates of %s: %v", sourceLogType, err)
+			}
+			config.Format = html.UnescapeString(formats.Json)
+		}
+		req := lssconfigcontroller.LSSResource{
+			ID:              config.ID,
+			LSSConfig:       config,
+			ConnectorGroups: expandConnectorGroups(d),
+		}
+
+		if config.ID != "" {
+			log.Printf("[INFO] Updating lss config controller %s of log type %s\n", config.ID, sourceLogType)
+			_, err := lssconfigcontroller.Update(ctx, service, config.ID, &req)
+			if err == nil {
+				continue
+			}
+			if respErr, ok := err.(*errorx.ErrorResponse); !ok || !respErr.IsObjectNotFound() {
+				return err
+			}
+			// Deleted outside Terraform, create it again.
+			config.ID, req.ID = "", ""
+		}
+
+		log.Printf("[INFO] Creating lss config controller of log type %s\n", sourceLogType)
+		resp, _, err := lssconfigcontroller.Create(ctx, service, &req)
+		if err != nil {
+			return err
+		}
+		configIDs[sourceLogType] = resp.ID
+	}
+	return nil
+}
+
+// expandLSSReceiverConfigs returns the config block, as expanded by
+// expandLSSConfig, of the LSS configuration of each log type.
+func expandLSSReceiverConfigs(d *schema.ResourceData) map[string]map[string]interface{} {
+	configs := map[string]map[string]interface{}{}
+	for _, v := range d.Get("log_types").(*schema.Set).List() {
+		logType := v.(map[string]interface{})
+		sourceLogType := logType["source_log_type"].(string)
+		configs[sourceLogType] = map[string]interface{}{
+			"audit_message":   "",
+			"description":     d.Get("description").(string),
+			"enabled":         d.Get("enabled").(bool),
+			"filter":          logType["filter"],
+			"format":          logType["format"].(string),
+			"name":            fmt.Sprintf("%s %s", d.Get("name").(string), sourceLogType),
+			"lss_host":        d.Get("lss_host").(string),
+			"lss_port":        d.Get("lss_port").(string),
+			"source_log_type": sourceLogType,
+			"use_tls":         d.Get("use_tls").(bool),
+		}
+	}
+	return configs
+}
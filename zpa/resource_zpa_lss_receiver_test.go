@@ -0,0 +1,187 @@
+package zpa
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/zscaler/terraform-provider-zpa/v4/zpa/common/resourcetype"
+	"github.com/zscaler/terraform-provider-zpa/v4/zpa/common/testing/method"
+	"github.com/zscaler/terraform-provider-zpa/v4/zpa/common/testing/variable"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/lssconfigcontroller"
+)
+
+func TestAccResourceLSSReceiver_Basic(t *testing.T) {
+	resourceTypeAndName, _, generatedName := method.GenerateRandomSourcesTypeAndName(resourcetype.ZPALSSReceiver)
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+	rPort := acctest.RandIntRange(1000, 9999)
+	rIP, _ := acctest.RandIpAddress("192.168.100.0/25")
+
+	appConnectorGroupTypeAndName, _, appConnectorGroupGeneratedName := method.GenerateRandomSourcesTypeAndName(resourcetype.ZPAAppConnectorGroup)
+	appConnectorGroupHCL := testAccCheckAppConnectorGroupConfigure(appConnectorGroupTypeAndName, appConnectorGroupGeneratedName, variable.AppConnectorDescription, variable.AppConnectorEnabled)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckLSSReceiverDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckLSSReceiverConfigure(generatedName, rName, rIP, rPort, appConnectorGroupHCL, appConnectorGroupTypeAndName, `
+  log_types {
+    source_log_type = "zpn_trans_log"
+  }
+  log_types {
+    source_log_type = "zpn_auth_log"
+    filter          = ["ZPN_STATUS_AUTHENTICATED"]
+  }
+  log_types {
+    source_log_type = "zpn_audit_log"
+  }`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckLSSReceiverExists(resourceTypeAndName),
+					resource.TestCheckResourceAttr(resourceTypeAndName, "log_types.#", "3"),
+					resource.TestCheckResourceAttr(resourceTypeAndName, "lss_config_ids.%", "3"),
+					resource.TestCheckResourceAttr(resourceTypeAndName, "connector_groups.#", "1"),
+				),
+			},
+			// Removing a log type deletes its LSS configuration
+			{
+				Config: testAccCheckLSSReceiverConfigure(generatedName, rName, rIP, rPort, appConnectorGroupHCL, appConnectorGroupTypeAndName, `
+  log_types {
+    source_log_type = "zpn_trans_log"
+  }
+  log_types {
+    source_log_type = "zpn_auth_log"
+    filter          = ["ZPN_STATUS_AUTHENTICATED", "ZPN_STATUS_DISCONNECTED"]
+  }`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckLSSReceiverExists(resourceTypeAndName),
+					resource.TestCheckResourceAttr(resourceTypeAndName, "log_types.#", "2"),
+					resource.TestCheckResourceAttr(resourceTypeAndName, "lss_config_ids.%", "2"),
+					resource.TestCheckNoResourceAttr(resourceTypeAndName, "lss_config_ids.zpn_audit_log"),
+				),
+			},
+			// The import ID is generated, so the imported state is checked
+			// rather than verified against the created one.
+			{
+				ResourceName:      resourceTypeAndName,
+				ImportState:       true,
+				ImportStateIdFunc: testAccLSSReceiverImportStateID(resourceTypeAndName),
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if len(states) != 1 {
+						return fmt.Errorf("expected 1 imported lss receiver, got %d", len(states))
+					}
+					attributes := states[0].Attributes
+					for key, want := range map[string]string{
+						"name":               rName,
+						"lss_host":           rIP,
+						"lss_port":           fmt.Sprintf("%d", rPort),
+						"use_tls":            "true",
+						"log_types.#":        "2",
+						"lss_config_ids.%":   "2",
+						"connector_groups.#": "1",
+					} {
+						if attributes[key] != want {
+							return fmt.Errorf("imported %s is %q, want %q", key, attributes[key], want)
+						}
+					}
+					return nil
+				},
+			},
+			{
+				Config: testAccCheckLSSReceiverConfigure(generatedName, rName, rIP, rPort, appConnectorGroupHCL, appConnectorGroupTypeAndName, `
+  log_types {
+    source_log_type = "zpn_auth_log"
+    filter          = ["ZPN_STATUS_AUTHENTICATD"]
+  }`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("did you mean ZPN_STATUS_AUTHENTICATED"),
+			},
+		},
+	})
+}
+
+func testAccLSSReceiverImportStateID(resourceTypeAndName string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[resourceTypeAndName]
+		if !ok {
+			return "", fmt.Errorf("not found: %s", resourceTypeAndName)
+		}
+		return rs.Primary.Attributes["lss_config_ids.zpn_auth_log"] + "," + rs.Primary.Attributes["lss_config_ids.zpn_trans_log"], nil
+	}
+}
+
+func testAccCheckLSSReceiverDestroy(s *terraform.State) error {
+	apiClient := testAccProvider.Meta().(*Client)
+	service := apiClient.Service
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != resourcetype.ZPALSSReceiver {
+			continue
+		}
+		for key, configID := range rs.Primary.Attributes {
+			if key == "lss_config_ids.%" || !regexp.MustCompile(`^lss_config_ids\.`).MatchString(key) {
+				continue
+			}
+			if _, _, err := lssconfigcontroller.Get(context.Background(), service, configID); err == nil {
+				return fmt.Errorf("lss config controller %s of lss receiver %s still exists", configID, rs.Primary.ID)
+			}
+		}
+	}
+	return nil
+}
+
+func testAccCheckLSSReceiverExists(resource string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resource]
+		if !ok {
+			return fmt.Errorf("lss receiver not found: %s", resource)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no lss receiver ID is set")
+		}
+
+		apiClient := testAccProvider.Meta().(*Client)
+		service := apiClient.Service
+		for key, configID := range rs.Primary.Attributes {
+			if key == "lss_config_ids.%" || !regexp.MustCompile(`^lss_config_ids\.`).MatchString(key) {
+				continue
+			}
+			if _, _, err := lssconfigcontroller.Get(context.Background(), service, configID); err != nil {
+				return fmt.Errorf("failed fetching lss config controller %s of %s. Received error: %s", configID, resource, err)
+			}
+		}
+		return nil
+	}
+}
+
+func testAccCheckLSSReceiverConfigure(generatedName, name, lssHost string, rPort int, appConnectorGroupHCL, appConnectorGroupTypeAndName, logTypes string) string {
+	return fmt.Sprintf(`
+// app connector group resource
+%s
+
+resource "%s" "%s" {
+  name     = "%s"
+  lss_host = "%s"
+  lss_port = "%d"
+  use_tls  = true
+  connector_groups {
+    id = [%s.id]
+  }
+%s
+}
+`,
+		appConnectorGroupHCL,
+		resourcetype.ZPALSSReceiver,
+		generatedName,
+		name,
+		lssHost,
+		rPort,
+		appConnectorGroupTypeAndName,
+		logTypes,
+	)
+}
@@ -18,6 +18,7 @@ func resourcePolicyBrowserProtectionRule() *schema.Resource {
 		ReadContext:   resourcePolicyBrowserProtectionRuleRead,
 		UpdateContext: resourcePolicyBrowserProtectionRuleUpdate,
 		DeleteContext: resourcePolicyBrowserProtectionRuleDelete,
+		CustomizeDiff: customizePolicyRuleV2Diff("CLIENTLESS_SESSION_PROTECTION_POLICY"),
 		Importer: &schema.ResourceImporter{
 			StateContext: importPolicyStateContextFuncV2([]string{"CLIENTLESS_SESSION_PROTECTION_POLICY"}),
 		},
@@ -32,6 +33,17 @@ func resourcePolicyBrowserProtectionRule() *schema.Resource {
 				Required:    true,
 				Description: "This is the name of the policy.",
 			},
+			"place_before_rule_id":   policyRulePlacementSchema("place_before_rule_id"),
+			"place_after_rule_id":    policyRulePlacementSchema("place_after_rule_id"),
+			"place_before_rule_name": policyRulePlacementSchema("place_before_rule_name"),
+			"place_after_rule_name":  policyRulePlacementSchema("place_after_rule_name"),
+			"placement_neighbor":     policyRulePlacementNeighborSchema(),
+			"priority": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Rules created in the same apply are created in ascending priority, so that they get ascending rule orders. Only used by the provider when the rule is created.",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
 			"description": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -50,6 +62,9 @@ func resourcePolicyBrowserProtectionRule() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"condition_expression": conditionExpressionSchema("CLIENTLESS_SESSION_PROTECTION_POLICY"),
+			"resolved_names":       policyOperandNamesSchema(),
+			"pruned_references":    prunedReferencesSchema(),
 			"conditions": {
 				Type:        schema.TypeSet,
 				Optional:    true,
@@ -86,19 +101,11 @@ func resourcePolicyBrowserProtectionRule() *schema.Resource {
 										},
 									},
 									"object_type": {
-										Type:        schema.TypeString,
-										Optional:    true,
-										Computed:    true,
-										Description: "  This is for specifying the policy critiera.",
-										ValidateFunc: validation.StringInSlice([]string{
-											"APP",
-											"APP_GROUP",
-											"CLIENT_TYPE",
-											"SAML",
-											"SCIM",
-											"SCIM_GROUP",
-											"USER_PORTAL",
-										}, false),
+										Type:         schema.TypeString,
+										Optional:     true,
+										Computed:     true,
+										Description:  "  This is for specifying the policy critiera.",
+										ValidateFunc: validation.StringInSlice(policyOperandObjectTypes(policyTypeSessionProtection), false),
 									},
 									"entry_values": {
 										Type:     schema.TypeSet,
@@ -160,13 +167,23 @@ func resourcePolicyBrowserProtectionRuleCreate(ctx context.Context, d *schema.Re
 	}
 	log.Printf("[INFO] Creating zpa policy browser protection rule with request\n%+v\n", req)
 
-	policysetcontrollerv2, _, err := policysetcontrollerv2.CreateRule(ctx, service, req)
+	err = zClient.createPolicyRule(ctx, service, "CLIENTLESS_SESSION_PROTECTION_POLICY", microTenantID, policyRuleCreationPriority(d), func() (string, error) {
+		resp, _, err := policysetcontrollerv2.CreateRule(ctx, service, req)
+		if err != nil {
+			return "", err
+		}
+		d.SetId(resp.ID)
+		return resp.ID, nil
+	})
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	d.SetId(policysetcontrollerv2.ID)
 
-	return resourcePolicyBrowserProtectionRuleRead(ctx, d, meta)
+	if err := applyPolicyRulePlacement(ctx, d, zClient, "CLIENTLESS_SESSION_PROTECTION_POLICY"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourcePolicyBrowserProtectionRuleRead(withoutPrefetch(ctx), d, meta)
 }
 
 func resourcePolicyBrowserProtectionRuleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -203,9 +220,14 @@ func resourcePolicyBrowserProtectionRuleRead(ctx context.Context, d *schema.Reso
 	_ = d.Set("action", v2PolicyRule.Action)
 	_ = d.Set("policy_set_id", policySetID)
 	_ = d.Set("microtenant_id", v2PolicyRule.MicroTenantID)
-	_ = d.Set("conditions", flattenConditionsV2(v2PolicyRule.Conditions))
+	diags := danglingPolicyReferenceWarnings(ctx, meta, collectPolicyReferences(v2PolicyRule.Conditions, microTenantID))
+	setPolicyRuleConditionsV2(d, v2PolicyRule)
 
-	return nil
+	if err := readPolicyRulePlacement(ctx, d, zClient, "CLIENTLESS_SESSION_PROTECTION_POLICY"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
 }
 
 func resourcePolicyBrowserProtectionRuleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -251,7 +273,11 @@ func resourcePolicyBrowserProtectionRuleUpdate(ctx context.Context, d *schema.Re
 		return diag.FromErr(err)
 	}
 
-	return resourcePolicyBrowserProtectionRuleRead(ctx, d, meta)
+	if err := applyPolicyRulePlacement(ctx, d, zClient, "CLIENTLESS_SESSION_PROTECTION_POLICY"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourcePolicyBrowserProtectionRuleRead(withoutPrefetch(ctx), d, meta)
 }
 
 func resourcePolicyBrowserProtectionRuleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -274,12 +300,13 @@ func resourcePolicyBrowserProtectionRuleDelete(ctx context.Context, d *schema.Re
 	if _, err := policysetcontrollerv2.Delete(ctx, service, policySetID, d.Id()); err != nil {
 		return diag.FromErr(fmt.Errorf("failed to delete policy browser protection rule: %w", err))
 	}
+	zClient.invalidatePolicyRulesPrefetch("CLIENTLESS_SESSION_PROTECTION_POLICY")
 
 	return nil
 }
 
 func expandBrowserProtectionPolicyRule(d *schema.ResourceData, policySetID string) (*policysetcontrollerv2.PolicyRule, error) {
-	conditions, err := ExpandPolicyConditionsV2(d)
+	conditions, err := expandPolicyRuleConditionsV2(d, "CLIENTLESS_SESSION_PROTECTION_POLICY")
 	if err != nil {
 		return nil, err
 	}
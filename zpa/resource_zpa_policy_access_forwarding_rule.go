@@ -34,21 +34,7 @@ func resourcePolicyForwardingRule() *schema.Resource {
 						"INTERCEPT_ACCESSIBLE",
 					}, false),
 				},
-				"conditions": GetPolicyConditionsSchema([]string{
-					"APP",
-					"APP_GROUP",
-					"CLIENT_TYPE",
-					"BRANCH_CONNECTOR_GROUP",
-					"EDGE_CONNECTOR_GROUP",
-					"POSTURE",
-					"MACHINE_GRP",
-					"TRUSTED_NETWORK",
-					"PLATFORM",
-					"IDP",
-					"SAML",
-					"SCIM",
-					"SCIM_GROUP",
-				}),
+				"conditions": GetPolicyConditionsSchema(policyOperandObjectTypes(policyTypeForwarding)),
 			},
 		),
 	}
@@ -131,9 +117,10 @@ func resourcePolicyForwardingRuleRead(ctx context.Context, d *schema.ResourceDat
 	_ = d.Set("policy_type", resp.PolicyType)
 	_ = d.Set("priority", resp.Priority)
 	_ = d.Set("microtenant_id", resp.MicroTenantID)
+	diags := danglingPolicyReferenceWarnings(ctx, meta, collectPolicyReferencesV1(resp.Conditions, microTenantID))
 	_ = d.Set("conditions", flattenPolicyConditions(resp.Conditions))
 
-	return nil
+	return diags
 }
 
 func resourcePolicyForwardingRuleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -17,6 +17,7 @@ func resourcePolicyForwardingRuleV2() *schema.Resource {
 		ReadContext:   resourcePolicyForwardingRuleV2Read,
 		UpdateContext: resourcePolicyForwardingRuleV2Update,
 		DeleteContext: resourcePolicyForwardingRuleV2Delete,
+		CustomizeDiff: withPolicyRuleExpiry(customizePolicyRuleV2Diff("CLIENT_FORWARDING_POLICY")),
 		Importer: &schema.ResourceImporter{
 			StateContext: importPolicyStateContextFuncV2([]string{"CLIENT_FORWARDING_POLICY", "BYPASS_POLICY"}),
 		},
@@ -31,11 +32,24 @@ func resourcePolicyForwardingRuleV2() *schema.Resource {
 				Required:    true,
 				Description: "This is the name of the policy.",
 			},
+			"place_before_rule_id":   policyRulePlacementSchema("place_before_rule_id"),
+			"place_after_rule_id":    policyRulePlacementSchema("place_after_rule_id"),
+			"place_before_rule_name": policyRulePlacementSchema("place_before_rule_name"),
+			"place_after_rule_name":  policyRulePlacementSchema("place_after_rule_name"),
+			"placement_neighbor":     policyRulePlacementNeighborSchema(),
+			"priority": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Rules created in the same apply are created in ascending priority, so that they get ascending rule orders. Only used by the provider when the rule is created.",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
 			"description": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Description: "This is the description of the access policy.",
 			},
+			"expires_at": policyRuleExpiresAtSchema(),
+			"expired":    policyRuleExpiredSchema(),
 			"action": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -50,6 +64,9 @@ func resourcePolicyForwardingRuleV2() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"condition_expression": conditionExpressionSchema("CLIENT_FORWARDING_POLICY"),
+			"resolved_names":       policyOperandNamesSchema(),
+			"pruned_references":    prunedReferencesSchema(),
 			"conditions": {
 				Type:        schema.TypeSet,
 				Optional:    true,
@@ -86,25 +103,11 @@ func resourcePolicyForwardingRuleV2() *schema.Resource {
 										},
 									},
 									"object_type": {
-										Type:        schema.TypeString,
-										Optional:    true,
-										Computed:    true,
-										Description: "  This is for specifying the policy critiera.",
-										ValidateFunc: validation.StringInSlice([]string{
-											"APP",
-											"APP_GROUP",
-											"CLIENT_TYPE",
-											"BRANCH_CONNECTOR_GROUP",
-											"EDGE_CONNECTOR_GROUP",
-											"POSTURE",
-											"MACHINE_GRP",
-											"TRUSTED_NETWORK",
-											"PLATFORM",
-											"IDP",
-											"SAML",
-											"SCIM",
-											"SCIM_GROUP",
-										}, false),
+										Type:         schema.TypeString,
+										Optional:     true,
+										Computed:     true,
+										Description:  "  This is for specifying the policy critiera.",
+										ValidateFunc: validation.StringInSlice(policyOperandObjectTypes(policyTypeForwarding), false),
 									},
 									"entry_values": {
 										Type:     schema.TypeSet,
@@ -165,14 +168,23 @@ func resourcePolicyForwardingRuleV2Create(ctx context.Context, d *schema.Resourc
 		return diag.FromErr(err)
 	}
 
-	resp, _, err := policysetcontrollerv2.CreateRule(ctx, service, req)
+	err = zClient.createPolicyRule(ctx, service, "CLIENT_FORWARDING_POLICY", microTenantID, policyRuleCreationPriority(d), func() (string, error) {
+		resp, _, err := policysetcontrollerv2.CreateRule(ctx, service, req)
+		if err != nil {
+			return "", err
+		}
+		d.SetId(resp.ID)
+		return resp.ID, nil
+	})
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	d.SetId(resp.ID)
+	if err := applyPolicyRulePlacement(ctx, d, zClient, "CLIENT_FORWARDING_POLICY"); err != nil {
+		return diag.FromErr(err)
+	}
 
-	return resourcePolicyForwardingRuleV2Read(ctx, d, meta)
+	return resourcePolicyForwardingRuleV2Read(withoutPrefetch(ctx), d, meta)
 }
 
 func resourcePolicyForwardingRuleV2Read(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -193,6 +205,9 @@ func resourcePolicyForwardingRuleV2Read(ctx context.Context, d *schema.ResourceD
 	resp, _, err := policysetcontrollerv2.GetPolicyRule(ctx, service, policySetID, d.Id())
 	if err != nil {
 		if errResp, ok := err.(*errorx.ErrorResponse); ok && errResp.IsObjectNotFound() {
+			if keepExpiredPolicyRule(d, meta) {
+				return nil
+			}
 			log.Printf("[WARN] Removing policy rule %s from state because it no longer exists in ZPA", d.Id())
 			d.SetId("")
 			return nil
@@ -204,13 +219,18 @@ func resourcePolicyForwardingRuleV2Read(ctx context.Context, d *schema.ResourceD
 
 	d.SetId(resp.ID)
 	d.Set("name", v2PolicyRule.Name)
-	d.Set("description", v2PolicyRule.Description)
+	diags := readPolicyRuleExpiry(d, meta, v2PolicyRule.Description, resp.Disabled)
 	d.Set("action", v2PolicyRule.Action)
 	d.Set("policy_set_id", policySetID) // Here, you're setting it based on fetched ID
 	d.Set("microtenant_id", v2PolicyRule.MicroTenantID)
-	d.Set("conditions", flattenConditionsV2(v2PolicyRule.Conditions))
+	diags = append(diags, danglingPolicyReferenceWarnings(ctx, meta, collectPolicyReferences(v2PolicyRule.Conditions, microTenantID))...)
+	setPolicyRuleConditionsV2(d, v2PolicyRule)
 
-	return nil
+	if err := readPolicyRulePlacement(ctx, d, zClient, "CLIENT_FORWARDING_POLICY"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
 }
 
 func resourcePolicyForwardingRuleV2Update(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -242,6 +262,19 @@ func resourcePolicyForwardingRuleV2Update(ctx context.Context, d *schema.Resourc
 		return diag.FromErr(err)
 	}
 
+	if d.Get("expired").(bool) {
+		deleted, err := expirePolicyRule(ctx, service, meta, policySetID, ruleID, req)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if deleted {
+			zClient.invalidatePolicyRulesPrefetch("CLIENT_FORWARDING_POLICY")
+			return nil
+		}
+	} else {
+		reenablePolicyRule(d, req)
+	}
+
 	// Checking the current state of the rule to handle cases where it might have been deleted outside Terraform
 	_, _, err = policysetcontrollerv2.GetPolicyRule(ctx, service, policySetID, ruleID)
 	if err != nil {
@@ -256,7 +289,11 @@ func resourcePolicyForwardingRuleV2Update(ctx context.Context, d *schema.Resourc
 		return diag.FromErr(err)
 	}
 
-	return resourcePolicyForwardingRuleV2Read(ctx, d, meta)
+	if err := applyPolicyRulePlacement(ctx, d, zClient, "CLIENT_FORWARDING_POLICY"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourcePolicyForwardingRuleV2Read(withoutPrefetch(ctx), d, meta)
 }
 
 func resourcePolicyForwardingRuleV2Delete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -275,15 +312,16 @@ func resourcePolicyForwardingRuleV2Delete(ctx context.Context, d *schema.Resourc
 		service = service.WithMicroTenant(microTenantID)
 	}
 
-	if _, err := policysetcontrollerv2.Delete(ctx, service, policySetID, d.Id()); err != nil {
+	if _, err := policysetcontrollerv2.Delete(ctx, service, policySetID, d.Id()); ignoreExpiredPolicyRuleNotFound(d, err) != nil {
 		return diag.FromErr(err)
 	}
+	zClient.invalidatePolicyRulesPrefetch("CLIENT_FORWARDING_POLICY")
 
 	return nil
 }
 
 func expandPolicyForwardingRuleV2(d *schema.ResourceData, policySetID string) (*policysetcontrollerv2.PolicyRule, error) {
-	conditions, err := ExpandPolicyConditionsV2(d)
+	conditions, err := expandPolicyRuleConditionsV2(d, "CLIENT_FORWARDING_POLICY")
 	if err != nil {
 		return nil, err
 	}
@@ -291,7 +329,7 @@ func expandPolicyForwardingRuleV2(d *schema.ResourceData, policySetID string) (*
 	return &policysetcontrollerv2.PolicyRule{
 		ID:            d.Get("id").(string),
 		Name:          d.Get("name").(string),
-		Description:   d.Get("description").(string),
+		Description:   joinPolicyRuleExpiry(d.Get("description").(string), d.Get("expires_at").(string)),
 		Action:        d.Get("action").(string),
 		MicroTenantID: GetString(d.Get("microtenant_id")),
 		PolicySetID:   policySetID,
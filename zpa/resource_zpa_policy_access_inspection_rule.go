@@ -33,18 +33,7 @@ func resourcePolicyInspectionRule() *schema.Resource {
 						"BYPASS_INSPECT",
 					}, false),
 				},
-				"conditions": GetPolicyConditionsSchema([]string{
-					"APP",
-					"APP_GROUP",
-					"CLIENT_TYPE",
-					"EDGE_CONNECTOR_GROUP",
-					"IDP",
-					"POSTURE",
-					"SAML",
-					"SCIM",
-					"SCIM_GROUP",
-					"TRUSTED_NETWORK",
-				}),
+				"conditions": GetPolicyConditionsSchema(policyOperandObjectTypes(policyTypeInspection)),
 			},
 		),
 	}
@@ -162,9 +151,10 @@ func resourcePolicyInspectionRuleRead(ctx context.Context, d *schema.ResourceDat
 	_ = d.Set("operator", resp.Operator)
 	_ = d.Set("policy_set_id", resp.PolicySetID)
 	_ = d.Set("zpn_inspection_profile_id", resp.ZpnInspectionProfileID)
+	diags := danglingPolicyReferenceWarnings(ctx, meta, collectPolicyReferencesV1(resp.Conditions, microTenantID))
 	_ = d.Set("conditions", flattenPolicyConditions(resp.Conditions))
 
-	return nil
+	return diags
 }
 
 func resourcePolicyInspectionRuleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
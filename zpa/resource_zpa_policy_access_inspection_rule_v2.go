@@ -17,6 +17,7 @@ func resourcePolicyInspectionRuleV2() *schema.Resource {
 		ReadContext:   resourcePolicyInspectionRuleV2Read,
 		UpdateContext: resourcePolicyInspectionRuleV2Update,
 		DeleteContext: resourcePolicyInspectionRuleV2Delete,
+		CustomizeDiff: customizePolicyRuleV2Diff("INSPECTION_POLICY"),
 		Importer: &schema.ResourceImporter{
 			StateContext: importPolicyStateContextFuncV2([]string{"INSPECTION_POLICY"}),
 		},
@@ -30,6 +31,17 @@ func resourcePolicyInspectionRuleV2() *schema.Resource {
 				Required:    true,
 				Description: "This is the name of the policy.",
 			},
+			"place_before_rule_id":   policyRulePlacementSchema("place_before_rule_id"),
+			"place_after_rule_id":    policyRulePlacementSchema("place_after_rule_id"),
+			"place_before_rule_name": policyRulePlacementSchema("place_before_rule_name"),
+			"place_after_rule_name":  policyRulePlacementSchema("place_after_rule_name"),
+			"placement_neighbor":     policyRulePlacementNeighborSchema(),
+			"priority": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Rules created in the same apply are created in ascending priority, so that they get ascending rule orders. Only used by the provider when the rule is created.",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
 			"description": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -53,6 +65,9 @@ func resourcePolicyInspectionRuleV2() *schema.Resource {
 				Optional: true,
 				Computed: true,
 			},
+			"condition_expression": conditionExpressionSchema("INSPECTION_POLICY"),
+			"resolved_names":       policyOperandNamesSchema(),
+			"pruned_references":    prunedReferencesSchema(),
 			"conditions": {
 				Type:        schema.TypeSet,
 				Optional:    true,
@@ -89,22 +104,11 @@ func resourcePolicyInspectionRuleV2() *schema.Resource {
 										},
 									},
 									"object_type": {
-										Type:        schema.TypeString,
-										Optional:    true,
-										Computed:    true,
-										Description: "  This is for specifying the policy critiera.",
-										ValidateFunc: validation.StringInSlice([]string{
-											"APP",
-											"APP_GROUP",
-											"CLIENT_TYPE",
-											"EDGE_CONNECTOR_GROUP",
-											"IDP",
-											"POSTURE",
-											"SAML",
-											"SCIM",
-											"SCIM_GROUP",
-											"TRUSTED_NETWORK",
-										}, false),
+										Type:         schema.TypeString,
+										Optional:     true,
+										Computed:     true,
+										Description:  "  This is for specifying the policy critiera.",
+										ValidateFunc: validation.StringInSlice(policyOperandObjectTypes(policyTypeInspection), false),
 									},
 									"entry_values": {
 										Type:     schema.TypeSet,
@@ -166,14 +170,23 @@ func resourcePolicyInspectionRuleV2Create(ctx context.Context, d *schema.Resourc
 		return diag.FromErr(err)
 	}
 
-	resp, _, err := policysetcontrollerv2.CreateRule(ctx, service, req)
+	err = zClient.createPolicyRule(ctx, service, "INSPECTION_POLICY", microTenantID, policyRuleCreationPriority(d), func() (string, error) {
+		resp, _, err := policysetcontrollerv2.CreateRule(ctx, service, req)
+		if err != nil {
+			return "", err
+		}
+		d.SetId(resp.ID)
+		return resp.ID, nil
+	})
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	d.SetId(resp.ID)
+	if err := applyPolicyRulePlacement(ctx, d, zClient, "INSPECTION_POLICY"); err != nil {
+		return diag.FromErr(err)
+	}
 
-	return resourcePolicyInspectionRuleV2Read(ctx, d, meta)
+	return resourcePolicyInspectionRuleV2Read(withoutPrefetch(ctx), d, meta)
 }
 
 func resourcePolicyInspectionRuleV2Read(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -210,9 +223,14 @@ func resourcePolicyInspectionRuleV2Read(ctx context.Context, d *schema.ResourceD
 	d.Set("policy_set_id", policySetID) // Here, you're setting it based on fetched ID
 	d.Set("zpn_inspection_profile_id", v2PolicyRule.ZpnInspectionProfileID)
 	d.Set("microtenant_id", v2PolicyRule.MicroTenantID)
-	d.Set("conditions", flattenConditionsV2(v2PolicyRule.Conditions))
+	diags := danglingPolicyReferenceWarnings(ctx, meta, collectPolicyReferences(v2PolicyRule.Conditions, microTenantID))
+	setPolicyRuleConditionsV2(d, v2PolicyRule)
 
-	return nil
+	if err := readPolicyRulePlacement(ctx, d, zClient, "INSPECTION_POLICY"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
 }
 
 func resourcePolicyInspectionRuleV2Update(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -258,7 +276,11 @@ func resourcePolicyInspectionRuleV2Update(ctx context.Context, d *schema.Resourc
 		return diag.FromErr(err)
 	}
 
-	return resourcePolicyInspectionRuleV2Read(ctx, d, meta)
+	if err := applyPolicyRulePlacement(ctx, d, zClient, "INSPECTION_POLICY"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourcePolicyInspectionRuleV2Read(withoutPrefetch(ctx), d, meta)
 }
 
 func resourcePolicyInspectionRuleV2Delete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -281,12 +303,13 @@ func resourcePolicyInspectionRuleV2Delete(ctx context.Context, d *schema.Resourc
 	if _, err := policysetcontrollerv2.Delete(ctx, service, policySetID, d.Id()); err != nil {
 		return diag.FromErr(err)
 	}
+	zClient.invalidatePolicyRulesPrefetch("INSPECTION_POLICY")
 
 	return nil
 }
 
 func expandPolicyInspectionRule(d *schema.ResourceData, policySetID string) (*policysetcontrollerv2.PolicyRule, error) {
-	conditions, err := ExpandPolicyConditionsV2(d)
+	conditions, err := expandPolicyRuleConditionsV2(d, "INSPECTION_POLICY")
 	if err != nil {
 		return nil, err
 	}
@@ -33,17 +33,7 @@ func resourcePolicyIsolationRule() *schema.Resource {
 						"BYPASS_ISOLATE",
 					}, false),
 				},
-				"conditions": GetPolicyConditionsSchema([]string{
-					"APP",
-					"APP_GROUP",
-					"CLIENT_TYPE",
-					"EDGE_CONNECTOR_GROUP",
-					"PLATFORM",
-					"IDP",
-					"SAML",
-					"SCIM",
-					"SCIM_GROUP",
-				}),
+				"conditions": GetPolicyConditionsSchema(policyOperandObjectTypes(policyTypeIsolation)),
 			},
 		),
 	}
@@ -161,9 +151,10 @@ func resourcePolicyIsolationRuleRead(ctx context.Context, d *schema.ResourceData
 	_ = d.Set("operator", resp.Operator)
 	_ = d.Set("policy_set_id", resp.PolicySetID)
 	_ = d.Set("zpn_isolation_profile_id", resp.ZpnIsolationProfileID)
+	diags := danglingPolicyReferenceWarnings(ctx, meta, collectPolicyReferencesV1(resp.Conditions, microTenantID))
 	_ = d.Set("conditions", flattenPolicyConditions(resp.Conditions))
 
-	return nil
+	return diags
 }
 
 func resourcePolicyIsolationRuleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
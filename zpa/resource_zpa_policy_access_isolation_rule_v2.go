@@ -17,6 +17,7 @@ func resourcePolicyIsolationRuleV2() *schema.Resource {
 		ReadContext:   resourcePolicyIsolationRuleV2Read,
 		UpdateContext: resourcePolicyIsolationRuleV2Update,
 		DeleteContext: resourcePolicyIsolationRuleV2Delete,
+		CustomizeDiff: customizePolicyRuleV2Diff("ISOLATION_POLICY"),
 		Importer: &schema.ResourceImporter{
 			StateContext: importPolicyStateContextFuncV2([]string{"ISOLATION_POLICY"}),
 		},
@@ -31,6 +32,17 @@ func resourcePolicyIsolationRuleV2() *schema.Resource {
 				Required:    true,
 				Description: "This is the name of the policy.",
 			},
+			"place_before_rule_id":   policyRulePlacementSchema("place_before_rule_id"),
+			"place_after_rule_id":    policyRulePlacementSchema("place_after_rule_id"),
+			"place_before_rule_name": policyRulePlacementSchema("place_before_rule_name"),
+			"place_after_rule_name":  policyRulePlacementSchema("place_after_rule_name"),
+			"placement_neighbor":     policyRulePlacementNeighborSchema(),
+			"priority": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Rules created in the same apply are created in ascending priority, so that they get ascending rule orders. Only used by the provider when the rule is created.",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
 			"description": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -54,6 +66,9 @@ func resourcePolicyIsolationRuleV2() *schema.Resource {
 				Optional: true,
 				Computed: true,
 			},
+			"condition_expression": conditionExpressionSchema("ISOLATION_POLICY"),
+			"resolved_names":       policyOperandNamesSchema(),
+			"pruned_references":    prunedReferencesSchema(),
 			"conditions": {
 				Type:        schema.TypeSet,
 				Optional:    true,
@@ -90,23 +105,11 @@ func resourcePolicyIsolationRuleV2() *schema.Resource {
 										},
 									},
 									"object_type": {
-										Type:        schema.TypeString,
-										Optional:    true,
-										Computed:    true,
-										Description: "  This is for specifying the policy critiera.",
-										ValidateFunc: validation.StringInSlice([]string{
-											"APP",
-											"APP_GROUP",
-											"CLIENT_TYPE",
-											"EDGE_CONNECTOR_GROUP",
-											"PLATFORM",
-											"IDP",
-											"SAML",
-											"SCIM",
-											"SCIM_GROUP",
-											"CHROME_ENTERPRISE",
-											"CHROME_POSTURE_PROFILE",
-										}, false),
+										Type:         schema.TypeString,
+										Optional:     true,
+										Computed:     true,
+										Description:  "  This is for specifying the policy critiera.",
+										ValidateFunc: validation.StringInSlice(policyOperandObjectTypes(policyTypeIsolation), false),
 									},
 									"entry_values": {
 										Type:     schema.TypeSet,
@@ -168,14 +171,23 @@ func resourcePolicyIsolationRuleV2Create(ctx context.Context, d *schema.Resource
 		return diag.FromErr(err)
 	}
 
-	resp, _, err := policysetcontrollerv2.CreateRule(ctx, service, req)
+	err = zClient.createPolicyRule(ctx, service, "ISOLATION_POLICY", microTenantID, policyRuleCreationPriority(d), func() (string, error) {
+		resp, _, err := policysetcontrollerv2.CreateRule(ctx, service, req)
+		if err != nil {
+			return "", err
+		}
+		d.SetId(resp.ID)
+		return resp.ID, nil
+	})
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	d.SetId(resp.ID)
+	if err := applyPolicyRulePlacement(ctx, d, zClient, "ISOLATION_POLICY"); err != nil {
+		return diag.FromErr(err)
+	}
 
-	return resourcePolicyIsolationRuleV2Read(ctx, d, meta)
+	return resourcePolicyIsolationRuleV2Read(withoutPrefetch(ctx), d, meta)
 }
 
 func resourcePolicyIsolationRuleV2Read(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -211,9 +223,14 @@ func resourcePolicyIsolationRuleV2Read(ctx context.Context, d *schema.ResourceDa
 	d.Set("policy_set_id", policySetID) // Here, you're setting it based on fetched ID
 	d.Set("zpn_isolation_profile_id", v2PolicyRule.ZpnIsolationProfileID)
 	d.Set("microtenant_id", v2PolicyRule.MicroTenantID)
-	d.Set("conditions", flattenConditionsV2(v2PolicyRule.Conditions))
+	diags := danglingPolicyReferenceWarnings(ctx, meta, collectPolicyReferences(v2PolicyRule.Conditions, microTenantID))
+	setPolicyRuleConditionsV2(d, v2PolicyRule)
 
-	return nil
+	if err := readPolicyRulePlacement(ctx, d, zClient, "ISOLATION_POLICY"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
 }
 
 func resourcePolicyIsolationRuleV2Update(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -259,7 +276,11 @@ func resourcePolicyIsolationRuleV2Update(ctx context.Context, d *schema.Resource
 		return diag.FromErr(err)
 	}
 
-	return resourcePolicyIsolationRuleV2Read(ctx, d, meta)
+	if err := applyPolicyRulePlacement(ctx, d, zClient, "ISOLATION_POLICY"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourcePolicyIsolationRuleV2Read(withoutPrefetch(ctx), d, meta)
 }
 
 func resourcePolicyIsolationRuleV2Delete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -282,12 +303,13 @@ func resourcePolicyIsolationRuleV2Delete(ctx context.Context, d *schema.Resource
 	if _, err := policysetcontrollerv2.Delete(ctx, service, policySetID, d.Id()); err != nil {
 		return diag.FromErr(err)
 	}
+	zClient.invalidatePolicyRulesPrefetch("ISOLATION_POLICY")
 
 	return nil
 }
 
 func expandPolicyIsolationRule(d *schema.ResourceData, policySetID string) (*policysetcontrollerv2.PolicyRule, error) {
-	conditions, err := ExpandPolicyConditionsV2(d)
+	conditions, err := expandPolicyRuleConditionsV2(d, "ISOLATION_POLICY")
 	if err != nil {
 		return nil, err
 	}
@@ -36,9 +36,7 @@ func resourcePolicyRedictionRule() *schema.Resource {
 						"REDIRECT_ALWAYS",
 					}, false),
 				},
-				"conditions": GetPolicyConditionsSchema([]string{
-					"CLIENT_TYPE",
-				}),
+				"conditions": GetPolicyConditionsSchema(policyOperandObjectTypes(policyTypeRedirection)),
 				"service_edge_groups": {
 					Type:        schema.TypeList,
 					Optional:    true,
@@ -76,13 +74,19 @@ func validatePolicyRedirectionRuleAction(d *schema.ResourceData) error {
 		return fmt.Errorf("service_edge_groups has unexpected type %T", raw)
 	}
 
+	return validateRedirectionServiceEdgeGroups(action, len(serviceEdgeGroups))
+}
+
+// validateRedirectionServiceEdgeGroups checks that service edge groups are
+// only set for the redirect actions that use them.
+func validateRedirectionServiceEdgeGroups(action string, serviceEdgeGroups int) error {
 	switch action {
 	case "REDIRECT_PREFERRED", "REDIRECT_ALWAYS":
-		if len(serviceEdgeGroups) == 0 {
+		if serviceEdgeGroups == 0 {
 			return fmt.Errorf("one or more ZPA Private Service Edge groups must be selected when the Private Service Edge Selection Method is %s", action)
 		}
 	case "REDIRECT_DEFAULT":
-		if len(serviceEdgeGroups) > 0 {
+		if serviceEdgeGroups > 0 {
 			return fmt.Errorf("ZPA Private Service Edge groups must be empty when the Private Service Edge Selection Method is REDIRECT_DEFAULT")
 		}
 	}
@@ -151,9 +155,10 @@ func resourcePolicyRedictionRuleRead(ctx context.Context, d *schema.ResourceData
 	_ = d.Set("operator", resp.Operator)
 	_ = d.Set("policy_set_id", resp.PolicySetID)
 	_ = d.Set("policy_type", resp.PolicyType)
+	diags := danglingPolicyReferenceWarnings(ctx, meta, collectPolicyReferencesV1(resp.Conditions, microTenantID))
 	_ = d.Set("conditions", flattenPolicyConditions(resp.Conditions))
 	_ = d.Set("service_edge_groups", flattenServiceEdgeGroupSimple(resp.ServiceEdgeGroups))
-	return nil
+	return diags
 }
 
 func resourcePolicyRedictionRuleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -0,0 +1,361 @@
+package zpa
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/errorx"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontrollerv2"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/serviceedgegroup"
+)
+
+func resourcePolicyRedirectionRuleV2() *schema.Resource {
+	customizeDiff := customizePolicyRuleV2Diff("REDIRECTION_POLICY")
+	return &schema.Resource{
+		CreateContext: resourcePolicyRedirectionRuleV2Create,
+		ReadContext:   resourcePolicyRedirectionRuleV2Read,
+		UpdateContext: resourcePolicyRedirectionRuleV2Update,
+		DeleteContext: resourcePolicyRedirectionRuleV2Delete,
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			if err := validatePolicyRedirectionRuleV2Diff(d); err != nil {
+				return err
+			}
+			return customizeDiff(ctx, d, meta)
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: importPolicyStateContextFuncV2([]string{"REDIRECTION_POLICY"}),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "This is the name of the policy.",
+			},
+			"place_before_rule_id":   policyRulePlacementSchema("place_before_rule_id"),
+			"place_after_rule_id":    policyRulePlacementSchema("place_after_rule_id"),
+			"place_before_rule_name": policyRulePlacementSchema("place_before_rule_name"),
+			"place_after_rule_name":  policyRulePlacementSchema("place_after_rule_name"),
+			"placement_neighbor":     policyRulePlacementNeighborSchema(),
+			"priority": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Rules created in the same apply are created in ascending priority, so that they get ascending rule orders. Only used by the provider when the rule is created.",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "This is the description of the redirection policy.",
+			},
+			"action": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The Private Service Edge selection method of the rule.",
+				ValidateFunc: validation.StringInSlice([]string{
+					"REDIRECT_DEFAULT",
+					"REDIRECT_PREFERRED",
+					"REDIRECT_ALWAYS",
+				}, false),
+			},
+			"service_edge_groups": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "The Private Service Edge groups of the rule. Required for REDIRECT_PREFERRED and REDIRECT_ALWAYS, not supported for REDIRECT_DEFAULT.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeSet,
+							Required: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+			"policy_set_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"condition_expression": conditionExpressionSchema("REDIRECTION_POLICY"),
+			"resolved_names":       policyOperandNamesSchema(),
+			"pruned_references":    prunedReferencesSchema(),
+			"conditions": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Computed:    true,
+				Description: "This is for proviidng the set of conditions for the policy.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"operator": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+							ValidateFunc: validation.StringInSlice([]string{
+								"AND",
+								"OR",
+							}, false),
+						},
+						"operands": {
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Computed:    true,
+							Description: "This signifies the various policy criteria.",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"values": {
+										Type:        schema.TypeSet,
+										Optional:    true,
+										Description: "This denotes a list of values for the given object type. The value depend upon the key. If rhs is defined this list will be ignored",
+										Elem: &schema.Schema{
+											Type: schema.TypeString,
+										},
+									},
+									"object_type": {
+										Type:         schema.TypeString,
+										Optional:     true,
+										Computed:     true,
+										Description:  "  This is for specifying the policy critiera.",
+										ValidateFunc: validation.StringInSlice(policyOperandObjectTypes(policyTypeRedirection), false),
+									},
+									"entry_values": {
+										Type:     schema.TypeSet,
+										Optional: true,
+										Computed: true,
+										Elem: &schema.Resource{
+											Schema: map[string]*schema.Schema{
+												"rhs": {
+													Type:     schema.TypeString,
+													Optional: true,
+												},
+												"lhs": {
+													Type:     schema.TypeString,
+													Optional: true,
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"microtenant_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// validatePolicyRedirectionRuleV2Diff checks the service edge groups against
+// the action during plan, once both are known.
+func validatePolicyRedirectionRuleV2Diff(d *schema.ResourceDiff) error {
+	if !d.NewValueKnown("action") || !d.NewValueKnown("service_edge_groups") {
+		return nil
+	}
+	serviceEdgeGroups := 0
+	if blocks, ok := d.Get("service_edge_groups").([]interface{}); ok && len(blocks) > 0 {
+		if block, ok := blocks[0].(map[string]interface{}); ok {
+			if ids, ok := block["id"].(*schema.Set); ok {
+				serviceEdgeGroups = ids.Len()
+			}
+		}
+	}
+	return validateRedirectionServiceEdgeGroups(d.Get("action").(string), serviceEdgeGroups)
+}
+
+func resourcePolicyRedirectionRuleV2Create(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zClient := meta.(*Client)
+	service := zClient.Service
+
+	microTenantID := GetString(d.Get("microtenant_id"))
+	if microTenantID != "" {
+		service = service.WithMicroTenant(microTenantID)
+	}
+	policySetID, err := fetchPolicySetIDByType(ctx, zClient, "REDIRECTION_POLICY", microTenantID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	_ = d.Set("policy_set_id", policySetID)
+
+	req, err := expandPolicyRedirectionRuleV2(d, policySetID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	log.Printf("[INFO] Creating zpa policy redirection rule with request\n%+v\n", req)
+
+	if err := ValidatePolicyRuleConditions(d); err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = zClient.createPolicyRule(ctx, service, "REDIRECTION_POLICY", microTenantID, policyRuleCreationPriority(d), func() (string, error) {
+		resp, _, err := policysetcontrollerv2.CreateRule(ctx, service, req)
+		if err != nil {
+			return "", err
+		}
+		d.SetId(resp.ID)
+		return resp.ID, nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := applyPolicyRulePlacement(ctx, d, zClient, "REDIRECTION_POLICY"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourcePolicyRedirectionRuleV2Read(withoutPrefetch(ctx), d, meta)
+}
+
+func resourcePolicyRedirectionRuleV2Read(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zClient := meta.(*Client)
+	service := zClient.Service
+
+	microTenantID := GetString(d.Get("microtenant_id"))
+	if microTenantID != "" {
+		service = service.WithMicroTenant(microTenantID)
+	}
+
+	policySetID, err := fetchPolicySetIDByType(ctx, zClient, "REDIRECTION_POLICY", microTenantID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO] Getting Policy Set Redirection Rule: policySetID:%s id: %s\n", policySetID, d.Id())
+	resp, _, err := policysetcontrollerv2.GetPolicyRule(ctx, service, policySetID, d.Id())
+	if err != nil {
+		if errResp, ok := err.(*errorx.ErrorResponse); ok && errResp.IsObjectNotFound() {
+			log.Printf("[WARN] Removing policy rule %s from state because it no longer exists in ZPA", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	v2PolicyRule := ConvertV1ResponseToV2Request(*resp)
+
+	d.SetId(resp.ID)
+	_ = d.Set("name", v2PolicyRule.Name)
+	_ = d.Set("description", v2PolicyRule.Description)
+	_ = d.Set("action", v2PolicyRule.Action)
+	_ = d.Set("policy_set_id", policySetID)
+	_ = d.Set("microtenant_id", v2PolicyRule.MicroTenantID)
+	_ = d.Set("service_edge_groups", flattenServiceEdgeGroupIDs(v2PolicyRule.ServiceEdgeGroups))
+	diags := danglingPolicyReferenceWarnings(ctx, meta, collectPolicyReferences(v2PolicyRule.Conditions, microTenantID))
+	setPolicyRuleConditionsV2(d, v2PolicyRule)
+
+	if err := readPolicyRulePlacement(ctx, d, zClient, "REDIRECTION_POLICY"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
+
+func resourcePolicyRedirectionRuleV2Update(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zClient := meta.(*Client)
+	service := zClient.Service
+
+	microTenantID := GetString(d.Get("microtenant_id"))
+	if microTenantID != "" {
+		service = service.WithMicroTenant(microTenantID)
+	}
+
+	policySetID, err := fetchPolicySetIDByType(ctx, zClient, "REDIRECTION_POLICY", microTenantID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	_ = d.Set("policy_set_id", policySetID)
+
+	ruleID := d.Id()
+	log.Printf("[INFO] Updating policy redirection rule ID: %v\n", ruleID)
+	req, err := expandPolicyRedirectionRuleV2(d, policySetID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := ValidatePolicyRuleConditions(d); err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Checking the current state of the rule to handle cases where it might have been deleted outside Terraform
+	_, _, err = policysetcontrollerv2.GetPolicyRule(ctx, service, policySetID, ruleID)
+	if err != nil {
+		if errResp, ok := err.(*errorx.ErrorResponse); ok && errResp.IsObjectNotFound() {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	if _, err := policysetcontrollerv2.UpdateRule(ctx, service, policySetID, ruleID, req); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := applyPolicyRulePlacement(ctx, d, zClient, "REDIRECTION_POLICY"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourcePolicyRedirectionRuleV2Read(withoutPrefetch(ctx), d, meta)
+}
+
+func resourcePolicyRedirectionRuleV2Delete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zClient := meta.(*Client)
+	service := zClient.Service
+
+	microTenantID := GetString(d.Get("microtenant_id"))
+	if microTenantID != "" {
+		service = service.WithMicroTenant(microTenantID)
+	}
+
+	policySetID, err := fetchPolicySetIDByType(ctx, zClient, "REDIRECTION_POLICY", microTenantID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO] Deleting policy redirection rule with id %v\n", d.Id())
+	if _, err := policysetcontrollerv2.Delete(ctx, service, policySetID, d.Id()); err != nil {
+		return diag.FromErr(err)
+	}
+	zClient.invalidatePolicyRulesPrefetch("REDIRECTION_POLICY")
+
+	return nil
+}
+
+func expandPolicyRedirectionRuleV2(d *schema.ResourceData, policySetID string) (*policysetcontrollerv2.PolicyRule, error) {
+	serviceEdgeGroups := expandCommonServiceEdgeGroups(d)
+	if err := validateRedirectionServiceEdgeGroups(d.Get("action").(string), len(serviceEdgeGroups)); err != nil {
+		return nil, err
+	}
+	conditions, err := expandPolicyRuleConditionsV2(d, "REDIRECTION_POLICY")
+	if err != nil {
+		return nil, err
+	}
+	if serviceEdgeGroups == nil {
+		// An empty list clears the service edge groups of the rule.
+		serviceEdgeGroups = []serviceedgegroup.ServiceEdgeGroup{}
+	}
+	return &policysetcontrollerv2.PolicyRule{
+		ID:                d.Get("id").(string),
+		Name:              d.Get("name").(string),
+		Description:       d.Get("description").(string),
+		Action:            d.Get("action").(string),
+		MicroTenantID:     GetString(d.Get("microtenant_id")),
+		PolicySetID:       policySetID,
+		Conditions:        conditions,
+		ServiceEdgeGroups: serviceEdgeGroups,
+	}, nil
+}
@@ -0,0 +1,185 @@
+package zpa
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/zscaler/terraform-provider-zpa/v4/zpa/common/resourcetype"
+	"github.com/zscaler/terraform-provider-zpa/v4/zpa/common/testing/method"
+	"github.com/zscaler/terraform-provider-zpa/v4/zpa/common/testing/variable"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontrollerv2"
+)
+
+func TestAccResourcePolicyRedirectionRuleV2_Basic(t *testing.T) {
+	resourceTypeAndName, _, generatedName := method.GenerateRandomSourcesTypeAndName(resourcetype.ZPAPolicyRedirectionRuleV2)
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+	updatedRName := acctest.RandomWithPrefix("tf-updated")
+	randDesc := acctest.RandString(20)
+
+	serviceEdgeGroupTypeAndName, _, serviceEdgeGroupGeneratedName := method.GenerateRandomSourcesTypeAndName(resourcetype.ZPAServiceEdgeGroup)
+	serviceEdgeGroupHCL := testAccCheckServiceEdgeGroupConfigure(serviceEdgeGroupTypeAndName, "tf-acc-test-"+serviceEdgeGroupGeneratedName, variable.ServiceEdgeDescription, variable.ServiceEdgeEnabled)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPolicyRedirectionRuleV2Destroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPolicyRedirectionRuleV2Configure(resourceTypeAndName, generatedName, rName, randDesc, "REDIRECT_PREFERRED", serviceEdgeGroupHCL, serviceEdgeGroupTypeAndName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPolicyRedirectionRuleV2Exists(resourceTypeAndName),
+					resource.TestCheckResourceAttr(resourceTypeAndName, "name", rName),
+					resource.TestCheckResourceAttr(resourceTypeAndName, "description", randDesc),
+					resource.TestCheckResourceAttr(resourceTypeAndName, "action", "REDIRECT_PREFERRED"),
+					resource.TestCheckResourceAttr(resourceTypeAndName, "service_edge_groups.#", "1"),
+					resource.TestCheckResourceAttr(resourceTypeAndName, "service_edge_groups.0.id.#", "1"),
+					resource.TestCheckResourceAttr(resourceTypeAndName, "conditions.#", "1"),
+				),
+			},
+
+			// Update test
+			{
+				Config: testAccCheckPolicyRedirectionRuleV2Configure(resourceTypeAndName, generatedName, updatedRName, randDesc, "REDIRECT_ALWAYS", serviceEdgeGroupHCL, serviceEdgeGroupTypeAndName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPolicyRedirectionRuleV2Exists(resourceTypeAndName),
+					resource.TestCheckResourceAttr(resourceTypeAndName, "name", updatedRName),
+					resource.TestCheckResourceAttr(resourceTypeAndName, "action", "REDIRECT_ALWAYS"),
+					resource.TestCheckResourceAttr(resourceTypeAndName, "service_edge_groups.#", "1"),
+					resource.TestCheckResourceAttr(resourceTypeAndName, "conditions.#", "1"),
+				),
+			},
+			// Import test
+			{
+				ResourceName:      resourceTypeAndName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Service edge groups are rejected during plan for REDIRECT_DEFAULT
+			{
+				Config:      testAccCheckPolicyRedirectionRuleV2Configure(resourceTypeAndName, generatedName, updatedRName, randDesc, "REDIRECT_DEFAULT", serviceEdgeGroupHCL, serviceEdgeGroupTypeAndName),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("must be empty when the Private Service Edge Selection Method is REDIRECT_DEFAULT"),
+			},
+		},
+	})
+}
+
+func testAccCheckPolicyRedirectionRuleV2Destroy(s *terraform.State) error {
+	apiClient := testAccProvider.Meta().(*Client)
+	redirectionPolicy, _, err := policysetcontrollerv2.GetByPolicyType(context.Background(), apiClient.Service, "REDIRECTION_POLICY")
+	if err != nil {
+		return fmt.Errorf("failed fetching resource REDIRECTION_POLICY. Received error: %s", err)
+	}
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != resourcetype.ZPAPolicyRedirectionRuleV2 {
+			continue
+		}
+
+		microTenantID := rs.Primary.Attributes["microtenant_id"]
+		service := apiClient.Service
+		if microTenantID != "" {
+			service = service.WithMicroTenant(microTenantID)
+		}
+
+		rule, _, err := policysetcontrollerv2.GetPolicyRule(context.Background(), service, redirectionPolicy.ID, rs.Primary.ID)
+
+		if err == nil {
+			return fmt.Errorf("id %s already exists", rs.Primary.ID)
+		}
+
+		if rule != nil {
+			return fmt.Errorf("policy redirection rule with id %s exists and wasn't destroyed", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckPolicyRedirectionRuleV2Exists(resource string) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		rs, ok := state.RootModule().Resources[resource]
+		if !ok {
+			return fmt.Errorf("didn't find resource: %s", resource)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("no record ID is set")
+		}
+
+		apiClient := testAccProvider.Meta().(*Client)
+		microTenantID := rs.Primary.Attributes["microtenant_id"]
+		service := apiClient.Service
+		if microTenantID != "" {
+			service = service.WithMicroTenant(microTenantID)
+		}
+
+		resp, _, err := policysetcontrollerv2.GetByPolicyType(context.Background(), apiClient.Service, "REDIRECTION_POLICY")
+		if err != nil {
+			return fmt.Errorf("failed fetching resource REDIRECTION_POLICY. Received error: %s", err)
+		}
+		_, _, err = policysetcontrollerv2.GetPolicyRule(context.Background(), service, resp.ID, rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("failed fetching resource %s. Received error: %s", resource, err)
+		}
+		return nil
+	}
+}
+
+func testAccCheckPolicyRedirectionRuleV2Configure(resourceTypeAndName, rName, generatedName, desc, action, serviceEdgeGroupHCL, serviceEdgeGroupTypeAndName string) string {
+	return fmt.Sprintf(`
+
+// service edge group resource
+%s
+
+// redirection policy rule resource
+%s
+
+data "%s" "%s" {
+  id = "${%s.id}"
+}
+`,
+		// resource variables
+		serviceEdgeGroupHCL,
+		getPolicyRedirectionRuleV2HCL(rName, generatedName, desc, action, serviceEdgeGroupTypeAndName),
+
+		// data source variables
+		resourcetype.ZPAPolicyType,
+		generatedName,
+		resourceTypeAndName,
+	)
+}
+
+func getPolicyRedirectionRuleV2HCL(rName, generatedName, desc, action, serviceEdgeGroupTypeAndName string) string {
+	return fmt.Sprintf(`
+
+resource "%s" "%s" {
+	name        = "%s"
+	description = "%s"
+	action      = "%s"
+	service_edge_groups {
+		id = ["${%s.id}"]
+	}
+	conditions {
+		operator = "OR"
+		operands {
+			object_type = "CLIENT_TYPE"
+			values      = ["zpn_client_type_branch_connector", "zpn_client_type_edge_connector"]
+		}
+	}
+	depends_on = [ %s ]
+}
+`,
+		// resource variables
+		resourcetype.ZPAPolicyRedirectionRuleV2,
+		rName,
+		generatedName,
+		desc,
+		action,
+		serviceEdgeGroupTypeAndName,
+		serviceEdgeGroupTypeAndName,
+	)
+}
@@ -62,26 +62,7 @@ func resourcePolicyAccessRule() *schema.Resource {
 						},
 					},
 				},
-				"conditions": GetPolicyConditionsSchema([]string{
-					"APP",
-					"APP_GROUP",
-					"LOCATION",
-					"IDP",
-					"SAML",
-					"SCIM",
-					"SCIM_GROUP",
-					"CLIENT_TYPE",
-					"POSTURE",
-					"TRUSTED_NETWORK",
-					"BRANCH_CONNECTOR_GROUP",
-					"EDGE_CONNECTOR_GROUP",
-					"MACHINE_GRP",
-					"COUNTRY_CODE",
-					"PLATFORM",
-					"RISK_FACTOR_TYPE",
-					"CHROME_ENTERPRISE",
-					"WORKLOAD_TAG_GROUP",
-				}),
+				"conditions": GetPolicyConditionsSchema(policyOperandObjectTypes(policyTypeAccess)),
 			},
 		),
 	}
@@ -166,11 +147,12 @@ func resourcePolicyAccessRead(ctx context.Context, d *schema.ResourceData, meta
 	_ = d.Set("priority", resp.Priority)
 	_ = d.Set("lss_default_rule", resp.LSSDefaultRule)
 	_ = d.Set("microtenant_id", microTenantID)
+	diags := danglingPolicyReferenceWarnings(ctx, meta, collectPolicyReferencesV1(resp.Conditions, microTenantID))
 	_ = d.Set("conditions", flattenPolicyConditions(resp.Conditions))
 	_ = d.Set("app_server_groups", flattenCommonAppServerGroupSimple(resp.AppServerGroups))
 	_ = d.Set("app_connector_groups", flattenCommonAppConnectorGroups(resp.AppConnectorGroups))
 
-	return nil
+	return diags
 }
 
 func resourcePolicyAccessUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
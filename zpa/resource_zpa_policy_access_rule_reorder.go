@@ -300,6 +300,7 @@ func resourcePolicyAccessReorderUpdate(ctx context.Context, d *schema.ResourceDa
 		log.Printf("[ERROR] Bulk reordering rules failed: %v", err)
 		return diag.FromErr(err)
 	}
+	zClient.invalidatePolicyRulesPrefetch(d.Get("policy_type").(string))
 
 	d.SetId(fmt.Sprintf("%s-%s", d.Get("policy_type").(string), "reorder"))
 	return resourcePolicyAccessReorderRead(ctx, d, meta)
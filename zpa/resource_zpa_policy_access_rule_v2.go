@@ -20,6 +20,7 @@ func resourcePolicyAccessRuleV2() *schema.Resource {
 		ReadContext:   resourcePolicyAccessV2Read,
 		UpdateContext: resourcePolicyAccessV2Update,
 		DeleteContext: resourcePolicyAccessV2Delete,
+		CustomizeDiff: withPolicyRuleExpiry(customizePolicyRuleV2Diff("ACCESS_POLICY")),
 		Importer: &schema.ResourceImporter{
 			StateContext: importPolicyStateContextFuncV2([]string{"ACCESS_POLICY", "GLOBAL_POLICY"}),
 		},
@@ -34,11 +35,24 @@ func resourcePolicyAccessRuleV2() *schema.Resource {
 				Required:    true,
 				Description: "This is the name of the policy.",
 			},
+			"place_before_rule_id":   policyRulePlacementSchema("place_before_rule_id"),
+			"place_after_rule_id":    policyRulePlacementSchema("place_after_rule_id"),
+			"place_before_rule_name": policyRulePlacementSchema("place_before_rule_name"),
+			"place_after_rule_name":  policyRulePlacementSchema("place_after_rule_name"),
+			"placement_neighbor":     policyRulePlacementNeighborSchema(),
+			"priority": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Rules created in the same apply are created in ascending priority, so that they get ascending rule orders. Only used by the provider when the rule is created.",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
 			"description": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Description: "This is the description of the access policy.",
 			},
+			"expires_at": policyRuleExpiresAtSchema(),
+			"expired":    policyRuleExpiredSchema(),
 			"action": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -68,6 +82,10 @@ func resourcePolicyAccessRuleV2() *schema.Resource {
 				Computed:    true,
 				Description: "This is for providing a customer message for the user.",
 			},
+			"condition_expression":     conditionExpressionSchema("ACCESS_POLICY"),
+			"resolved_names":           policyOperandNamesSchema(),
+			"expanded_country_aliases": expandedCountryAliasesSchema(),
+			"pruned_references":        prunedReferencesSchema(),
 			"conditions": {
 				Type:     schema.TypeSet,
 				Optional: true,
@@ -108,28 +126,8 @@ func resourcePolicyAccessRuleV2() *schema.Resource {
 										Type:     schema.TypeString,
 										Optional: true,
 										// Computed:    true,
-										Description: "  This is for specifying the policy critiera.",
-										ValidateFunc: validation.StringInSlice([]string{
-											"APP",
-											"APP_GROUP",
-											"LOCATION",
-											"IDP",
-											"SAML",
-											"SCIM",
-											"SCIM_GROUP",
-											"CLIENT_TYPE",
-											"POSTURE",
-											"TRUSTED_NETWORK",
-											"BRANCH_CONNECTOR_GROUP",
-											"EDGE_CONNECTOR_GROUP",
-											"MACHINE_GRP",
-											"COUNTRY_CODE",
-											"PLATFORM",
-											"RISK_FACTOR_TYPE",
-											"CHROME_ENTERPRISE",
-											"CHROME_POSTURE_PROFILE",
-											"WORKLOAD_TAG_GROUP",
-										}, false),
+										Description:  "  This is for specifying the policy critiera.",
+										ValidateFunc: validation.StringInSlice(policyOperandObjectTypes(policyTypeAccess), false),
 									},
 									"entry_values": {
 										Type:     schema.TypeSet,
@@ -266,13 +264,23 @@ func resourcePolicyAccessV2Create(ctx context.Context, d *schema.ResourceData, m
 		return diag.FromErr(err)
 	}
 
-	policysetcontrollerv2, _, err := policysetcontrollerv2.CreateRule(ctx, service, req)
+	err = zClient.createPolicyRule(ctx, service, "ACCESS_POLICY", microTenantID, policyRuleCreationPriority(d), func() (string, error) {
+		resp, _, err := policysetcontrollerv2.CreateRule(ctx, service, req)
+		if err != nil {
+			return "", err
+		}
+		d.SetId(resp.ID)
+		return resp.ID, nil
+	})
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	d.SetId(policysetcontrollerv2.ID)
 
-	return resourcePolicyAccessV2Read(ctx, d, meta)
+	if err := applyPolicyRulePlacement(ctx, d, zClient, "ACCESS_POLICY"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourcePolicyAccessV2Read(withoutPrefetch(ctx), d, meta)
 }
 
 func resourcePolicyAccessV2Read(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -293,6 +301,9 @@ func resourcePolicyAccessV2Read(ctx context.Context, d *schema.ResourceData, met
 	resp, _, err := policysetcontrollerv2.GetPolicyRule(ctx, service, policySetID, d.Id())
 	if err != nil {
 		if errResp, ok := err.(*errorx.ErrorResponse); ok && errResp.IsObjectNotFound() {
+			if keepExpiredPolicyRule(d, meta) {
+				return nil
+			}
 			log.Printf("[WARN] Removing policy rule %s from state because it no longer exists in ZPA", d.Id())
 			d.SetId("")
 			return nil
@@ -306,18 +317,23 @@ func resourcePolicyAccessV2Read(ctx context.Context, d *schema.ResourceData, met
 	log.Printf("[INFO] Got Policy Set Rule:\n%+v\n", resp)
 	d.SetId(resp.ID)
 	_ = d.Set("name", v2PolicyRule.Name)
-	_ = d.Set("description", v2PolicyRule.Description)
+	diags := readPolicyRuleExpiry(d, meta, v2PolicyRule.Description, resp.Disabled)
 	_ = d.Set("action", v2PolicyRule.Action)
 	_ = d.Set("operator", v2PolicyRule.Operator)
 	_ = d.Set("policy_set_id", policySetID)
 	_ = d.Set("custom_msg", v2PolicyRule.CustomMsg)
 	_ = d.Set("extranet_enabled", resp.ExtranetEnabled)
-	_ = d.Set("conditions", flattenConditionsV2(v2PolicyRule.Conditions))
+	diags = append(diags, danglingPolicyReferenceWarnings(ctx, meta, collectPolicyReferences(v2PolicyRule.Conditions, microTenantID))...)
+	setPolicyRuleConditionsV2(d, v2PolicyRule)
 	_ = d.Set("app_server_groups", flattenCommonAppServerGroupSimple(resp.AppServerGroups))
 	_ = d.Set("app_connector_groups", flattenCommonAppConnectorGroups(resp.AppConnectorGroups))
 	_ = d.Set("extranet_dto", flattenExtranetDTO(&resp.ExtranetDTO))
 
-	return nil
+	if err := readPolicyRulePlacement(ctx, d, zClient, "ACCESS_POLICY"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
 }
 
 func resourcePolicyAccessV2Update(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -353,6 +369,19 @@ func resourcePolicyAccessV2Update(ctx context.Context, d *schema.ResourceData, m
 		return diag.FromErr(err)
 	}
 
+	if d.Get("expired").(bool) {
+		deleted, err := expirePolicyRule(ctx, service, meta, policySetID, ruleID, req)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if deleted {
+			zClient.invalidatePolicyRulesPrefetch("ACCESS_POLICY")
+			return nil
+		}
+	} else {
+		reenablePolicyRule(d, req)
+	}
+
 	_, _, err = policysetcontrollerv2.GetPolicyRule(ctx, service, policySetID, ruleID)
 	if err != nil {
 		if errResp, ok := err.(*errorx.ErrorResponse); ok && errResp.IsObjectNotFound() {
@@ -366,7 +395,11 @@ func resourcePolicyAccessV2Update(ctx context.Context, d *schema.ResourceData, m
 		return diag.FromErr(err)
 	}
 
-	return resourcePolicyAccessV2Read(ctx, d, meta)
+	if err := applyPolicyRulePlacement(ctx, d, zClient, "ACCESS_POLICY"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourcePolicyAccessV2Read(withoutPrefetch(ctx), d, meta)
 }
 
 func resourcePolicyAccessV2Delete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -390,22 +423,23 @@ func resourcePolicyAccessV2Delete(ctx context.Context, d *schema.ResourceData, m
 
 	log.Printf("[INFO] Deleting access policy set rule with id %v\n", d.Id())
 
-	if _, err := policysetcontrollerv2.Delete(ctx, service, policySetID, d.Id()); err != nil {
+	if _, err := policysetcontrollerv2.Delete(ctx, service, policySetID, d.Id()); ignoreExpiredPolicyRuleNotFound(d, err) != nil {
 		return diag.FromErr(err)
 	}
+	zClient.invalidatePolicyRulesPrefetch("ACCESS_POLICY")
 
 	return nil
 }
 
 func expandCreatePolicyRuleV2(d *schema.ResourceData, policySetID string) (*policysetcontrollerv2.PolicyRule, error) {
-	conditions, err := ExpandPolicyConditionsV2(d)
+	conditions, err := expandPolicyRuleConditionsV2(d, "ACCESS_POLICY")
 	if err != nil {
 		return nil, err
 	}
 	rule := &policysetcontrollerv2.PolicyRule{
 		ID:              d.Get("id").(string),
 		Name:            d.Get("name").(string),
-		Description:     d.Get("description").(string),
+		Description:     joinPolicyRuleExpiry(d.Get("description").(string), d.Get("expires_at").(string)),
 		Action:          d.Get("action").(string),
 		CustomMsg:       d.Get("custom_msg").(string),
 		Operator:        d.Get("operator").(string),
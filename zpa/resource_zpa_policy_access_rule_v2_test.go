@@ -3,15 +3,20 @@ package zpa
 import (
 	"context"
 	"fmt"
+	"os"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/zscaler/terraform-provider-zpa/v4/zpa/common/resourcetype"
 	"github.com/zscaler/terraform-provider-zpa/v4/zpa/common/testing/method"
 	"github.com/zscaler/terraform-provider-zpa/v4/zpa/common/testing/variable"
 	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontrollerv2"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/segmentgroup"
 )
 
 func TestAccResourcePolicyAccessRuleV2_Basic(t *testing.T) {
@@ -281,3 +286,398 @@ resource "%s" "%s" {
 		segmentGroupTypeAndName,
 	)
 }
+
+func TestAccResourcePolicyAccessRuleV2_Placement(t *testing.T) {
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPolicyAccessRuleV2Destroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPolicyAccessRuleV2PlacementConfigure(rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPolicyAccessRuleV2Exists(resourcetype.ZPAPolicyAccessRuleV2+".anchor"),
+					testAccCheckPolicyAccessRuleV2Exists(resourcetype.ZPAPolicyAccessRuleV2+".placed"),
+					resource.TestCheckResourceAttrPair(
+						resourcetype.ZPAPolicyAccessRuleV2+".placed", "place_after_rule_id",
+						resourcetype.ZPAPolicyAccessRuleV2+".anchor", "id",
+					),
+				),
+			},
+			// No drift once the rule is in place
+			{
+				Config:   testAccCheckPolicyAccessRuleV2PlacementConfigure(rName),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func testAccCheckPolicyAccessRuleV2PlacementConfigure(rName string) string {
+	return fmt.Sprintf(`
+resource "%[1]s" "anchor" {
+  name   = "%[2]s-anchor"
+  action = "DENY"
+  conditions {
+    operator = "OR"
+    operands {
+      object_type = "CLIENT_TYPE"
+      values      = ["zpn_client_type_exporter"]
+    }
+  }
+}
+
+resource "%[1]s" "placed" {
+  name                = "%[2]s-placed"
+  action              = "ALLOW"
+  place_after_rule_id = %[1]s.anchor.id
+  conditions {
+    operator = "OR"
+    operands {
+      object_type = "CLIENT_TYPE"
+      values      = ["zpn_client_type_exporter"]
+    }
+  }
+}
+`, resourcetype.ZPAPolicyAccessRuleV2, rName)
+}
+
+func TestAccResourcePolicyAccessRuleV2_ConditionExpression(t *testing.T) {
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+	resourceName := resourcetype.ZPAPolicyAccessRuleV2 + ".expression"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPolicyAccessRuleV2Destroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPolicyAccessRuleV2ConditionExpressionConfigure(rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPolicyAccessRuleV2Exists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "operator", "AND"),
+					resource.TestCheckResourceAttr(resourceName, "condition_expression", `(CLIENT_TYPE in ["zpn_client_type_exporter", "zpn_client_type_zapp"] or PLATFORM == "windows") and COUNTRY_CODE in ["CA", "US"]`),
+					resource.TestCheckResourceAttr(resourceName, "conditions.#", "0"),
+				),
+			},
+			// The canonical expression read back does not produce a diff
+			{
+				Config:   testAccCheckPolicyAccessRuleV2ConditionExpressionConfigure(rName),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func testAccCheckPolicyAccessRuleV2ConditionExpressionConfigure(rName string) string {
+	return fmt.Sprintf(`
+resource "%s" "expression" {
+  name                 = "%s"
+  action               = "ALLOW"
+  condition_expression = "COUNTRY_CODE in [\"US\", \"CA\"] and (CLIENT_TYPE == \"zpn_client_type_zapp\" or CLIENT_TYPE == \"zpn_client_type_exporter\" or PLATFORM == \"windows\")"
+}
+`, resourcetype.ZPAPolicyAccessRuleV2, rName)
+}
+
+func TestAccResourcePolicyAccessRuleV2_NameReferences(t *testing.T) {
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+	resourceName := resourcetype.ZPAPolicyAccessRuleV2 + ".names"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPolicyAccessRuleV2Destroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPolicyAccessRuleV2NameReferencesConfigure(rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPolicyAccessRuleV2Exists(resourceName),
+					resource.TestCheckResourceAttrPair(
+						resourceName, "resolved_names.APP_GROUP.values:"+rName,
+						resourcetype.ZPASegmentGroup+".names", "id",
+					),
+				),
+			},
+			// The IDs read back are mapped to the names of the configuration
+			{
+				Config:   testAccCheckPolicyAccessRuleV2NameReferencesConfigure(rName),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func testAccCheckPolicyAccessRuleV2NameReferencesConfigure(rName string) string {
+	return fmt.Sprintf(`
+resource "%[1]s" "names" {
+  name    = "%[3]s"
+  enabled = true
+}
+
+resource "%[2]s" "names" {
+  name   = "%[3]s"
+  action = "ALLOW"
+  conditions {
+    operator = "OR"
+    operands {
+      object_type = "APP_GROUP"
+      values      = ["name:${%[1]s.names.name}"]
+    }
+  }
+}
+`, resourcetype.ZPASegmentGroup, resourcetype.ZPAPolicyAccessRuleV2, rName)
+}
+
+func TestAccResourcePolicyAccessRuleV2_ExpiresAt(t *testing.T) {
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+	resourceName := resourcetype.ZPAPolicyAccessRuleV2 + ".expiring"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPolicyAccessRuleV2Destroy,
+		Steps: []resource.TestStep{
+			// A rule cannot be created already expired
+			{
+				Config:      testAccCheckPolicyAccessRuleV2ExpiresAtConfigure(rName, "2020-01-01T00:00:00Z"),
+				ExpectError: regexp.MustCompile("expires_at 2020-01-01T00:00:00Z is in the past"),
+			},
+			{
+				Config: testAccCheckPolicyAccessRuleV2ExpiresAtConfigure(rName, "2099-01-01T00:00:00Z"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPolicyAccessRuleV2Exists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "description", "temporary vendor access"),
+					resource.TestCheckResourceAttr(resourceName, "expires_at", "2099-01-01T00:00:00Z"),
+					resource.TestCheckResourceAttr(resourceName, "expired", "false"),
+				),
+			},
+			// The expiry moved into the past disables the rule
+			{
+				Config: testAccCheckPolicyAccessRuleV2ExpiresAtConfigure(rName, "2020-01-01T00:00:00Z"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPolicyAccessRuleV2Exists(resourceName),
+					testAccCheckPolicyAccessRuleV2Disabled(resourceName, true),
+					resource.TestCheckResourceAttr(resourceName, "expired", "true"),
+				),
+			},
+			{
+				Config:   testAccCheckPolicyAccessRuleV2ExpiresAtConfigure(rName, "2020-01-01T00:00:00Z"),
+				PlanOnly: true,
+			},
+			// The expiry moved back into the future enables the rule again
+			{
+				Config: testAccCheckPolicyAccessRuleV2ExpiresAtConfigure(rName, "2099-01-01T00:00:00Z"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPolicyAccessRuleV2Disabled(resourceName, false),
+					resource.TestCheckResourceAttr(resourceName, "description", "temporary vendor access"),
+					resource.TestCheckResourceAttr(resourceName, "expires_at", "2099-01-01T00:00:00Z"),
+					resource.TestCheckResourceAttr(resourceName, "expired", "false"),
+				),
+			},
+		},
+	})
+}
+
+// testAccCheckPolicyAccessRuleV2Disabled checks whether the rule is disabled
+// in ZPA.
+func testAccCheckPolicyAccessRuleV2Disabled(resource string, disabled bool) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		rs, ok := state.RootModule().Resources[resource]
+		if !ok {
+			return fmt.Errorf("didn't find resource: %s", resource)
+		}
+		apiClient := testAccProvider.Meta().(*Client)
+		rule, _, err := policysetcontrollerv2.GetPolicyRule(context.Background(), apiClient.Service, rs.Primary.Attributes["policy_set_id"], rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("failed fetching resource %s. Recevied error: %s", resource, err)
+		}
+		if (rule.Disabled == "1") != disabled {
+			return fmt.Errorf("rule %s has disabled = %q, want disabled %t", rs.Primary.ID, rule.Disabled, disabled)
+		}
+		return nil
+	}
+}
+
+func testAccCheckPolicyAccessRuleV2ExpiresAtConfigure(rName, expiresAt string) string {
+	return fmt.Sprintf(`
+resource "%s" "expiring" {
+  name        = "%s"
+  description = "temporary vendor access"
+  action      = "ALLOW"
+  expires_at  = "%s"
+  conditions {
+    operator = "OR"
+    operands {
+      object_type = "CLIENT_TYPE"
+      values      = ["zpn_client_type_exporter"]
+    }
+  }
+}
+`, resourcetype.ZPAPolicyAccessRuleV2, rName, expiresAt)
+}
+
+func TestAccResourcePolicyAccessRuleV2_CountryAliases(t *testing.T) {
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+	resourceName := resourcetype.ZPAPolicyAccessRuleV2 + ".geo"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPolicyAccessRuleV2Destroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCheckPolicyAccessRuleV2CountryAliasesConfigure(rName, `"@NOPE"`),
+				ExpectError: regexp.MustCompile("unknown country alias @NOPE"),
+			},
+			{
+				Config:      testAccCheckPolicyAccessRuleV2CountryAliasesConfigure(rName, `"@EU", "FR"`),
+				ExpectError: regexp.MustCompile("FR is already part of @EU"),
+			},
+			{
+				Config: testAccCheckPolicyAccessRuleV2CountryAliasesConfigure(rName, `"@OFAC_SANCTIONED", "RU"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPolicyAccessRuleV2Exists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "expanded_country_aliases.%", "1"),
+					resource.TestCheckResourceAttr(resourceName, "expanded_country_aliases.@OFAC_SANCTIONED", "CU,IR,KP,SY"),
+					resource.TestCheckResourceAttr(resourceName, "conditions.#", "1"),
+				),
+			},
+			// The countries read back are collapsed into the alias
+			{
+				Config:   testAccCheckPolicyAccessRuleV2CountryAliasesConfigure(rName, `"@OFAC_SANCTIONED", "RU"`),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func testAccCheckPolicyAccessRuleV2CountryAliasesConfigure(rName, countries string) string {
+	return fmt.Sprintf(`
+resource "%s" "geo" {
+  name   = "%s"
+  action = "DENY"
+  conditions {
+    operator = "OR"
+    operands {
+      object_type = "COUNTRY_CODE"
+      dynamic "entry_values" {
+        for_each = [%s]
+        content {
+          lhs = entry_values.value
+          rhs = "true"
+        }
+      }
+    }
+  }
+}
+`, resourcetype.ZPAPolicyAccessRuleV2, rName, countries)
+}
+
+func TestAccResourcePolicyAccessRuleV2_PruneDanglingReferences(t *testing.T) {
+	t.Setenv("ZSCALER_PRUNE_DANGLING_REFERENCES", "true")
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+	resourceName := resourcetype.ZPAPolicyAccessRuleV2 + ".prune"
+	goneName := resourcetype.ZPASegmentGroup + ".gone"
+
+	// The ID of the segment group deleted outside Terraform, passed to the
+	// configuration once its resource is removed.
+	var goneID string
+	t.Setenv("TF_VAR_gone_segment_group_id", "")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPolicyAccessRuleV2Destroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPolicyAccessRuleV2PruneConfigure(rName, true, ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPolicyAccessRuleV2Exists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "pruned_references.#", "0"),
+					func(s *terraform.State) error {
+						rs, ok := s.RootModule().Resources[goneName]
+						if !ok {
+							return fmt.Errorf("not found: %s", goneName)
+						}
+						goneID = rs.Primary.ID
+						return os.Setenv("TF_VAR_gone_segment_group_id", goneID)
+					},
+				),
+			},
+			// The segment group is deleted outside Terraform, its value is
+			// pruned from the rule instead of failing the plan.
+			{
+				PreConfig: func() {
+					service := testAccProvider.Meta().(*Client).Service
+					if _, err := segmentgroup.Delete(context.Background(), service, goneID); err != nil {
+						t.Fatalf("failed to delete segment group %s: %v", goneID, err)
+					}
+				},
+				Config: testAccCheckPolicyAccessRuleV2PruneConfigure(rName, false, ""),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPolicyAccessRuleV2Exists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "pruned_references.#", "1"),
+					func(s *terraform.State) error {
+						return resource.TestCheckTypeSetElemAttr(resourceName, "pruned_references.*", "APP_GROUP.values:"+goneID)(s)
+					},
+					func(s *terraform.State) error {
+						conditions := []policysetcontrollerv2.PolicyRuleResourceConditions{{
+							Operands: []policysetcontrollerv2.PolicyRuleResourceOperands{{ObjectType: "APP_GROUP", Values: []string{goneID}}},
+						}}
+						diags := danglingPolicyReferenceWarnings(context.Background(), testAccProvider.Meta(), collectPolicyReferences(conditions, ""))
+						if len(diags) != 1 || diags[0].Severity != diag.Warning || !strings.Contains(diags[0].Detail, goneID) {
+							return fmt.Errorf("expected a warning about segment group %s, got %+v", goneID, diags)
+						}
+						return nil
+					},
+				),
+			},
+			// Values added to the configuration are never pruned
+			{
+				Config:      testAccCheckPolicyAccessRuleV2PruneConfigure(rName, false, "72058304855015574"),
+				ExpectError: regexp.MustCompile("does not reference an existing object"),
+			},
+		},
+	})
+}
+
+// testAccCheckPolicyAccessRuleV2PruneConfigure returns a rule referencing two
+// segment groups. Without withGone, the second segment group is referenced by
+// the ID in the gone_segment_group_id variable instead of its resource.
+func testAccCheckPolicyAccessRuleV2PruneConfigure(rName string, withGone bool, extra string) string {
+	gone := `
+variable "gone_segment_group_id" {
+  type = string
+}
+`
+	goneID := "var.gone_segment_group_id"
+	if withGone {
+		gone = fmt.Sprintf(`
+resource "%s" "gone" {
+  name    = "%s-gone"
+  enabled = true
+}
+`, resourcetype.ZPASegmentGroup, rName)
+		goneID = resourcetype.ZPASegmentGroup + ".gone.id"
+	}
+	return fmt.Sprintf(`
+resource "%[1]s" "prune" {
+  name    = "%[3]s"
+  enabled = true
+}
+%[5]s
+resource "%[2]s" "prune" {
+  name   = "%[3]s"
+  action = "ALLOW"
+  conditions {
+    operator = "OR"
+    operands {
+      object_type = "APP_GROUP"
+      values      = compact([%[1]s.prune.id, %[6]s, %[4]q])
+    }
+  }
+}
+`, resourcetype.ZPASegmentGroup, resourcetype.ZPAPolicyAccessRuleV2, rName, extra, gone, goneID)
+}
@@ -32,17 +32,7 @@ func resourcePolicyTimeoutRule() *schema.Resource {
 						"RE_AUTH",
 					}, false),
 				},
-				"conditions": GetPolicyConditionsSchema([]string{
-					"APP",
-					"APP_GROUP",
-					"CLIENT_TYPE",
-					"IDP",
-					"POSTURE",
-					"PLATFORM",
-					"SAML",
-					"SCIM",
-					"SCIM_GROUP",
-				}),
+				"conditions": GetPolicyConditionsSchema(policyOperandObjectTypes(policyTypeTimeout)),
 			},
 		),
 	}
@@ -127,9 +117,10 @@ func resourcePolicyTimeoutRuleRead(ctx context.Context, d *schema.ResourceData,
 	_ = d.Set("reauth_idle_timeout", resp.ReauthIdleTimeout)
 	_ = d.Set("reauth_timeout", resp.ReauthTimeout)
 	_ = d.Set("microtenant_id", resp.MicroTenantID)
+	diags := danglingPolicyReferenceWarnings(ctx, meta, collectPolicyReferencesV1(resp.Conditions, microTenantID))
 	_ = d.Set("conditions", flattenPolicyConditions(resp.Conditions))
 
-	return nil
+	return diags
 }
 
 func resourcePolicyTimeoutRuleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
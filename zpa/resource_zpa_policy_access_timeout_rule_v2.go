@@ -20,6 +20,7 @@ func resourcePolicyTimeoutRuleV2() *schema.Resource {
 		ReadContext:   resourcePolicyTimeoutRuleV2Read,
 		UpdateContext: resourcePolicyTimeoutRuleV2Update,
 		DeleteContext: resourcePolicyTimeoutRuleV2Delete,
+		CustomizeDiff: customizePolicyRuleV2Diff("TIMEOUT_POLICY"),
 		Importer: &schema.ResourceImporter{
 			StateContext: importPolicyStateContextFuncV2([]string{"TIMEOUT_POLICY", "REAUTH_POLICY"}),
 		},
@@ -34,6 +35,17 @@ func resourcePolicyTimeoutRuleV2() *schema.Resource {
 				Required:    true,
 				Description: "This is the name of the policy.",
 			},
+			"place_before_rule_id":   policyRulePlacementSchema("place_before_rule_id"),
+			"place_after_rule_id":    policyRulePlacementSchema("place_after_rule_id"),
+			"place_before_rule_name": policyRulePlacementSchema("place_before_rule_name"),
+			"place_after_rule_name":  policyRulePlacementSchema("place_after_rule_name"),
+			"placement_neighbor":     policyRulePlacementNeighborSchema(),
+			"priority": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Rules created in the same apply are created in ascending priority, so that they get ascending rule orders. Only used by the provider when the rule is created.",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
 			"description": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -64,6 +76,9 @@ func resourcePolicyTimeoutRuleV2() *schema.Resource {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
+			"condition_expression": conditionExpressionSchema("TIMEOUT_POLICY"),
+			"resolved_names":       policyOperandNamesSchema(),
+			"pruned_references":    prunedReferencesSchema(),
 			"conditions": {
 				Type:        schema.TypeSet,
 				Optional:    true,
@@ -100,21 +115,11 @@ func resourcePolicyTimeoutRuleV2() *schema.Resource {
 										},
 									},
 									"object_type": {
-										Type:        schema.TypeString,
-										Optional:    true,
-										Computed:    true,
-										Description: "  This is for specifying the policy critiera.",
-										ValidateFunc: validation.StringInSlice([]string{
-											"APP",
-											"APP_GROUP",
-											"CLIENT_TYPE",
-											"IDP",
-											"POSTURE",
-											"PLATFORM",
-											"SAML",
-											"SCIM",
-											"SCIM_GROUP",
-										}, false),
+										Type:         schema.TypeString,
+										Optional:     true,
+										Computed:     true,
+										Description:  "  This is for specifying the policy critiera.",
+										ValidateFunc: validation.StringInSlice(policyOperandObjectTypes(policyTypeTimeout), false),
 									},
 									"entry_values": {
 										Type:     schema.TypeSet,
@@ -190,13 +195,23 @@ func resourcePolicyTimeoutRuleV2Create(ctx context.Context, d *schema.ResourceDa
 	}
 	log.Printf("[INFO] Creating zpa policy timeout rule with request\n%+v\n", req)
 
-	policysetcontrollerv2, _, err := policysetcontrollerv2.CreateRule(ctx, service, req)
+	err = zClient.createPolicyRule(ctx, service, "TIMEOUT_POLICY", microTenantID, policyRuleCreationPriority(d), func() (string, error) {
+		resp, _, err := policysetcontrollerv2.CreateRule(ctx, service, req)
+		if err != nil {
+			return "", err
+		}
+		d.SetId(resp.ID)
+		return resp.ID, nil
+	})
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	d.SetId(policysetcontrollerv2.ID)
 
-	return resourcePolicyTimeoutRuleV2Read(ctx, d, meta)
+	if err := applyPolicyRulePlacement(ctx, d, zClient, "TIMEOUT_POLICY"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourcePolicyTimeoutRuleV2Read(withoutPrefetch(ctx), d, meta)
 }
 
 func resourcePolicyTimeoutRuleV2Read(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -245,9 +260,14 @@ func resourcePolicyTimeoutRuleV2Read(ctx context.Context, d *schema.ResourceData
 	}
 
 	_ = d.Set("microtenant_id", v2PolicyRule.MicroTenantID)
-	_ = d.Set("conditions", flattenConditionsV2(v2PolicyRule.Conditions))
+	diags := danglingPolicyReferenceWarnings(ctx, meta, collectPolicyReferences(v2PolicyRule.Conditions, microTenantID))
+	setPolicyRuleConditionsV2(d, v2PolicyRule)
 
-	return nil
+	if err := readPolicyRulePlacement(ctx, d, zClient, "TIMEOUT_POLICY"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
 }
 
 func resourcePolicyTimeoutRuleV2Update(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -306,7 +326,11 @@ func resourcePolicyTimeoutRuleV2Update(ctx context.Context, d *schema.ResourceDa
 		return diag.FromErr(err)
 	}
 
-	return resourcePolicyTimeoutRuleV2Read(ctx, d, meta)
+	if err := applyPolicyRulePlacement(ctx, d, zClient, "TIMEOUT_POLICY"); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourcePolicyTimeoutRuleV2Read(withoutPrefetch(ctx), d, meta)
 }
 
 func resourcePolicyTimeoutRuleV2Delete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -329,12 +353,13 @@ func resourcePolicyTimeoutRuleV2Delete(ctx context.Context, d *schema.ResourceDa
 	if _, err := policysetcontrollerv2.Delete(ctx, service, policySetID, d.Id()); err != nil {
 		return diag.FromErr(fmt.Errorf("failed to delete policy timeout rule: %w", err))
 	}
+	zClient.invalidatePolicyRulesPrefetch("TIMEOUT_POLICY")
 
 	return nil
 }
 
 func expandTimeOutPolicyRule(d *schema.ResourceData, policySetID string) (*policysetcontrollerv2.PolicyRule, error) {
-	conditions, err := ExpandPolicyConditionsV2(d)
+	conditions, err := expandPolicyRuleConditionsV2(d, "TIMEOUT_POLICY")
 	if err != nil {
 		return nil, err
 	}
@@ -3,6 +3,7 @@ package zpa
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
@@ -60,6 +61,51 @@ func TestAccResourcePolicyTimeoutRuleV2_Basic(t *testing.T) {
 	})
 }
 
+// Timeout rules have no rule operator: the top-level "or" of the expression
+// becomes a single condition.
+func TestAccResourcePolicyTimeoutRuleV2_ConditionExpression(t *testing.T) {
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+	resourceName := resourcetype.ZPAPolicyTimeOutRuleV2 + ".expression"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPolicyTimeoutRuleV2Destroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPolicyTimeoutRuleV2ConditionExpressionConfigure(rName, `CLIENT_TYPE == \"zpn_client_type_zapp\" or PLATFORM == \"windows\"`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPolicyTimeoutRuleV2Exists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "condition_expression", `CLIENT_TYPE == "zpn_client_type_zapp" or PLATFORM == "windows"`),
+					resource.TestCheckResourceAttr(resourceName, "conditions.#", "0"),
+				),
+			},
+			// The canonical expression read back does not produce a diff
+			{
+				Config:   testAccCheckPolicyTimeoutRuleV2ConditionExpressionConfigure(rName, `CLIENT_TYPE == \"zpn_client_type_zapp\" or PLATFORM == \"windows\"`),
+				PlanOnly: true,
+			},
+			// An "or" between conditions cannot be sent without a rule operator
+			{
+				Config:      testAccCheckPolicyTimeoutRuleV2ConditionExpressionConfigure(rName, `(CLIENT_TYPE == \"zpn_client_type_zapp\" and PLATFORM == \"windows\") or COUNTRY_CODE == \"US\"`),
+				ExpectError: regexp.MustCompile(`a top-level "or" can only join comparisons`),
+			},
+		},
+	})
+}
+
+func testAccCheckPolicyTimeoutRuleV2ConditionExpressionConfigure(rName, expression string) string {
+	return fmt.Sprintf(`
+resource "%s" "expression" {
+  name                 = "%s"
+  action               = "RE_AUTH"
+  reauth_idle_timeout  = "10 Days"
+  reauth_timeout       = "10 Days"
+  condition_expression = "%s"
+}
+`, resourcetype.ZPAPolicyTimeOutRuleV2, rName, expression)
+}
+
 func testAccCheckPolicyTimeoutRuleV2Destroy(s *terraform.State) error {
 	apiClient := testAccProvider.Meta().(*Client)
 	accessPolicy, _, err := policysetcontrollerv2.GetByPolicyType(context.Background(), apiClient.Service, "TIMEOUT_POLICY")
@@ -264,11 +264,12 @@ func resourcePolicyCapabilitiesAccessRuleRead(ctx context.Context, d *schema.Res
 	_ = d.Set("action", v2PolicyRule.Action)
 	_ = d.Set("microtenant_id", v2PolicyRule.MicroTenantID)
 	_ = d.Set("policy_set_id", policySetID) // Here, you're setting it based on fetched ID
+	diags := danglingPolicyReferenceWarnings(ctx, meta, collectPolicyReferences(v2PolicyRule.Conditions, microTenantID))
 	_ = d.Set("conditions", flattenConditionsV2(v2PolicyRule.Conditions))
 	if len(resp.PrivilegedCapabilities.Capabilities) > 0 {
 		_ = d.Set("privileged_capabilities", flattenPrivilegedCapabilities(resp.PrivilegedCapabilities))
 	}
-	return nil
+	return diags
 }
 
 func resourcePolicyCapabilitiesAccessRuleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
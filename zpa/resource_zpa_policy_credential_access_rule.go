@@ -226,6 +226,7 @@ func resourcePolicyCredentialAccessRuleRead(ctx context.Context, d *schema.Resou
 	_ = d.Set("action", v2PolicyRule.Action)
 	_ = d.Set("policy_set_id", policySetID)
 	_ = d.Set("microtenant_id", v2PolicyRule.MicroTenantID)
+	diags := danglingPolicyReferenceWarnings(ctx, meta, collectPolicyReferences(v2PolicyRule.Conditions, microTenantID))
 	_ = d.Set("conditions", flattenConditionsV2(v2PolicyRule.Conditions))
 	_ = d.Set("credential", flattenCredential(resp.Credential))
 	_ = d.Set("credential_pool", flattenCredential(resp.CredentialPool))
@@ -239,7 +240,7 @@ func resourcePolicyCredentialAccessRuleRead(ctx context.Context, d *schema.Resou
 		_ = d.Set("microtenant_id", "")
 	}
 
-	return nil
+	return diags
 }
 
 func resourcePolicyCredentialAccessRuleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
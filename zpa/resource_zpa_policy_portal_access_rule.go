@@ -238,11 +238,12 @@ func resourcePolicyPortalAccessRuleRuleRead(ctx context.Context, d *schema.Resou
 	_ = d.Set("action", v2PolicyRule.Action)
 	_ = d.Set("microtenant_id", v2PolicyRule.MicroTenantID)
 	_ = d.Set("policy_set_id", policySetID) // Here, you're setting it based on fetched ID
+	diags := danglingPolicyReferenceWarnings(ctx, meta, collectPolicyReferences(v2PolicyRule.Conditions, microTenantID))
 	_ = d.Set("conditions", flattenConditionsV2(v2PolicyRule.Conditions))
 	if len(resp.PrivilegedCapabilities.Capabilities) > 0 {
 		_ = d.Set("privileged_portal_capabilities", flattenPrivilegedPortalCapabilities(resp.PrivilegedPortalCapabilities))
 	}
-	return nil
+	return diags
 }
 
 func resourcePolicyPortalAccessRuleRuleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -0,0 +1,266 @@
+package zpa
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/errorx"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontrollerv2"
+)
+
+func resourcePolicyRuleJSON() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourcePolicyRuleJSONCreate,
+		ReadContext:   resourcePolicyRuleJSONRead,
+		UpdateContext: resourcePolicyRuleJSONUpdate,
+		DeleteContext: resourcePolicyRuleJSONDelete,
+		CustomizeDiff: resourcePolicyRuleJSONCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourcePolicyRuleJSONImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"policy_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(conditionPolicyTypes, false),
+			},
+			"microtenant_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"rule_json": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The rule as JSON, in the format of the rules returned by the API. Attributes set by the API, such as IDs and timestamps, are ignored, unknown attributes are rejected.",
+				ValidateFunc: func(v interface{}, key string) (warnings []string, errs []error) {
+					if _, err := parsePolicyRuleJSON(v.(string)); err != nil {
+						errs = append(errs, fmt.Errorf("%s: %v", key, err))
+					}
+					return
+				},
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					if old == "" {
+						return false
+					}
+					oldCanonical, err := canonicalPolicyRuleJSON(old)
+					if err != nil {
+						return false
+					}
+					newCanonical, err := canonicalPolicyRuleJSON(new)
+					return err == nil && oldCanonical == newCanonical
+				},
+			},
+			"priority": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Description:  "Rules created in the same apply are created in ascending priority, so that they get ascending rule orders. Only used by the provider when the rule is created.",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"policy_set_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// resourcePolicyRuleJSONCustomizeDiff checks the converted rule against the
+// operand registry and the tenant during plan, like the v2 policy rule
+// resources do for their conditions.
+func resourcePolicyRuleJSONCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("rule_json") || !d.NewValueKnown("policy_type") || !d.NewValueKnown("microtenant_id") {
+		return nil
+	}
+	req, err := parsePolicyRuleJSON(d.Get("rule_json").(string))
+	if err != nil {
+		// Reported by the validation of the attribute
+		return nil
+	}
+	if err := validatePolicyRuleJSONConditions(req, d.Get("policy_type").(string)); err != nil {
+		return fmt.Errorf("rule_json: %v", err)
+	}
+	if d.HasChange("rule_json") || d.HasChange("microtenant_id") {
+		_ = d.SetNew("name", req.Name)
+	}
+
+	zClient, ok := meta.(*Client)
+	if !ok || zClient == nil || zClient.referenceCache == nil {
+		return nil
+	}
+	if d.Id() != "" && !d.HasChange("rule_json") && !d.HasChange("microtenant_id") {
+		return nil
+	}
+	return checkPolicyReferences(ctx, zClient, collectPolicyReferences(req.Conditions, GetString(d.Get("microtenant_id"))))
+}
+
+func expandPolicyRuleJSON(d *schema.ResourceData, policySetID string) (*policysetcontrollerv2.PolicyRule, error) {
+	req, err := parsePolicyRuleJSON(d.Get("rule_json").(string))
+	if err != nil {
+		return nil, fmt.Errorf("rule_json: %v", err)
+	}
+	if err := validatePolicyRuleJSONConditions(req, d.Get("policy_type").(string)); err != nil {
+		return nil, fmt.Errorf("rule_json: %v", err)
+	}
+	req.ID = d.Id()
+	req.PolicySetID = policySetID
+	req.MicroTenantID = GetString(d.Get("microtenant_id"))
+	return &req, nil
+}
+
+func resourcePolicyRuleJSONCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zClient := meta.(*Client)
+	service := zClient.Service
+
+	microTenantID := GetString(d.Get("microtenant_id"))
+	if microTenantID != "" {
+		service = service.WithMicroTenant(microTenantID)
+	}
+
+	policyType := d.Get("policy_type").(string)
+	policySetID, err := fetchPolicySetIDByType(ctx, zClient, policyType, microTenantID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	_ = d.Set("policy_set_id", policySetID)
+
+	req, err := expandPolicyRuleJSON(d, policySetID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	log.Printf("[INFO] Creating %s rule %q from JSON\n%+v\n", policyType, req.Name, req)
+
+	err = zClient.createPolicyRule(ctx, service, policyType, microTenantID, policyRuleCreationPriority(d), func() (string, error) {
+		resp, _, err := policysetcontrollerv2.CreateRule(ctx, service, req)
+		if err != nil {
+			return "", err
+		}
+		d.SetId(resp.ID)
+		return resp.ID, nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourcePolicyRuleJSONRead(ctx, d, meta)
+}
+
+func resourcePolicyRuleJSONRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zClient := meta.(*Client)
+	service := zClient.Service
+
+	microTenantID := GetString(d.Get("microtenant_id"))
+	if microTenantID != "" {
+		service = service.WithMicroTenant(microTenantID)
+	}
+
+	policyType := d.Get("policy_type").(string)
+	policySetID, err := fetchPolicySetIDByType(ctx, zClient, policyType, microTenantID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO] Getting %s rule: policySetID:%s id: %s\n", policyType, policySetID, d.Id())
+	resp, _, err := policysetcontrollerv2.GetPolicyRule(ctx, service, policySetID, d.Id())
+	if err != nil {
+		if errResp, ok := err.(*errorx.ErrorResponse); ok && errResp.IsObjectNotFound() {
+			log.Printf("[WARN] Removing policy rule %s from state because it no longer exists in ZPA", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(err)
+	}
+
+	diags := danglingPolicyReferenceWarnings(ctx, meta, collectPolicyReferences(ConvertV1ResponseToV2Request(*resp).Conditions, microTenantID))
+
+	ruleJSON, err := exportPolicyRuleJSON(*resp)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	_ = d.Set("rule_json", ruleJSON)
+	_ = d.Set("name", resp.Name)
+	_ = d.Set("policy_set_id", policySetID)
+	return diags
+}
+
+func resourcePolicyRuleJSONUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zClient := meta.(*Client)
+	service := zClient.Service
+
+	microTenantID := GetString(d.Get("microtenant_id"))
+	if microTenantID != "" {
+		service = service.WithMicroTenant(microTenantID)
+	}
+
+	policyType := d.Get("policy_type").(string)
+	policySetID, err := fetchPolicySetIDByType(ctx, zClient, policyType, microTenantID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if d.HasChange("rule_json") {
+		req, err := expandPolicyRuleJSON(d, policySetID)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		log.Printf("[INFO] Updating %s rule %s from JSON\n", policyType, d.Id())
+		if _, err := policysetcontrollerv2.UpdateRule(ctx, service, policySetID, d.Id(), req); err != nil {
+			if errResp, ok := err.(*errorx.ErrorResponse); ok && errResp.IsObjectNotFound() {
+				d.SetId("")
+				return nil
+			}
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourcePolicyRuleJSONRead(ctx, d, meta)
+}
+
+func resourcePolicyRuleJSONDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zClient := meta.(*Client)
+	service := zClient.Service
+
+	microTenantID := GetString(d.Get("microtenant_id"))
+	if microTenantID != "" {
+		service = service.WithMicroTenant(microTenantID)
+	}
+
+	policyType := d.Get("policy_type").(string)
+	policySetID, err := fetchPolicySetIDByType(ctx, zClient, policyType, microTenantID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[INFO] Deleting %s rule with id %v\n", policyType, d.Id())
+	if _, err := policysetcontrollerv2.Delete(ctx, service, policySetID, d.Id()); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+func resourcePolicyRuleJSONImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	// The import ID is the policy type and the rule ID, optionally followed
+	// by the microtenant ID: ACCESS_POLICY:<rule_id> or
+	// ACCESS_POLICY:<rule_id>:<microtenant_id>.
+	parts := strings.SplitN(d.Id(), ":", 3)
+	if len(parts) < 2 || parts[1] == "" || !contains(conditionPolicyTypes, parts[0]) {
+		return nil, fmt.Errorf("invalid import ID %q, expected <policy_type>:<rule_id> or <policy_type>:<rule_id>:<microtenant_id>", d.Id())
+	}
+	_ = d.Set("policy_type", parts[0])
+	if len(parts) == 3 {
+		_ = d.Set("microtenant_id", parts[2])
+	}
+	d.SetId(parts[1])
+	return []*schema.ResourceData{d}, nil
+}
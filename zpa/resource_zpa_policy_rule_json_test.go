@@ -0,0 +1,173 @@
+package zpa
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/zscaler/terraform-provider-zpa/v4/zpa/common/resourcetype"
+	"github.com/zscaler/terraform-provider-zpa/v4/zpa/common/testing/method"
+	"github.com/zscaler/terraform-provider-zpa/v4/zpa/common/testing/variable"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontrollerv2"
+)
+
+func TestAccResourcePolicyRuleJSON_Basic(t *testing.T) {
+	resourceTypeAndName := fmt.Sprintf("%s.test", resourcetype.ZPAPolicyRuleJSON)
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPolicyRuleJSONDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPolicyRuleJSONConfigure(rName, "ALLOW"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceTypeAndName, "name", rName),
+					resource.TestCheckResourceAttrSet(resourceTypeAndName, "policy_set_id"),
+					resource.TestCheckResourceAttrPair("data.zpa_policy_rule_json.test", "id", resourceTypeAndName, "id"),
+					resource.TestCheckResourceAttrSet("data.zpa_policy_rule_json.test", "rule_json"),
+				),
+			},
+			// Changing the action updates the rule in place
+			{
+				Config: testAccCheckPolicyRuleJSONConfigure(rName, "DENY"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceTypeAndName, "name", rName),
+				),
+			},
+			{
+				ResourceName:      resourceTypeAndName,
+				ImportState:       true,
+				ImportStateIdFunc: testAccPolicyRuleJSONImportStateID(resourceTypeAndName),
+				// rule_json is read back with the details the API adds
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"rule_json"},
+			},
+		},
+	})
+}
+
+func testAccPolicyRuleJSONImportStateID(resourceTypeAndName string) resource.ImportStateIdFunc {
+	return func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[resourceTypeAndName]
+		if !ok {
+			return "", fmt.Errorf("not found: %s", resourceTypeAndName)
+		}
+		return rs.Primary.Attributes["policy_type"] + ":" + rs.Primary.ID, nil
+	}
+}
+
+func testAccCheckPolicyRuleJSONDestroy(s *terraform.State) error {
+	apiClient := testAccProvider.Meta().(*Client)
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != resourcetype.ZPAPolicyRuleJSON {
+			continue
+		}
+		rule, _, err := policysetcontrollerv2.GetPolicyRule(context.Background(), apiClient.Service, rs.Primary.Attributes["policy_set_id"], rs.Primary.ID)
+		if err == nil && rule != nil {
+			return fmt.Errorf("policy rule with id %s still exists", rs.Primary.ID)
+		}
+	}
+	return nil
+}
+
+func testAccCheckPolicyRuleJSONConfigure(rName, action string) string {
+	return fmt.Sprintf(`
+resource "%[1]s" "test" {
+  policy_type = "ACCESS_POLICY"
+  rule_json = jsonencode({
+    name     = "%[2]s"
+    action   = "%[3]s"
+    operator = "AND"
+    conditions = [{
+      operator = "OR"
+      operands = [{
+        objectType = "CLIENT_TYPE"
+        lhs        = "id"
+        rhs        = "zpn_client_type_exporter"
+      }]
+    }]
+  })
+}
+
+data "zpa_policy_rule_json" "test" {
+  policy_type = "ACCESS_POLICY"
+  name        = %[1]s.test.name
+}
+`, resourcetype.ZPAPolicyRuleJSON, rName, action)
+}
+
+// Attributes the rule JSON converts to, such as the service edge groups of
+// redirection rules, are updated when they change.
+func TestAccResourcePolicyRuleJSON_ServiceEdgeGroups(t *testing.T) {
+	resourceTypeAndName := fmt.Sprintf("%s.redirection", resourcetype.ZPAPolicyRuleJSON)
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+
+	firstTypeAndName, _, firstName := method.GenerateRandomSourcesTypeAndName(resourcetype.ZPAServiceEdgeGroup)
+	secondTypeAndName, _, secondName := method.GenerateRandomSourcesTypeAndName(resourcetype.ZPAServiceEdgeGroup)
+	serviceEdgeGroupsHCL := testAccCheckServiceEdgeGroupConfigure(firstTypeAndName, "tf-acc-test-"+firstName, variable.ServiceEdgeDescription, variable.ServiceEdgeEnabled) +
+		testAccCheckServiceEdgeGroupConfigure(secondTypeAndName, "tf-acc-test-"+secondName, variable.ServiceEdgeDescription, variable.ServiceEdgeEnabled)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPolicyRuleJSONDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: serviceEdgeGroupsHCL + testAccCheckPolicyRuleJSONRedirectionConfigure(rName, firstTypeAndName),
+				Check:  testAccCheckPolicyRuleJSONServiceEdgeGroup(resourceTypeAndName, firstTypeAndName),
+			},
+			{
+				Config: serviceEdgeGroupsHCL + testAccCheckPolicyRuleJSONRedirectionConfigure(rName, secondTypeAndName),
+				Check:  testAccCheckPolicyRuleJSONServiceEdgeGroup(resourceTypeAndName, secondTypeAndName),
+			},
+		},
+	})
+}
+
+func testAccCheckPolicyRuleJSONServiceEdgeGroup(resourceTypeAndName, serviceEdgeGroupTypeAndName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceTypeAndName]
+		if !ok {
+			return fmt.Errorf("not found: %s", resourceTypeAndName)
+		}
+		group, ok := s.RootModule().Resources[serviceEdgeGroupTypeAndName]
+		if !ok {
+			return fmt.Errorf("not found: %s", serviceEdgeGroupTypeAndName)
+		}
+		apiClient := testAccProvider.Meta().(*Client)
+		rule, _, err := policysetcontrollerv2.GetPolicyRule(context.Background(), apiClient.Service, rs.Primary.Attributes["policy_set_id"], rs.Primary.ID)
+		if err != nil {
+			return fmt.Errorf("failed fetching rule %s: %v", rs.Primary.ID, err)
+		}
+		if len(rule.ServiceEdgeGroups) != 1 || rule.ServiceEdgeGroups[0].ID != group.Primary.ID {
+			return fmt.Errorf("expected service edge group %s, got %+v", group.Primary.ID, rule.ServiceEdgeGroups)
+		}
+		return nil
+	}
+}
+
+func testAccCheckPolicyRuleJSONRedirectionConfigure(rName, serviceEdgeGroupTypeAndName string) string {
+	return fmt.Sprintf(`
+resource "%[1]s" "redirection" {
+  policy_type = "REDIRECTION_POLICY"
+  rule_json = jsonencode({
+    name              = "%[2]s"
+    action            = "REDIRECT_PREFERRED"
+    serviceEdgeGroups = [{ id = %[3]s.id }]
+    conditions = [{
+      operator = "OR"
+      operands = [{
+        objectType = "CLIENT_TYPE"
+        lhs        = "id"
+        rhs        = "zpn_client_type_branch_connector"
+      }]
+    }]
+  })
+}
+`, resourcetype.ZPAPolicyRuleJSON, rName, serviceEdgeGroupTypeAndName)
+}
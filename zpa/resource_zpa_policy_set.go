@@ -0,0 +1,991 @@
+package zpa
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/errorx"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontroller"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontrollerv2"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/serviceedgegroup"
+)
+
+// deceptionRuleName is the system rule ZPA places first in the access policy
+// of tenants with Zscaler Deception. zpa_policy_set never manages or deletes
+// it, and keeps it in its slot.
+const deceptionRuleName = "Zscaler Deception"
+
+// policySetActions are the rule actions accepted by each policy type managed
+// by zpa_policy_set.
+var policySetActions = map[string][]string{
+	policyTypeAccess:            {"ALLOW", "DENY", "REQUIRE_APPROVAL"},
+	policyTypeTimeout:           {"RE_AUTH"},
+	policyTypeForwarding:        {"BYPASS", "INTERCEPT", "INTERCEPT_ACCESSIBLE"},
+	policyTypeInspection:        {"INSPECT", "BYPASS_INSPECT"},
+	policyTypeIsolation:         {"ISOLATE", "BYPASS_ISOLATE"},
+	policyTypeRedirection:       {"REDIRECT_DEFAULT", "REDIRECT_PREFERRED", "REDIRECT_ALWAYS"},
+	policyTypeSessionProtection: {"MONITOR", "DO_NOT_MONITOR"},
+}
+
+func resourcePolicySet() *schema.Resource {
+	var policyTypes, objectTypes []string
+	for policyType := range policySetActions {
+		policyTypes = append(policyTypes, policyType)
+	}
+	sort.Strings(policyTypes)
+	for _, t := range policyOperandRegistry {
+		objectTypes = append(objectTypes, t.objectType)
+	}
+
+	return &schema.Resource{
+		CreateContext: resourcePolicySetApply,
+		ReadContext:   resourcePolicySetRead,
+		UpdateContext: resourcePolicySetApply,
+		DeleteContext: resourcePolicySetDelete,
+		CustomizeDiff: resourcePolicySetCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourcePolicySetImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"policy_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(policyTypes, false),
+			},
+			"microtenant_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"authoritative": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Delete the rules of the policy that are not in the configuration and were never managed by this resource, and adopt existing rules by name. When false, such rules are kept in their current positions and reported in unmanaged_rules.",
+			},
+			"policy_set_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"rule": {
+				Type:        schema.TypeList,
+				Required:    true,
+				Description: "The rules of the policy, in evaluation order. Rules are identified by name; a renamed rule block updates its rule.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"description": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"action": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"operator": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+							ValidateFunc: validation.StringInSlice([]string{
+								"AND",
+								"OR",
+							}, false),
+						},
+						"custom_msg": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+						},
+						"zpn_inspection_profile_id": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The inspection profile of INSPECTION_POLICY rules.",
+						},
+						"zpn_isolation_profile_id": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The isolation profile of ISOLATION_POLICY rules.",
+						},
+						"reauth_idle_timeout": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Computed:    true,
+							Description: "The idle timeout of TIMEOUT_POLICY rules. Left empty, the API default is used.",
+						},
+						"reauth_timeout": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Computed:    true,
+							Description: "The timeout of TIMEOUT_POLICY rules. Left empty, the API default is used.",
+						},
+						"service_edge_groups": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							MaxItems:    1,
+							Description: "The Private Service Edge groups of REDIRECTION_POLICY rules. Required for REDIRECT_PREFERRED and REDIRECT_ALWAYS, not supported for REDIRECT_DEFAULT.",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"id": {
+										Type:     schema.TypeSet,
+										Required: true,
+										Elem:     &schema.Schema{Type: schema.TypeString},
+									},
+								},
+							},
+						},
+						"conditions": {
+							Type:     schema.TypeSet,
+							Optional: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"id": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"operator": {
+										Type:     schema.TypeString,
+										Optional: true,
+										ValidateFunc: validation.StringInSlice([]string{
+											"AND",
+											"OR",
+										}, false),
+									},
+									"operands": {
+										Type:     schema.TypeSet,
+										Optional: true,
+										Elem: &schema.Resource{
+											Schema: map[string]*schema.Schema{
+												"values": {
+													Type:     schema.TypeSet,
+													Optional: true,
+													Elem:     &schema.Schema{Type: schema.TypeString},
+												},
+												"object_type": {
+													Type:         schema.TypeString,
+													Optional:     true,
+													ValidateFunc: validation.StringInSlice(objectTypes, false),
+												},
+												"entry_values": {
+													Type:     schema.TypeSet,
+													Optional: true,
+													Elem: &schema.Resource{
+														Schema: map[string]*schema.Schema{
+															"rhs": {
+																Type:     schema.TypeString,
+																Optional: true,
+															},
+															"lhs": {
+																Type:     schema.TypeString,
+																Optional: true,
+															},
+														},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"resolved_names": policyOperandNamesSchema(),
+			"rule_ids": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "The IDs of the managed rules, by rule name.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"unmanaged_rules": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Rules of the policy that are not in the configuration, other than the Zscaler Deception rule.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"rule_order": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourcePolicySetCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	policyType := d.Get("policy_type").(string)
+	rules := d.Get("rule").([]interface{})
+
+	names := map[string]bool{}
+	for i, r := range rules {
+		rule, _ := r.(map[string]interface{})
+		if rule == nil {
+			continue
+		}
+		// Attributes referencing resources not created yet are validated
+		// on apply.
+		known := func(attribute string) bool {
+			return policySetRuleAttributeKnown(d, i, attribute)
+		}
+		if err := validatePolicySetRule(policyType, rule, known); err != nil {
+			return fmt.Errorf("rule.%d: %v", i, err)
+		}
+		name, _ := rule["name"].(string)
+		if name == "" {
+			continue
+		}
+		if names[name] {
+			return fmt.Errorf("rule.%d: rule name %q is used more than once, rule names must be unique", i, name)
+		}
+		names[name] = true
+	}
+
+	conditions, known, err := plannedPolicySetConditions(d)
+	if err != nil {
+		return err
+	}
+	if err := planPolicySetOperandNames(ctx, d, meta, conditions, known); err != nil {
+		return err
+	}
+	if err := validatePolicySetConditionReferences(ctx, d, meta, conditions, known); err != nil {
+		return err
+	}
+
+	// rule_ids changes when rules are added or removed.
+	ruleIDs := d.Get("rule_ids").(map[string]interface{})
+	sameRules := len(ruleIDs) == len(names)
+	for name := range names {
+		if _, ok := ruleIDs[name]; !ok {
+			sameRules = false
+		}
+	}
+	if !sameRules {
+		if err := d.SetNewComputed("rule_ids"); err != nil {
+			return err
+		}
+	}
+	// Rules reported as unmanaged are deleted on the next apply once the set
+	// becomes authoritative.
+	if d.Get("authoritative").(bool) && len(d.Get("unmanaged_rules").([]interface{})) > 0 {
+		return d.SetNew("unmanaged_rules", []interface{}{})
+	}
+	return nil
+}
+
+// policySetRuleAttributeKnown reports whether an attribute of rule block i is
+// known. Unknown elements of nested sets, such as a service edge group ID
+// created by the same apply, are only visible in the raw configuration.
+func policySetRuleAttributeKnown(d *schema.ResourceDiff, i int, attribute string) bool {
+	if !d.NewValueKnown(fmt.Sprintf("rule.%d.%s", i, attribute)) {
+		return false
+	}
+	v, diags := d.GetRawConfigAt(cty.GetAttrPath("rule").IndexInt(i).GetAttr(attribute))
+	return diags.HasError() || v.IsWhollyKnown()
+}
+
+// plannedPolicySetConditions returns the planned conditions of each rule block,
+// and whether they are known. Conditions that are not known yet are nil.
+func plannedPolicySetConditions(d *schema.ResourceDiff) ([][]policysetcontrollerv2.PolicyRuleResourceConditions, []bool, error) {
+	rules := d.Get("rule").([]interface{})
+	conditions := make([][]policysetcontrollerv2.PolicyRuleResourceConditions, len(rules))
+	known := make([]bool, len(rules))
+	for i, r := range rules {
+		known[i] = policySetRuleAttributeKnown(d, i, "conditions")
+		rule, _ := r.(map[string]interface{})
+		conditionsSet, ok := rule["conditions"].(*schema.Set)
+		if !known[i] || !ok || conditionsSet.Len() == 0 {
+			continue
+		}
+		expanded, err := expandPolicyConditionSetV2(conditionsSet)
+		if err != nil {
+			return nil, nil, fmt.Errorf("rule.%d: %v", i, err)
+		}
+		conditions[i] = expanded
+	}
+	return conditions, known, nil
+}
+
+// planPolicySetOperandNames plans resolved_names like planPolicyOperandNames
+// does for the v2 policy rule resources, from the conditions of every rule
+// block. The rules of a policy set share the microtenant, so a name resolves
+// to the same ID in every rule.
+func planPolicySetOperandNames(ctx context.Context, d *schema.ResourceDiff, meta interface{}, conditions [][]policysetcontrollerv2.PolicyRuleResourceConditions, known []bool) error {
+	zClient, ok := meta.(*Client)
+	if !ok || zClient == nil {
+		return nil
+	}
+	allKnown := d.NewValueKnown("microtenant_id")
+	for _, ruleKnown := range known {
+		allKnown = allKnown && ruleKnown
+	}
+	if !allKnown {
+		return d.SetNewComputed("resolved_names")
+	}
+	var all []policysetcontrollerv2.PolicyRuleResourceConditions
+	for _, ruleConditions := range conditions {
+		all = append(all, ruleConditions...)
+	}
+	return planResolvedPolicyOperandNames(ctx, d, zClient, all, GetString(d.Get("microtenant_id")))
+}
+
+// validatePolicySetConditionReferences checks the IDs referenced by the
+// conditions of the changed rule blocks like validatePolicyConditionReferences
+// does for the v2 policy rule resources.
+func validatePolicySetConditionReferences(ctx context.Context, d *schema.ResourceDiff, meta interface{}, conditions [][]policysetcontrollerv2.PolicyRuleResourceConditions, known []bool) error {
+	zClient, ok := meta.(*Client)
+	if !ok || zClient == nil || zClient.referenceCache == nil || !d.NewValueKnown("microtenant_id") {
+		return nil
+	}
+	microTenantID := GetString(d.Get("microtenant_id"))
+	var refs []policyReference
+	for i, ruleConditions := range conditions {
+		if !known[i] || (d.Id() != "" && !d.HasChange(fmt.Sprintf("rule.%d.conditions", i)) && !d.HasChange("microtenant_id")) {
+			continue
+		}
+		for _, ref := range collectPolicyReferences(ruleConditions, microTenantID) {
+			ref.location = fmt.Sprintf("rule.%d.%s", i, ref.location)
+			refs = append(refs, ref)
+		}
+	}
+	return checkPolicyReferences(ctx, zClient, refs)
+}
+
+// validatePolicySetRule checks a rule block offline against its policy type.
+// known reports whether an attribute of the rule is known; the attributes
+// that are not are checked on apply.
+func validatePolicySetRule(policyType string, rule map[string]interface{}, known func(attribute string) bool) error {
+	name, _ := rule["name"].(string)
+	if name == deceptionRuleName {
+		return fmt.Errorf("the %s rule is managed by ZPA and cannot be part of zpa_policy_set", deceptionRuleName)
+	}
+	if action, _ := rule["action"].(string); action != "" && !contains(policySetActions[policyType], action) {
+		return fmt.Errorf("action must be one of %s for %s, got %q", strings.Join(quoteAll(policySetActions[policyType]), ", "), policyType, action)
+	}
+
+	onlyFor := map[string]string{
+		"zpn_inspection_profile_id": policyTypeInspection,
+		"zpn_isolation_profile_id":  policyTypeIsolation,
+		"reauth_idle_timeout":       policyTypeTimeout,
+		"reauth_timeout":            policyTypeTimeout,
+	}
+	for attribute, allowedType := range onlyFor {
+		if v, _ := rule[attribute].(string); v != "" && policyType != allowedType {
+			return fmt.Errorf("%s can only be set for %s rules", attribute, allowedType)
+		}
+	}
+	for _, attribute := range []string{"reauth_idle_timeout", "reauth_timeout"} {
+		if v, _ := rule[attribute].(string); v != "" {
+			if err := validateTimeoutIntervals(v); err != nil {
+				return fmt.Errorf("%s: %v", attribute, err)
+			}
+		}
+	}
+	serviceEdgeGroups := len(expandPolicySetServiceEdgeGroups(rule))
+	if serviceEdgeGroups > 0 && policyType != policyTypeRedirection {
+		return fmt.Errorf("service_edge_groups can only be set for %s rules", policyTypeRedirection)
+	}
+	if policyType == policyTypeRedirection && known("action") && known("service_edge_groups") {
+		action, _ := rule["action"].(string)
+		if err := validateRedirectionServiceEdgeGroups(action, serviceEdgeGroups); err != nil {
+			return err
+		}
+	}
+
+	conditions, ok := rule["conditions"].(*schema.Set)
+	if !ok || !known("conditions") {
+		return nil
+	}
+	for _, condition := range conditions.List() {
+		conditionMap, _ := condition.(map[string]interface{})
+		operands, ok := conditionMap["operands"].(*schema.Set)
+		if !ok {
+			continue
+		}
+		for _, operand := range operands.List() {
+			operandMap, _ := operand.(map[string]interface{})
+			objectType, _ := operandMap["object_type"].(string)
+			if t, ok := lookupPolicyOperandType(objectType); ok && !t.allowedIn(policyType) {
+				return fmt.Errorf("object_type %s is not supported in %s conditions", objectType, policyType)
+			}
+		}
+	}
+	if err := validateConditionSetObjectTypeUniqueness(conditions); err != nil {
+		return err
+	}
+	return validatePolicyConditionSet(conditions)
+}
+
+func resourcePolicySetImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	zClient := meta.(*Client)
+
+	// The import ID is the policy type, optionally followed by the
+	// microtenant ID: ACCESS_POLICY or ACCESS_POLICY:<microtenant_id>.
+	policyType, microTenantID, _ := strings.Cut(d.Id(), ":")
+	if _, ok := policySetActions[policyType]; !ok {
+		return nil, fmt.Errorf("invalid import ID %q, expected <policy_type> or <policy_type>:<microtenant_id>", d.Id())
+	}
+	_ = d.Set("policy_type", policyType)
+	_ = d.Set("microtenant_id", microTenantID)
+
+	service := zClient.Service
+	if microTenantID != "" {
+		service = service.WithMicroTenant(microTenantID)
+	}
+	policySetID, err := fetchPolicySetIDByType(ctx, zClient, policyType, microTenantID)
+	if err != nil {
+		return nil, err
+	}
+	rules, _, err := policysetcontrollerv2.GetAllByType(ctx, service, policyType)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every rule of the policy is adopted.
+	ruleIDs := map[string]interface{}{}
+	for _, rule := range rules {
+		if rule.Name != deceptionRuleName {
+			ruleIDs[rule.Name] = rule.ID
+		}
+	}
+	_ = d.Set("rule_ids", ruleIDs)
+	d.SetId(policySetID)
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourcePolicySetRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zClient := meta.(*Client)
+	service := zClient.Service
+
+	microTenantID := GetString(d.Get("microtenant_id"))
+	if microTenantID != "" {
+		service = service.WithMicroTenant(microTenantID)
+	}
+
+	policyType := d.Get("policy_type").(string)
+	log.Printf("[INFO] Getting %s rules of policy set %s", policyType, d.Id())
+	current, err := fetchPolicySetRules(ctx, service, policyType)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	managed := map[string]bool{}
+	for _, id := range d.Get("rule_ids").(map[string]interface{}) {
+		managed[id.(string)] = true
+	}
+
+	var diags diag.Diagnostics
+	var rules, unmanaged []interface{}
+	ruleIDs := map[string]interface{}{}
+	for _, rule := range current {
+		if rule.Name == deceptionRuleName {
+			continue
+		}
+		if managed[rule.ID] {
+			rules = append(rules, flattenPolicySetRule(d, rule, policyType))
+			ruleIDs[rule.Name] = rule.ID
+			for _, warning := range danglingPolicyReferenceWarnings(ctx, meta, collectPolicyReferences(ConvertV1ResponseToV2Request(rule).Conditions, microTenantID)) {
+				warning.Summary = fmt.Sprintf("Rule %q: %s", rule.Name, warning.Summary)
+				diags = append(diags, warning)
+			}
+			continue
+		}
+		order, _ := strconv.Atoi(rule.RuleOrder)
+		unmanaged = append(unmanaged, map[string]interface{}{
+			"id":         rule.ID,
+			"name":       rule.Name,
+			"rule_order": order,
+		})
+	}
+
+	_ = d.Set("rule", rules)
+	_ = d.Set("rule_ids", ruleIDs)
+	_ = d.Set("unmanaged_rules", unmanaged)
+
+	if len(unmanaged) > 0 && !d.Get("authoritative").(bool) {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("%d %s rules are not managed by zpa_policy_set", len(unmanaged), policyType),
+			Detail:   "The rules are kept in their current positions and listed in unmanaged_rules. Add them to the configuration and import the policy set, or set authoritative = true to delete them.",
+		})
+	}
+	return diags
+}
+
+func resourcePolicySetApply(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zClient := meta.(*Client)
+	service := zClient.Service
+
+	microTenantID := GetString(d.Get("microtenant_id"))
+	if microTenantID != "" {
+		service = service.WithMicroTenant(microTenantID)
+	}
+
+	policyType := d.Get("policy_type").(string)
+	policySetID, err := fetchPolicySetIDByType(ctx, zClient, policyType, microTenantID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	_ = d.Set("policy_set_id", policySetID)
+
+	for i, r := range d.Get("rule").([]interface{}) {
+		known := func(string) bool { return true }
+		if err := validatePolicySetRule(policyType, r.(map[string]interface{}), known); err != nil {
+			return diag.FromErr(fmt.Errorf("rule.%d: %v", i, err))
+		}
+	}
+	desired, err := expandPolicySetRules(d, policySetID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	current, err := fetchPolicySetRules(ctx, service, policyType)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	byName := map[string]policysetcontrollerv2.PolicyRuleResource{}
+	byID := map[string]policysetcontrollerv2.PolicyRuleResource{}
+	for _, rule := range current {
+		if rule.Name != deceptionRuleName {
+			byName[rule.Name] = rule
+			byID[rule.ID] = rule
+		}
+	}
+
+	// The rules created or imported by this resource are matched by ID, so
+	// that a renamed rule block updates its rule.
+	oldRules, _ := d.GetChange("rule")
+	oldRuleIDs, _ := d.GetChange("rule_ids")
+	managed := map[string]string{}
+	for name, id := range oldRuleIDs.(map[string]interface{}) {
+		managed[name] = id.(string)
+	}
+	var names, oldNames []string
+	for _, rule := range desired {
+		names = append(names, rule.Name)
+	}
+	for _, r := range oldRules.([]interface{}) {
+		if rule, ok := r.(map[string]interface{}); ok {
+			oldNames = append(oldNames, rule["name"].(string))
+		}
+	}
+	matchedIDs := matchPolicySetRules(names, oldNames, managed, func(id string) bool {
+		_, ok := byID[id]
+		return ok
+	})
+	claimed := map[string]bool{}
+	for _, id := range matchedIDs {
+		if id != "" {
+			claimed[id] = true
+		}
+	}
+	isManaged := map[string]bool{}
+	for _, id := range managed {
+		isManaged[id] = true
+	}
+
+	// The other configured rules adopt an existing rule by name. Without
+	// authoritative, only the rules managed by this resource may be updated;
+	// an existing rule with a configured name may be managed by another
+	// resource.
+	authoritative := d.Get("authoritative").(bool)
+	for i, rule := range desired {
+		if matchedIDs[i] != "" {
+			continue
+		}
+		existing, found := byName[rule.Name]
+		if !found || claimed[existing.ID] {
+			continue
+		}
+		if !authoritative && !isManaged[existing.ID] {
+			return diag.FromErr(fmt.Errorf("rule %q already exists in the %s policy (ID %s) and is not managed by this resource: import the policy set to adopt its rules, set authoritative = true, or rename the rule", rule.Name, policyType, existing.ID))
+		}
+		matchedIDs[i] = existing.ID
+		claimed[existing.ID] = true
+	}
+
+	// Create or update the configured rules, in order.
+	ruleIDs := map[string]interface{}{}
+	var orderedIDs []string
+	for i, rule := range desired {
+		if matchedIDs[i] == "" {
+			log.Printf("[INFO] Creating %s rule %q", policyType, rule.Name)
+			resp, _, err := policysetcontrollerv2.CreateRule(ctx, service, &rule)
+			if err != nil {
+				return diag.FromErr(fmt.Errorf("failed to create rule %q: %v", rule.Name, err))
+			}
+			ruleIDs[rule.Name] = resp.ID
+			orderedIDs = append(orderedIDs, resp.ID)
+			continue
+		}
+
+		existing := byID[matchedIDs[i]]
+		ruleIDs[rule.Name] = existing.ID
+		orderedIDs = append(orderedIDs, existing.ID)
+		if !policySetRuleChanged(existing, rule) {
+			continue
+		}
+		log.Printf("[INFO] Updating %s rule %q (%s)", policyType, rule.Name, existing.ID)
+		req := mergePolicySetRule(existing, rule)
+		if _, err := policysetcontrollerv2.UpdateRule(ctx, service, policySetID, existing.ID, &req); err != nil {
+			return diag.FromErr(fmt.Errorf("failed to update rule %q: %v", rule.Name, err))
+		}
+	}
+	_ = d.Set("rule_ids", ruleIDs)
+	d.SetId(policySetID)
+
+	// Delete the managed rules that were removed from the configuration and,
+	// with authoritative, the other rules that are not configured.
+	for _, rule := range current {
+		if claimed[rule.ID] || rule.Name == deceptionRuleName || (!isManaged[rule.ID] && !authoritative) {
+			continue
+		}
+		log.Printf("[INFO] Deleting %s rule %q (%s), it is not in the configuration", policyType, rule.Name, rule.ID)
+		if _, err := policysetcontrollerv2.Delete(ctx, service, policySetID, rule.ID); err != nil {
+			return diag.FromErr(fmt.Errorf("failed to delete rule %q: %v", rule.Name, err))
+		}
+	}
+
+	// Reorder only when the resulting order differs from the current one.
+	lock := zClient.policyRuleOrderLock(policyType, microTenantID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	after, err := fetchPolicySetRules(ctx, service, policyType)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	currentOrders := map[string]string{}
+	afterIDs := make([]string, len(after))
+	for i, rule := range after {
+		currentOrders[rule.ID] = rule.RuleOrder
+		afterIDs[i] = rule.ID
+	}
+	// The other rules, including Zscaler Deception wherever it is, keep
+	// their slots.
+	orderedIDs = orderPolicySetRulesInSlots(afterIDs, orderedIDs)
+	log.Printf("[DEBUG] %s rule order: %v", policyType, orderedIDs)
+
+	ruleIDToOrder := map[string]int{}
+	reorder := false
+	for i, id := range orderedIDs {
+		ruleIDToOrder[id] = i + 1
+		if currentOrders[id] != strconv.Itoa(i+1) {
+			reorder = true
+		}
+	}
+	if reorder {
+		log.Printf("[INFO] Reordering %d %s rules", len(ruleIDToOrder), policyType)
+		if _, err := policysetcontroller.BulkReorder(ctx, service, policyType, ruleIDToOrder); err != nil {
+			return diag.FromErr(fmt.Errorf("failed to reorder %s rules: %v", policyType, err))
+		}
+	}
+	zClient.invalidatePolicyRulesPrefetch(policyType)
+
+	return resourcePolicySetRead(ctx, d, meta)
+}
+
+func resourcePolicySetDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	zClient := meta.(*Client)
+	service := zClient.Service
+
+	microTenantID := GetString(d.Get("microtenant_id"))
+	if microTenantID != "" {
+		service = service.WithMicroTenant(microTenantID)
+	}
+
+	policySetID := d.Id()
+	for name, id := range d.Get("rule_ids").(map[string]interface{}) {
+		log.Printf("[INFO] Deleting rule %q (%s) of policy set %s", name, id, policySetID)
+		if _, err := policysetcontrollerv2.Delete(ctx, service, policySetID, id.(string)); err != nil {
+			if errResp, ok := err.(*errorx.ErrorResponse); ok && errResp.IsObjectNotFound() {
+				continue
+			}
+			return diag.FromErr(fmt.Errorf("failed to delete rule %q: %v", name, err))
+		}
+	}
+	zClient.invalidatePolicyRulesPrefetch(d.Get("policy_type").(string))
+	return nil
+}
+
+// matchPolicySetRules returns, for each configured rule name, the ID of the
+// managed rule it updates, or "" when it matches no managed rule. managed maps
+// the names of the rules managed before the apply to their IDs, and oldNames
+// are the previously configured names, in order. A rule matches the managed
+// rule of the same name, or the rule whose block was renamed in its
+// position. exists reports whether a rule ID is still in the policy.
+func matchPolicySetRules(names, oldNames []string, managed map[string]string, exists func(string) bool) []string {
+	configured := make(map[string]bool, len(names))
+	for _, name := range names {
+		configured[name] = true
+	}
+	claimed := map[string]bool{}
+	ids := make([]string, len(names))
+	for i, name := range names {
+		if id, ok := managed[name]; ok && exists(id) {
+			ids[i] = id
+			claimed[id] = true
+		}
+	}
+	for i := range names {
+		if ids[i] != "" || i >= len(oldNames) || configured[oldNames[i]] {
+			continue
+		}
+		if id, ok := managed[oldNames[i]]; ok && exists(id) && !claimed[id] {
+			ids[i] = id
+			claimed[id] = true
+		}
+	}
+	return ids
+}
+
+// orderPolicySetRulesInSlots returns ids, the rule IDs of a policy in order,
+// with the configured rules put in configured order within the slots they
+// occupy. The other rules keep their slots.
+func orderPolicySetRulesInSlots(ids, configured []string) []string {
+	isConfigured := make(map[string]bool, len(configured))
+	for _, id := range configured {
+		isConfigured[id] = true
+	}
+	ordered := append([]string(nil), ids...)
+	next := 0
+	for i, id := range ordered {
+		if isConfigured[id] && next < len(configured) {
+			ordered[i] = configured[next]
+			next++
+		}
+	}
+	return ordered
+}
+
+// fetchPolicySetRules returns the rules of policyType sorted by rule order.
+func fetchPolicySetRules(ctx context.Context, service *zscaler.Service, policyType string) ([]policysetcontrollerv2.PolicyRuleResource, error) {
+	rules, _, err := policysetcontrollerv2.GetAllByType(ctx, service, policyType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s rules: %v", policyType, err)
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		oi, _ := strconv.Atoi(rules[i].RuleOrder)
+		oj, _ := strconv.Atoi(rules[j].RuleOrder)
+		return oi < oj
+	})
+	return rules, nil
+}
+
+func expandPolicySetRules(d *schema.ResourceData, policySetID string) ([]policysetcontrollerv2.PolicyRule, error) {
+	policyType := d.Get("policy_type").(string)
+	var rules []policysetcontrollerv2.PolicyRule
+	for _, r := range d.Get("rule").([]interface{}) {
+		ruleMap := r.(map[string]interface{})
+		rule := policysetcontrollerv2.PolicyRule{
+			Name:                   ruleMap["name"].(string),
+			Description:            ruleMap["description"].(string),
+			Action:                 ruleMap["action"].(string),
+			Operator:               ruleMap["operator"].(string),
+			CustomMsg:              ruleMap["custom_msg"].(string),
+			ZpnInspectionProfileID: ruleMap["zpn_inspection_profile_id"].(string),
+			ZpnIsolationProfileID:  ruleMap["zpn_isolation_profile_id"].(string),
+			ServiceEdgeGroups:      expandPolicySetServiceEdgeGroups(ruleMap),
+			PolicySetID:            policySetID,
+		}
+		if conditions, ok := ruleMap["conditions"].(*schema.Set); ok {
+			expanded, err := expandPolicyConditionSetV2(conditions)
+			if err != nil {
+				return nil, err
+			}
+			// Name references are replaced with the IDs planned in
+			// resolved_names.
+			if err := replacePolicyOperandNames(d, expanded); err != nil {
+				return nil, fmt.Errorf("rule %q: %v", rule.Name, err)
+			}
+			rule.Conditions = expanded
+		}
+		if policyType == policyTypeTimeout {
+			// Timeouts left empty keep the value of the existing rule, or
+			// the API default for new rules.
+			if v := ruleMap["reauth_idle_timeout"].(string); v != "" {
+				idleTimeout, err := parseHumanReadableTimeout(v)
+				if err != nil {
+					return nil, fmt.Errorf("rule %q: reauth_idle_timeout: %v", rule.Name, err)
+				}
+				rule.ReauthIdleTimeout = strconv.Itoa(idleTimeout)
+			}
+			if v := ruleMap["reauth_timeout"].(string); v != "" {
+				timeout, err := parseHumanReadableTimeout(v)
+				if err != nil {
+					return nil, fmt.Errorf("rule %q: reauth_timeout: %v", rule.Name, err)
+				}
+				rule.ReauthTimeout = strconv.Itoa(timeout)
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// expandPolicySetServiceEdgeGroups returns the service edge groups of a rule
+// block.
+func expandPolicySetServiceEdgeGroups(rule map[string]interface{}) []serviceedgegroup.ServiceEdgeGroup {
+	blocks, _ := rule["service_edge_groups"].([]interface{})
+	if len(blocks) == 0 {
+		return nil
+	}
+	block, _ := blocks[0].(map[string]interface{})
+	ids, ok := block["id"].(*schema.Set)
+	if !ok {
+		return nil
+	}
+	var groups []serviceedgegroup.ServiceEdgeGroup
+	for _, id := range ids.List() {
+		groups = append(groups, serviceedgegroup.ServiceEdgeGroup{ID: id.(string)})
+	}
+	return groups
+}
+
+// flattenPolicySetRule returns the rule block of a rule. IDs resolved from
+// name references are set back to the names.
+func flattenPolicySetRule(d *schema.ResourceData, rule policysetcontrollerv2.PolicyRuleResource, policyType string) map[string]interface{} {
+	v2PolicyRule := ConvertV1ResponseToV2Request(rule)
+	restorePolicyOperandNames(d, v2PolicyRule.Conditions)
+	m := map[string]interface{}{
+		"name":                      v2PolicyRule.Name,
+		"description":               v2PolicyRule.Description,
+		"action":                    v2PolicyRule.Action,
+		"operator":                  v2PolicyRule.Operator,
+		"custom_msg":                v2PolicyRule.CustomMsg,
+		"zpn_inspection_profile_id": v2PolicyRule.ZpnInspectionProfileID,
+		"zpn_isolation_profile_id":  v2PolicyRule.ZpnIsolationProfileID,
+		"conditions":                flattenConditionsV2(v2PolicyRule.Conditions),
+	}
+	if policyType == policyTypeRedirection {
+		m["service_edge_groups"] = flattenServiceEdgeGroupIDs(v2PolicyRule.ServiceEdgeGroups)
+	}
+	if policyType == policyTypeTimeout {
+		for attribute, seconds := range map[string]string{
+			"reauth_idle_timeout": rule.ReauthIdleTimeout,
+			"reauth_timeout":      rule.ReauthTimeout,
+		} {
+			if seconds == "-1" {
+				m[attribute] = "never"
+			} else {
+				m[attribute] = secondsToHumanReadable(seconds)
+			}
+		}
+	}
+	return m
+}
+
+// policySetRuleChanged reports whether the attributes managed by
+// zpa_policy_set differ between the existing rule and the desired one.
+func policySetRuleChanged(existing policysetcontrollerv2.PolicyRuleResource, desired policysetcontrollerv2.PolicyRule) bool {
+	current := ConvertV1ResponseToV2Request(existing)
+	operator := func(op, defaultOp string) string {
+		if op == "" {
+			return defaultOp
+		}
+		return strings.ToUpper(op)
+	}
+	return current.Name != desired.Name ||
+		current.Description != desired.Description ||
+		current.Action != desired.Action ||
+		operator(current.Operator, "AND") != operator(desired.Operator, "AND") ||
+		(desired.CustomMsg != "" && current.CustomMsg != desired.CustomMsg) ||
+		current.ZpnInspectionProfileID != desired.ZpnInspectionProfileID ||
+		current.ZpnIsolationProfileID != desired.ZpnIsolationProfileID ||
+		(desired.ReauthTimeout != "" && current.ReauthTimeout != desired.ReauthTimeout) ||
+		(desired.ReauthIdleTimeout != "" && current.ReauthIdleTimeout != desired.ReauthIdleTimeout) ||
+		!sameServiceEdgeGroups(current.ServiceEdgeGroups, desired.ServiceEdgeGroups) ||
+		canonicalPolicyConditionsV2(current.Conditions) != canonicalPolicyConditionsV2(desired.Conditions)
+}
+
+// mergePolicySetRule applies the attributes managed by zpa_policy_set to the
+// existing rule, so that attributes it does not manage, such as server
+// groups, are kept by the update.
+func mergePolicySetRule(existing policysetcontrollerv2.PolicyRuleResource, desired policysetcontrollerv2.PolicyRule) policysetcontrollerv2.PolicyRule {
+	req := ConvertV1ResponseToV2Request(existing)
+	req.Name = desired.Name
+	req.Description = desired.Description
+	req.Action = desired.Action
+	req.PolicySetID = desired.PolicySetID
+	req.Conditions = desired.Conditions
+	req.ZpnInspectionProfileID = desired.ZpnInspectionProfileID
+	req.ZpnIsolationProfileID = desired.ZpnIsolationProfileID
+	req.ServiceEdgeGroups = desired.ServiceEdgeGroups
+	if desired.Operator != "" {
+		req.Operator = desired.Operator
+	}
+	if desired.CustomMsg != "" {
+		req.CustomMsg = desired.CustomMsg
+	}
+	if desired.ReauthTimeout != "" {
+		req.ReauthTimeout = desired.ReauthTimeout
+	}
+	if desired.ReauthIdleTimeout != "" {
+		req.ReauthIdleTimeout = desired.ReauthIdleTimeout
+	}
+	return req
+}
+
+// sameServiceEdgeGroups reports whether a and b are the same service edge
+// groups, in any order.
+func sameServiceEdgeGroups(a, b []serviceedgegroup.ServiceEdgeGroup) bool {
+	ids := func(groups []serviceedgegroup.ServiceEdgeGroup) string {
+		var ids []string
+		for _, group := range groups {
+			ids = append(ids, group.ID)
+		}
+		sort.Strings(ids)
+		return strings.Join(ids, ",")
+	}
+	return ids(a) == ids(b)
+}
+
+// canonicalPolicyConditionsV2 returns an order-independent representation of
+// v2 conditions, used to compare configured and existing conditions.
+func canonicalPolicyConditionsV2(conditions []policysetcontrollerv2.PolicyRuleResourceConditions) string {
+	var parts []string
+	for _, condition := range conditions {
+		operator := strings.ToUpper(condition.Operator)
+		if operator == "" {
+			operator = "OR"
+		}
+		var operands []string
+		for _, operand := range condition.Operands {
+			values := append([]string(nil), operand.Values...)
+			sort.Strings(values)
+			var entries []string
+			for _, ev := range operand.EntryValuesLHSRHS {
+				entries = append(entries, ev.LHS+"="+ev.RHS)
+			}
+			sort.Strings(entries)
+			operands = append(operands, fmt.Sprintf("%s(%s|%s)", operand.ObjectType, strings.Join(values, ","), strings.Join(entries, ",")))
+		}
+		sort.Strings(operands)
+		parts = append(parts, fmt.Sprintf("%s[%s]", operator, strings.Join(operands, ";")))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, " ")
+}
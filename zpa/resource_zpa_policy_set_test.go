@@ -0,0 +1,422 @@
+package zpa
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/zscaler/terraform-provider-zpa/v4/zpa/common/resourcetype"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/policysetcontrollerv2"
+	"github.com/zscaler/zscaler-sdk-go/v3/zscaler/zpa/services/serviceedgegroup"
+)
+
+func TestAccResourcePolicySet_Basic(t *testing.T) {
+	resourceTypeAndName := fmt.Sprintf("%s.test", resourcetype.ZPAPolicySet)
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+	ruleIDs := map[string]string{}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPolicySetDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPolicySetConfigure(rName, "first", "second"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceTypeAndName, "policy_type", "ACCESS_POLICY"),
+					resource.TestCheckResourceAttr(resourceTypeAndName, "rule.#", "2"),
+					resource.TestCheckResourceAttr(resourceTypeAndName, "rule.0.name", rName+"-first"),
+					resource.TestCheckResourceAttr(resourceTypeAndName, "rule.1.name", rName+"-second"),
+					resource.TestCheckResourceAttr(resourceTypeAndName, "rule_ids.%", "2"),
+				),
+			},
+			// Swapping the blocks reorders the rules without recreating them
+			{
+				Config: testAccCheckPolicySetConfigure(rName, "second", "first"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceTypeAndName, "rule.#", "2"),
+					resource.TestCheckResourceAttr(resourceTypeAndName, "rule.0.name", rName+"-second"),
+					resource.TestCheckResourceAttr(resourceTypeAndName, "rule.1.name", rName+"-first"),
+					resource.TestCheckResourceAttr(resourceTypeAndName, "rule_ids.%", "2"),
+					testAccCheckPolicySetRuleIDs(resourceTypeAndName, ruleIDs),
+				),
+			},
+			// Renaming the first block updates its rule, removing the second
+			// block deletes its rule
+			{
+				Config: testAccCheckPolicySetSingleRuleConfigure(rName, "renamed"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceTypeAndName, "rule.#", "1"),
+					resource.TestCheckResourceAttr(resourceTypeAndName, "rule.0.name", rName+"-renamed"),
+					resource.TestCheckResourceAttr(resourceTypeAndName, "rule_ids.%", "1"),
+					func(s *terraform.State) error {
+						return resource.TestCheckResourceAttr(resourceTypeAndName, "rule_ids."+rName+"-renamed", ruleIDs[rName+"-second"])(s)
+					},
+					testAccCheckPolicySetRuleDeleted(resourceTypeAndName, func() string { return ruleIDs[rName+"-first"] }),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourcePolicySet_TimeoutWithoutTimeouts(t *testing.T) {
+	resourceTypeAndName := fmt.Sprintf("%s.test", resourcetype.ZPAPolicySet)
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPolicySetDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPolicySetTimeoutConfigure(rName, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceTypeAndName, "policy_type", "TIMEOUT_POLICY"),
+					resource.TestCheckResourceAttr(resourceTypeAndName, "rule.#", "1"),
+					resource.TestCheckResourceAttrSet(resourceTypeAndName, "rule.0.reauth_timeout"),
+				),
+			},
+			// Setting one timeout keeps the other
+			{
+				Config: testAccCheckPolicySetTimeoutConfigure(rName, `reauth_timeout = "10 Days"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceTypeAndName, "rule.0.reauth_timeout", "10 Days"),
+					resource.TestCheckResourceAttrSet(resourceTypeAndName, "rule.0.reauth_idle_timeout"),
+				),
+			},
+		},
+	})
+}
+
+// An existing rule with a configured name is not adopted without authoritative.
+func TestAccResourcePolicySet_RefusesToAdoptByName(t *testing.T) {
+	rName := acctest.RandomWithPrefix("tf-acc-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPolicySetDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPolicySetExistingRuleConfigure(rName),
+			},
+			{
+				Config:      testAccCheckPolicySetExistingRuleConfigure(rName) + testAccCheckPolicySetConfigure(rName, "existing", "new"),
+				ExpectError: regexp.MustCompile(`already exists in the ACCESS_POLICY policy .* and is not managed by this resource`),
+			},
+		},
+	})
+}
+
+func TestOrderPolicySetRulesInSlots(t *testing.T) {
+	for _, tc := range []struct {
+		ids, configured, want []string
+	}{
+		{
+			ids:        []string{"deception", "a", "other1", "b", "other2", "c"},
+			configured: []string{"c", "a", "b"},
+			want:       []string{"deception", "c", "other1", "a", "other2", "b"},
+		},
+		{
+			ids:        []string{"other", "a", "b"},
+			configured: []string{"a", "b"},
+			want:       []string{"other", "a", "b"},
+		},
+		{
+			ids:        []string{"other"},
+			configured: nil,
+			want:       []string{"other"},
+		},
+		// Authoritative: Zscaler Deception keeps its slot, the created rule
+		// is moved from the end into the configured order
+		{
+			ids:        []string{"a", "deception", "b", "new"},
+			configured: []string{"new", "b", "a"},
+			want:       []string{"new", "deception", "b", "a"},
+		},
+	} {
+		if got := orderPolicySetRulesInSlots(tc.ids, tc.configured); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("orderPolicySetRulesInSlots(%v, %v) = %v, want %v", tc.ids, tc.configured, got, tc.want)
+		}
+	}
+}
+
+func TestMatchPolicySetRules(t *testing.T) {
+	managed := map[string]string{"a": "1", "b": "2", "c": "3"}
+	exists := func(id string) bool { return id != "3" }
+	for _, tc := range []struct {
+		name            string
+		names, oldNames []string
+		want            []string
+	}{
+		{
+			name:     "by name, in any order",
+			names:    []string{"b", "a"},
+			oldNames: []string{"a", "b"},
+			want:     []string{"2", "1"},
+		},
+		{
+			name:     "renamed block keeps its rule",
+			names:    []string{"renamed", "b"},
+			oldNames: []string{"a", "b"},
+			want:     []string{"1", "2"},
+		},
+		{
+			name:     "removed block is not matched",
+			names:    []string{"b"},
+			oldNames: []string{"a", "b"},
+			want:     []string{"2"},
+		},
+		{
+			name:     "new block in the position of a kept rule",
+			names:    []string{"new", "a"},
+			oldNames: []string{"a", "b"},
+			want:     []string{"", "1"},
+		},
+		{
+			name:     "deleted outside of Terraform",
+			names:    []string{"c", "renamed"},
+			oldNames: []string{"c", "a"},
+			want:     []string{"", "1"},
+		},
+		{
+			name:  "created",
+			names: []string{"x"},
+			want:  []string{""},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := matchPolicySetRules(tc.names, tc.oldNames, managed, exists); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("matchPolicySetRules(%v, %v) = %v, want %v", tc.names, tc.oldNames, got, tc.want)
+			}
+		})
+	}
+}
+
+// testAccCheckPolicySetRuleIDs copies the rule_ids of the resource to ids.
+func testAccCheckPolicySetRuleIDs(resourceName string, ids map[string]string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("didn't find resource: %s", resourceName)
+		}
+		for key, id := range rs.Primary.Attributes {
+			if key != "rule_ids.%" && strings.HasPrefix(key, "rule_ids.") {
+				ids[strings.TrimPrefix(key, "rule_ids.")] = id
+			}
+		}
+		return nil
+	}
+}
+
+func testAccCheckPolicySetRuleDeleted(resourceName string, id func() string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("didn't find resource: %s", resourceName)
+		}
+		apiClient := testAccProvider.Meta().(*Client)
+		rule, _, err := policysetcontrollerv2.GetPolicyRule(context.Background(), apiClient.Service, rs.Primary.ID, id())
+		if err == nil && rule != nil {
+			return fmt.Errorf("policy set rule with id %s still exists", id())
+		}
+		return nil
+	}
+}
+
+func testAccCheckPolicySetExistingRuleConfigure(rName string) string {
+	return fmt.Sprintf(`
+resource "%[1]s" "existing" {
+  name   = "%[2]s-existing"
+  action = "ALLOW"
+  conditions {
+    operator = "OR"
+    operands {
+      object_type = "CLIENT_TYPE"
+      values      = ["zpn_client_type_exporter"]
+    }
+  }
+}
+`, resourcetype.ZPAPolicyAccessRuleV2, rName)
+}
+
+func testAccCheckPolicySetDestroy(s *terraform.State) error {
+	apiClient := testAccProvider.Meta().(*Client)
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != resourcetype.ZPAPolicySet {
+			continue
+		}
+		for key, id := range rs.Primary.Attributes {
+			if key == "rule_ids.%" || !strings.HasPrefix(key, "rule_ids.") {
+				continue
+			}
+			rule, _, err := policysetcontrollerv2.GetPolicyRule(context.Background(), apiClient.Service, rs.Primary.ID, id)
+			if err == nil && rule != nil {
+				return fmt.Errorf("policy set rule with id %s still exists", id)
+			}
+		}
+	}
+	return nil
+}
+
+func testAccCheckPolicySetConfigure(rName, first, second string) string {
+	return fmt.Sprintf(`
+resource "%[1]s" "test" {
+  policy_type = "ACCESS_POLICY"
+
+  rule {
+    name   = "%[2]s-%[3]s"
+    action = "ALLOW"
+    conditions {
+      operator = "OR"
+      operands {
+        object_type = "CLIENT_TYPE"
+        values      = ["zpn_client_type_exporter"]
+      }
+    }
+  }
+
+  rule {
+    name   = "%[2]s-%[4]s"
+    action = "ALLOW"
+    conditions {
+      operator = "OR"
+      operands {
+        object_type = "CLIENT_TYPE"
+        values      = ["zpn_client_type_exporter"]
+      }
+    }
+  }
+}
+`, resourcetype.ZPAPolicySet, rName, first, second)
+}
+
+func testAccCheckPolicySetSingleRuleConfigure(rName, name string) string {
+	return fmt.Sprintf(`
+resource "%[1]s" "test" {
+  policy_type = "ACCESS_POLICY"
+
+  rule {
+    name   = "%[2]s-%[3]s"
+    action = "ALLOW"
+    conditions {
+      operator = "OR"
+      operands {
+        object_type = "CLIENT_TYPE"
+        values      = ["zpn_client_type_exporter"]
+      }
+    }
+  }
+}
+`, resourcetype.ZPAPolicySet, rName, name)
+}
+
+func testAccCheckPolicySetTimeoutConfigure(rName, timeouts string) string {
+	return fmt.Sprintf(`
+resource "%[1]s" "test" {
+  policy_type = "TIMEOUT_POLICY"
+
+  rule {
+    name   = "%[2]s"
+    action = "RE_AUTH"
+    %[3]s
+    conditions {
+      operator = "OR"
+      operands {
+        object_type = "CLIENT_TYPE"
+        values      = ["zpn_client_type_zapp"]
+      }
+    }
+  }
+}
+`, resourcetype.ZPAPolicySet, rName, timeouts)
+}
+
+func TestResourcePolicySetCustomizeDiff_Redirection(t *testing.T) {
+	// unknown is the value of attributes computed on apply in raw configs.
+	const unknown = "74D93920-ED26-11E3-AC10-0800200C9A66"
+	rule := func(action string, serviceEdgeGroupIDs ...interface{}) map[string]interface{} {
+		r := map[string]interface{}{"name": "a", "action": action}
+		if len(serviceEdgeGroupIDs) > 0 {
+			r["service_edge_groups"] = []interface{}{map[string]interface{}{"id": serviceEdgeGroupIDs}}
+		}
+		return r
+	}
+	for name, tc := range map[string]struct {
+		policyType string
+		rule       map[string]interface{}
+		wantErr    string
+	}{
+		"preferred": {policyType: policyTypeRedirection, rule: rule("REDIRECT_PREFERRED", "1")},
+		"default":   {policyType: policyTypeRedirection, rule: rule("REDIRECT_DEFAULT")},
+		"always without groups": {
+			policyType: policyTypeRedirection, rule: rule("REDIRECT_ALWAYS"),
+			wantErr: "one or more ZPA Private Service Edge groups must be selected",
+		},
+		"default with groups": {
+			policyType: policyTypeRedirection, rule: rule("REDIRECT_DEFAULT", "1"),
+			wantErr: "must be empty when the Private Service Edge Selection Method is REDIRECT_DEFAULT",
+		},
+		"other action": {
+			policyType: policyTypeRedirection, rule: rule("ALLOW"),
+			wantErr: `action must be one of "REDIRECT_DEFAULT", "REDIRECT_PREFERRED", "REDIRECT_ALWAYS"`,
+		},
+		"groups in another policy": {
+			policyType: policyTypeAccess, rule: rule("ALLOW", "1"),
+			wantErr: "service_edge_groups can only be set for REDIRECTION_POLICY rules",
+		},
+	} {
+		config := map[string]interface{}{"policy_type": tc.policyType, "rule": []interface{}{tc.rule}}
+		_, err := resourcePolicySet().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), nil)
+		if tc.wantErr == "" && err != nil {
+			t.Errorf("%s: unexpected error %v", name, err)
+		}
+		if tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)) {
+			t.Errorf("%s: got error %v, want %q", name, err, tc.wantErr)
+		}
+	}
+
+	// Service edge groups created by the same apply are checked on apply.
+	// Unknown set elements are only visible in the raw configuration.
+	config := map[string]interface{}{"policy_type": policyTypeRedirection, "rule": []interface{}{rule("REDIRECT_ALWAYS", unknown)}}
+	state := &terraform.InstanceState{RawConfig: cty.ObjectVal(map[string]cty.Value{
+		"rule": cty.ListVal([]cty.Value{cty.ObjectVal(map[string]cty.Value{
+			"service_edge_groups": cty.ListVal([]cty.Value{cty.ObjectVal(map[string]cty.Value{
+				"id": cty.SetVal([]cty.Value{cty.UnknownVal(cty.String)}),
+			})}),
+		})}),
+	})}
+	if _, err := resourcePolicySet().Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), nil); err != nil {
+		t.Errorf("service edge groups unknown: unexpected error %v", err)
+	}
+}
+
+func TestPolicySetRuleChanged_ServiceEdgeGroups(t *testing.T) {
+	existing := policysetcontrollerv2.PolicyRuleResource{
+		Name:              "a",
+		Action:            "REDIRECT_PREFERRED",
+		ServiceEdgeGroups: []serviceedgegroup.ServiceEdgeGroup{{ID: "1"}, {ID: "2"}},
+	}
+	desired := policysetcontrollerv2.PolicyRule{
+		Name:              "a",
+		Action:            "REDIRECT_PREFERRED",
+		ServiceEdgeGroups: []serviceedgegroup.ServiceEdgeGroup{{ID: "2"}, {ID: "1"}},
+	}
+	if policySetRuleChanged(existing, desired) {
+		t.Error("expected the same service edge groups in another order to be unchanged")
+	}
+	desired.ServiceEdgeGroups = desired.ServiceEdgeGroups[:1]
+	if !policySetRuleChanged(existing, desired) {
+		t.Error("expected a removed service edge group to change the rule")
+	}
+	if req := mergePolicySetRule(existing, desired); !sameServiceEdgeGroups(req.ServiceEdgeGroups, desired.ServiceEdgeGroups) {
+		t.Errorf("expected the update to set the service edge groups, got %v", req.ServiceEdgeGroups)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"log"
 	"strconv"
 	"time"
+	_ "time/tzdata" // Time zones are validated against the IANA database embedded in the provider
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -20,6 +21,7 @@ func resourcePRAPrivilegedApprovalController() *schema.Resource {
 		ReadContext:   resourcePRAPrivilegedApprovalControllerRead,
 		UpdateContext: resourcePRAPrivilegedApprovalControllerUpdate,
 		DeleteContext: resourcePRAPrivilegedApprovalControllerDelete,
+		CustomizeDiff: customizeDiffPRAApproval,
 		Importer: &schema.ResourceImporter{
 			StateContext: func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				zClient := meta.(*Client)
@@ -72,6 +74,13 @@ func resourcePRAPrivilegedApprovalController() *schema.Resource {
 				Computed:    true,
 				Description: "The end date that the user no longer has access to the privileged approval",
 			},
+			"duration": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"end_time"},
+				ValidateFunc:  validatePRAApprovalDuration,
+				Description:   "How long the user has access to the privileged approval from start_time, such as 8h or 3d. Sets end_time",
+			},
 			"status": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -88,13 +97,17 @@ func resourcePRAPrivilegedApprovalController() *schema.Resource {
 				Type:     schema.TypeSet,
 				Optional: true,
 				Computed: true,
+				Set:      hashWorkingHours,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"days": {
-							Type:        schema.TypeSet,
-							Optional:    true,
-							Computed:    true,
-							Elem:        &schema.Schema{Type: schema.TypeString},
+							Type:     schema.TypeSet,
+							Optional: true,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type:         schema.TypeString,
+								ValidateFunc: validation.StringInSlice(praWeekdays, false),
+							},
 							Description: "The days of the week that you want to enable the privileged approval",
 						},
 						"start_time": {
@@ -108,7 +121,8 @@ func resourcePRAPrivilegedApprovalController() *schema.Resource {
 							Type:        schema.TypeString,
 							Optional:    true,
 							Computed:    true,
-							Description: "The cron expression provided to configure the privileged approval start time working hours. The standard cron expression format is [Seconds][Minutes][Hours][Day of the Month][Month][Day of the Week][Year]",
+							Deprecated:  "start_time_cron is derived from days, start_time and end_time and will become read-only in the next major version. Remove it from the configuration.",
+							Description: "The cron expression of the privileged approval start time working hours, derived from days and start_time. The standard cron expression format is [Seconds][Minutes][Hours][Day of the Month][Month][Day of the Week][Year]",
 						},
 						"end_time": {
 							Type:         schema.TypeString,
@@ -121,7 +135,8 @@ func resourcePRAPrivilegedApprovalController() *schema.Resource {
 							Type:        schema.TypeString,
 							Optional:    true,
 							Computed:    true,
-							Description: "The cron expression provided to configure the privileged approval end time working hours. The standard cron expression format is [Seconds][Minutes][Hours][Day of the Month][Month][Day of the Week][Year]The cron expression provided to configure the privileged approval end time working hours. The standard cron expression format is [Seconds][Minutes][Hours][Day of the Month][Month][Day of the Week][Year]",
+							Deprecated:  "end_time_cron is derived from days, start_time and end_time and will become read-only in the next major version. Remove it from the configuration.",
+							Description: "The cron expression of the privileged approval end time working hours, derived from days and end_time. The standard cron expression format is [Seconds][Minutes][Hours][Day of the Month][Month][Day of the Week][Year]",
 						},
 						"timezone": {
 							Type:         schema.TypeString,
@@ -170,6 +185,13 @@ func resourcePRAPrivilegedApprovalControllerCreate(ctx context.Context, d *schem
 
 	// Convert user-provided RFC 2822 start and end times to epoch format.
 	startTimeStr, endTimeStr := d.Get("start_time").(string), d.Get("end_time").(string)
+	if duration := d.Get("duration").(string); duration != "" {
+		endTime, err := praApprovalEndTime(startTimeStr, duration)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		endTimeStr = endTime
+	}
 
 	// Validate start and end times
 	if err := validateStartTime(startTimeStr, endTimeStr); err != nil {
@@ -263,6 +285,13 @@ func resourcePRAPrivilegedApprovalControllerUpdate(ctx context.Context, d *schem
 
 	// Convert user-provided RFC 2822 start and end times to epoch format.
 	startTimeStr, endTimeStr := d.Get("start_time").(string), d.Get("end_time").(string)
+	if duration := d.Get("duration").(string); duration != "" {
+		endTime, err := praApprovalEndTime(startTimeStr, duration)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		endTimeStr = endTime
+	}
 
 	// Validate start and end times
 	if err := validateStartTime(startTimeStr, endTimeStr); err != nil {
@@ -363,7 +392,7 @@ func expandWorkingHours(d *schema.ResourceData) *praapproval.WorkingHours {
 				}
 			}
 
-			return &praapproval.WorkingHours{
+			workingHours := &praapproval.WorkingHours{
 				Days:          days,
 				StartTime:     workingHoursMap["start_time"].(string),
 				EndTime:       workingHoursMap["end_time"].(string),
@@ -371,6 +400,22 @@ func expandWorkingHours(d *schema.ResourceData) *praapproval.WorkingHours {
 				EndTimeCron:   workingHoursMap["end_time_cron"].(string),
 				TimeZone:      workingHoursMap["timezone"].(string),
 			}
+			// The cron expressions are planned from the days and times, or
+			// written in the configuration.
+			if (workingHours.StartTimeCron == "" || workingHours.EndTimeCron == "") && len(days) > 0 && workingHours.StartTime != "" && workingHours.EndTime != "" {
+				startCron, endCron, err := workingHoursCrons(days, workingHours.StartTime, workingHours.EndTime)
+				if err != nil {
+					log.Printf("[WARN] Keeping the cron expressions of working hours: %v", err)
+				} else {
+					if workingHours.StartTimeCron == "" {
+						workingHours.StartTimeCron = startCron
+					}
+					if workingHours.EndTimeCron == "" {
+						workingHours.EndTimeCron = endCron
+					}
+				}
+			}
+			return workingHours
 		}
 	}
 	return nil
@@ -390,9 +435,14 @@ func flattenPRAApplicationsSimple(apps []praapproval.Applications) []interface{}
 
 func validateTimeZone(v interface{}, k string) (ws []string, errors []error) {
 	tzStr := v.(string)
+	// LoadLocation also accepts "" and "Local", which are not IANA names.
+	if tzStr == "" || tzStr == "Local" {
+		errors = append(errors, fmt.Errorf("%q is not a valid timezone, expected an IANA name such as America/Vancouver", tzStr))
+		return
+	}
 	_, err := time.LoadLocation(tzStr)
 	if err != nil {
-		errors = append(errors, fmt.Errorf("%q is not a valid timezone", tzStr))
+		errors = append(errors, fmt.Errorf("%q is not a valid timezone, expected an IANA name such as America/Vancouver", tzStr))
 	}
 
 	return
@@ -3,6 +3,7 @@ package zpa
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -54,6 +55,73 @@ func TestAccResourcePRAPrivilegedApprovalController_Basic(t *testing.T) {
 	})
 }
 
+func TestAccResourcePRAPrivilegedApprovalController_Duration(t *testing.T) {
+	var praApproval praapproval.PrivilegedApproval
+	resourceTypeAndName, _, generatedName := method.GenerateRandomSourcesTypeAndName(resourcetype.ZPAPRAApprovalController)
+	resourceName := strings.Split(resourceTypeAndName, ".")[1]
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPRAPrivilegedApprovalDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckPRAPrivilegedApprovalDurationConfigure(resourceTypeAndName, generatedName, "3d"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckPRAPrivilegedApprovalExists(resourceTypeAndName, &praApproval),
+					resource.TestCheckResourceAttr(resourceTypeAndName, "duration", "3d"),
+					resource.TestCheckResourceAttrSet(resourceTypeAndName, "end_time"),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceTypeAndName, "working_hours.*", map[string]string{
+						"start_time_cron": "0 10 0 ? * MON,TUE,WED,THU,FRI,SAT,SUN",
+						"end_time_cron":   "0 15 9 ? * MON,TUE,WED,THU,FRI,SAT,SUN",
+					}),
+				),
+			},
+			// A second approval of the same user and application during the
+			// same days is rejected during plan
+			{
+				Config: testAccCheckPRAPrivilegedApprovalDurationConfigure(resourceTypeAndName, generatedName, "3d") + fmt.Sprintf(`
+resource "%s" "%s_overlap" {
+	email_ids = %s.email_ids
+	start_time = %s.start_time
+	duration = "8h"
+	status = "ACTIVE"
+	applications {
+		id = [zpa_application_segment_pra.this.id]
+	}
+}
+`, resourcetype.ZPAPRAApprovalController, resourceName, resourceTypeAndName, resourceTypeAndName),
+				ExpectError: regexp.MustCompile("overlaps other approvals"),
+			},
+			// Two new approvals of the same configuration that overlap each
+			// other are rejected during plan, the end of each computed from
+			// its duration
+			{
+				Config: testAccCheckPRAPrivilegedApprovalDurationConfigure(resourceTypeAndName, generatedName, "3d") + fmt.Sprintf(`
+resource "%[1]s" "%[2]s_next" {
+	count = 2
+	email_ids = %[3]s.email_ids
+	start_time = %[3]s.end_time
+	duration = "${8 + count.index}h"
+	status = "FUTURE"
+	applications {
+		id = [zpa_application_segment_pra.this.id]
+	}
+}
+`, resourcetype.ZPAPRAApprovalController, resourceName, resourceTypeAndName),
+				ExpectError: regexp.MustCompile("overlaps other approvals(.|\\n)*planned approval for"),
+			},
+			// Import test
+			{
+				ResourceName:            resourceTypeAndName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"duration"},
+			},
+		},
+	})
+}
+
 func testAccCheckPRAPrivilegedApprovalDestroy(s *terraform.State) error {
 	apiClient := testAccProvider.Meta().(*Client)
 
@@ -199,3 +267,92 @@ data "%s" "%s" {
 		resourcetype.ZPAPRAApprovalController, resourceName,
 	)
 }
+
+func testAccCheckPRAPrivilegedApprovalDurationConfigure(resourceTypeAndName, generatedName, duration string) string {
+	startTime := time.Now().Add(-30 * time.Minute).Format(time.RFC1123)
+
+	window := fmt.Sprintf(`start_time = "%s"
+	duration = "%s"`, startTime, duration)
+	return testAccCheckPRAPrivilegedApprovalWindowConfigure(resourceTypeAndName, generatedName, window)
+}
+
+func testAccCheckPRAPrivilegedApprovalWindowConfigure(resourceTypeAndName, generatedName, window string) string {
+	resourceName := strings.Split(resourceTypeAndName, ".")[1] // Extract the resource name
+
+	userEmails := []string{
+		"kathy.kavanagh@securitygeek.io",
+	}
+	emailIDs := fmt.Sprintf(`["%s"]`, strings.Join(userEmails, `", "`))
+	return fmt.Sprintf(`
+
+resource "zpa_application_segment_pra" "this" {
+	name             = "tf-acc-test-%s"
+	description      = "tf-acc-test-%s"
+	enabled          = true
+	health_reporting = "ON_ACCESS"
+	bypass_type      = "NEVER"
+	is_cname_enabled = true
+	tcp_port_ranges  = ["3222", "3222", "3391", "3391"]
+	domain_names     = ["ssh_pra3222.example.com", "rdp_pra3391.example.com"]
+	segment_group_id = zpa_segment_group.this.id
+	common_apps_dto {
+		apps_config {
+		domain               = "rdp_pra3391.example.com"
+		application_protocol = "RDP"
+		connection_security  = "ANY"
+		application_port     = "3391"
+		app_types            = ["SECURE_REMOTE_ACCESS"]
+		}
+		apps_config {
+		domain               = "ssh_pra3222.example.com"
+		application_protocol = "SSH"
+		application_port     = "3222"
+		app_types            = ["SECURE_REMOTE_ACCESS"]
+		}
+	}
+}
+
+resource "zpa_segment_group" "this" {
+	name        = "tf-acc-test-%s"
+	description = "tf-acc-test-%s"
+	enabled     = true
+}
+
+resource "%s" "%s" {
+	email_ids = %s
+	%s
+	status = "ACTIVE"
+	applications {
+		id = [zpa_application_segment_pra.this.id]
+}
+	working_hours {
+		days = ["FRI", "MON", "SAT", "SUN", "THU", "TUE", "WED"]
+		start_time = "00:10"
+		end_time = "09:15"
+		timezone = "America/Vancouver"
+	}
+}
+
+data "%s" "%s" {
+	id = "${%s.%s.id}"
+  }
+`,
+		// Resource name for pra application segment and segment group
+		generatedName,
+		generatedName,
+		generatedName,
+		generatedName,
+
+		// Resource type and name for privileged approval
+		resourcetype.ZPAPRAApprovalController,
+		resourceName,
+		emailIDs,
+		window,
+
+		// Data source type and name
+		resourcetype.ZPAPRAApprovalController, resourceName,
+
+		// Reference to the resource
+		resourcetype.ZPAPRAApprovalController, resourceName,
+	)
+}
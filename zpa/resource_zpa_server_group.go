@@ -217,10 +217,11 @@ func resourceServerGroupCreate(ctx context.Context, d *schema.ResourceData, meta
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	zClient.invalidatePrefetch(prefetchServerGroup, prefetchApplicationSegment, prefetchApplicationServer)
 	log.Printf("[INFO] Created server group request. ID: %v\n", resp)
 	d.SetId(resp.ID)
 
-	return resourceServerGroupRead(ctx, d, meta)
+	return resourceServerGroupRead(withoutPrefetch(ctx), d, meta)
 }
 
 func resourceServerGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -232,15 +233,19 @@ func resourceServerGroupRead(ctx context.Context, d *schema.ResourceData, meta i
 		service = service.WithMicroTenant(microTenantID)
 	}
 
-	resp, _, err := servergroup.Get(ctx, service, d.Id())
-	if err != nil {
-		if respErr, ok := err.(*errorx.ErrorResponse); ok && respErr.IsObjectNotFound() {
-			log.Printf("[WARN] Removing server group %s from state because it no longer exists in ZPA", d.Id())
-			d.SetId("")
-			return nil
-		}
+	resp := zClient.prefetchedServerGroup(ctx, service, microTenantID, d.Id())
+	if resp == nil {
+		var err error
+		resp, _, err = servergroup.Get(ctx, service, d.Id())
+		if err != nil {
+			if respErr, ok := err.(*errorx.ErrorResponse); ok && respErr.IsObjectNotFound() {
+				log.Printf("[WARN] Removing server group %s from state because it no longer exists in ZPA", d.Id())
+				d.SetId("")
+				return nil
+			}
 
-		return diag.FromErr(err)
+			return diag.FromErr(err)
+		}
 	}
 
 	log.Printf("[INFO] Getting server group:\n%+v\n", resp)
@@ -295,7 +300,8 @@ func resourceServerGroupUpdate(ctx context.Context, d *schema.ResourceData, meta
 	if _, err := servergroup.Update(ctx, service, id, &req); err != nil {
 		return diag.FromErr(err)
 	}
-	return resourceServerGroupRead(ctx, d, meta)
+	zClient.invalidatePrefetch(prefetchServerGroup, prefetchApplicationSegment, prefetchApplicationServer)
+	return resourceServerGroupRead(withoutPrefetch(ctx), d, meta)
 }
 
 func resourceServerGroupDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -319,6 +325,7 @@ func resourceServerGroupDelete(ctx context.Context, d *schema.ResourceData, meta
 	if _, err := servergroup.Delete(ctx, service, d.Id()); err != nil {
 		return diag.FromErr(err)
 	}
+	zClient.invalidatePrefetch(prefetchServerGroup, prefetchApplicationSegment, prefetchApplicationServer)
 	d.SetId("")
 	log.Printf("[INFO] Server group deleted")
 	return nil
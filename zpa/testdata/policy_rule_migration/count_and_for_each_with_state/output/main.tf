@@ -0,0 +1,68 @@
+resource "zpa_policy_access_rule_v2" "regions" {
+  count  = 2
+  name   = "Region ${count.index}"
+  action = "ALLOW"
+
+  conditions {
+    operator = "OR"
+    operands {
+      object_type = "APP_GROUP"
+      values      = [var.segment_group_ids[count.index]]
+    }
+  }
+}
+
+resource "zpa_policy_forwarding_rule_v2" "bypass" {
+  for_each = toset(["office", "lab"])
+  name     = "Bypass ${each.key}"
+  action   = "BYPASS"
+
+  conditions {
+    operator = "OR"
+    operands {
+      object_type = "CLIENT_TYPE"
+      values      = ["zpn_client_type_zapp"]
+    }
+  }
+}
+
+resource "zpa_policy_access_rule_v2" "new" {
+  name   = "Not applied yet"
+  action = "DENY"
+}
+
+# Move the existing rules to the v2 resources without recreating them.
+# These blocks can be removed after the next apply.
+import {
+  to = zpa_policy_access_rule_v2.regions[0]
+  id = "216196257331370001"
+}
+
+import {
+  to = zpa_policy_access_rule_v2.regions[1]
+  id = "216196257331370002"
+}
+
+removed {
+  from = zpa_policy_access_rule.regions
+  lifecycle {
+    destroy = false
+  }
+}
+
+import {
+  to = zpa_policy_forwarding_rule_v2.bypass["lab"]
+  id = "216196257331370003"
+}
+
+import {
+  to = zpa_policy_forwarding_rule_v2.bypass["office"]
+  id = "216196257331370004"
+}
+
+removed {
+  from = zpa_policy_forwarding_rule.bypass
+  lifecycle {
+    destroy = false
+  }
+}
@@ -0,0 +1,34 @@
+resource "zpa_policy_access_rule" "regions" {
+  count  = 2
+  name   = "Region ${count.index}"
+  action = "ALLOW"
+
+  conditions {
+    operator = "OR"
+    operands {
+      object_type = "APP_GROUP"
+      lhs         = "id"
+      rhs         = var.segment_group_ids[count.index]
+    }
+  }
+}
+
+resource "zpa_policy_forwarding_rule" "bypass" {
+  for_each = toset(["office", "lab"])
+  name     = "Bypass ${each.key}"
+  action   = "BYPASS"
+
+  conditions {
+    operator = "OR"
+    operands {
+      object_type = "CLIENT_TYPE"
+      lhs         = "id"
+      rhs         = "zpn_client_type_zapp"
+    }
+  }
+}
+
+resource "zpa_policy_access_rule" "new" {
+  name   = "Not applied yet"
+  action = "DENY"
+}
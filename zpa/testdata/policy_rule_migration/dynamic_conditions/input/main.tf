@@ -0,0 +1,30 @@
+resource "zpa_policy_access_rule" "dynamic" {
+  name   = "Dynamic"
+  action = "ALLOW"
+
+  dynamic "conditions" {
+    for_each = var.conditions
+    content {
+      operator = "OR"
+      operands {
+        object_type = conditions.value.object_type
+        lhs         = "id"
+        rhs         = conditions.value.id
+      }
+    }
+  }
+}
+
+resource "zpa_policy_access_rule" "idp" {
+  name   = "IdP"
+  action = "ALLOW"
+
+  conditions {
+    operator = "OR"
+    operands {
+      object_type = "IDP"
+      lhs         = "id"
+      rhs         = data.zpa_idp_controller.this.id
+    }
+  }
+}
@@ -0,0 +1,35 @@
+resource "zpa_policy_timeout_rule" "contractors" {
+  name                = "Contractors"
+  action              = "RE_AUTH"
+  rule_order          = 3
+  reauth_idle_timeout = "600"
+  reauth_timeout      = "172800"
+
+  conditions {
+    operator = "OR"
+    operands {
+      object_type = "SCIM_GROUP"
+      lhs         = data.zpa_idp_controller.this.id
+      rhs         = data.zpa_scim_groups.contractors.id
+    }
+    operands {
+      object_type = "SCIM_GROUP"
+      lhs         = data.zpa_idp_controller.this.id
+      rhs         = data.zpa_scim_groups.vendors.id
+    }
+  }
+
+  conditions {
+    operator = "OR"
+    operands {
+      object_type = "POSTURE"
+      lhs         = data.zpa_posture_profile.crowdstrike.posture_udid
+      rhs         = "true"
+    }
+    operands {
+      object_type = "PLATFORM"
+      lhs         = "windows"
+      rhs         = "true"
+    }
+  }
+}
@@ -0,0 +1,53 @@
+resource "zpa_policy_timeout_rule_v2" "contractors" {
+  name                = "Contractors"
+  action              = "RE_AUTH"
+  reauth_idle_timeout = "600"
+  reauth_timeout      = "172800"
+
+  conditions {
+    operator = "OR"
+    operands {
+      object_type = "SCIM_GROUP"
+      entry_values {
+        lhs = data.zpa_idp_controller.this.id
+        rhs = data.zpa_scim_groups.contractors.id
+      }
+      entry_values {
+        lhs = data.zpa_idp_controller.this.id
+        rhs = data.zpa_scim_groups.vendors.id
+      }
+    }
+  }
+
+  conditions {
+    operator = "OR"
+    operands {
+      object_type = "POSTURE"
+      entry_values {
+        lhs = data.zpa_posture_profile.crowdstrike.posture_udid
+        rhs = "true"
+      }
+    }
+    operands {
+      object_type = "PLATFORM"
+      entry_values {
+        lhs = "windows"
+        rhs = "true"
+      }
+    }
+  }
+}
+
+# Move the existing rules to the v2 resources without recreating them.
+# These blocks can be removed after the next apply.
+import {
+  to = zpa_policy_timeout_rule_v2.contractors
+  id = "Contractors"
+}
+
+removed {
+  from = zpa_policy_timeout_rule.contractors
+  lifecycle {
+    destroy = false
+  }
+}
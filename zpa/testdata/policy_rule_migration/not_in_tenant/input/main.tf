@@ -0,0 +1,16 @@
+resource "zpa_policy_inspection_rule" "existing" {
+  name                      = "Existing"
+  action                    = "INSPECT"
+  zpn_inspection_profile_id = zpa_inspection_profile.this.id
+}
+
+resource "zpa_policy_inspection_rule" "planned" {
+  name                      = "Planned"
+  action                    = "INSPECT"
+  zpn_inspection_profile_id = zpa_inspection_profile.this.id
+}
+
+resource "zpa_policy_isolation_rule" "computed_name" {
+  name   = "${var.prefix}-isolate"
+  action = "ISOLATE"
+}
@@ -0,0 +1,30 @@
+resource "zpa_policy_inspection_rule_v2" "existing" {
+  name                      = "Existing"
+  action                    = "INSPECT"
+  zpn_inspection_profile_id = zpa_inspection_profile.this.id
+}
+
+resource "zpa_policy_inspection_rule_v2" "planned" {
+  name                      = "Planned"
+  action                    = "INSPECT"
+  zpn_inspection_profile_id = zpa_inspection_profile.this.id
+}
+
+resource "zpa_policy_isolation_rule" "computed_name" {
+  name   = "${var.prefix}-isolate"
+  action = "ISOLATE"
+}
+
+# Move the existing rules to the v2 resources without recreating them.
+# These blocks can be removed after the next apply.
+import {
+  to = zpa_policy_inspection_rule_v2.existing
+  id = "Existing"
+}
+
+removed {
+  from = zpa_policy_inspection_rule.existing
+  lifecycle {
+    destroy = false
+  }
+}
@@ -0,0 +1,11 @@
+resource "zpa_policy_access_rule" "allow" {
+  name   = "Allow"
+  action = "ALLOW"
+}
+
+resource "zpa_policy_access_rule" "deny" {
+  name        = "Deny"
+  description = "Below ${zpa_policy_access_rule.allow.name}"
+  action      = "DENY"
+  depends_on  = [zpa_policy_access_rule.allow]
+}
@@ -0,0 +1,7 @@
+output "allow_rule_id" {
+  value = zpa_policy_access_rule.allow.id
+}
+
+output "unrelated" {
+  value = zpa_policy_access_rule_reorder.this.id
+}
@@ -0,0 +1,37 @@
+resource "zpa_policy_access_rule_v2" "allow" {
+  name   = "Allow"
+  action = "ALLOW"
+}
+
+resource "zpa_policy_access_rule_v2" "deny" {
+  name        = "Deny"
+  description = "Below ${zpa_policy_access_rule_v2.allow.name}"
+  action      = "DENY"
+  depends_on  = [zpa_policy_access_rule_v2.allow]
+}
+
+# Move the existing rules to the v2 resources without recreating them.
+# These blocks can be removed after the next apply.
+import {
+  to = zpa_policy_access_rule_v2.allow
+  id = "Allow"
+}
+
+removed {
+  from = zpa_policy_access_rule.allow
+  lifecycle {
+    destroy = false
+  }
+}
+
+import {
+  to = zpa_policy_access_rule_v2.deny
+  id = "Deny"
+}
+
+removed {
+  from = zpa_policy_access_rule.deny
+  lifecycle {
+    destroy = false
+  }
+}
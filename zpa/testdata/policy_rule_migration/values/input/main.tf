@@ -0,0 +1,53 @@
+resource "zpa_policy_access_rule" "finance" {
+  name        = "Finance"
+  description = "Finance applications"
+  action      = "ALLOW"
+  operator    = "AND"
+
+  conditions {
+    operator = "OR"
+    operands {
+      object_type = "APP"
+      lhs         = "id"
+      rhs         = zpa_application_segment.ledger.id
+    }
+    operands {
+      object_type = "APP"
+      lhs         = "id"
+      rhs_list    = var.finance_app_ids
+    }
+    operands {
+      object_type = "APP"
+      lhs         = "id"
+      rhs         = "216196257331291924"
+    }
+  }
+
+  conditions {
+    operator = "OR"
+    operands {
+      object_type = "CLIENT_TYPE"
+      lhs         = "id"
+      rhs         = "zpn_client_type_exporter"
+    }
+    operands {
+      object_type = "CLIENT_TYPE"
+      lhs         = "id"
+      rhs         = "zpn_client_type_zapp"
+    }
+  }
+}
+
+resource "zpa_policy_access_rule" "hr" {
+  name   = "HR"
+  action = "ALLOW"
+
+  conditions {
+    operator = "OR"
+    operands {
+      object_type = "APP_GROUP"
+      lhs         = "id"
+      rhs_list    = [zpa_segment_group.hr.id]
+    }
+  }
+}
@@ -0,0 +1,61 @@
+resource "zpa_policy_access_rule_v2" "finance" {
+  name        = "Finance"
+  description = "Finance applications"
+  action      = "ALLOW"
+  operator    = "AND"
+
+  conditions {
+    operator = "OR"
+    operands {
+      object_type = "APP"
+      values      = setunion([zpa_application_segment.ledger.id, "216196257331291924"], var.finance_app_ids)
+    }
+  }
+
+  conditions {
+    operator = "OR"
+    operands {
+      object_type = "CLIENT_TYPE"
+      values      = ["zpn_client_type_exporter", "zpn_client_type_zapp"]
+    }
+  }
+}
+
+resource "zpa_policy_access_rule_v2" "hr" {
+  name   = "HR"
+  action = "ALLOW"
+
+  conditions {
+    operator = "OR"
+    operands {
+      object_type = "APP_GROUP"
+      values      = [zpa_segment_group.hr.id]
+    }
+  }
+}
+
+# Move the existing rules to the v2 resources without recreating them.
+# These blocks can be removed after the next apply.
+import {
+  to = zpa_policy_access_rule_v2.finance
+  id = "Finance"
+}
+
+removed {
+  from = zpa_policy_access_rule.finance
+  lifecycle {
+    destroy = false
+  }
+}
+
+import {
+  to = zpa_policy_access_rule_v2.hr
+  id = "HR"
+}
+
+removed {
+  from = zpa_policy_access_rule.hr
+  lifecycle {
+    destroy = false
+  }
+}
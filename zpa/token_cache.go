@@ -0,0 +1,259 @@
+package zpa
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// tokenCacheExpirySkew is how long before its expiry a cached token stops
+// being handed out, so that it does not expire mid-run.
+const tokenCacheExpirySkew = 2 * time.Minute
+
+// cachedToken is the plaintext content of a token cache entry.
+type cachedToken struct {
+	AccessToken string    `json:"access_token"`
+	TokenType   string    `json:"token_type"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// tokenCacheTransport persists OneAPI access tokens on disk so that the
+// provider processes Terraform starts for validate, plan and apply can reuse a
+// token instead of each performing a new token exchange.
+//
+// Entries are keyed by client ID, vanity domain, cloud, auth_endpoint override
+// and a fingerprint of the client secret or private key, and are encrypted
+// with AES-GCM using a key derived from that secret. An entry written with
+// different credentials fails to decrypt and is ignored.
+type tokenCacheTransport struct {
+	next http.RoundTripper
+	path string
+	key  []byte
+}
+
+func newTokenCacheTransport(next http.RoundTripper, c *Config) (*tokenCacheTransport, error) {
+	dir := c.tokenCacheDir
+	if dir == "" {
+		userCacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine token cache directory: %v", err)
+		}
+		dir = filepath.Join(userCacheDir, "terraform-provider-zpa", "tokens")
+	}
+
+	secret := c.clientSecret
+	if secret == "" {
+		secret = c.privateKey
+		// private_key may be a path to the key file
+		if content, err := os.ReadFile(c.privateKey); err == nil {
+			secret = string(content)
+		}
+	}
+	if c.clientID == "" || secret == "" {
+		return nil, fmt.Errorf("token cache requires client_id and either client_secret or private_key")
+	}
+
+	// The fingerprint of the secret keeps configurations that share a client
+	// ID but not its secret from overwriting each other's entry.
+	fingerprint := hmac.New(sha256.New, []byte(secret))
+	fingerprint.Write([]byte(c.clientID))
+	id := sha256.Sum256([]byte(strings.Join([]string{
+		c.clientID,
+		strings.ToLower(c.vanityDomain),
+		strings.ToLower(c.cloud),
+		strings.TrimSuffix(c.authEndpoint, "/"),
+		hex.EncodeToString(fingerprint.Sum(nil)[:8]),
+	}, "\x00")))
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("terraform-provider-zpa/token-cache/v1\x00"))
+	mac.Write(id[:])
+
+	log.Printf("[INFO] OAuth token cache enabled in %s", dir)
+	return &tokenCacheTransport{
+		next: next,
+		path: filepath.Join(dir, hex.EncodeToString(id[:])+".json"),
+		key:  mac.Sum(nil),
+	}, nil
+}
+
+func isTokenRequest(req *http.Request) bool {
+	return req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/oauth2/v1/token")
+}
+
+func (t *tokenCacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isTokenRequest(req) {
+		resp, err := t.next.RoundTrip(req)
+		// A revoked or otherwise rejected token must not be handed out again,
+		// neither to the SDK when it requests a new one nor to later processes.
+		if err == nil && resp.StatusCode == http.StatusUnauthorized {
+			t.drop()
+		}
+		return resp, err
+	}
+
+	if token, ok := t.load(); ok {
+		log.Printf("[DEBUG] Using cached OAuth token, valid until %s", token.ExpiresAt.Format(time.RFC3339))
+		return tokenResponse(req, token)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	var issued struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &issued); err != nil || issued.AccessToken == "" || issued.ExpiresIn <= 0 {
+		log.Printf("[DEBUG] Not caching OAuth token response: unexpected payload")
+		return resp, nil
+	}
+	t.store(cachedToken{
+		AccessToken: issued.AccessToken,
+		TokenType:   issued.TokenType,
+		ExpiresAt:   time.Now().Add(time.Duration(issued.ExpiresIn) * time.Second),
+	})
+	return resp, nil
+}
+
+func tokenResponse(req *http.Request, token *cachedToken) (*http.Response, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"access_token": token.AccessToken,
+		"token_type":   token.TokenType,
+		"expires_in":   int(time.Until(token.ExpiresAt).Seconds()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func (t *tokenCacheTransport) load() (*cachedToken, bool) {
+	data, err := os.ReadFile(t.path)
+	if err != nil {
+		return nil, false
+	}
+	plaintext, err := t.decrypt(data)
+	if err != nil {
+		log.Printf("[DEBUG] Ignoring OAuth token cache entry: %v", err)
+		return nil, false
+	}
+	var token cachedToken
+	if err := json.Unmarshal(plaintext, &token); err != nil {
+		return nil, false
+	}
+	if token.AccessToken == "" || time.Until(token.ExpiresAt) < tokenCacheExpirySkew {
+		return nil, false
+	}
+	return &token, true
+}
+
+func (t *tokenCacheTransport) drop() {
+	if err := os.Remove(t.path); err == nil {
+		log.Printf("[DEBUG] Dropped cached OAuth token after an unauthorized response")
+	} else if !os.IsNotExist(err) {
+		log.Printf("[WARN] Failed to remove OAuth token cache entry: %v", err)
+	}
+}
+
+func (t *tokenCacheTransport) store(token cachedToken) {
+	plaintext, err := json.Marshal(token)
+	if err != nil {
+		return
+	}
+	data, err := t.encrypt(plaintext)
+	if err != nil {
+		log.Printf("[WARN] Failed to encrypt OAuth token cache entry: %v", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(t.path), 0o700); err != nil {
+		log.Printf("[WARN] Failed to create OAuth token cache directory: %v", err)
+		return
+	}
+	// Write to a temporary file first so concurrent provider processes never
+	// read a partially written entry.
+	tmp, err := os.CreateTemp(filepath.Dir(t.path), ".token-*")
+	if err != nil {
+		log.Printf("[WARN] Failed to write OAuth token cache entry: %v", err)
+		return
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		log.Printf("[WARN] Failed to write OAuth token cache entry: %v", err)
+		return
+	}
+	if err := tmp.Close(); err != nil {
+		return
+	}
+	if err := os.Rename(tmp.Name(), t.path); err != nil {
+		log.Printf("[WARN] Failed to write OAuth token cache entry: %v", err)
+	}
+}
+
+func (t *tokenCacheTransport) encrypt(plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(t.key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func (t *tokenCacheTransport) decrypt(data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(t.key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, fmt.Errorf("entry is truncated")
+	}
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("entry was written with different credentials or is corrupt")
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,174 @@
+package zpa
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// testTokenCacheServer serves OneAPI token requests and an API endpoint that
+// answers with status to requests.
+func testTokenCacheServer(t *testing.T, tokenRequests *int32, status *int32) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/oauth2/v1/token") {
+			n := atomic.AddInt32(tokenRequests, 1)
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":3600}`, n)
+			return
+		}
+		w.WriteHeader(int(atomic.LoadInt32(status)))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func testTokenCacheConfig(dir string) *Config {
+	return &Config{
+		clientID:          "client",
+		clientSecret:      "secret",
+		vanityDomain:      "acme",
+		tokenCache:        true,
+		tokenCacheDir:     dir,
+		requestTimeout:    10,
+		rateLimitInterval: 10,
+	}
+}
+
+func requestToken(t *testing.T, client *http.Client, server *httptest.Server) string {
+	t.Helper()
+	resp, err := client.Post(server.URL+"/oauth2/v1/token", "application/x-www-form-urlencoded", strings.NewReader("grant_type=client_credentials"))
+	if err != nil {
+		t.Fatalf("token request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return string(body)
+}
+
+func TestTokenCache_SecondClientReusesToken(t *testing.T) {
+	var tokenRequests int32
+	status := int32(http.StatusOK)
+	server := testTokenCacheServer(t, &tokenRequests, &status)
+	dir := t.TempDir()
+
+	first, err := testTokenCacheConfig(dir).httpClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body := requestToken(t, first, server); !strings.Contains(body, "token-1") {
+		t.Fatalf("unexpected token response %s", body)
+	}
+
+	// A new configuration stands for the next provider process
+	second, err := testTokenCacheConfig(dir).httpClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body := requestToken(t, second, server); !strings.Contains(body, "token-1") {
+		t.Fatalf("expected the cached token, got %s", body)
+	}
+	if tokenRequests != 1 {
+		t.Fatalf("expected 1 token request, got %d", tokenRequests)
+	}
+
+	// Other credentials do not get the cached token
+	other := testTokenCacheConfig(dir)
+	other.clientSecret = "other"
+	third, err := other.httpClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body := requestToken(t, third, server); !strings.Contains(body, "token-2") {
+		t.Fatalf("expected a new token, got %s", body)
+	}
+}
+
+func TestTokenCache_UnauthorizedDropsToken(t *testing.T) {
+	var tokenRequests int32
+	status := int32(http.StatusUnauthorized)
+	server := testTokenCacheServer(t, &tokenRequests, &status)
+	dir := t.TempDir()
+
+	client, err := testTokenCacheConfig(dir).httpClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	requestToken(t, client, server)
+	resp, err := client.Get(server.URL + "/zpa/mgmtconfig/v1/admin/customers/1/application")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if body := requestToken(t, client, server); !strings.Contains(body, "token-2") {
+		t.Fatalf("expected a new token after the unauthorized response, got %s", body)
+	}
+}
+
+func TestTokenCache_LegacyClientIsNotCached(t *testing.T) {
+	var tokenRequests int32
+	status := int32(http.StatusOK)
+	server := testTokenCacheServer(t, &tokenRequests, &status)
+	dir := t.TempDir()
+
+	for i := 0; i < 2; i++ {
+		c := testTokenCacheConfig(dir)
+		c.useLegacyClient = true
+		client, err := c.httpClient()
+		if err != nil {
+			t.Fatal(err)
+		}
+		requestToken(t, client, server)
+	}
+	if tokenRequests != 2 {
+		t.Fatalf("expected 2 token requests, got %d", tokenRequests)
+	}
+}
+
+func TestTokenCache_KeyedByAuthEndpointAndSecret(t *testing.T) {
+	var tokenRequests int32
+	status := int32(http.StatusOK)
+	server := testTokenCacheServer(t, &tokenRequests, &status)
+	dir := t.TempDir()
+
+	request := func(c *Config) string {
+		t.Helper()
+		client, err := c.httpClient()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return requestToken(t, client, server)
+	}
+
+	if body := request(testTokenCacheConfig(dir)); !strings.Contains(body, "token-1") {
+		t.Fatalf("unexpected token response %s", body)
+	}
+
+	// Another secret for the same client ID gets its own entry
+	other := testTokenCacheConfig(dir)
+	other.clientSecret = "other"
+	if body := request(other); !strings.Contains(body, "token-2") {
+		t.Fatalf("expected a new token for another secret, got %s", body)
+	}
+	if body := request(testTokenCacheConfig(dir)); !strings.Contains(body, "token-1") {
+		t.Fatalf("expected the entry of the first secret to be kept, got %s", body)
+	}
+
+	// A token issued by another auth server is not reused
+	overridden := testTokenCacheConfig(dir)
+	overridden.authEndpoint = server.URL + "/idp"
+	if body := request(overridden); !strings.Contains(body, "token-3") {
+		t.Fatalf("expected a new token for another auth_endpoint, got %s", body)
+	}
+	if body := request(overridden); !strings.Contains(body, "token-3") {
+		t.Fatalf("expected the cached token of the auth_endpoint, got %s", body)
+	}
+
+	if tokenRequests != 3 {
+		t.Fatalf("expected 3 token requests, got %d", tokenRequests)
+	}
+}
@@ -0,0 +1,335 @@
+package zpa
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// usesHTTPClient reports whether an option carried by the HTTP client of
+// httpClient is set. Otherwise the SDK keeps its own HTTP client.
+func (c *Config) usesHTTPClient() bool {
+	return c.httpProxy != "" || c.httpsProxy != "" ||
+		c.caBundleFile != "" || c.clientCertFile != "" || c.clientKeyFile != "" ||
+		c.apiEndpoint != "" || c.authEndpoint != "" ||
+		c.getRateLimit > 0 || c.writeRateLimit > 0 ||
+		(c.tokenCache && !c.useLegacyClient)
+}
+
+// httpClient returns the HTTP client shared by every SDK client created from
+// this configuration. It is built once so that state held by the transport
+// chain, such as the rate limiter buckets, is shared by all resources and data
+// sources in a run.
+func (c *Config) httpClient() (*http.Client, error) {
+	c.httpClientOnce.Do(func() {
+		var base http.RoundTripper
+		base, c.httpClientErr = c.baseTransport()
+		if c.httpClientErr != nil {
+			return
+		}
+
+		var transport http.RoundTripper = base
+		if c.apiEndpoint != "" || c.authEndpoint != "" {
+			transport, c.httpClientErr = newEndpointTransport(transport, c.apiEndpoint, c.authEndpoint)
+			if c.httpClientErr != nil {
+				return
+			}
+		}
+		if c.getRateLimit > 0 || c.writeRateLimit > 0 {
+			transport = newRateLimitedTransport(transport, c.getRateLimit, c.writeRateLimit, time.Duration(c.rateLimitInterval)*time.Second)
+		}
+		// The token cache is the outermost transport so that a cached token
+		// does not use up rate limiter tokens. Legacy clients sign in with a
+		// different flow that is not cached.
+		if c.tokenCache && !c.useLegacyClient {
+			tokenCache, err := newTokenCacheTransport(transport, c)
+			if err != nil {
+				log.Printf("[WARN] OAuth token cache disabled: %v", err)
+			} else {
+				transport = tokenCache
+			}
+		}
+
+		c.sharedHTTPClient = &http.Client{
+			Transport: transport,
+			Timeout:   time.Duration(c.requestTimeout) * time.Second,
+		}
+	})
+	return c.sharedHTTPClient, c.httpClientErr
+}
+
+// baseTransport returns the innermost transport of the chain. It carries the
+// proxy and TLS settings, so they apply to token requests as well as API calls
+// for both the OneAPI and the legacy client.
+func (c *Config) baseTransport() (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if c.httpProxy != "" || c.httpsProxy != "" {
+		proxyFunc, err := c.proxyFunc()
+		if err != nil {
+			return nil, err
+		}
+		transport.Proxy = proxyFunc
+	}
+
+	tlsConfig, err := c.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig
+	}
+	return transport, nil
+}
+
+// proxyFunc builds the proxy selection function from http_proxy, https_proxy
+// and no_proxy. ZPA endpoints are all HTTPS, so http_proxy is also used for
+// HTTPS requests unless https_proxy is set, which matches the behaviour of
+// earlier releases. Credentials in the proxy URL are sent in the
+// Proxy-Authorization header.
+func (c *Config) proxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	for name, value := range map[string]string{"http_proxy": c.httpProxy, "https_proxy": c.httpsProxy} {
+		if value == "" {
+			continue
+		}
+		proxyURL, err := url.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s URL: %v", name, err)
+		}
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return nil, fmt.Errorf("invalid %s URL %q: scheme must be http, https or socks5", name, proxyURL.Redacted())
+		}
+		log.Printf("[INFO] Using %s %s", name, proxyURL.Redacted())
+	}
+
+	httpsProxy := c.httpsProxy
+	if httpsProxy == "" {
+		httpsProxy = c.httpProxy
+	}
+	proxyConfig := &httpproxy.Config{
+		HTTPProxy:  c.httpProxy,
+		HTTPSProxy: httpsProxy,
+		NoProxy:    c.noProxy,
+	}
+	selectProxy := proxyConfig.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return selectProxy(req.URL)
+	}, nil
+}
+
+// tlsConfig returns the TLS configuration for ca_bundle_file, client_cert_file
+// and client_key_file, or nil when none of them is set.
+func (c *Config) tlsConfig() (*tls.Config, error) {
+	if c.caBundleFile == "" && c.clientCertFile == "" && c.clientKeyFile == "" {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if c.caBundleFile != "" {
+		pem, err := os.ReadFile(c.caBundleFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ca_bundle_file: %v", err)
+		}
+		// The bundle is added to the system roots so that a TLS-inspecting
+		// proxy certificate does not break direct connections.
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("ca_bundle_file %s does not contain any PEM encoded certificate", c.caBundleFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if c.clientCertFile != "" || c.clientKeyFile != "" {
+		if c.clientCertFile == "" || c.clientKeyFile == "" {
+			return nil, fmt.Errorf("client_cert_file and client_key_file must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(c.clientCertFile, c.clientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// logEffectiveEndpoints logs where OneAPI authentication and API requests are
+// sent, taking the api_endpoint and auth_endpoint overrides into account.
+func (c *Config) logEffectiveEndpoints() {
+	cloud := c.cloud
+	if cloud == "" {
+		cloud = "PRODUCTION"
+	}
+	authEndpoint := fmt.Sprintf("derived from vanity_domain %q and zscaler_cloud %q", c.vanityDomain, cloud)
+	if c.authEndpoint != "" {
+		authEndpoint = c.authEndpoint
+	}
+	apiEndpoint := fmt.Sprintf("derived from zscaler_cloud %q", cloud)
+	if c.apiEndpoint != "" {
+		apiEndpoint = c.apiEndpoint
+	}
+	log.Printf("[INFO] Effective authentication endpoint: %s", authEndpoint)
+	log.Printf("[INFO] Effective API endpoint: %s", apiEndpoint)
+}
+
+// endpointTransport redirects requests to the api_endpoint and auth_endpoint
+// overrides. Authentication requests go to the auth endpoint, everything else to
+// the API endpoint. The path of an override is used as a prefix, which allows
+// pointing the provider at a relay that serves the API under a sub path.
+type endpointTransport struct {
+	next http.RoundTripper
+	api  *url.URL
+	auth *url.URL
+}
+
+func newEndpointTransport(next http.RoundTripper, apiEndpoint, authEndpoint string) (*endpointTransport, error) {
+	t := &endpointTransport{next: next}
+	var err error
+	if apiEndpoint != "" {
+		if t.api, err = url.Parse(apiEndpoint); err != nil {
+			return nil, fmt.Errorf("invalid api_endpoint: %v", err)
+		}
+		log.Printf("[INFO] Sending API requests to %s", t.api.Redacted())
+	}
+	if authEndpoint != "" {
+		if t.auth, err = url.Parse(authEndpoint); err != nil {
+			return nil, fmt.Errorf("invalid auth_endpoint: %v", err)
+		}
+		log.Printf("[INFO] Sending authentication requests to %s", t.auth.Redacted())
+	}
+	return t, nil
+}
+
+// isAuthRequest reports whether req is a OneAPI token request or a legacy
+// client sign-in request.
+func isAuthRequest(req *http.Request) bool {
+	return isTokenRequest(req) || strings.HasSuffix(req.URL.Path, "/signin")
+}
+
+func (t *endpointTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	target := t.api
+	if isAuthRequest(req) {
+		target = t.auth
+	}
+	if target == nil {
+		return t.next.RoundTrip(req)
+	}
+
+	rewritten := req.Clone(req.Context())
+	rewritten.URL.Scheme = target.Scheme
+	rewritten.URL.Host = target.Host
+	rewritten.URL.Path = strings.TrimSuffix(target.Path, "/") + req.URL.Path
+	rewritten.URL.RawPath = ""
+	rewritten.Host = ""
+	log.Printf("[TRACE] Rewriting %s %s to %s", req.Method, req.URL.Redacted(), rewritten.URL.Redacted())
+	return t.next.RoundTrip(rewritten)
+}
+
+// tokenBucket is a reservation based token bucket. Reservations may drive the
+// token count negative, in which case the caller must wait for the returned
+// duration before sending its request. This keeps callers in arrival order.
+type tokenBucket struct {
+	mu       sync.Mutex
+	capacity float64
+	tokens   float64
+	rate     float64 // tokens per second
+	last     time.Time
+}
+
+func newTokenBucket(requests int, interval time.Duration) *tokenBucket {
+	return &tokenBucket{
+		capacity: float64(requests),
+		tokens:   float64(requests),
+		rate:     float64(requests) / interval.Seconds(),
+		last:     time.Now(),
+	}
+}
+
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// rateLimitedTransport throttles outgoing requests with one token bucket for
+// GET requests and one for POST/PUT/PATCH/DELETE requests, matching the way
+// ZPA publishes its API rate limits. Rate limited responses are logged so that
+// retries done by the SDK show up in the provider logs.
+type rateLimitedTransport struct {
+	next    http.RoundTripper
+	get     *tokenBucket
+	write   *tokenBucket
+	delayed int64
+	limited int64
+}
+
+func newRateLimitedTransport(next http.RoundTripper, getRequests, writeRequests int, interval time.Duration) *rateLimitedTransport {
+	t := &rateLimitedTransport{next: next}
+	if getRequests > 0 {
+		t.get = newTokenBucket(getRequests, interval)
+	}
+	if writeRequests > 0 {
+		t.write = newTokenBucket(writeRequests, interval)
+	}
+	log.Printf("[INFO] Provider rate limiter enabled: %d GET and %d write requests per %s", getRequests, writeRequests, interval)
+	return t
+}
+
+func (t *rateLimitedTransport) bucketFor(method string) *tokenBucket {
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		return t.get
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return t.write
+	}
+	return nil
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if bucket := t.bucketFor(req.Method); bucket != nil {
+		if wait := bucket.reserve(); wait > 0 {
+			count := atomic.AddInt64(&t.delayed, 1)
+			log.Printf("[DEBUG] Rate limiter delaying %s %s by %s (%d requests delayed so far)", req.Method, req.URL.Path, wait.Round(time.Millisecond), count)
+			timer := time.NewTimer(wait)
+			select {
+			case <-req.Context().Done():
+				timer.Stop()
+				return nil, req.Context().Err()
+			case <-timer.C:
+			}
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+		count := atomic.AddInt64(&t.limited, 1)
+		log.Printf("[WARN] ZPA API rate limit hit on %s %s (Retry-After: %q, %d rate limited responses so far)", req.Method, req.URL.Path, resp.Header.Get("Retry-After"), count)
+	}
+	return resp, err
+}
@@ -34,6 +34,8 @@ func validateCountryCode(value interface{}, key string) ([]string, []error) {
 	return warnings, errors
 }
 
+// The status codes and client types below are only used when the LSS catalog
+// cannot be fetched from the API, see lssCatalogOf.
 var supportedLSSUserActivity = []string{
 	"BRK_MT_SETUP_FAIL_BIND_TO_AST_LOCAL_OWNER",
 	"CLT_INVALID_DOMAIN",
@@ -200,7 +202,10 @@ var validObjectTypesForTransLog = map[string]struct{}{
 	"CLIENT_TYPE": {},
 }
 
-func validateLSSConfigControllerFilters(sourceLogType, objectType, filter string, values []string, operands []lssconfigcontroller.PolicyRuleResourceOperands) error {
+// validateLSSConfigControllerFilters checks the filter and the policy rule
+// operands of an LSS configuration against the catalog of status codes and
+// client types.
+func validateLSSConfigControllerFilters(catalog *lssCatalog, sourceLogType, objectType, filter string, values []string, operands []lssconfigcontroller.PolicyRuleResourceOperands) error {
 	// New logic to check if filters are not supported for specific log types
 	if filter != "" {
 		if displayName, found := noFilterSupportLogTypes[sourceLogType]; found {
@@ -209,41 +214,12 @@ func validateLSSConfigControllerFilters(sourceLogType, objectType, filter string
 	}
 	// Filter validation
 	if filter != "" {
-		isValidFilter := false
-		switch sourceLogType {
-		case "zpn_auth_log":
-			for _, validFilter := range supportedLSSUserStatus {
-				if filter == validFilter {
-					isValidFilter = true
-					break
-				}
-			}
-		case "zpn_trans_log":
-			for _, validFilter := range supportedLSSUserActivity {
-				if filter == validFilter {
-					isValidFilter = true
-					break
-				}
-			}
-		case "zpn_ast_auth_log":
-			for _, validFilter := range supportedAppConnectorStatus {
-				if filter == validFilter {
-					isValidFilter = true
-					break
-				}
-			}
-		case "zpn_sys_auth_log":
-			for _, validFilter := range supportedPrivateServiceEdgeStatus {
-				if filter == validFilter {
-					isValidFilter = true
-					break
-				}
-			}
-		default:
+		codes, ok := catalog.statusCodes[sourceLogType]
+		if !ok {
 			return fmt.Errorf("invalid source_log_type: %s", sourceLogType)
 		}
-		if !isValidFilter {
-			return fmt.Errorf("invalid filter: %s for source_log_type: %s", filter, sourceLogType)
+		if err := catalog.check("filter for source_log_type "+sourceLogType, filter, codes); err != nil {
+			return err
 		}
 	}
 	// Object type validation
@@ -265,8 +241,8 @@ func validateLSSConfigControllerFilters(sourceLogType, objectType, filter string
 		// Value validation for CLIENT_TYPE
 		if objectType == "CLIENT_TYPE" {
 			for _, value := range values {
-				if _, exists := supportedClientTypes[value]; !exists {
-					return fmt.Errorf("invalid value: %s for object_type CLIENT_TYPE", value)
+				if err := catalog.check("value for object_type CLIENT_TYPE", value, catalog.clientTypes); err != nil {
+					return err
 				}
 			}
 		}